@@ -2,6 +2,7 @@ package batchsubmitter
 
 import (
 	"context"
+	"crypto/ecdsa"
 	"os"
 	"time"
 
@@ -9,8 +10,10 @@ import (
 	"github.com/ethereum-optimism/optimism/batch-submitter/drivers/sequencer"
 	bsscore "github.com/ethereum-optimism/optimism/bss-core"
 	"github.com/ethereum-optimism/optimism/bss-core/dial"
+	"github.com/ethereum-optimism/optimism/bss-core/drivers"
 	"github.com/ethereum-optimism/optimism/bss-core/metrics"
-	"github.com/ethereum-optimism/optimism/bss-core/txmgr"
+	"github.com/ethereum-optimism/optimism/op-service/txmgr"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/getsentry/sentry-go"
 	"github.com/urfave/cli"
@@ -111,11 +114,23 @@ func Main(gitVersion string) func(ctx *cli.Context) error {
 			return err
 		}
 
-		txManagerConfig := txmgr.Config{
-			ResubmissionTimeout:       cfg.ResubmissionTimeout,
-			ReceiptQueryInterval:      time.Second,
-			NumConfirmations:          cfg.NumConfirmations,
-			SafeAbortNonceTooLowCount: cfg.SafeAbortNonceTooLowCount,
+		// The tx managers of the services share the L1 backend, which falls
+		// back to a default gas tip cap if the L1 node can't suggest one.
+		txMgrBackend := drivers.MaxPriorityFeeFallbackBackend{ETHBackend: l1Client}
+		newTxManager := func(name string, privKey *ecdsa.PrivateKey) *txmgr.Manager {
+			return txmgr.NewManager(txmgr.ManagerConfig{
+				Config: txmgr.Config{
+					Log:                       log.Root(),
+					Name:                      name,
+					ResubmissionTimeout:       cfg.ResubmissionTimeout,
+					ReceiptQueryInterval:      time.Second,
+					NumConfirmations:          cfg.NumConfirmations,
+					SafeAbortNonceTooLowCount: cfg.SafeAbortNonceTooLowCount,
+				},
+				ChainID: chainID,
+				From:    crypto.PubkeyToAddress(privKey.PublicKey),
+				Signer:  txmgr.PrivateKeySignerFn(privKey, chainID),
+			}, txMgrBackend, txmgr.NewFeePricer(log.Root(), txmgr.PricingConfig{}, txMgrBackend, nil))
 		}
 
 		var services []*bsscore.Service
@@ -129,7 +144,6 @@ func Main(gitVersion string) func(ctx *cli.Context) error {
 				MaxTxSize:             cfg.MaxL1TxSize,
 				MaxPlaintextBatchSize: cfg.MaxPlaintextBatchSize,
 				CTCAddr:               ctcAddress,
-				ChainID:               chainID,
 				PrivKey:               sequencerPrivKey,
 				BatchType:             sequencer.BatchTypeFromString(cfg.SequencerBatchType),
 			})
@@ -138,12 +152,11 @@ func Main(gitVersion string) func(ctx *cli.Context) error {
 			}
 
 			services = append(services, bsscore.NewService(bsscore.ServiceConfig{
-				Context:         ctx,
-				Driver:          batchTxDriver,
-				PollInterval:    cfg.PollInterval,
-				ClearPendingTx:  cfg.ClearPendingTxs,
-				L1Client:        l1Client,
-				TxManagerConfig: txManagerConfig,
+				Context:      ctx,
+				Driver:       batchTxDriver,
+				PollInterval: cfg.PollInterval,
+				L1Client:     l1Client,
+				TxManager:    newTxManager("Sequencer", sequencerPrivKey),
 			}))
 		}
 
@@ -157,7 +170,6 @@ func Main(gitVersion string) func(ctx *cli.Context) error {
 				MaxStateRootElements: cfg.MaxStateRootElements,
 				SCCAddr:              sccAddress,
 				CTCAddr:              ctcAddress,
-				ChainID:              chainID,
 				PrivKey:              proposerPrivKey,
 			})
 			if err != nil {
@@ -165,12 +177,11 @@ func Main(gitVersion string) func(ctx *cli.Context) error {
 			}
 
 			services = append(services, bsscore.NewService(bsscore.ServiceConfig{
-				Context:         ctx,
-				Driver:          batchStateDriver,
-				PollInterval:    cfg.PollInterval,
-				ClearPendingTx:  cfg.ClearPendingTxs,
-				L1Client:        l1Client,
-				TxManagerConfig: txManagerConfig,
+				Context:      ctx,
+				Driver:       batchStateDriver,
+				PollInterval: cfg.PollInterval,
+				L1Client:     l1Client,
+				TxManager:    newTxManager("Proposer", proposerPrivKey),
 			}))
 		}
 
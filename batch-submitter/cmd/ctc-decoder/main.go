@@ -0,0 +1,150 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"os"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/urfave/cli"
+
+	"github.com/ethereum-optimism/optimism/batch-submitter/ctcdecoder"
+)
+
+var (
+	l1EthRpcFlag = cli.StringFlag{
+		Name:     "l1-eth-rpc",
+		Usage:    "HTTP provider URL for L1",
+		Required: true,
+	}
+	ctcAddressFlag = cli.StringFlag{
+		Name:     "ctc-address",
+		Usage:    "Address of the CanonicalTransactionChain contract",
+		Required: true,
+	}
+	startBlockFlag = cli.Uint64Flag{
+		Name:     "start-block",
+		Usage:    "First L1 block to scan for batches",
+		Required: true,
+	}
+	endBlockFlag = cli.Uint64Flag{
+		Name:     "end-block",
+		Usage:    "Last L1 block to scan for batches",
+		Required: true,
+	}
+	enqueueStartBlockFlag = cli.Uint64Flag{
+		Name: "enqueue-start-block",
+		Usage: "First L1 block to scan for enqueued transactions, which must not " +
+			"be after the enqueue transaction of the first queue element of the " +
+			"batches. Defaults to the start block",
+	}
+	formatFlag = cli.StringFlag{
+		Name: "format",
+		Usage: "Output format: json writes a line of JSON per batch, rlp writes " +
+			"a stream of RLP encoded elements and fails on invalid batches, " +
+			"including batches with queue elements that were not enqueued " +
+			"after the enqueue start block",
+		Value: ctcdecoder.FormatJSON,
+	}
+	outputFlag = cli.StringFlag{
+		Name:  "output",
+		Usage: "File to write the decoded batches to, stdout if empty",
+	}
+	maxBlockRangeFlag = cli.Uint64Flag{
+		Name:  "max-block-range",
+		Usage: "Maximum number of L1 blocks to fetch batch events of per request",
+		Value: 2000,
+	}
+	logLevelFlag = cli.StringFlag{
+		Name:  "log-level",
+		Usage: "The lowest log level that will be output",
+		Value: "info",
+	}
+)
+
+func main() {
+	app := cli.NewApp()
+	app.Name = "ctc-decoder"
+	app.Usage = "Decode legacy CanonicalTransactionChain batches"
+	app.Description = "Tool that fetches the batches appended to the " +
+		"CanonicalTransactionChain in a range of L1 blocks, and exports " +
+		"their contexts and elements. Queue elements are resolved from the " +
+		"TransactionEnqueued events of the CanonicalTransactionChain"
+	app.Flags = []cli.Flag{
+		l1EthRpcFlag,
+		ctcAddressFlag,
+		startBlockFlag,
+		endBlockFlag,
+		enqueueStartBlockFlag,
+		formatFlag,
+		outputFlag,
+		maxBlockRangeFlag,
+		logLevelFlag,
+	}
+	app.Action = run
+
+	if err := app.Run(os.Args); err != nil {
+		log.Crit("Application failed", "message", err)
+	}
+}
+
+func run(ctx *cli.Context) error {
+	lvl, err := log.LvlFromString(ctx.String(logLevelFlag.Name))
+	if err != nil {
+		return err
+	}
+	// Batches may be written to stdout, so log to stderr.
+	logger := log.New()
+	logger.SetHandler(log.LvlFilterHandler(lvl, log.StreamHandler(os.Stderr, log.TerminalFormat(false))))
+
+	if !common.IsHexAddress(ctx.String(ctcAddressFlag.Name)) {
+		return errors.New("invalid CTC address")
+	}
+	ctcAddr := common.HexToAddress(ctx.String(ctcAddressFlag.Name))
+
+	out := os.Stdout
+	if path := ctx.String(outputFlag.Name); path != "" {
+		out, err = os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer out.Close()
+	}
+	buf := bufio.NewWriter(out)
+	writer, err := ctcdecoder.NewWriter(ctx.String(formatFlag.Name), buf)
+	if err != nil {
+		return err
+	}
+
+	client, err := ethclient.DialContext(context.Background(), ctx.String(l1EthRpcFlag.Name))
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	decoder, err := ctcdecoder.NewDecoder(client, ctcAddr, ctx.Uint64(maxBlockRangeFlag.Name), logger)
+	if err != nil {
+		return err
+	}
+	start := ctx.Uint64(startBlockFlag.Name)
+	// Queue elements may be appended long after they were enqueued.
+	enqueueStart := ctx.Uint64(enqueueStartBlockFlag.Name)
+	if ctx.IsSet(enqueueStartBlockFlag.Name) && enqueueStart < start {
+		if err := decoder.LoadEnqueued(context.Background(), enqueueStart, start-1); err != nil {
+			return err
+		}
+	}
+	err = decoder.Batches(
+		context.Background(),
+		start,
+		ctx.Uint64(endBlockFlag.Name),
+		writer.WriteBatch,
+	)
+	if err != nil {
+		return err
+	}
+	return buf.Flush()
+}
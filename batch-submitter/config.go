@@ -127,10 +127,6 @@ type Config struct {
 	//submitter key should hold before it starts to log errors.
 	SafeMinimumEtherBalance uint64
 
-	// ClearPendingTxs is a boolean to clear the pending transactions in the
-	// mempool on startup.
-	ClearPendingTxs bool
-
 	/* Optional Params */
 
 	// LogLevel is the lowest log level that will be output.
@@ -219,7 +215,6 @@ func NewConfig(ctx *cli.Context) (Config, error) {
 		RunTxBatchSubmitter:       ctx.GlobalBool(flags.RunTxBatchSubmitterFlag.Name),
 		RunStateBatchSubmitter:    ctx.GlobalBool(flags.RunStateBatchSubmitterFlag.Name),
 		SafeMinimumEtherBalance:   ctx.GlobalUint64(flags.SafeMinimumEtherBalanceFlag.Name),
-		ClearPendingTxs:           ctx.GlobalBool(flags.ClearPendingTxsFlag.Name),
 		/* Optional Flags */
 		LogLevel:            ctx.GlobalString(flags.LogLevelFlag.Name),
 		LogTerminal:         ctx.GlobalBool(flags.LogTerminalFlag.Name),
@@ -0,0 +1,345 @@
+package ctcdecoder
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum-optimism/optimism/batch-submitter/bindings/ctc"
+	"github.com/ethereum-optimism/optimism/batch-submitter/drivers/sequencer"
+	l2types "github.com/ethereum-optimism/optimism/l2geth/core/types"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const (
+	appendSequencerBatchMethodName  = "appendSequencerBatch"
+	sequencerBatchAppendedEventName = "SequencerBatchAppended"
+	transactionEnqueuedEventName    = "TransactionEnqueued"
+
+	// batchHeaderSize is the size of the should_start_at_element,
+	// total_elements_to_append and num_contexts fields of a batch.
+	batchHeaderSize = 5 + 3 + 3
+)
+
+// ErrNotAppendSequencerBatch represents the error when the calldata of a
+// batch transaction is not a direct call to appendSequencerBatch, e.g.
+// because the CTC was called by another contract.
+var ErrNotAppendSequencerBatch = errors.New("not an appendSequencerBatch call")
+
+// L1Client fetches the batch transactions of the CTC. It is implemented by
+// `ethclient.Client`.
+type L1Client interface {
+	FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error)
+	TransactionByHash(ctx context.Context, hash common.Hash) (*types.Transaction, bool, error)
+}
+
+// Element is an element of the CTC, i.e. a legacy L2 transaction. The JSON
+// fields are named after the fields of l2geth transactions, so that they can
+// be compared with the l2geth RPC.
+type Element struct {
+	// Index is the index of the element in the CTC. The element is the
+	// single transaction of L2 block Index+1.
+	Index       uint64 `json:"index"`
+	QueueOrigin string `json:"queueOrigin"`
+	// QueueIndex is only set for queue elements.
+	QueueIndex *uint64 `json:"queueIndex,omitempty"`
+	// L1BlockNumber and L1Timestamp are the block number and timestamp of
+	// the context of sequencer elements, and of the enqueue transaction of
+	// queue elements.
+	L1BlockNumber *uint64 `json:"l1BlockNumber,omitempty"`
+	L1Timestamp   *uint64 `json:"l1Timestamp,omitempty"`
+	// Hash and RawTransaction are only set for sequencer elements.
+	Hash           *common.Hash  `json:"hash,omitempty"`
+	RawTransaction hexutil.Bytes `json:"rawTransaction,omitempty"`
+	// L1TxOrigin, Target, GasLimit and Data are only set for queue
+	// elements, from their TransactionEnqueued event.
+	L1TxOrigin *common.Address `json:"l1TxOrigin,omitempty"`
+	Target     *common.Address `json:"to,omitempty"`
+	GasLimit   *uint64         `json:"gas,omitempty"`
+	Data       hexutil.Bytes   `json:"input,omitempty"`
+}
+
+// Batch is an appendSequencerBatch call to the CTC, along with the elements
+// it appends.
+type Batch struct {
+	L1TxHash              common.Hash              `json:"l1_tx_hash"`
+	L1BlockNumber         uint64                   `json:"l1_block_number"`
+	BatchType             string                   `json:"batch_type"`
+	ShouldStartAtElement  uint64                   `json:"should_start_at_element"`
+	TotalElementsToAppend uint64                   `json:"total_elements_to_append"`
+	StartingQueueIndex    uint64                   `json:"starting_queue_index"`
+	Contexts              []sequencer.BatchContext `json:"contexts"`
+	Elements              []*Element               `json:"elements"`
+	// Error is set if the batch can't be decoded, or is inconsistent with
+	// its SequencerBatchAppended event.
+	Error string `json:"error,omitempty"`
+}
+
+// Decoder reads the sequencer batches appended to the CTC. It is not safe
+// for concurrent use.
+type Decoder struct {
+	client     L1Client
+	ctcAddr    common.Address
+	filterer   *ctc.CanonicalTransactionChainFilterer
+	methodID   []byte
+	eventID    common.Hash
+	enqueuedID common.Hash
+	maxRange   uint64
+	log        log.Logger
+
+	// enqueued holds the enqueued transactions that were not appended by a
+	// batch yet, by queue index.
+	enqueued map[uint64]*ctc.CanonicalTransactionChainTransactionEnqueued
+}
+
+// NewDecoder creates a Decoder of the batches of the CTC at ctcAddr. Logs are
+// fetched in ranges of at most maxRange blocks, since L1 providers limit the
+// range of log queries.
+func NewDecoder(client L1Client, ctcAddr common.Address, maxRange uint64, logger log.Logger) (*Decoder, error) {
+	if maxRange == 0 {
+		return nil, errors.New("max block range must be positive")
+	}
+	filterer, err := ctc.NewCanonicalTransactionChainFilterer(ctcAddr, nil)
+	if err != nil {
+		return nil, err
+	}
+	ctcABI, err := ctc.CanonicalTransactionChainMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	return &Decoder{
+		client:     client,
+		ctcAddr:    ctcAddr,
+		filterer:   filterer,
+		methodID:   ctcABI.Methods[appendSequencerBatchMethodName].ID,
+		eventID:    ctcABI.Events[sequencerBatchAppendedEventName].ID,
+		enqueuedID: ctcABI.Events[transactionEnqueuedEventName].ID,
+		maxRange:   maxRange,
+		log:        logger,
+		enqueued:   make(map[uint64]*ctc.CanonicalTransactionChainTransactionEnqueued),
+	}, nil
+}
+
+// LoadEnqueued loads the transactions enqueued to the CTC from the start to
+// the end L1 block, inclusive. Batches only resolves the queue elements that
+// were enqueued in the blocks it scans, so the transactions enqueued before
+// its start block must be loaded first.
+func (d *Decoder) LoadEnqueued(ctx context.Context, start, end uint64) error {
+	return d.filterLogs(ctx, start, end, []common.Hash{d.enqueuedID}, func(l types.Log) error {
+		return d.addEnqueued(l)
+	})
+}
+
+// Batches calls fn with every batch appended to the CTC from the start to the
+// end L1 block, inclusive, in order. The queue elements of the batches are
+// resolved from the TransactionEnqueued events of the CTC.
+func (d *Decoder) Batches(ctx context.Context, start, end uint64, fn func(*Batch) error) error {
+	return d.filterLogs(ctx, start, end, []common.Hash{d.eventID, d.enqueuedID}, func(l types.Log) error {
+		if l.Topics[0] == d.enqueuedID {
+			return d.addEnqueued(l)
+		}
+		batch, err := d.batch(ctx, l)
+		if err != nil {
+			return err
+		}
+		if batch.Error != "" {
+			d.log.Warn("Invalid batch", "tx", batch.L1TxHash, "err", batch.Error)
+		}
+		return fn(batch)
+	})
+}
+
+// filterLogs calls fn with every CTC log of the given event IDs from the start
+// to the end L1 block, inclusive, in order.
+func (d *Decoder) filterLogs(ctx context.Context, start, end uint64, eventIDs []common.Hash, fn func(types.Log) error) error {
+	if end < start {
+		return fmt.Errorf("end block %d is before start block %d", end, start)
+	}
+
+	for from := start; from <= end; from += d.maxRange {
+		to := from + d.maxRange - 1
+		if to > end || to < from {
+			to = end
+		}
+		logs, err := d.client.FilterLogs(ctx, ethereum.FilterQuery{
+			FromBlock: new(big.Int).SetUint64(from),
+			ToBlock:   new(big.Int).SetUint64(to),
+			Addresses: []common.Address{d.ctcAddr},
+			Topics:    [][]common.Hash{eventIDs},
+		})
+		if err != nil {
+			return fmt.Errorf("cannot fetch CTC logs of L1 blocks %d-%d: %w", from, to, err)
+		}
+		d.log.Info("Fetched CTC logs", "from", from, "to", to, "logs", len(logs))
+
+		for _, l := range logs {
+			if l.Removed || len(l.Topics) == 0 {
+				continue
+			}
+			if err := fn(l); err != nil {
+				return err
+			}
+		}
+		if to == end {
+			break
+		}
+	}
+	return nil
+}
+
+func (d *Decoder) addEnqueued(l types.Log) error {
+	event, err := d.filterer.ParseTransactionEnqueued(l)
+	if err != nil {
+		return fmt.Errorf("cannot parse enqueue event of tx %s: %w", l.TxHash, err)
+	}
+	d.enqueued[event.QueueIndex.Uint64()] = event
+	return nil
+}
+
+func (d *Decoder) batch(ctx context.Context, l types.Log) (*Batch, error) {
+	event, err := d.filterer.ParseSequencerBatchAppended(l)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse batch event of tx %s: %w", l.TxHash, err)
+	}
+	tx, _, err := d.client.TransactionByHash(ctx, l.TxHash)
+	if err != nil {
+		return nil, fmt.Errorf("cannot fetch batch tx %s: %w", l.TxHash, err)
+	}
+
+	batch, err := d.DecodeBatch(tx.Data(), event.StartingQueueIndex.Uint64())
+	if batch == nil {
+		batch = &Batch{StartingQueueIndex: event.StartingQueueIndex.Uint64()}
+	}
+	batch.L1TxHash = l.TxHash
+	batch.L1BlockNumber = l.BlockNumber
+	if err != nil {
+		batch.Error = err.Error()
+		return batch, nil
+	}
+
+	if total := batch.ShouldStartAtElement + batch.TotalElementsToAppend; total != event.TotalElements.Uint64() {
+		batch.Error = fmt.Sprintf("batch ends at element %d, but the CTC has %d elements", total, event.TotalElements)
+	}
+	var numQueued uint64
+	for _, e := range batch.Elements {
+		if e.QueueIndex != nil {
+			numQueued++
+		}
+	}
+	if numQueued != event.NumQueueElements.Uint64() && batch.Error == "" {
+		batch.Error = fmt.Sprintf("batch has %d queue elements, but the CTC appended %d", numQueued, event.NumQueueElements)
+	}
+
+	for _, e := range batch.Elements {
+		if e.QueueIndex == nil {
+			continue
+		}
+		enqueued, ok := d.enqueued[*e.QueueIndex]
+		if !ok {
+			if batch.Error == "" {
+				batch.Error = fmt.Sprintf("queue element %d was not enqueued in the scanned L1 blocks", *e.QueueIndex)
+			}
+			continue
+		}
+		delete(d.enqueued, *e.QueueIndex)
+		blockNumber, timestamp := enqueued.Raw.BlockNumber, enqueued.Timestamp.Uint64()
+		origin, target, gasLimit := enqueued.L1TxOrigin, enqueued.Target, enqueued.GasLimit.Uint64()
+		e.L1BlockNumber = &blockNumber
+		e.L1Timestamp = &timestamp
+		e.L1TxOrigin = &origin
+		e.Target = &target
+		e.GasLimit = &gasLimit
+		e.Data = enqueued.Data
+	}
+	return batch, nil
+}
+
+// DecodeBatch decodes the calldata of an appendSequencerBatch call, given the
+// index of the first queue element appended by the batch. Only the queue
+// index of queue elements is set, since the rest is not part of the batch.
+// The partially decoded batch is returned along with any error.
+func (d *Decoder) DecodeBatch(calldata []byte, startingQueueIndex uint64) (*Batch, error) {
+	if !bytes.HasPrefix(calldata, d.methodID) {
+		return nil, ErrNotAppendSequencerBatch
+	}
+	data := calldata[len(d.methodID):]
+
+	var params sequencer.AppendSequencerBatchParams
+	if err := params.Read(bytes.NewReader(data)); err != nil {
+		return nil, fmt.Errorf("cannot decode batch: %w", err)
+	}
+
+	batch := &Batch{
+		BatchType:             batchType(data).String(),
+		ShouldStartAtElement:  params.ShouldStartAtElement,
+		TotalElementsToAppend: params.TotalElementsToAppend,
+		StartingQueueIndex:    startingQueueIndex,
+		Contexts:              params.Contexts,
+		Elements:              make([]*Element, 0, params.TotalElementsToAppend),
+	}
+
+	index := params.ShouldStartAtElement
+	queueIndex := startingQueueIndex
+	txs := params.Txs
+	for i, c := range params.Contexts {
+		if c.NumSequencedTxs > uint64(len(txs)) {
+			return batch, fmt.Errorf("context %d has %d sequencer txs, but only %d are left",
+				i, c.NumSequencedTxs, len(txs))
+		}
+		for _, tx := range txs[:c.NumSequencedTxs] {
+			hash := common.Hash(tx.Tx().Hash())
+			blockNumber, timestamp := c.BlockNumber, c.Timestamp
+			batch.Elements = append(batch.Elements, &Element{
+				Index:          index,
+				QueueOrigin:    l2types.QueueOriginSequencer.String(),
+				L1BlockNumber:  &blockNumber,
+				L1Timestamp:    &timestamp,
+				Hash:           &hash,
+				RawTransaction: tx.RawTx(),
+			})
+			index++
+		}
+		txs = txs[c.NumSequencedTxs:]
+
+		for j := uint64(0); j < c.NumSubsequentQueueTxs; j++ {
+			qi := queueIndex
+			batch.Elements = append(batch.Elements, &Element{
+				Index:       index,
+				QueueOrigin: l2types.QueueOriginL1ToL2.String(),
+				QueueIndex:  &qi,
+			})
+			index++
+			queueIndex++
+		}
+	}
+
+	if len(txs) != 0 {
+		return batch, fmt.Errorf("%d sequencer txs are not covered by a context", len(txs))
+	}
+	if n := uint64(len(batch.Elements)); n != params.TotalElementsToAppend {
+		return batch, fmt.Errorf("contexts have %d elements, but the batch appends %d", n, params.TotalElementsToAppend)
+	}
+	return batch, nil
+}
+
+// batchType returns the type of a batch that was successfully read, which is
+// set by the marker context, if any.
+func batchType(data []byte) sequencer.BatchType {
+	if len(data) < batchHeaderSize+16 {
+		return sequencer.BatchTypeLegacy
+	}
+	if numContexts := data[8:batchHeaderSize]; bytes.Equal(numContexts, []byte{0, 0, 0}) {
+		return sequencer.BatchTypeLegacy
+	}
+	var c sequencer.BatchContext
+	if err := c.Read(bytes.NewReader(data[batchHeaderSize:])); err != nil || !c.IsMarkerContext() {
+		return sequencer.BatchTypeLegacy
+	}
+	return c.MarkerBatchType()
+}
@@ -0,0 +1,373 @@
+package ctcdecoder_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum-optimism/optimism/batch-submitter/bindings/ctc"
+	"github.com/ethereum-optimism/optimism/batch-submitter/ctcdecoder"
+	"github.com/ethereum-optimism/optimism/batch-submitter/drivers/sequencer"
+	l2common "github.com/ethereum-optimism/optimism/l2geth/common"
+	l2types "github.com/ethereum-optimism/optimism/l2geth/core/types"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/require"
+)
+
+var testCTCAddr = common.HexToAddress("0x5E4e65926BA27467555EB562121fac00D24E9dD2")
+
+// fakeL1Client serves the events and batch transactions of a fake CTC.
+type fakeL1Client struct {
+	logs    []types.Log
+	txs     map[common.Hash]*types.Transaction
+	queries []ethereum.FilterQuery
+}
+
+func (c *fakeL1Client) FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	c.queries = append(c.queries, q)
+	var logs []types.Log
+	for _, l := range c.logs {
+		if l.BlockNumber < q.FromBlock.Uint64() || l.BlockNumber > q.ToBlock.Uint64() {
+			continue
+		}
+		for _, id := range q.Topics[0] {
+			if l.Topics[0] == id {
+				logs = append(logs, l)
+			}
+		}
+	}
+	return logs, nil
+}
+
+func (c *fakeL1Client) TransactionByHash(ctx context.Context, hash common.Hash) (*types.Transaction, bool, error) {
+	return c.txs[hash], false, nil
+}
+
+// addBatch includes a batch appending numQueued queue elements in an L1
+// block.
+func (c *fakeL1Client) addBatch(t *testing.T, blockNumber uint64, calldata []byte, startingQueueIndex, numQueued, totalElements uint64) common.Hash {
+	ctcABI, err := ctc.CanonicalTransactionChainMetaData.GetAbi()
+	require.NoError(t, err)
+	event := ctcABI.Events["SequencerBatchAppended"]
+	data, err := event.Inputs.Pack(
+		new(big.Int).SetUint64(startingQueueIndex),
+		new(big.Int).SetUint64(numQueued),
+		new(big.Int).SetUint64(totalElements),
+	)
+	require.NoError(t, err)
+
+	tx := types.NewTransaction(uint64(len(c.txs)), testCTCAddr, common.Big0, 0, common.Big0, calldata)
+	c.txs[tx.Hash()] = tx
+	c.logs = append(c.logs, types.Log{
+		Address:     testCTCAddr,
+		Topics:      []common.Hash{event.ID},
+		Data:        data,
+		BlockNumber: blockNumber,
+		TxHash:      tx.Hash(),
+	})
+	return tx.Hash()
+}
+
+// addEnqueued includes the enqueue of a transaction in an L1 block.
+func (c *fakeL1Client) addEnqueued(t *testing.T, blockNumber, queueIndex, timestamp uint64, data []byte) {
+	ctcABI, err := ctc.CanonicalTransactionChainMetaData.GetAbi()
+	require.NoError(t, err)
+	event := ctcABI.Events["TransactionEnqueued"]
+	nonIndexed, err := event.Inputs.NonIndexed().Pack(
+		new(big.Int).SetUint64(21000+queueIndex),
+		data,
+		new(big.Int).SetUint64(timestamp),
+	)
+	require.NoError(t, err)
+	c.logs = append(c.logs, types.Log{
+		Address: testCTCAddr,
+		Topics: []common.Hash{
+			event.ID,
+			common.BytesToHash(testL1TxOrigin.Bytes()),
+			common.BytesToHash(testTarget.Bytes()),
+			common.BigToHash(new(big.Int).SetUint64(queueIndex)),
+		},
+		Data:        nonIndexed,
+		BlockNumber: blockNumber,
+	})
+}
+
+var (
+	testL1TxOrigin = common.Address{0x0a}
+	testTarget     = common.Address{0x0b}
+)
+
+func newL2Tx(nonce uint64) *sequencer.CachedTx {
+	tx := l2types.NewTransaction(nonce, l2common.Address{0x01}, big.NewInt(1), 21000, big.NewInt(1), nil)
+	return sequencer.NewCachedTx(tx)
+}
+
+// batchCalldata encodes an appendSequencerBatch call.
+func batchCalldata(t *testing.T, params *sequencer.AppendSequencerBatchParams, batchType sequencer.BatchType) []byte {
+	ctcABI, err := ctc.CanonicalTransactionChainMetaData.GetAbi()
+	require.NoError(t, err)
+	data, err := params.Serialize(batchType)
+	require.NoError(t, err)
+	return append(ctcABI.Methods["appendSequencerBatch"].ID, data...)
+}
+
+func newDecoder(t *testing.T, client ctcdecoder.L1Client, maxRange uint64) *ctcdecoder.Decoder {
+	logger := log.New()
+	logger.SetHandler(log.DiscardHandler())
+	decoder, err := ctcdecoder.NewDecoder(client, testCTCAddr, maxRange, logger)
+	require.NoError(t, err)
+	return decoder
+}
+
+func TestDecodeBatch(t *testing.T) {
+	t.Parallel()
+
+	txs := []*sequencer.CachedTx{newL2Tx(0), newL2Tx(1), newL2Tx(2)}
+	params := &sequencer.AppendSequencerBatchParams{
+		ShouldStartAtElement:  10,
+		TotalElementsToAppend: 5,
+		Contexts: []sequencer.BatchContext{
+			{NumSequencedTxs: 2, NumSubsequentQueueTxs: 1, Timestamp: 1000, BlockNumber: 100},
+			{NumSequencedTxs: 1, NumSubsequentQueueTxs: 1, Timestamp: 1012, BlockNumber: 101},
+		},
+		Txs: txs,
+	}
+
+	for _, batchType := range []sequencer.BatchType{sequencer.BatchTypeLegacy, sequencer.BatchTypeZlib} {
+		batchType := batchType
+		t.Run(batchType.String(), func(t *testing.T) {
+			decoder := newDecoder(t, nil, 1)
+			batch, err := decoder.DecodeBatch(batchCalldata(t, params, batchType), 7)
+			require.NoError(t, err)
+			require.Equal(t, batchType.String(), batch.BatchType)
+			require.Equal(t, uint64(10), batch.ShouldStartAtElement)
+			require.Equal(t, uint64(5), batch.TotalElementsToAppend)
+			require.Equal(t, params.Contexts, batch.Contexts)
+			require.Len(t, batch.Elements, 5)
+
+			for i, e := range batch.Elements {
+				require.Equal(t, uint64(10+i), e.Index)
+			}
+			for i, j := range []int{0, 1, 3} {
+				e := batch.Elements[j]
+				require.Equal(t, l2types.QueueOriginSequencer.String(), e.QueueOrigin)
+				require.Nil(t, e.QueueIndex)
+				require.Equal(t, []byte(txs[i].RawTx()), []byte(e.RawTransaction))
+				require.Equal(t, common.Hash(txs[i].Tx().Hash()), *e.Hash)
+			}
+			require.Equal(t, uint64(100), *batch.Elements[1].L1BlockNumber)
+			require.Equal(t, uint64(1012), *batch.Elements[3].L1Timestamp)
+
+			for i, j := range []int{2, 4} {
+				e := batch.Elements[j]
+				require.Equal(t, l2types.QueueOriginL1ToL2.String(), e.QueueOrigin)
+				require.Equal(t, uint64(7+i), *e.QueueIndex)
+				require.Nil(t, e.L1BlockNumber)
+				require.Empty(t, e.RawTransaction)
+			}
+		})
+	}
+}
+
+func TestDecodeBatchErrors(t *testing.T) {
+	t.Parallel()
+
+	decoder := newDecoder(t, nil, 1)
+
+	_, err := decoder.DecodeBatch([]byte{0x01, 0x02, 0x03, 0x04}, 0)
+	require.ErrorIs(t, err, ctcdecoder.ErrNotAppendSequencerBatch)
+
+	params := &sequencer.AppendSequencerBatchParams{
+		TotalElementsToAppend: 3,
+		Contexts: []sequencer.BatchContext{
+			{NumSequencedTxs: 1, Timestamp: 1000, BlockNumber: 100},
+		},
+		Txs: []*sequencer.CachedTx{newL2Tx(0), newL2Tx(1)},
+	}
+	batch, err := decoder.DecodeBatch(batchCalldata(t, params, sequencer.BatchTypeLegacy), 0)
+	require.EqualError(t, err, "1 sequencer txs are not covered by a context")
+	require.Len(t, batch.Elements, 1)
+}
+
+func TestBatches(t *testing.T) {
+	t.Parallel()
+
+	client := &fakeL1Client{txs: make(map[common.Hash]*types.Transaction)}
+	client.addEnqueued(t, 2, 0, 990, []byte{0x01, 0x02})
+	first := client.addBatch(t, 3, batchCalldata(t, &sequencer.AppendSequencerBatchParams{
+		ShouldStartAtElement:  0,
+		TotalElementsToAppend: 2,
+		Contexts: []sequencer.BatchContext{
+			{NumSequencedTxs: 1, NumSubsequentQueueTxs: 1, Timestamp: 1000, BlockNumber: 2},
+		},
+		Txs: []*sequencer.CachedTx{newL2Tx(0)},
+	}, sequencer.BatchTypeZlib), 0, 1, 2)
+	// The event claims more elements than the batch appends.
+	inconsistent := client.addBatch(t, 7, batchCalldata(t, &sequencer.AppendSequencerBatchParams{
+		ShouldStartAtElement:  2,
+		TotalElementsToAppend: 1,
+		Contexts: []sequencer.BatchContext{
+			{NumSequencedTxs: 1, Timestamp: 1012, BlockNumber: 6},
+		},
+		Txs: []*sequencer.CachedTx{newL2Tx(1)},
+	}, sequencer.BatchTypeLegacy), 1, 0, 4)
+	notDecodable := client.addBatch(t, 8, []byte{0xde, 0xad}, 1, 0, 4)
+	client.addBatch(t, 20, nil, 1, 0, 4)
+
+	decoder := newDecoder(t, client, 4)
+	var batches []*ctcdecoder.Batch
+	err := decoder.Batches(context.Background(), 1, 10, func(b *ctcdecoder.Batch) error {
+		batches = append(batches, b)
+		return nil
+	})
+	require.NoError(t, err)
+
+	require.Len(t, client.queries, 3)
+	require.Equal(t, uint64(1), client.queries[0].FromBlock.Uint64())
+	require.Equal(t, uint64(4), client.queries[0].ToBlock.Uint64())
+	require.Equal(t, uint64(9), client.queries[2].FromBlock.Uint64())
+	require.Equal(t, uint64(10), client.queries[2].ToBlock.Uint64())
+
+	require.Len(t, batches, 3)
+	require.Equal(t, first, batches[0].L1TxHash)
+	require.Equal(t, uint64(3), batches[0].L1BlockNumber)
+	require.Empty(t, batches[0].Error)
+	require.Len(t, batches[0].Elements, 2)
+	queued := batches[0].Elements[1]
+	require.Equal(t, uint64(0), *queued.QueueIndex)
+	require.Equal(t, uint64(2), *queued.L1BlockNumber)
+	require.Equal(t, uint64(990), *queued.L1Timestamp)
+	require.Equal(t, testL1TxOrigin, *queued.L1TxOrigin)
+	require.Equal(t, testTarget, *queued.Target)
+	require.Equal(t, uint64(21000), *queued.GasLimit)
+	require.Equal(t, []byte{0x01, 0x02}, []byte(queued.Data))
+
+	require.Equal(t, inconsistent, batches[1].L1TxHash)
+	require.Equal(t, "batch ends at element 3, but the CTC has 4 elements", batches[1].Error)
+
+	require.Equal(t, notDecodable, batches[2].L1TxHash)
+	require.Equal(t, ctcdecoder.ErrNotAppendSequencerBatch.Error(), batches[2].Error)
+}
+
+func TestRLPWriter(t *testing.T) {
+	t.Parallel()
+
+	decoder := newDecoder(t, nil, 1)
+	txs := []*sequencer.CachedTx{newL2Tx(0)}
+	batch, err := decoder.DecodeBatch(batchCalldata(t, &sequencer.AppendSequencerBatchParams{
+		ShouldStartAtElement:  4,
+		TotalElementsToAppend: 2,
+		Contexts: []sequencer.BatchContext{
+			{NumSequencedTxs: 1, NumSubsequentQueueTxs: 1, Timestamp: 1000, BlockNumber: 100},
+		},
+		Txs: txs,
+	}, sequencer.BatchTypeLegacy), 3)
+	require.NoError(t, err)
+
+	// Queue elements must be resolved from their enqueue event.
+	var buf bytes.Buffer
+	w, err := ctcdecoder.NewWriter(ctcdecoder.FormatRLP, &buf)
+	require.NoError(t, err)
+	require.ErrorContains(t, w.WriteBatch(batch), "unresolved queue element 3")
+
+	queued := batch.Elements[1]
+	blockNumber, timestamp, gasLimit := uint64(99), uint64(990), uint64(21000)
+	queued.L1BlockNumber, queued.L1Timestamp, queued.GasLimit = &blockNumber, &timestamp, &gasLimit
+	queued.L1TxOrigin, queued.Target, queued.Data = &testL1TxOrigin, &testTarget, []byte{0x01}
+	buf.Reset()
+	require.NoError(t, w.WriteBatch(batch))
+
+	stream := rlp.NewStream(&buf, 0)
+	var elems [2]ctcdecoder.RLPElement
+	require.NoError(t, stream.Decode(&elems[0]))
+	require.NoError(t, stream.Decode(&elems[1]))
+	require.Equal(t, ctcdecoder.RLPElement{
+		Index:          4,
+		QueueOrigin:    uint8(l2types.QueueOriginSequencer),
+		L1BlockNumber:  100,
+		L1Timestamp:    1000,
+		RawTransaction: txs[0].RawTx(),
+		Data:           []byte{},
+	}, elems[0])
+	require.Equal(t, ctcdecoder.RLPElement{
+		Index:          5,
+		QueueOrigin:    uint8(l2types.QueueOriginL1ToL2),
+		QueueIndex:     3,
+		L1BlockNumber:  99,
+		L1Timestamp:    990,
+		RawTransaction: []byte{},
+		L1TxOrigin:     testL1TxOrigin,
+		Target:         testTarget,
+		GasLimit:       21000,
+		Data:           []byte{0x01},
+	}, elems[1])
+
+	// Invalid batches can't be exported.
+	batch.Error = "invalid batch"
+	buf.Reset()
+	require.Error(t, w.WriteBatch(batch))
+	require.Zero(t, buf.Len())
+
+	_, err = ctcdecoder.NewWriter("csv", &buf)
+	require.Error(t, err)
+}
+
+// TestBatchesQueueElements asserts that queue elements are resolved from the
+// enqueue events loaded before the scanned blocks, and that batches with
+// queue elements that were not enqueued in the scanned blocks are invalid.
+func TestBatchesQueueElements(t *testing.T) {
+	t.Parallel()
+
+	client := &fakeL1Client{txs: make(map[common.Hash]*types.Transaction)}
+	client.addEnqueued(t, 2, 5, 990, []byte{0x01})
+	client.addEnqueued(t, 3, 6, 1002, nil)
+	client.addBatch(t, 10, batchCalldata(t, &sequencer.AppendSequencerBatchParams{
+		ShouldStartAtElement:  0,
+		TotalElementsToAppend: 3,
+		Contexts: []sequencer.BatchContext{
+			{NumSequencedTxs: 1, NumSubsequentQueueTxs: 2, Timestamp: 1000, BlockNumber: 9},
+		},
+		Txs: []*sequencer.CachedTx{newL2Tx(0)},
+	}, sequencer.BatchTypeLegacy), 5, 2, 3)
+
+	var batches []*ctcdecoder.Batch
+	collect := func(b *ctcdecoder.Batch) error {
+		batches = append(batches, b)
+		return nil
+	}
+
+	decoder := newDecoder(t, client, 100)
+	require.NoError(t, decoder.Batches(context.Background(), 3, 10, collect))
+	require.Len(t, batches, 1)
+	require.Equal(t, "queue element 5 was not enqueued in the scanned L1 blocks", batches[0].Error)
+	require.Nil(t, batches[0].Elements[1].L1TxOrigin)
+	require.Equal(t, uint64(1002), *batches[0].Elements[2].L1Timestamp)
+
+	var buf bytes.Buffer
+	w, err := ctcdecoder.NewWriter(ctcdecoder.FormatRLP, &buf)
+	require.NoError(t, err)
+	require.Error(t, w.WriteBatch(batches[0]))
+
+	batches = nil
+	decoder = newDecoder(t, client, 100)
+	require.NoError(t, decoder.LoadEnqueued(context.Background(), 1, 2))
+	require.NoError(t, decoder.Batches(context.Background(), 3, 10, collect))
+	require.Len(t, batches, 1)
+	require.Empty(t, batches[0].Error)
+	for i, e := range batches[0].Elements[1:] {
+		require.Equal(t, uint64(5+i), *e.QueueIndex)
+		require.Equal(t, testTarget, *e.Target)
+	}
+	require.Equal(t, uint64(2), *batches[0].Elements[1].L1BlockNumber)
+	require.Equal(t, []byte{0x01}, []byte(batches[0].Elements[1].Data))
+	require.NoError(t, w.WriteBatch(batches[0]))
+
+	_, err = json.Marshal(batches[0])
+	require.NoError(t, err)
+}
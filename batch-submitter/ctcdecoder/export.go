@@ -0,0 +1,109 @@
+package ctcdecoder
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	l2types "github.com/ethereum-optimism/optimism/l2geth/core/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+const (
+	FormatJSON = "json"
+	FormatRLP  = "rlp"
+)
+
+// Writer exports decoded batches.
+type Writer interface {
+	WriteBatch(batch *Batch) error
+}
+
+// NewWriter returns a Writer of batches in the given format to w.
+func NewWriter(format string, w io.Writer) (Writer, error) {
+	switch format {
+	case FormatJSON:
+		return &jsonWriter{enc: json.NewEncoder(w)}, nil
+	case FormatRLP:
+		return &rlpWriter{w: w}, nil
+	default:
+		return nil, fmt.Errorf("unknown export format %q", format)
+	}
+}
+
+// jsonWriter writes each batch as a line of JSON.
+type jsonWriter struct {
+	enc *json.Encoder
+}
+
+func (j *jsonWriter) WriteBatch(batch *Batch) error {
+	return j.enc.Encode(batch)
+}
+
+// RLPElement is the RLP encoding of an Element. The raw transaction of queue
+// elements is empty, and their queue origin is l2types.QueueOriginL1ToL2.
+// The L1 tx origin, target, gas limit and data of sequencer elements are
+// zero.
+type RLPElement struct {
+	Index          uint64
+	QueueOrigin    uint8
+	QueueIndex     uint64
+	L1BlockNumber  uint64
+	L1Timestamp    uint64
+	RawTransaction []byte
+	L1TxOrigin     common.Address
+	Target         common.Address
+	GasLimit       uint64
+	Data           []byte
+}
+
+// rlpWriter writes the elements of each batch as a stream of RLPElements.
+// Invalid batches are rejected with an error, including batches with queue
+// elements that could not be resolved, as their elements may be missing or
+// wrong, and skipping them would leave a gap in the stream.
+type rlpWriter struct {
+	w io.Writer
+}
+
+func (r *rlpWriter) WriteBatch(batch *Batch) error {
+	if batch.Error != "" {
+		return fmt.Errorf("cannot export invalid batch of tx %s as RLP: %s",
+			batch.L1TxHash, batch.Error)
+	}
+	for _, e := range batch.Elements {
+		elem := RLPElement{
+			Index:          e.Index,
+			QueueOrigin:    uint8(l2types.QueueOriginSequencer),
+			RawTransaction: e.RawTransaction,
+		}
+		if e.QueueIndex != nil {
+			if e.L1TxOrigin == nil {
+				return fmt.Errorf("cannot export unresolved queue element %d of tx %s as RLP",
+					*e.QueueIndex, batch.L1TxHash)
+			}
+			elem.QueueOrigin = uint8(l2types.QueueOriginL1ToL2)
+			elem.QueueIndex = *e.QueueIndex
+		}
+		if e.L1BlockNumber != nil {
+			elem.L1BlockNumber = *e.L1BlockNumber
+		}
+		if e.L1Timestamp != nil {
+			elem.L1Timestamp = *e.L1Timestamp
+		}
+		if e.L1TxOrigin != nil {
+			elem.L1TxOrigin = *e.L1TxOrigin
+		}
+		if e.Target != nil {
+			elem.Target = *e.Target
+		}
+		if e.GasLimit != nil {
+			elem.GasLimit = *e.GasLimit
+		}
+		elem.Data = e.Data
+		if err := rlp.Encode(r.w, &elem); err != nil {
+			return err
+		}
+	}
+	return nil
+}
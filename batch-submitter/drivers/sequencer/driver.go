@@ -5,18 +5,15 @@ import (
 	"crypto/ecdsa"
 	"fmt"
 	"math/big"
-	"strings"
 
 	"github.com/ethereum-optimism/optimism/batch-submitter/bindings/ctc"
-	"github.com/ethereum-optimism/optimism/bss-core/drivers"
 	"github.com/ethereum-optimism/optimism/bss-core/metrics"
-	"github.com/ethereum-optimism/optimism/bss-core/txmgr"
 	l2ethclient "github.com/ethereum-optimism/optimism/l2geth/ethclient"
+	"github.com/ethereum-optimism/optimism/op-service/txmgr"
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/log"
@@ -37,18 +34,16 @@ type Config struct {
 	MaxTxSize             uint64
 	MaxPlaintextBatchSize uint64
 	CTCAddr               common.Address
-	ChainID               *big.Int
 	PrivKey               *ecdsa.PrivateKey
 	BatchType             BatchType
 }
 
 type Driver struct {
-	cfg            Config
-	ctcContract    *ctc.CanonicalTransactionChain
-	rawCtcContract *bind.BoundContract
-	walletAddr     common.Address
-	ctcABI         *abi.ABI
-	metrics        *Metrics
+	cfg         Config
+	ctcContract *ctc.CanonicalTransactionChain
+	walletAddr  common.Address
+	ctcABI      *abi.ABI
+	metrics     *Metrics
 }
 
 func NewDriver(cfg Config) (*Driver, error) {
@@ -59,32 +54,19 @@ func NewDriver(cfg Config) (*Driver, error) {
 		return nil, err
 	}
 
-	parsed, err := abi.JSON(strings.NewReader(
-		ctc.CanonicalTransactionChainABI,
-	))
-	if err != nil {
-		return nil, err
-	}
-
 	ctcABI, err := ctc.CanonicalTransactionChainMetaData.GetAbi()
 	if err != nil {
 		return nil, err
 	}
 
-	rawCtcContract := bind.NewBoundContract(
-		cfg.CTCAddr, parsed, cfg.L1Client, cfg.L1Client,
-		cfg.L1Client,
-	)
-
 	walletAddr := crypto.PubkeyToAddress(cfg.PrivKey.PublicKey)
 
 	return &Driver{
-		cfg:            cfg,
-		ctcContract:    ctcContract,
-		rawCtcContract: rawCtcContract,
-		walletAddr:     walletAddr,
-		ctcABI:         ctcABI,
-		metrics:        NewMetrics(cfg.Name),
+		cfg:         cfg,
+		ctcContract: ctcContract,
+		walletAddr:  walletAddr,
+		ctcABI:      ctcABI,
+		metrics:     NewMetrics(cfg.Name),
 	}, nil
 }
 
@@ -103,21 +85,6 @@ func (d *Driver) Metrics() metrics.Metrics {
 	return d.metrics
 }
 
-// ClearPendingTx a publishes a transaction at the next available nonce in order
-// to clear any transactions in the mempool left over from a prior running
-// instance of the batch submitter.
-func (d *Driver) ClearPendingTx(
-	ctx context.Context,
-	txMgr txmgr.TxManager,
-	l1Client *ethclient.Client,
-) error {
-
-	return drivers.ClearPendingTx(
-		d.cfg.Name, ctx, txMgr, l1Client, d.walletAddr, d.cfg.PrivKey,
-		d.cfg.ChainID,
-	)
-}
-
 // GetBatchBlockRange returns the start and end L2 block heights that need to be
 // processed. Note that the end value is *exclusive*, therefore if the returned
 // values are identical nothing needs to be processed.
@@ -152,20 +119,17 @@ func (d *Driver) GetBatchBlockRange(
 }
 
 // CraftBatchTx transforms the L2 blocks between start and end into a batch
-// transaction using the given nonce. A dummy gas price is used in the resulting
-// transaction to use for size estimation. A nil transaction is returned if the
-// transaction does not meet the minimum size requirements.
-//
-// NOTE: This method SHOULD NOT publish the resulting transaction.
+// transaction candidate. A nil candidate is returned if the transaction does
+// not meet the minimum size requirements.
 func (d *Driver) CraftBatchTx(
 	ctx context.Context,
-	start, end, nonce *big.Int,
-) (*types.Transaction, error) {
+	start, end *big.Int,
+) (*txmgr.TxCandidate, error) {
 
 	name := d.cfg.Name
 
 	log.Info(name+" crafting batch tx", "start", start, "end", end,
-		"nonce", nonce, "type", d.cfg.BatchType.String())
+		"type", d.cfg.BatchType.String())
 
 	var (
 		batchElements  []BatchElement
@@ -274,109 +238,22 @@ func (d *Driver) CraftBatchTx(
 			"final_size", len(calldata),
 			"batch_type", d.cfg.BatchType)
 
-		opts, err := bind.NewKeyedTransactorWithChainID(
-			d.cfg.PrivKey, d.cfg.ChainID,
-		)
+		// The estimated gas limits fail semi-regularly with out of gas
+		// exceptions. To remedy this we add a buffer to account for any
+		// network variability.
+		gasLimit, err := d.cfg.L1Client.EstimateGas(ctx, ethereum.CallMsg{
+			From: d.walletAddr,
+			To:   &d.cfg.CTCAddr,
+			Data: calldata,
+		})
 		if err != nil {
 			return nil, err
 		}
-		opts.Context = ctx
-		opts.Nonce = nonce
-		opts.NoSend = true
-
-		tx, err := d.rawCtcContract.RawTransact(opts, calldata)
-		switch {
-		case err == nil:
-			return tx, nil
-
-		// If the transaction failed because the backend does not support
-		// eth_maxPriorityFeePerGas, fallback to using the default constant.
-		// Currently Alchemy is the only backend provider that exposes this
-		// method, so in the event their API is unreachable we can fallback to a
-		// degraded mode of operation. This also applies to our test
-		// environments, as hardhat doesn't support the query either.
-		case drivers.IsMaxPriorityFeePerGasNotFoundError(err):
-			log.Warn(d.cfg.Name + " eth_maxPriorityFeePerGas is unsupported " +
-				"by current backend, using fallback gasTipCap")
-			opts.GasTipCap = drivers.FallbackGasTipCap
-			return d.rawCtcContract.RawTransact(opts, calldata)
-
-		default:
-			return nil, err
-		}
-	}
-}
-
-// UpdateGasPrice signs an otherwise identical txn to the one provided but with
-// updated gas prices sampled from the existing network conditions.
-//
-// NOTE: Thie method SHOULD NOT publish the resulting transaction.
-func (d *Driver) UpdateGasPrice(
-	ctx context.Context,
-	tx *types.Transaction,
-) (*types.Transaction, error) {
-
-	gasTipCap, err := d.cfg.L1Client.SuggestGasTipCap(ctx)
-	if err != nil {
-		// If the transaction failed because the backend does not support
-		// eth_maxPriorityFeePerGas, fallback to using the default constant.
-		// Currently Alchemy is the only backend provider that exposes this
-		// method, so in the event their API is unreachable we can fallback to a
-		// degraded mode of operation. This also applies to our test
-		// environments, as hardhat doesn't support the query either.
-		if !drivers.IsMaxPriorityFeePerGasNotFoundError(err) {
-			return nil, err
-		}
-
-		log.Warn(d.cfg.Name + " eth_maxPriorityFeePerGas is unsupported " +
-			"by current backend, using fallback gasTipCap")
-		gasTipCap = drivers.FallbackGasTipCap
-	}
 
-	header, err := d.cfg.L1Client.HeaderByNumber(ctx, nil)
-	if err != nil {
-		return nil, err
+		return &txmgr.TxCandidate{
+			To:       &d.cfg.CTCAddr,
+			TxData:   calldata,
+			GasLimit: 6 * gasLimit / 5, // add 20% buffer to gas limit
+		}, nil
 	}
-	gasFeeCap := txmgr.CalcGasFeeCap(header.BaseFee, gasTipCap)
-
-	// The estimated gas limits performed by RawTransact fail semi-regularly
-	// with out of gas exceptions. To remedy this we extract the internal calls
-	// to perform gas price/gas limit estimation here and add a buffer to
-	// account for any network variability.
-	gasLimit, err := d.cfg.L1Client.EstimateGas(ctx, ethereum.CallMsg{
-		From:      d.walletAddr,
-		To:        &d.cfg.CTCAddr,
-		GasPrice:  nil,
-		GasTipCap: gasTipCap,
-		GasFeeCap: gasFeeCap,
-		Value:     nil,
-		Data:      tx.Data(),
-	})
-	if err != nil {
-		return nil, err
-	}
-
-	opts, err := bind.NewKeyedTransactorWithChainID(
-		d.cfg.PrivKey, d.cfg.ChainID,
-	)
-	if err != nil {
-		return nil, err
-	}
-	opts.Context = ctx
-	opts.Nonce = new(big.Int).SetUint64(tx.Nonce())
-	opts.GasTipCap = gasTipCap
-	opts.GasFeeCap = gasFeeCap
-	opts.GasLimit = 6 * gasLimit / 5 // add 20% buffer to gas limit
-	opts.NoSend = true
-
-	return d.rawCtcContract.RawTransact(opts, tx.Data())
-}
-
-// SendTransaction injects a signed transaction into the pending pool for
-// execution.
-func (d *Driver) SendTransaction(
-	ctx context.Context,
-	tx *types.Transaction,
-) error {
-	return d.cfg.L1Client.SendTransaction(ctx, tx)
 }
@@ -149,13 +149,6 @@ var (
 		Required: true,
 		EnvVar:   prefixEnvVar("SAFE_MINIMUM_ETHER_BALANCE"),
 	}
-	ClearPendingTxsFlag = cli.BoolFlag{
-		Name: "clear-pending-txs",
-		Usage: "Whether or not to clear pending transaction in the " +
-			"mempool on startup",
-		Required: true,
-		EnvVar:   prefixEnvVar("CLEAR_PENDING_TXS"),
-	}
 
 	/* Optional Flags */
 
@@ -273,7 +266,6 @@ var requiredFlags = []cli.Flag{
 	RunTxBatchSubmitterFlag,
 	RunStateBatchSubmitterFlag,
 	SafeMinimumEtherBalanceFlag,
-	ClearPendingTxsFlag,
 }
 
 var optionalFlags = []cli.Flag{
@@ -6,9 +6,12 @@ replace github.com/ethereum-optimism/optimism/bss-core v0.0.0 => ../bss-core
 
 replace github.com/ethereum-optimism/optimism/l2geth v0.0.0 => ../l2geth
 
+replace github.com/ethereum-optimism/optimism/op-service v0.0.0 => ../op-service
+
 require (
 	github.com/ethereum-optimism/optimism/bss-core v0.0.0
 	github.com/ethereum-optimism/optimism/l2geth v0.0.0
+	github.com/ethereum-optimism/optimism/op-service v0.0.0
 	github.com/ethereum/go-ethereum v1.10.17
 	github.com/getsentry/sentry-go v0.12.0
 	github.com/prometheus/client_golang v1.11.0
@@ -1,9 +1,13 @@
 package drivers
 
 import (
+	"context"
 	"errors"
 	"math/big"
 	"strings"
+
+	"github.com/ethereum-optimism/optimism/op-service/txmgr"
+	"github.com/ethereum/go-ethereum/log"
 )
 
 var (
@@ -24,3 +28,31 @@ func IsMaxPriorityFeePerGasNotFoundError(err error) bool {
 		err.Error(), errMaxPriorityFeePerGasNotFound.Error(),
 	)
 }
+
+// MaxPriorityFeeFallbackBackend wraps the L1 backend of a tx manager, and
+// suggests the FallbackGasTipCap if the backend does not support
+// eth_maxPriorityFeePerGas. Currently Alchemy is the only backend provider
+// that exposes this method, so in the event their API is unreachable we can
+// fallback to a degraded mode of operation. This also applies to our test
+// environments, as hardhat doesn't support the query either.
+type MaxPriorityFeeFallbackBackend struct {
+	txmgr.ETHBackend
+}
+
+// SuggestGasTipCap retrieves the currently suggested gas tip cap, or the
+// FallbackGasTipCap if the backend does not support the query.
+func (b MaxPriorityFeeFallbackBackend) SuggestGasTipCap(
+	ctx context.Context,
+) (*big.Int, error) {
+
+	gasTipCap, err := b.ETHBackend.SuggestGasTipCap(ctx)
+	if err != nil {
+		if !IsMaxPriorityFeePerGasNotFoundError(err) {
+			return nil, err
+		}
+		log.Warn("eth_maxPriorityFeePerGas is unsupported by current " +
+			"backend, using fallback gasTipCap")
+		return FallbackGasTipCap, nil
+	}
+	return gasTipCap, nil
+}
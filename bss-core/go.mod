@@ -2,8 +2,11 @@ module github.com/ethereum-optimism/optimism/bss-core
 
 go 1.18
 
+replace github.com/ethereum-optimism/optimism/op-service v0.0.0 => ../op-service
+
 require (
 	github.com/decred/dcrd/hdkeychain/v3 v3.0.0
+	github.com/ethereum-optimism/optimism/op-service v0.0.0
 	github.com/ethereum/go-ethereum v1.10.17
 	github.com/getsentry/sentry-go v0.12.0
 	github.com/prometheus/client_golang v1.11.0
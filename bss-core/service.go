@@ -1,16 +1,15 @@
 package bsscore
 
 import (
-	"bytes"
 	"context"
+	"errors"
 	"math/big"
 	"sync"
 	"time"
 
 	"github.com/ethereum-optimism/optimism/bss-core/metrics"
-	"github.com/ethereum-optimism/optimism/bss-core/txmgr"
+	"github.com/ethereum-optimism/optimism/op-service/txmgr"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/log"
 )
@@ -33,11 +32,6 @@ type Driver interface {
 	// Metrics returns the subservice telemetry object.
 	Metrics() metrics.Metrics
 
-	// ClearPendingTx a publishes a transaction at the next available nonce in
-	// order to clear any transactions in the mempool left over from a prior
-	// running instance of the batch submitter.
-	ClearPendingTx(context.Context, txmgr.TxManager, *ethclient.Client) error
-
 	// GetBatchBlockRange returns the start and end L2 block heights that
 	// need to be processed. Note that the end value is *exclusive*,
 	// therefore if the returned values are identical nothing needs to be
@@ -45,38 +39,25 @@ type Driver interface {
 	GetBatchBlockRange(ctx context.Context) (*big.Int, *big.Int, error)
 
 	// CraftBatchTx transforms the L2 blocks between start and end into a batch
-	// transaction using the given nonce. A dummy gas price is used in the
-	// resulting transaction to use for size estimation. The driver may return a
-	// nil value for transaction if there is no action that needs to be
-	// performed.
-	//
-	// NOTE: This method SHOULD NOT publish the resulting transaction.
+	// transaction candidate, which is priced, signed and published by the tx
+	// manager. The driver may return a nil candidate if there is no action
+	// that needs to be performed.
 	CraftBatchTx(
 		ctx context.Context,
-		start, end, nonce *big.Int,
-	) (*types.Transaction, error)
-
-	// UpdateGasPrice signs an otherwise identical txn to the one provided but
-	// with updated gas prices sampled from the existing network conditions.
-	//
-	// NOTE: Thie method SHOULD NOT publish the resulting transaction.
-	UpdateGasPrice(
-		ctx context.Context,
-		tx *types.Transaction,
-	) (*types.Transaction, error)
-
-	// SendTransaction injects a signed transaction into the pending pool for
-	// execution.
-	SendTransaction(ctx context.Context, tx *types.Transaction) error
+		start, end *big.Int,
+	) (*txmgr.TxCandidate, error)
 }
 
 type ServiceConfig struct {
-	Context         context.Context
-	Driver          Driver
-	PollInterval    time.Duration
-	ClearPendingTx  bool
-	L1Client        *ethclient.Client
-	TxManagerConfig txmgr.Config
+	Context      context.Context
+	Driver       Driver
+	PollInterval time.Duration
+	L1Client     *ethclient.Client
+
+	// TxManager sends the batch transactions from the wallet of the driver.
+	// On start it cancels any transactions left pending by a prior running
+	// instance.
+	TxManager *txmgr.Manager
 }
 
 type Service struct {
@@ -84,7 +65,7 @@ type Service struct {
 	ctx    context.Context
 	cancel func()
 
-	txMgr   txmgr.TxManager
+	txMgr   *txmgr.Manager
 	metrics metrics.Metrics
 
 	wg sync.WaitGroup
@@ -93,20 +74,19 @@ type Service struct {
 func NewService(cfg ServiceConfig) *Service {
 	ctx, cancel := context.WithCancel(cfg.Context)
 
-	txMgr := txmgr.NewSimpleTxManager(
-		cfg.Driver.Name(), cfg.TxManagerConfig, cfg.L1Client,
-	)
-
 	return &Service{
 		cfg:     cfg,
 		ctx:     ctx,
 		cancel:  cancel,
-		txMgr:   txMgr,
+		txMgr:   cfg.TxManager,
 		metrics: cfg.Driver.Metrics(),
 	}
 }
 
 func (s *Service) Start() error {
+	if err := s.txMgr.Start(s.ctx); err != nil {
+		return err
+	}
 	s.wg.Add(1)
 	go s.eventLoop()
 	return nil
@@ -115,6 +95,7 @@ func (s *Service) Start() error {
 func (s *Service) Stop() error {
 	s.cancel()
 	s.wg.Wait()
+	s.txMgr.Close()
 	return nil
 }
 
@@ -123,19 +104,6 @@ func (s *Service) eventLoop() {
 
 	name := s.cfg.Driver.Name()
 
-	if s.cfg.ClearPendingTx {
-		const maxClearRetries = 3
-		for i := 0; i < maxClearRetries; i++ {
-			err := s.cfg.Driver.ClearPendingTx(s.ctx, s.txMgr, s.cfg.L1Client)
-			if err == nil {
-				break
-			} else if i < maxClearRetries-1 {
-				continue
-			}
-			log.Crit("Unable to confirm a clearing transaction", "err", err)
-		}
-	}
-
 	ticker := time.NewTicker(s.cfg.PollInterval)
 	defer ticker.Stop()
 
@@ -170,55 +138,44 @@ func (s *Service) eventLoop() {
 			}
 			log.Info(name+" block range", "start", start, "end", end)
 
-			// Query for the submitter's current nonce.
-			nonce64, err := s.cfg.L1Client.NonceAt(
-				s.ctx, s.cfg.Driver.WalletAddr(), nil,
-			)
-			if err != nil {
-				log.Error(name+" unable to get current nonce",
-					"err", err)
-				continue
-			}
-			nonce := new(big.Int).SetUint64(nonce64)
-
 			batchTxBuildStart := time.Now()
-			tx, err := s.cfg.Driver.CraftBatchTx(
-				s.ctx, start, end, nonce,
-			)
+			candidate, err := s.cfg.Driver.CraftBatchTx(s.ctx, start, end)
 			if err != nil {
 				log.Error(name+" unable to craft batch tx",
 					"err", err)
 				continue
-			} else if tx == nil {
+			} else if candidate == nil {
 				continue
 			}
 			batchTxBuildTime := time.Since(batchTxBuildStart) / time.Millisecond
 			s.metrics.BatchTxBuildTimeMs().Set(float64(batchTxBuildTime))
 
 			// Record the size of the batch transaction.
-			var txBuf bytes.Buffer
-			if err := tx.EncodeRLP(&txBuf); err != nil {
-				log.Error(name+" unable to encode batch tx", "err", err)
+			s.metrics.BatchSizeBytes().Observe(float64(len(candidate.TxData)))
+
+			// The tx manager assigns the nonce and gas price of the batch
+			// transaction, and replaces it at a bumped gas price until one of
+			// the submitted transactions confirms.
+			batchConfirmationStart := time.Now()
+			q, err := s.txMgr.Queue(s.ctx, *candidate)
+			if err != nil {
+				log.Error(name+" unable to queue batch tx", "err", err)
+				s.metrics.FailedSubmissions().Inc()
 				continue
 			}
-			s.metrics.BatchSizeBytes().Observe(float64(len(txBuf.Bytes())))
-
-			// Construct the transaction submission clousure that will attempt
-			// to send the next transaction at the given nonce and gas price.
-			updateGasPrice := func(ctx context.Context) (*types.Transaction, error) {
-				log.Info(name+" updating batch tx gas price", "start", start,
-					"end", end, "nonce", nonce)
-
-				return s.cfg.Driver.UpdateGasPrice(ctx, tx)
+			log.Info(name+" queued batch tx", "start", start, "end", end,
+				"nonce", q.Nonce())
+
+			// A transaction that cannot be replaced within the fee limits is
+			// still pending, and is waited on until it confirms, to not submit
+			// its range again.
+			receipt, err := q.Wait(s.ctx)
+			for errors.Is(err, txmgr.ErrReplacementLimited) || errors.Is(err, txmgr.ErrBudgetExhausted) {
+				log.Warn(name+" batch tx replacement limited, waiting for it to confirm",
+					"nonce", q.Nonce(), "err", err)
+				receipt, err = q.Wait(s.ctx)
 			}
 
-			// Wait until one of our submitted transactions confirms. If no
-			// receipt is received it's likely our gas price was too low.
-			batchConfirmationStart := time.Now()
-			receipt, err := s.txMgr.Send(
-				s.ctx, updateGasPrice, s.cfg.Driver.SendTransaction,
-			)
-
 			// Record the confirmation time and gas used if we receive a
 			// receipt, as this indicates the transaction confirmed. We record
 			// these metrics here as the transaction may have reverted, and will
@@ -232,7 +189,7 @@ func (s *Service) eventLoop() {
 
 			if err != nil {
 				log.Error(name+" unable to publish batch tx",
-					"err", err)
+					"nonce", q.Nonce(), "err", err)
 				s.metrics.FailedSubmissions().Inc()
 				continue
 			}
@@ -11,6 +11,11 @@ var (
 		Usage:  "L1 HTTP Endpoint",
 		EnvVar: "GAS_PRICE_ORACLE_ETHEREUM_HTTP_URL",
 	}
+	EthereumHttpUrlsFlag = cli.StringSliceFlag{
+		Name:   "ethereum-http-urls",
+		Usage:  "L1 HTTP Endpoints to read the L1 base fee from, defaults to the ethereum-http-url",
+		EnvVar: "GAS_PRICE_ORACLE_ETHEREUM_HTTP_URLS",
+	}
 	LayerTwoHttpUrlFlag = cli.StringFlag{
 		Name:   "layer-two-http-url",
 		Value:  "http://127.0.0.1:9545",
@@ -53,6 +58,11 @@ var (
 		Usage:  "Enable updating the L2 gas price",
 		EnvVar: "GAS_PRICE_ORACLE_ENABLE_L2_GAS_PRICE",
 	}
+	ShadowModeFlag = cli.BoolFlag{
+		Name:   "shadow-mode",
+		Usage:  "Run the update loops without sending transactions, the private key is optional",
+		EnvVar: "GAS_PRICE_ORACLE_SHADOW_MODE",
+	}
 	LogLevelFlag = cli.IntFlag{
 		Name:   "loglevel",
 		Value:  3,
@@ -101,17 +111,98 @@ var (
 		Usage:  "only update when the L1 base fee changes by more than this factor",
 		EnvVar: "GAS_PRICE_ORACLE_L1_BASE_FEE_SIGNIFICANT_FACTOR",
 	}
+	L1BaseFeeMinSourcesFlag = cli.IntFlag{
+		Name:   "l1-base-fee-min-sources",
+		Usage:  "min number of L1 endpoints agreeing on the L1 base fee, defaults to a majority",
+		EnvVar: "GAS_PRICE_ORACLE_L1_BASE_FEE_MIN_SOURCES",
+	}
+	L1BaseFeeMaxDeviationFlag = cli.Float64Flag{
+		Name:   "l1-base-fee-max-deviation",
+		Value:  0.25,
+		Usage:  "ignore L1 endpoints whose base fee differs from the median by more than this factor",
+		EnvVar: "GAS_PRICE_ORACLE_L1_BASE_FEE_MAX_DEVIATION",
+	}
+	L1BaseFeeMaxHeadLagFlag = cli.Uint64Flag{
+		Name:   "l1-base-fee-max-head-lag",
+		Value:  2,
+		Usage:  "ignore L1 endpoints whose tip is more than this number of blocks behind the median tip",
+		EnvVar: "GAS_PRICE_ORACLE_L1_BASE_FEE_MAX_HEAD_LAG",
+	}
+	L1BaseFeeMaxHeadAgeSecondsFlag = cli.Uint64Flag{
+		Name:   "l1-base-fee-max-head-age-seconds",
+		Usage:  "ignore L1 endpoints whose tip is older than this, 0 disables the check",
+		EnvVar: "GAS_PRICE_ORACLE_L1_BASE_FEE_MAX_HEAD_AGE_SECONDS",
+	}
 	L2GasPriceSignificanceFactorFlag = cli.Float64Flag{
 		Name:   "significant-factor",
 		Value:  0.05,
 		Usage:  "only update when the gas price changes by more than this factor",
 		EnvVar: "GAS_PRICE_ORACLE_SIGNIFICANT_FACTOR",
 	}
+	L2GasPriceControllerFlag = cli.StringFlag{
+		Name:   "l2-gas-price-controller",
+		Value:  "proportional",
+		Usage:  "controller of the L2 gas price: proportional, eip1559 or pid",
+		EnvVar: "GAS_PRICE_ORACLE_L2_GAS_PRICE_CONTROLLER",
+	}
+	EIP1559ElasticityFlag = cli.Uint64Flag{
+		Name:   "eip1559-elasticity",
+		Value:  2,
+		Usage:  "ratio of the average block gas limit to the gas target of the eip1559 controller",
+		EnvVar: "GAS_PRICE_ORACLE_EIP1559_ELASTICITY",
+	}
+	EIP1559ChangeDenominatorFlag = cli.Uint64Flag{
+		Name:   "eip1559-change-denominator",
+		Value:  8,
+		Usage:  "inverse of the max change of gas price per block of the eip1559 controller",
+		EnvVar: "GAS_PRICE_ORACLE_EIP1559_CHANGE_DENOMINATOR",
+	}
+	PIDKpFlag = cli.Float64Flag{
+		Name:   "pid-kp",
+		Value:  1,
+		Usage:  "proportional gain of the pid controller",
+		EnvVar: "GAS_PRICE_ORACLE_PID_KP",
+	}
+	PIDKiFlag = cli.Float64Flag{
+		Name:   "pid-ki",
+		Value:  0,
+		Usage:  "integral gain of the pid controller",
+		EnvVar: "GAS_PRICE_ORACLE_PID_KI",
+	}
+	PIDKdFlag = cli.Float64Flag{
+		Name:   "pid-kd",
+		Value:  0,
+		Usage:  "derivative gain of the pid controller",
+		EnvVar: "GAS_PRICE_ORACLE_PID_KD",
+	}
+	PIDIntegralLimitFlag = cli.Float64Flag{
+		Name:   "pid-integral-limit",
+		Value:  1,
+		Usage:  "max absolute accumulated error of the pid controller",
+		EnvVar: "GAS_PRICE_ORACLE_PID_INTEGRAL_LIMIT",
+	}
 	WaitForReceiptFlag = cli.BoolFlag{
 		Name:   "wait-for-receipt",
 		Usage:  "wait for receipts when sending transactions",
 		EnvVar: "GAS_PRICE_ORACLE_WAIT_FOR_RECEIPT",
 	}
+	StatusEnabledFlag = cli.BoolFlag{
+		Name:   "status",
+		Usage:  "Enable the HTTP endpoint reporting computed and on-chain prices",
+		EnvVar: "GAS_PRICE_ORACLE_STATUS_ENABLE",
+	}
+	StatusHTTPFlag = cli.StringFlag{
+		Name:   "status.addr",
+		Usage:  "Status HTTP server listening interface",
+		Value:  "127.0.0.1",
+		EnvVar: "GAS_PRICE_ORACLE_STATUS_HTTP",
+	}
+	StatusPortFlag = cli.IntFlag{
+		Name:   "status.port",
+		Usage:  "Status HTTP server listening port",
+		Value:  6061,
+		EnvVar: "GAS_PRICE_ORACLE_STATUS_PORT",
+	}
 	MetricsEnabledFlag = cli.BoolFlag{
 		Name:   "metrics",
 		Usage:  "Enable metrics collection and reporting",
@@ -162,6 +253,7 @@ var (
 
 var Flags = []cli.Flag{
 	EthereumHttpUrlFlag,
+	EthereumHttpUrlsFlag,
 	LayerTwoHttpUrlFlag,
 	L1ChainIDFlag,
 	L2ChainIDFlag,
@@ -176,10 +268,25 @@ var Flags = []cli.Flag{
 	AverageBlockGasLimitPerEpochFlag,
 	EpochLengthSecondsFlag,
 	L1BaseFeeEpochLengthSecondsFlag,
+	L1BaseFeeMinSourcesFlag,
+	L1BaseFeeMaxDeviationFlag,
+	L1BaseFeeMaxHeadLagFlag,
+	L1BaseFeeMaxHeadAgeSecondsFlag,
 	L2GasPriceSignificanceFactorFlag,
+	L2GasPriceControllerFlag,
+	EIP1559ElasticityFlag,
+	EIP1559ChangeDenominatorFlag,
+	PIDKpFlag,
+	PIDKiFlag,
+	PIDKdFlag,
+	PIDIntegralLimitFlag,
 	WaitForReceiptFlag,
 	EnableL1BaseFeeFlag,
 	EnableL2GasPriceFlag,
+	ShadowModeFlag,
+	StatusEnabledFlag,
+	StatusHTTPFlag,
+	StatusPortFlag,
 	MetricsEnabledFlag,
 	MetricsHTTPFlag,
 	MetricsPortFlag,
@@ -189,3 +296,25 @@ var Flags = []cli.Flag{
 	MetricsInfluxDBUsernameFlag,
 	MetricsInfluxDBPasswordFlag,
 }
+
+var (
+	StartBlockNumberFlag = cli.Uint64Flag{
+		Name:  "start-block-number",
+		Usage: "first L2 block to replay",
+	}
+	EndBlockNumberFlag = cli.Uint64Flag{
+		Name:  "end-block-number",
+		Usage: "last L2 block to replay",
+	}
+	InitialGasPriceFlag = cli.Uint64Flag{
+		Name:  "initial-gas-price",
+		Usage: "gas price at the start block, defaults to the floor price",
+	}
+)
+
+// SimulateFlags are the flags of the simulate command
+var SimulateFlags = []cli.Flag{
+	StartBlockNumberFlag,
+	EndBlockNumberFlag,
+	InitialGasPriceFlag,
+}
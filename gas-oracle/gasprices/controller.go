@@ -0,0 +1,57 @@
+package gasprices
+
+import (
+	"fmt"
+)
+
+// Names of the available gas price controllers
+const (
+	ProportionalController = "proportional"
+	EIP1559Controller      = "eip1559"
+	PIDController          = "pid"
+)
+
+// Controllers lists the names of the available gas price controllers
+var Controllers = []string{
+	ProportionalController,
+	EIP1559Controller,
+	PIDController,
+}
+
+// Epoch holds the gas used by each block of an epoch
+type Epoch struct {
+	GasUsedByBlock []uint64
+	LengthSeconds  uint64
+}
+
+// TotalGasUsed returns the amount of gas used by all blocks of the epoch
+func (e Epoch) TotalGasUsed() uint64 {
+	total := uint64(0)
+	for _, gasUsed := range e.GasUsedByBlock {
+		total += gasUsed
+	}
+	return total
+}
+
+// AvgGasPerSecond returns the average gas per second over the epoch
+func (e Epoch) AvgGasPerSecond() float64 {
+	return float64(e.TotalGasUsed()) / float64(e.LengthSeconds)
+}
+
+// GasPriceController computes the L2 gas price of the next epoch based on
+// the gas used during the last one
+type GasPriceController interface {
+	// CompleteEpochBlocks ends the given epoch and updates the current gas
+	// price for the next epoch
+	CompleteEpochBlocks(epoch Epoch) (uint64, error)
+	// CurPrice returns the current gas price
+	CurPrice() uint64
+}
+
+// checkEpoch ensures that an epoch can be used to compute a gas price
+func checkEpoch(epoch Epoch) error {
+	if epoch.LengthSeconds < 1 {
+		return fmt.Errorf("epoch length cannot be less than 1 second, got %d", epoch.LengthSeconds)
+	}
+	return nil
+}
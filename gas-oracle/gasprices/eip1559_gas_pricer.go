@@ -0,0 +1,103 @@
+package gasprices
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// EIP1559GasPricer updates the gas price after every block of an epoch like
+// the EIP-1559 base fee. The price increases when a block uses more than the
+// gas target, which is the block gas limit divided by the elasticity, and
+// decreases when it uses less. Each block moves the price by at most
+// 1/changeDenominator.
+type EIP1559GasPricer struct {
+	curPrice          uint64
+	floorPrice        uint64
+	gasTarget         uint64
+	changeDenominator uint64
+}
+
+// NewEIP1559GasPricer creates an EIP1559GasPricer and checks its config
+// beforehand
+func NewEIP1559GasPricer(curPrice, floorPrice, blockGasLimit, elasticity, changeDenominator uint64) (*EIP1559GasPricer, error) {
+	if floorPrice < 1 {
+		return nil, errors.New("floorPrice must be greater than or equal to 1")
+	}
+	if elasticity < 1 {
+		return nil, errors.New("elasticity must be greater than or equal to 1")
+	}
+	if blockGasLimit < elasticity {
+		return nil, errors.New("blockGasLimit must be greater than or equal to elasticity")
+	}
+	if changeDenominator < 1 {
+		return nil, errors.New("changeDenominator must be greater than or equal to 1")
+	}
+	return &EIP1559GasPricer{
+		curPrice:          max(curPrice, floorPrice),
+		floorPrice:        floorPrice,
+		gasTarget:         blockGasLimit / elasticity,
+		changeDenominator: changeDenominator,
+	}, nil
+}
+
+// CalcNextBlockGasPrice calculates the gas price following a block that used
+// the given amount of gas
+func (p *EIP1559GasPricer) CalcNextBlockGasPrice(curPrice, gasUsed uint64) uint64 {
+	if gasUsed == p.gasTarget {
+		return curPrice
+	}
+
+	price := new(big.Int).SetUint64(curPrice)
+	target := new(big.Int).SetUint64(p.gasTarget)
+	denominator := new(big.Int).SetUint64(p.changeDenominator)
+
+	if gasUsed > p.gasTarget {
+		// delta = max(price * (gasUsed - target) / target / denominator, 1)
+		delta := new(big.Int).SetUint64(gasUsed - p.gasTarget)
+		delta.Mul(delta, price)
+		delta.Div(delta, target)
+		delta.Div(delta, denominator)
+		if delta.Sign() == 0 {
+			delta.SetUint64(1)
+		}
+		price.Add(price, delta)
+		if !price.IsUint64() {
+			return ^uint64(0)
+		}
+		return price.Uint64()
+	}
+
+	// delta = price * (target - gasUsed) / target / denominator
+	delta := new(big.Int).SetUint64(p.gasTarget - gasUsed)
+	delta.Mul(delta, price)
+	delta.Div(delta, target)
+	delta.Div(delta, denominator)
+	price.Sub(price, delta)
+	return max(p.floorPrice, price.Uint64())
+}
+
+// CompleteEpochBlocks applies the gas used by each block of the epoch to the
+// current gas price
+func (p *EIP1559GasPricer) CompleteEpochBlocks(epoch Epoch) (uint64, error) {
+	if err := checkEpoch(epoch); err != nil {
+		return 0, err
+	}
+
+	price := p.curPrice
+	for _, gasUsed := range epoch.GasUsedByBlock {
+		price = p.CalcNextBlockGasPrice(price, gasUsed)
+	}
+
+	log.Debug("Calculated next epoch gas price", "blocks", len(epoch.GasUsedByBlock),
+		"gasTarget", p.gasTarget, "previous", p.curPrice, "result", price)
+
+	p.curPrice = price
+	return price, nil
+}
+
+// CurPrice returns the current gas price
+func (p *EIP1559GasPricer) CurPrice() uint64 {
+	return p.curPrice
+}
@@ -0,0 +1,108 @@
+package gasprices
+
+import (
+	"testing"
+)
+
+func TestCalcNextBlockGasPrice(t *testing.T) {
+	// Target 5_000_000 gas per block, with at most 12.5% change per block
+	gp, err := NewEIP1559GasPricer(1000, 100, 10_000_000, 2, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tcs := []struct {
+		name     string
+		curPrice uint64
+		gasUsed  uint64
+		expected uint64
+	}{
+		{
+			name:     "No change expected when at target",
+			curPrice: 1000,
+			gasUsed:  5_000_000,
+			expected: 1000,
+		},
+		{
+			name:     "Full block increases the price by 1/8",
+			curPrice: 1000,
+			gasUsed:  10_000_000,
+			expected: 1125,
+		},
+		{
+			name:     "Empty block decreases the price by 1/8",
+			curPrice: 1000,
+			gasUsed:  0,
+			expected: 875,
+		},
+		{
+			name:     "Half way above target increases the price by 1/16",
+			curPrice: 1000,
+			gasUsed:  7_500_000,
+			expected: 1062,
+		},
+		{
+			name:     "Price increases by at least 1 above target",
+			curPrice: 100,
+			gasUsed:  5_000_001,
+			expected: 101,
+		},
+		{
+			name:     "Price does not go below the floor",
+			curPrice: 110,
+			gasUsed:  0,
+			expected: 100,
+		},
+	}
+	for _, tc := range tcs {
+		got := gp.CalcNextBlockGasPrice(tc.curPrice, tc.gasUsed)
+		if got != tc.expected {
+			t.Fatalf("failed on test: %s. Got: %d, expected: %d", tc.name, got, tc.expected)
+		}
+	}
+}
+
+func TestEIP1559GasPricerAppliesEveryBlock(t *testing.T) {
+	gp, err := NewEIP1559GasPricer(1000, 1, 10_000_000, 2, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Two full blocks compound, whereas their average would be a single
+	// full block over a 2 block epoch
+	price, err := gp.CompleteEpochBlocks(Epoch{
+		GasUsedByBlock: []uint64{10_000_000, 10_000_000},
+		LengthSeconds:  10,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if price != 1265 || gp.CurPrice() != 1265 {
+		t.Fatalf("gp.curPrice not updated correctly. Got: %d, expected: %d", gp.CurPrice(), 1265)
+	}
+
+	// A burst followed by an empty block nearly cancels out
+	price, err = gp.CompleteEpochBlocks(Epoch{
+		GasUsedByBlock: []uint64{10_000_000, 0},
+		LengthSeconds:  10,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if price != 1246 {
+		t.Fatalf("gp.curPrice not updated correctly. Got: %d, expected: %d", price, 1246)
+	}
+}
+
+func TestNewEIP1559GasPricerChecksConfig(t *testing.T) {
+	if _, err := NewEIP1559GasPricer(1, 0, 10, 2, 8); err == nil {
+		t.Fatal("Expected a zero floor price to be rejected")
+	}
+	if _, err := NewEIP1559GasPricer(1, 1, 10, 0, 8); err == nil {
+		t.Fatal("Expected a zero elasticity to be rejected")
+	}
+	if _, err := NewEIP1559GasPricer(1, 1, 1, 2, 8); err == nil {
+		t.Fatal("Expected a zero gas target to be rejected")
+	}
+	if _, err := NewEIP1559GasPricer(1, 1, 10, 2, 0); err == nil {
+		t.Fatal("Expected a zero change denominator to be rejected")
+	}
+}
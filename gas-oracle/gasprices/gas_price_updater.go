@@ -14,7 +14,7 @@ type GetGasUsedByBlockFn func(*big.Int) (uint64, error)
 
 type GasPriceUpdater struct {
 	mu                     *sync.RWMutex
-	gasPricer              *GasPricer
+	gasPricer              GasPriceController
 	epochStartBlockNumber  uint64
 	averageBlockGasLimit   uint64
 	epochLengthSeconds     uint64
@@ -24,7 +24,7 @@ type GasPriceUpdater struct {
 }
 
 func NewGasPriceUpdater(
-	gasPricer *GasPricer,
+	gasPricer GasPriceController,
 	epochStartBlockNumber uint64,
 	averageBlockGasLimit uint64,
 	epochLengthSeconds uint64,
@@ -67,26 +67,27 @@ func (g *GasPriceUpdater) UpdateGasPrice() error {
 		return nil
 	}
 
-	// Accumulate the amount of gas that has been used in the epoch
-	totalGasUsed := uint64(0)
+	// Collect the amount of gas that each block of the epoch has used
+	epoch := Epoch{
+		GasUsedByBlock: make([]uint64, 0, latestBlockNumber-g.epochStartBlockNumber),
+		LengthSeconds:  g.epochLengthSeconds,
+	}
 	for i := g.epochStartBlockNumber + 1; i <= latestBlockNumber; i++ {
 		gasUsed, err := g.getGasUsedByBlockFn(new(big.Int).SetUint64(i))
-		log.Trace("fetching gas used", "height", i, "gas-used", gasUsed, "total-gas", totalGasUsed)
+		log.Trace("fetching gas used", "height", i, "gas-used", gasUsed)
 		if err != nil {
 			return err
 		}
-		totalGasUsed += gasUsed
+		epoch.GasUsedByBlock = append(epoch.GasUsedByBlock, gasUsed)
 	}
 
-	averageGasPerSecond := float64(totalGasUsed) / float64(g.epochLengthSeconds)
-
-	log.Debug("UpdateGasPrice", "average-gas-per-second", averageGasPerSecond, "current-price", g.gasPricer.curPrice)
-	_, err = g.gasPricer.CompleteEpoch(averageGasPerSecond)
+	log.Debug("UpdateGasPrice", "average-gas-per-second", epoch.AvgGasPerSecond(), "current-price", g.gasPricer.CurPrice())
+	_, err = g.gasPricer.CompleteEpochBlocks(epoch)
 	if err != nil {
 		return err
 	}
 	g.epochStartBlockNumber = latestBlockNumber
-	err = g.updateL2GasPriceFn(g.gasPricer.curPrice)
+	err = g.updateL2GasPriceFn(g.gasPricer.CurPrice())
 	if err != nil {
 		return err
 	}
@@ -96,5 +97,5 @@ func (g *GasPriceUpdater) UpdateGasPrice() error {
 func (g *GasPriceUpdater) GetGasPrice() uint64 {
 	g.mu.RLock()
 	defer g.mu.RUnlock()
-	return g.gasPricer.curPrice
+	return g.gasPricer.CurPrice()
 }
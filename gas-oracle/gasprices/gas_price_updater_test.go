@@ -116,7 +116,7 @@ func TestUsageOfGasPriceUpdater(t *testing.T) {
 			repeatCount: 3,
 			// Make sure the gas price is increasing
 			postHook: func(prevGasPrice uint64, gasPriceUpdater *GasPriceUpdater) {
-				curPrice := gasPriceUpdater.gasPricer.curPrice
+				curPrice := gasPriceUpdater.gasPricer.(*GasPricer).curPrice
 				if prevGasPrice >= curPrice {
 					t.Fatalf("Expected gas price to increase. Got %d, was %d", curPrice, prevGasPrice)
 				}
@@ -132,13 +132,13 @@ func TestUsageOfGasPriceUpdater(t *testing.T) {
 			numBlocks:   3,
 			repeatCount: 0,
 			postHook: func(prevGasPrice uint64, gasPriceUpdater *GasPriceUpdater) {
-				curPrice := gasPriceUpdater.gasPricer.curPrice
+				curPrice := gasPriceUpdater.gasPricer.(*GasPricer).curPrice
 				if prevGasPrice != curPrice {
 					t.Fatalf("Expected gas price to stablize. Got %d, was %d", curPrice, prevGasPrice)
 				}
 
-				targetGps := gasPriceUpdater.gasPricer.getTargetGasPerSecond()
-				averageGps := gasPriceUpdater.gasPricer.avgGasPerSecondLastEpoch
+				targetGps := gasPriceUpdater.gasPricer.(*GasPricer).getTargetGasPerSecond()
+				averageGps := gasPriceUpdater.gasPricer.(*GasPricer).avgGasPerSecondLastEpoch
 				if targetGps != averageGps {
 					t.Fatalf("Average gas/second (%f) did not converge to target (%f)",
 						averageGps, targetGps)
@@ -150,15 +150,15 @@ func TestUsageOfGasPriceUpdater(t *testing.T) {
 			numBlocks:   1,
 			repeatCount: 5,
 			postHook: func(prevGasPrice uint64, gasPriceUpdater *GasPriceUpdater) {
-				curPrice := gasPriceUpdater.gasPricer.curPrice
-				if prevGasPrice <= curPrice && curPrice != gasPriceUpdater.gasPricer.floorPrice {
+				curPrice := gasPriceUpdater.gasPricer.(*GasPricer).curPrice
+				if prevGasPrice <= curPrice && curPrice != gasPriceUpdater.gasPricer.(*GasPricer).floorPrice {
 					t.Fatalf("Expected gas price either reduce or be at the floor.")
 				}
 			},
 		},
 	}
 	loop := func(epoch MockEpoch) {
-		prevGasPrice := gasUpdater.gasPricer.curPrice
+		prevGasPrice := gasUpdater.gasPricer.CurPrice()
 		incrementCurrentBlock(epoch.numBlocks)
 		err = gasUpdater.UpdateGasPrice()
 		if err != nil {
@@ -85,6 +85,20 @@ func (p *GasPricer) CompleteEpoch(avgGasPerSecondLastEpoch float64) (uint64, err
 	return gp, nil
 }
 
+// CompleteEpochBlocks ends the epoch using the average gas per second over
+// the epoch
+func (p *GasPricer) CompleteEpochBlocks(epoch Epoch) (uint64, error) {
+	if err := checkEpoch(epoch); err != nil {
+		return 0, err
+	}
+	return p.CompleteEpoch(epoch.AvgGasPerSecond())
+}
+
+// CurPrice returns the current gas price
+func (p *GasPricer) CurPrice() uint64 {
+	return p.curPrice
+}
+
 func max(a, b uint64) uint64 {
 	if a >= b {
 		return a
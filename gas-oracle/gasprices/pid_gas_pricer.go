@@ -0,0 +1,106 @@
+package gasprices
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// PIDGains configures the terms of a PIDGasPricer. The error of an epoch is
+// the relative difference between the average gas per second and the target.
+type PIDGains struct {
+	// Kp weighs the error of the last epoch
+	Kp float64
+	// Ki weighs the sum of the errors of all epochs
+	Ki float64
+	// Kd weighs the change of the error since the previous epoch
+	Kd float64
+	// IntegralLimit bounds the absolute sum of the errors, so that a long
+	// period above or below target does not keep moving the price once
+	// demand returns to the target
+	IntegralLimit float64
+}
+
+// PIDGasPricer moves the gas price by a percentage computed by a PID
+// controller, clamped by maxPercentChangePerEpoch. The GasPricer is the
+// special case where Kp is 1 and Ki and Kd are 0.
+type PIDGasPricer struct {
+	curPrice              uint64
+	floorPrice            uint64
+	getTargetGasPerSecond GetTargetGasPerSecond
+	gains                 PIDGains
+	maxChangePerEpoch     float64
+	integral              float64
+	lastError             float64
+	hasLastError          bool
+}
+
+// NewPIDGasPricer creates a PIDGasPricer and checks its config beforehand
+func NewPIDGasPricer(curPrice, floorPrice uint64, getTargetGasPerSecond GetTargetGasPerSecond, gains PIDGains, maxPercentChangePerEpoch float64) (*PIDGasPricer, error) {
+	if floorPrice < 1 {
+		return nil, errors.New("floorPrice must be greater than or equal to 1")
+	}
+	// A change of 100% or more would let a negative output move the price to
+	// zero or below
+	if maxPercentChangePerEpoch <= 0 || maxPercentChangePerEpoch >= 1 {
+		return nil, errors.New("maxPercentChangePerEpoch must be between (0,1)")
+	}
+	if gains.IntegralLimit < 0 {
+		return nil, errors.New("integralLimit cannot be negative")
+	}
+	return &PIDGasPricer{
+		curPrice:              max(curPrice, floorPrice),
+		floorPrice:            floorPrice,
+		getTargetGasPerSecond: getTargetGasPerSecond,
+		gains:                 gains,
+		maxChangePerEpoch:     maxPercentChangePerEpoch,
+	}, nil
+}
+
+// CompleteEpochBlocks ends the epoch and updates the current gas price for
+// the next epoch
+func (p *PIDGasPricer) CompleteEpochBlocks(epoch Epoch) (uint64, error) {
+	if err := checkEpoch(epoch); err != nil {
+		return 0, err
+	}
+	targetGasPerSecond := p.getTargetGasPerSecond()
+	if targetGasPerSecond < 1 {
+		return 0, fmt.Errorf("gasPerSecond cannot be less than 1, got %f", targetGasPerSecond)
+	}
+
+	avgGasPerSecond := epoch.AvgGasPerSecond()
+	epochError := avgGasPerSecond/targetGasPerSecond - 1
+
+	integral := p.integral + epochError
+	integral = math.Max(-p.gains.IntegralLimit, math.Min(p.gains.IntegralLimit, integral))
+
+	derivative := 0.0
+	if p.hasLastError {
+		derivative = epochError - p.lastError
+	}
+
+	output := p.gains.Kp*epochError + p.gains.Ki*integral + p.gains.Kd*derivative
+	proportionToChangeBy := 1 + math.Max(-p.maxChangePerEpoch, math.Min(p.maxChangePerEpoch, output))
+
+	// Clamp at the floor before converting, as converting a negative float
+	// to uint64 is undefined
+	updated := float64(max(1, p.curPrice)) * proportionToChangeBy
+	updated = math.Max(float64(p.floorPrice), updated)
+	result := uint64(math.Ceil(updated))
+
+	log.Debug("Calculated next epoch gas price", "error", epochError, "integral", integral,
+		"derivative", derivative, "proportionToChangeBy", proportionToChangeBy, "result", result)
+
+	p.curPrice = result
+	p.integral = integral
+	p.lastError = epochError
+	p.hasLastError = true
+	return result, nil
+}
+
+// CurPrice returns the current gas price
+func (p *PIDGasPricer) CurPrice() uint64 {
+	return p.curPrice
+}
@@ -0,0 +1,132 @@
+package gasprices
+
+import (
+	"testing"
+)
+
+// epochWithGasPerSecond returns a single block epoch of 10 seconds with the
+// given average gas per second
+func epochWithGasPerSecond(gasPerSecond uint64) Epoch {
+	return Epoch{
+		GasUsedByBlock: []uint64{gasPerSecond * 10},
+		LengthSeconds:  10,
+	}
+}
+
+func TestPIDGasPricerProportionalOnly(t *testing.T) {
+	// With only a proportional gain of 1, the PID controller behaves like
+	// the GasPricer
+	pid, err := NewPIDGasPricer(100, 1, returnConstFn(10), PIDGains{Kp: 1}, 0.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gp, err := NewGasPricer(100, 1, returnConstFn(10), 0.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, gps := range []uint64{10, 1, 5, 100, 20, 15, 10} {
+		expected, err := gp.CompleteEpochBlocks(epochWithGasPerSecond(gps))
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := pid.CompleteEpochBlocks(epochWithGasPerSecond(gps))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != expected {
+			t.Fatalf("PID controller diverged from GasPricer. Got: %d, expected: %d", got, expected)
+		}
+	}
+}
+
+func TestPIDGasPricerIntegral(t *testing.T) {
+	pid, err := NewPIDGasPricer(100, 1, returnConstFn(10), PIDGains{Ki: 0.25, IntegralLimit: 1.5}, 0.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Twice the target accumulates an error of 1 each epoch, bounded to 1.5
+	expected := []uint64{125, 172, 237}
+	for i, want := range expected {
+		got, err := pid.CompleteEpochBlocks(epochWithGasPerSecond(20))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Fatalf("epoch %d: Got: %d, expected: %d", i, got, want)
+		}
+	}
+
+	// Back at target, the price keeps moving with the accumulated error
+	got, err := pid.CompleteEpochBlocks(epochWithGasPerSecond(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != 326 {
+		t.Fatalf("Got: %d, expected: %d", got, 326)
+	}
+}
+
+func TestPIDGasPricerDerivative(t *testing.T) {
+	pid, err := NewPIDGasPricer(100, 1, returnConstFn(10), PIDGains{Kd: 0.5}, 0.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// No derivative on the first epoch
+	got, err := pid.CompleteEpochBlocks(epochWithGasPerSecond(20))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != 100 {
+		t.Fatalf("Got: %d, expected: %d", got, 100)
+	}
+	// Error drops from 1 to 0, so the price is reduced by half
+	got, err = pid.CompleteEpochBlocks(epochWithGasPerSecond(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != 50 {
+		t.Fatalf("Got: %d, expected: %d", got, 50)
+	}
+}
+
+func TestPIDGasPricerAtFloor(t *testing.T) {
+	pid, err := NewPIDGasPricer(100, 100, returnConstFn(10), PIDGains{Kp: 1}, 0.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := pid.CompleteEpochBlocks(epochWithGasPerSecond(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != 100 {
+		t.Fatalf("Expected the price to stay at the floor. Got: %d", got)
+	}
+}
+
+func TestPIDGasPricerLargeNegativeOutput(t *testing.T) {
+	// Far below target, the output of a large gain is clamped to the max
+	// change per epoch, and the price never drops below the floor
+	pid, err := NewPIDGasPricer(100, 30, returnConstFn(10), PIDGains{Kp: 1000}, 0.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []uint64{50, 30, 30}
+	for i, want := range expected {
+		got, err := pid.CompleteEpochBlocks(epochWithGasPerSecond(0))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Fatalf("epoch %d: Got: %d, expected: %d", i, got, want)
+		}
+	}
+}
+
+func TestNewPIDGasPricerMaxChange(t *testing.T) {
+	for _, maxChange := range []float64{-0.5, 0, 1, 1.5} {
+		_, err := NewPIDGasPricer(100, 1, returnConstFn(10), PIDGains{Kp: 1}, maxChange)
+		if err == nil {
+			t.Fatalf("Expected maxPercentChangePerEpoch %f to be rejected", maxChange)
+		}
+	}
+}
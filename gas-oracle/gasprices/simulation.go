@@ -0,0 +1,75 @@
+package gasprices
+
+import (
+	"errors"
+	"math/big"
+)
+
+type GetBlockTimestampFn func(*big.Int) (uint64, error)
+
+// LoadEpochs splits the blocks from startBlockNumber to endBlockNumber
+// inclusive into epochs of epochLengthSeconds, based on the timestamp of
+// each block. Epochs in which no block was produced are kept empty.
+func LoadEpochs(
+	startBlockNumber uint64,
+	endBlockNumber uint64,
+	epochLengthSeconds uint64,
+	getGasUsedByBlockFn GetGasUsedByBlockFn,
+	getBlockTimestampFn GetBlockTimestampFn,
+) ([]Epoch, error) {
+	if endBlockNumber < startBlockNumber {
+		return nil, errors.New("end block number cannot be less than the start block number")
+	}
+	if epochLengthSeconds < 1 {
+		return nil, errors.New("epochLengthSeconds cannot be less than 1 second")
+	}
+
+	var epochs []Epoch
+	epochStartTimestamp := uint64(0)
+	for i := startBlockNumber; i <= endBlockNumber; i++ {
+		number := new(big.Int).SetUint64(i)
+		timestamp, err := getBlockTimestampFn(number)
+		if err != nil {
+			return nil, err
+		}
+		gasUsed, err := getGasUsedByBlockFn(number)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(epochs) == 0 {
+			epochStartTimestamp = timestamp
+			epochs = append(epochs, Epoch{LengthSeconds: epochLengthSeconds})
+		}
+		if timestamp < epochStartTimestamp {
+			return nil, errors.New("block timestamps are not monotonic")
+		}
+		for timestamp >= epochStartTimestamp+epochLengthSeconds {
+			epochStartTimestamp += epochLengthSeconds
+			epochs = append(epochs, Epoch{LengthSeconds: epochLengthSeconds})
+		}
+
+		last := &epochs[len(epochs)-1]
+		last.GasUsedByBlock = append(last.GasUsedByBlock, gasUsed)
+	}
+	return epochs, nil
+}
+
+// Simulate replays the epochs through a controller and returns the gas price
+// set at the end of each epoch. Like the GasPriceUpdater, the price is not
+// updated at the end of epochs without blocks.
+func Simulate(controller GasPriceController, epochs []Epoch) ([]uint64, error) {
+	prices := make([]uint64, 0, len(epochs))
+	for _, epoch := range epochs {
+		if len(epoch.GasUsedByBlock) == 0 {
+			prices = append(prices, controller.CurPrice())
+			continue
+		}
+		price, err := controller.CompleteEpochBlocks(epoch)
+		if err != nil {
+			return nil, err
+		}
+		prices = append(prices, price)
+	}
+	return prices, nil
+}
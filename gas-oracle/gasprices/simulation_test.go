@@ -0,0 +1,57 @@
+package gasprices
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+func TestLoadEpochs(t *testing.T) {
+	timestamps := map[uint64]uint64{
+		1: 100,
+		2: 104,
+		3: 109,
+		4: 110,
+		// No block in the epoch starting at 120
+		5: 131,
+	}
+	getBlockTimestamp := func(number *big.Int) (uint64, error) {
+		return timestamps[number.Uint64()], nil
+	}
+	getGasUsedByBlock := func(number *big.Int) (uint64, error) {
+		return number.Uint64() * 1000, nil
+	}
+
+	epochs, err := LoadEpochs(1, 5, 10, getGasUsedByBlock, getBlockTimestamp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Epoch{
+		{GasUsedByBlock: []uint64{1000, 2000, 3000}, LengthSeconds: 10},
+		{GasUsedByBlock: []uint64{4000}, LengthSeconds: 10},
+		{LengthSeconds: 10},
+		{GasUsedByBlock: []uint64{5000}, LengthSeconds: 10},
+	}
+	if !reflect.DeepEqual(expected, epochs) {
+		t.Fatalf("Got: %v, expected: %v", epochs, expected)
+	}
+}
+
+func TestSimulateSkipsEmptyEpochs(t *testing.T) {
+	gp, err := NewGasPricer(100, 1, returnConstFn(10), 0.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prices, err := Simulate(gp, []Epoch{
+		epochWithGasPerSecond(20),
+		{LengthSeconds: 10},
+		epochWithGasPerSecond(5),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []uint64{150, 150, 75}
+	if !reflect.DeepEqual(expected, prices) {
+		t.Fatalf("Got: %v, expected: %v", prices, expected)
+	}
+}
@@ -53,6 +53,11 @@ func main() {
 			return err
 		}
 
+		if config.StatusEnabled {
+			address := fmt.Sprintf("%s:%d", config.StatusHTTP, config.StatusPort)
+			oracle.SetupStatusServer(address, gpo.Status())
+		}
+
 		if config.MetricsEnabled {
 			address := fmt.Sprintf("%s:%d", config.MetricsHTTP, config.MetricsPort)
 			log.Info("Enabling stand-alone metrics HTTP endpoint", "address", address)
@@ -73,6 +78,31 @@ func main() {
 		return nil
 	}
 
+	app.Commands = []cli.Command{
+		{
+			Name:  "simulate",
+			Usage: "Replay L2 blocks through each gas price controller",
+			Description: "Fetch the gas used by the L2 blocks in a range, split them " +
+				"into epochs and print the gas price set by each controller at the end " +
+				"of every epoch as CSV. Controllers are configured with the global options.",
+			Flags: flags.SimulateFlags,
+			Action: func(ctx *cli.Context) error {
+				if !ctx.IsSet(flags.StartBlockNumberFlag.Name) || !ctx.IsSet(flags.EndBlockNumberFlag.Name) {
+					return fmt.Errorf("--%s and --%s are required",
+						flags.StartBlockNumberFlag.Name, flags.EndBlockNumberFlag.Name)
+				}
+				config := oracle.NewSimulationConfig(ctx)
+				return oracle.Simulate(
+					config,
+					ctx.Uint64(flags.StartBlockNumberFlag.Name),
+					ctx.Uint64(flags.EndBlockNumberFlag.Name),
+					ctx.Uint64(flags.InitialGasPriceFlag.Name),
+					os.Stdout,
+				)
+			},
+		},
+	}
+
 	err := app.Run(os.Args)
 	if err != nil {
 		log.Crit("application failed", "message", err)
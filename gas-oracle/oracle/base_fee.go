@@ -3,6 +3,7 @@ package oracle
 import (
 	"context"
 	"fmt"
+	"math/big"
 
 	"github.com/ethereum-optimism/optimism/gas-oracle/bindings"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
@@ -10,26 +11,34 @@ import (
 	"github.com/ethereum/go-ethereum/log"
 )
 
-func wrapUpdateBaseFee(l1Backend bind.ContractTransactor, l2Backend DeployContractBackend, cfg *Config) (func() error, error) {
-	if cfg.privateKey == nil {
-		return nil, errNoPrivateKey
-	}
-	if cfg.l2ChainID == nil {
-		return nil, errNoChainID
-	}
+// wrapUpdateBaseFee is used by the BaseFeeLoop to update the L1 base fee to
+// the value returned by getL1BaseFee.
+// In shadow mode, the base fee that would have been set is only logged and
+// recorded in the status, and no transaction is sent.
+func wrapUpdateBaseFee(getL1BaseFee func() (*big.Int, error), l2Backend DeployContractBackend, cfg *Config, status *Status) (func() error, error) {
+	var opts *bind.TransactOpts
+	if !cfg.shadowMode {
+		if cfg.privateKey == nil {
+			return nil, errNoPrivateKey
+		}
+		if cfg.l2ChainID == nil {
+			return nil, errNoChainID
+		}
 
-	opts, err := bind.NewKeyedTransactorWithChainID(cfg.privateKey, cfg.l2ChainID)
-	if err != nil {
-		return nil, err
-	}
-	// Once https://github.com/ethereum/go-ethereum/pull/23062 is released
-	// then we can remove setting the context here
-	if opts.Context == nil {
-		opts.Context = context.Background()
+		var err error
+		opts, err = bind.NewKeyedTransactorWithChainID(cfg.privateKey, cfg.l2ChainID)
+		if err != nil {
+			return nil, err
+		}
+		// Once https://github.com/ethereum/go-ethereum/pull/23062 is released
+		// then we can remove setting the context here
+		if opts.Context == nil {
+			opts.Context = context.Background()
+		}
+		// Don't send the transaction using the `contract` so that we can inspect
+		// it beforehand
+		opts.NoSend = true
 	}
-	// Don't send the transaction using the `contract` so that we can inspect
-	// it beforehand
-	opts.NoSend = true
 
 	// Create a new contract bindings in scope of the updateL2GasPriceFn
 	// that is returned from this function
@@ -45,15 +54,25 @@ func wrapUpdateBaseFee(l1Backend bind.ContractTransactor, l2Backend DeployContra
 		if err != nil {
 			return err
 		}
-		tip, err := l1Backend.HeaderByNumber(context.Background(), nil)
+		l1BaseFee, err := getL1BaseFee()
 		if err != nil {
 			return err
 		}
-		if tip.BaseFee == nil {
-			return errNoBaseFee
+		significant := isDifferenceSignificant(baseFee.Uint64(), l1BaseFee.Uint64(), cfg.l1BaseFeeSignificanceFactor)
+		status.setL1BaseFee(l1BaseFee, baseFee, significant)
+
+		if cfg.shadowMode {
+			log.Info("shadow L1 base fee", "l1-base-fee", l1BaseFee, "current", baseFee,
+				"would-update", significant)
+			shadowL1BaseFeeGauge.Update(l1BaseFee.Int64())
+			if significant {
+				txShadowCounter.Inc(1)
+			}
+			return nil
 		}
-		if !isDifferenceSignificant(baseFee.Uint64(), tip.BaseFee.Uint64(), cfg.l1BaseFeeSignificanceFactor) {
-			log.Debug("non significant base fee update", "tip", tip.BaseFee, "current", baseFee)
+
+		if !significant {
+			log.Debug("non significant base fee update", "l1-base-fee", l1BaseFee, "current", baseFee)
 			return nil
 		}
 
@@ -69,7 +88,7 @@ func wrapUpdateBaseFee(l1Backend bind.ContractTransactor, l2Backend DeployContra
 			opts.GasPrice = gasPrice
 		}
 
-		tx, err := contract.SetL1BaseFee(opts, tip.BaseFee)
+		tx, err := contract.SetL1BaseFee(opts, l1BaseFee)
 		if err != nil {
 			return err
 		}
@@ -29,7 +29,14 @@ func TestBaseFeeUpdate(t *testing.T) {
 		gasPrice:              big.NewInt(784637584),
 	}
 
-	update, err := wrapUpdateBaseFee(sim, sim, cfg)
+	sources, err := NewL1BaseFeeSources([]string{""}, []L1HeaderReader{sim}, L1BaseFeeParams{
+		MaxDeviation: 0.25,
+		MinSources:   1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	update, err := wrapUpdateBaseFee(sources.BaseFee, sim, cfg, NewStatus(false))
 	if err != nil {
 		t.Fatal(err)
 	}
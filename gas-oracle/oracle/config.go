@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"math/big"
 	"strings"
+	"time"
 
 	"github.com/ethereum-optimism/optimism/gas-oracle/flags"
+	"github.com/ethereum-optimism/optimism/gas-oracle/gasprices"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/log"
@@ -17,7 +19,7 @@ import (
 type Config struct {
 	l1ChainID                    *big.Int
 	l2ChainID                    *big.Int
-	ethereumHttpUrl              string
+	ethereumHttpUrls             []string
 	layerTwoHttpUrl              string
 	gasPriceOracleAddress        common.Address
 	privateKey                   *ecdsa.PrivateKey
@@ -32,7 +34,17 @@ type Config struct {
 	l2GasPriceSignificanceFactor float64
 	l1BaseFeeSignificanceFactor  float64
 	enableL1BaseFee              bool
+	l1BaseFeeParams              L1BaseFeeParams
 	enableL2GasPrice             bool
+	shadowMode                   bool
+	l2GasPriceController         string
+	eip1559Elasticity            uint64
+	eip1559ChangeDenominator     uint64
+	pidGains                     gasprices.PIDGains
+	// Status server config
+	StatusEnabled bool
+	StatusHTTP    string
+	StatusPort    int
 	// Metrics config
 	MetricsEnabled          bool
 	MetricsHTTP             string
@@ -47,20 +59,32 @@ type Config struct {
 // NewConfig creates a new Config
 func NewConfig(ctx *cli.Context) *Config {
 	cfg := Config{}
-	cfg.ethereumHttpUrl = ctx.GlobalString(flags.EthereumHttpUrlFlag.Name)
+	// The L1 base fee is read from every L1 source, which default to the
+	// single L1 endpoint
+	cfg.ethereumHttpUrls = ctx.GlobalStringSlice(flags.EthereumHttpUrlsFlag.Name)
+	if len(cfg.ethereumHttpUrls) == 0 {
+		cfg.ethereumHttpUrls = []string{ctx.GlobalString(flags.EthereumHttpUrlFlag.Name)}
+	}
+	cfg.l1BaseFeeParams = L1BaseFeeParams{
+		MaxHeadLag:   ctx.GlobalUint64(flags.L1BaseFeeMaxHeadLagFlag.Name),
+		MaxHeadAge:   time.Duration(ctx.GlobalUint64(flags.L1BaseFeeMaxHeadAgeSecondsFlag.Name)) * time.Second,
+		MaxDeviation: ctx.GlobalFloat64(flags.L1BaseFeeMaxDeviationFlag.Name),
+		MinSources:   ctx.GlobalInt(flags.L1BaseFeeMinSourcesFlag.Name),
+	}
+	// Default to a majority of the L1 sources
+	if cfg.l1BaseFeeParams.MinSources == 0 {
+		cfg.l1BaseFeeParams.MinSources = len(cfg.ethereumHttpUrls)/2 + 1
+	}
 	cfg.layerTwoHttpUrl = ctx.GlobalString(flags.LayerTwoHttpUrlFlag.Name)
 	addr := ctx.GlobalString(flags.GasPriceOracleAddressFlag.Name)
 	cfg.gasPriceOracleAddress = common.HexToAddress(addr)
-	cfg.targetGasPerSecond = ctx.GlobalUint64(flags.TargetGasPerSecondFlag.Name)
-	cfg.maxPercentChangePerEpoch = ctx.GlobalFloat64(flags.MaxPercentChangePerEpochFlag.Name)
-	cfg.averageBlockGasLimitPerEpoch = ctx.GlobalUint64(flags.AverageBlockGasLimitPerEpochFlag.Name)
-	cfg.epochLengthSeconds = ctx.GlobalUint64(flags.EpochLengthSecondsFlag.Name)
+	setGasPricerConfig(ctx, &cfg)
 	cfg.l1BaseFeeEpochLengthSeconds = ctx.GlobalUint64(flags.L1BaseFeeEpochLengthSecondsFlag.Name)
 	cfg.l2GasPriceSignificanceFactor = ctx.GlobalFloat64(flags.L2GasPriceSignificanceFactorFlag.Name)
-	cfg.floorPrice = ctx.GlobalUint64(flags.FloorPriceFlag.Name)
 	cfg.l1BaseFeeSignificanceFactor = ctx.GlobalFloat64(flags.L1BaseFeeSignificanceFactorFlag.Name)
 	cfg.enableL1BaseFee = ctx.GlobalBool(flags.EnableL1BaseFeeFlag.Name)
 	cfg.enableL2GasPrice = ctx.GlobalBool(flags.EnableL2GasPriceFlag.Name)
+	cfg.shadowMode = ctx.GlobalBool(flags.ShadowModeFlag.Name)
 
 	if ctx.GlobalIsSet(flags.PrivateKeyFlag.Name) {
 		hex := ctx.GlobalString(flags.PrivateKeyFlag.Name)
@@ -70,7 +94,7 @@ func NewConfig(ctx *cli.Context) *Config {
 			log.Error(fmt.Sprintf("Option %q: %v", flags.PrivateKeyFlag.Name, err))
 		}
 		cfg.privateKey = key
-	} else {
+	} else if !cfg.shadowMode {
 		log.Crit("No private key configured")
 	}
 
@@ -92,6 +116,10 @@ func NewConfig(ctx *cli.Context) *Config {
 		cfg.waitForReceipt = true
 	}
 
+	cfg.StatusEnabled = ctx.GlobalBool(flags.StatusEnabledFlag.Name)
+	cfg.StatusHTTP = ctx.GlobalString(flags.StatusHTTPFlag.Name)
+	cfg.StatusPort = ctx.GlobalInt(flags.StatusPortFlag.Name)
+
 	cfg.MetricsEnabled = ctx.GlobalBool(flags.MetricsEnabledFlag.Name)
 	cfg.MetricsHTTP = ctx.GlobalString(flags.MetricsHTTPFlag.Name)
 	cfg.MetricsPort = ctx.GlobalInt(flags.MetricsPortFlag.Name)
@@ -103,3 +131,30 @@ func NewConfig(ctx *cli.Context) *Config {
 
 	return &cfg
 }
+
+// NewSimulationConfig creates a Config holding only the options needed to
+// replay L2 blocks through the gas price controllers
+func NewSimulationConfig(ctx *cli.Context) *Config {
+	cfg := Config{}
+	cfg.layerTwoHttpUrl = ctx.GlobalString(flags.LayerTwoHttpUrlFlag.Name)
+	setGasPricerConfig(ctx, &cfg)
+	return &cfg
+}
+
+// setGasPricerConfig sets the options of the L2 gas price controllers
+func setGasPricerConfig(ctx *cli.Context, cfg *Config) {
+	cfg.floorPrice = ctx.GlobalUint64(flags.FloorPriceFlag.Name)
+	cfg.targetGasPerSecond = ctx.GlobalUint64(flags.TargetGasPerSecondFlag.Name)
+	cfg.maxPercentChangePerEpoch = ctx.GlobalFloat64(flags.MaxPercentChangePerEpochFlag.Name)
+	cfg.averageBlockGasLimitPerEpoch = ctx.GlobalUint64(flags.AverageBlockGasLimitPerEpochFlag.Name)
+	cfg.epochLengthSeconds = ctx.GlobalUint64(flags.EpochLengthSecondsFlag.Name)
+	cfg.l2GasPriceController = ctx.GlobalString(flags.L2GasPriceControllerFlag.Name)
+	cfg.eip1559Elasticity = ctx.GlobalUint64(flags.EIP1559ElasticityFlag.Name)
+	cfg.eip1559ChangeDenominator = ctx.GlobalUint64(flags.EIP1559ChangeDenominatorFlag.Name)
+	cfg.pidGains = gasprices.PIDGains{
+		Kp:            ctx.GlobalFloat64(flags.PIDKpFlag.Name),
+		Ki:            ctx.GlobalFloat64(flags.PIDKiFlag.Name),
+		Kd:            ctx.GlobalFloat64(flags.PIDKdFlag.Name),
+		IntegralLimit: ctx.GlobalFloat64(flags.PIDIntegralLimitFlag.Name),
+	}
+}
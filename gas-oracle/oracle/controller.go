@@ -0,0 +1,44 @@
+package oracle
+
+import (
+	"fmt"
+
+	"github.com/ethereum-optimism/optimism/gas-oracle/gasprices"
+)
+
+// newGasPriceController creates the L2 gas price controller with the given
+// name, starting at curPrice
+func newGasPriceController(name string, curPrice uint64, cfg *Config) (gasprices.GasPriceController, error) {
+	getTargetGasPerSecond := func() float64 {
+		return float64(cfg.targetGasPerSecond)
+	}
+
+	switch name {
+	case gasprices.ProportionalController:
+		return gasprices.NewGasPricer(
+			curPrice,
+			cfg.floorPrice,
+			getTargetGasPerSecond,
+			cfg.maxPercentChangePerEpoch,
+		)
+	case gasprices.EIP1559Controller:
+		return gasprices.NewEIP1559GasPricer(
+			curPrice,
+			cfg.floorPrice,
+			cfg.averageBlockGasLimitPerEpoch,
+			cfg.eip1559Elasticity,
+			cfg.eip1559ChangeDenominator,
+		)
+	case gasprices.PIDController:
+		return gasprices.NewPIDGasPricer(
+			curPrice,
+			cfg.floorPrice,
+			getTargetGasPerSecond,
+			cfg.pidGains,
+			cfg.maxPercentChangePerEpoch,
+		)
+	default:
+		return nil, fmt.Errorf("unknown L2 gas price controller %q, expected one of %v",
+			name, gasprices.Controllers)
+	}
+}
@@ -42,8 +42,9 @@ type GasPriceOracle struct {
 	stop            chan struct{}
 	contract        *bindings.GasPriceOracle
 	l2Backend       DeployContractBackend
-	l1Backend       bind.ContractTransactor
+	l1BaseFee       *L1BaseFeeSources
 	gasPriceUpdater *gasprices.GasPriceUpdater
+	status          *Status
 	config          *Config
 }
 
@@ -55,13 +56,18 @@ func (g *GasPriceOracle) Start() error {
 	if g.config.l2ChainID == nil {
 		return fmt.Errorf("layer-two: %w", errNoChainID)
 	}
-	if g.config.privateKey == nil {
-		return errNoPrivateKey
-	}
+	if g.config.shadowMode {
+		log.Warn("Starting Gas Price Oracle in shadow mode, no transactions will be sent",
+			"l1-chain-id", g.l1ChainID, "l2-chain-id", g.l2ChainID)
+	} else {
+		if g.config.privateKey == nil {
+			return errNoPrivateKey
+		}
 
-	address := crypto.PubkeyToAddress(g.config.privateKey.PublicKey)
-	log.Info("Starting Gas Price Oracle", "l1-chain-id", g.l1ChainID,
-		"l2-chain-id", g.l2ChainID, "address", address.Hex())
+		address := crypto.PubkeyToAddress(g.config.privateKey.PublicKey)
+		log.Info("Starting Gas Price Oracle", "l1-chain-id", g.l1ChainID,
+			"l2-chain-id", g.l2ChainID, "address", address.Hex())
+	}
 
 	price, err := g.contract.GasPrice(&bind.CallOpts{
 		Context: context.Background(),
@@ -81,6 +87,11 @@ func (g *GasPriceOracle) Start() error {
 	return nil
 }
 
+// Status returns the prices last computed by the update loops
+func (g *GasPriceOracle) Status() *Status {
+	return g.status
+}
+
 func (g *GasPriceOracle) Stop() {
 	close(g.stop)
 }
@@ -130,7 +141,7 @@ func (g *GasPriceOracle) BaseFeeLoop() {
 	timer := time.NewTicker(time.Duration(g.config.l1BaseFeeEpochLengthSeconds) * time.Second)
 	defer timer.Stop()
 
-	updateBaseFee, err := wrapUpdateBaseFee(g.l1Backend, g.l2Backend, g.config)
+	updateBaseFee, err := wrapUpdateBaseFee(g.l1BaseFee.BaseFee, g.l2Backend, g.config, g.status)
 	if err != nil {
 		panic(err)
 	}
@@ -181,9 +192,13 @@ func NewGasPriceOracle(cfg *Config) (*GasPriceOracle, error) {
 		return nil, err
 	}
 
-	l1Client, err := ethclient.Dial(cfg.ethereumHttpUrl)
-	if err != nil {
-		return nil, err
+	// Create a client for each L1 source
+	l1Clients := make([]L1Backend, len(cfg.ethereumHttpUrls))
+	for i, url := range cfg.ethereumHttpUrls {
+		l1Clients[i], err = ethclient.Dial(url)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	// Ensure that we can actually connect to both backends
@@ -192,11 +207,19 @@ func NewGasPriceOracle(cfg *Config) (*GasPriceOracle, error) {
 		log.Error("Unable to connect to layer two")
 		return nil, err
 	}
-	log.Info("Connecting to layer one")
-	if err := ensureConnection(l1Client); err != nil {
+	// Only a quorum of L1 sources must be reachable, the others are used
+	// once they respond
+	log.Info("Connecting to layer one", "sources", len(l1Clients),
+		"minSources", cfg.l1BaseFeeParams.MinSources)
+	l1ChainID, l1Backends, err := connectL1Sources(context.Background(), cfg.ethereumHttpUrls,
+		l1Clients, cfg.l1BaseFeeParams.MinSources, cfg.l1ChainID, time.Second)
+	if err != nil {
 		log.Error("Unable to connect to layer one")
 		return nil, err
 	}
+	if cfg.l1ChainID == nil {
+		cfg.l1ChainID = l1ChainID
+	}
 
 	address := cfg.gasPriceOracleAddress
 	contract, err := bindings.NewGasPriceOracle(address, l2Client)
@@ -213,18 +236,12 @@ func NewGasPriceOracle(cfg *Config) (*GasPriceOracle, error) {
 	}
 
 	// Create a gas pricer for the gas price updater
-	log.Info("Creating GasPricer", "currentPrice", currentPrice,
-		"floorPrice", cfg.floorPrice, "targetGasPerSecond", cfg.targetGasPerSecond,
+	log.Info("Creating GasPricer", "controller", cfg.l2GasPriceController,
+		"currentPrice", currentPrice, "floorPrice", cfg.floorPrice,
+		"targetGasPerSecond", cfg.targetGasPerSecond,
 		"maxPercentChangePerEpoch", cfg.maxPercentChangePerEpoch)
 
-	gasPricer, err := gasprices.NewGasPricer(
-		currentPrice.Uint64(),
-		cfg.floorPrice,
-		func() float64 {
-			return float64(cfg.targetGasPerSecond)
-		},
-		cfg.maxPercentChangePerEpoch,
-	)
+	gasPricer, err := newGasPriceController(cfg.l2GasPriceController, currentPrice.Uint64(), cfg)
 	if err != nil {
 		return nil, err
 	}
@@ -233,10 +250,6 @@ func NewGasPriceOracle(cfg *Config) (*GasPriceOracle, error) {
 	if err != nil {
 		return nil, err
 	}
-	l1ChainID, err := l1Client.ChainID(context.Background())
-	if err != nil {
-		return nil, err
-	}
 
 	if cfg.l2ChainID != nil {
 		if cfg.l2ChainID.Cmp(l2ChainID) != 0 {
@@ -247,16 +260,7 @@ func NewGasPriceOracle(cfg *Config) (*GasPriceOracle, error) {
 		cfg.l2ChainID = l2ChainID
 	}
 
-	if cfg.l1ChainID != nil {
-		if cfg.l1ChainID.Cmp(l1ChainID) != 0 {
-			return nil, fmt.Errorf("%w: L1: configured with %d and got %d",
-				errWrongChainID, cfg.l1ChainID, l1ChainID)
-		}
-	} else {
-		cfg.l1ChainID = l1ChainID
-	}
-
-	if cfg.privateKey == nil {
+	if cfg.privateKey == nil && !cfg.shadowMode {
 		return nil, errNoPrivateKey
 	}
 
@@ -272,7 +276,8 @@ func NewGasPriceOracle(cfg *Config) (*GasPriceOracle, error) {
 	getLatestBlockNumberFn := wrapGetLatestBlockNumberFn(l2Client)
 	// updateL2GasPriceFn is used by the GasPriceUpdater to
 	// update the gas price
-	updateL2GasPriceFn, err := wrapUpdateL2GasPriceFn(l2Client, cfg)
+	status := NewStatus(cfg.shadowMode)
+	updateL2GasPriceFn, err := wrapUpdateL2GasPriceFn(l2Client, cfg, status)
 	if err != nil {
 		return nil, err
 	}
@@ -298,6 +303,14 @@ func NewGasPriceOracle(cfg *Config) (*GasPriceOracle, error) {
 		return nil, err
 	}
 
+	log.Info("Creating L1BaseFeeSources", "sources", len(l1Backends),
+		"minSources", cfg.l1BaseFeeParams.MinSources, "maxDeviation", cfg.l1BaseFeeParams.MaxDeviation,
+		"maxHeadLag", cfg.l1BaseFeeParams.MaxHeadLag, "maxHeadAge", cfg.l1BaseFeeParams.MaxHeadAge)
+	l1BaseFee, err := NewL1BaseFeeSources(cfg.ethereumHttpUrls, l1Backends, cfg.l1BaseFeeParams)
+	if err != nil {
+		return nil, err
+	}
+
 	gpo := GasPriceOracle{
 		l2ChainID:       l2ChainID,
 		l1ChainID:       l1ChainID,
@@ -305,13 +318,17 @@ func NewGasPriceOracle(cfg *Config) (*GasPriceOracle, error) {
 		stop:            make(chan struct{}),
 		contract:        contract,
 		gasPriceUpdater: gasPriceUpdater,
+		status:          status,
 		config:          cfg,
 		l2Backend:       l2Client,
-		l1Backend:       l1Client,
+		l1BaseFee:       l1BaseFee,
 	}
 
-	if err := gpo.ensure(); err != nil {
-		return nil, err
+	// The owner is only needed to send transactions
+	if !cfg.shadowMode {
+		if err := gpo.ensure(); err != nil {
+			return nil, err
+		}
 	}
 
 	return &gpo, nil
@@ -0,0 +1,302 @@
+package oracle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	ometrics "github.com/ethereum-optimism/optimism/gas-oracle/metrics"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+var (
+	// errNotEnoughL1Sources represents the error when too few L1 sources
+	// returned a usable base fee to trust their median
+	errNotEnoughL1Sources = errors.New("not enough L1 sources")
+
+	l1BaseFeeMedianGauge  = metrics.NewRegisteredGauge("l1_base_fee/median", ometrics.DefaultRegistry)
+	l1BaseFeeSourcesGauge = metrics.NewRegisteredGauge("l1_base_fee/sources", ometrics.DefaultRegistry)
+)
+
+// l1SourceTimeout bounds the time to fetch the L1 tip from a single source
+const l1SourceTimeout = 10 * time.Second
+
+// L1HeaderReader is implemented by the `ethclient.Client` of each L1 source
+type L1HeaderReader interface {
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+}
+
+// l1Source is an L1 endpoint used to read the base fee, along with its
+// metrics
+type l1Source struct {
+	name             string
+	backend          L1HeaderReader
+	deviationGauge   metrics.GaugeFloat64
+	errorCounter     metrics.Counter
+	staleCounter     metrics.Counter
+	outlierCounter   metrics.Counter
+	baseFeeGauge     metrics.Gauge
+	blockNumberGauge metrics.Gauge
+}
+
+// l1Observation is the tip of an L1 source
+type l1Observation struct {
+	source      int
+	blockNumber uint64
+	timestamp   uint64
+	baseFee     *big.Int
+}
+
+// L1BaseFeeParams configures how the base fees of the L1 sources are
+// aggregated
+type L1BaseFeeParams struct {
+	// MaxHeadLag is the number of blocks a source can be behind the median
+	// tip of all sources before it is considered stale
+	MaxHeadLag uint64
+	// MaxHeadAge is the age of a tip after which it is considered stale, or
+	// zero to accept tips of any age
+	MaxHeadAge time.Duration
+	// MaxDeviation is the relative difference to the median base fee above
+	// which a source is considered an outlier
+	MaxDeviation float64
+	// MinSources is the number of sources that must agree on the base fee
+	MinSources int
+}
+
+// L1BaseFeeSources reads the L1 base fee as the median of the base fees of
+// the tips of several L1 sources, so that a single misbehaving source can't
+// set the L1 base fee on L2
+type L1BaseFeeSources struct {
+	sources []*l1Source
+	params  L1BaseFeeParams
+	now     func() time.Time
+}
+
+// NewL1BaseFeeSources creates L1BaseFeeSources reading from the given
+// backends, each named after the host of its URL
+func NewL1BaseFeeSources(urls []string, backends []L1HeaderReader, params L1BaseFeeParams) (*L1BaseFeeSources, error) {
+	if len(urls) != len(backends) {
+		return nil, errors.New("mismatched L1 urls and backends")
+	}
+	if params.MinSources < 1 || params.MinSources > len(backends) {
+		return nil, fmt.Errorf("min L1 sources must be between 1 and %d, got %d",
+			len(backends), params.MinSources)
+	}
+	if params.MaxDeviation <= 0 {
+		return nil, errors.New("max L1 base fee deviation must be positive")
+	}
+
+	sources := make([]*l1Source, len(backends))
+	names := make(map[string]bool)
+	for i, backend := range backends {
+		name := l1SourceName(i, urls[i])
+		if names[name] {
+			name = fmt.Sprintf("%s-%d", name, i)
+		}
+		names[name] = true
+
+		prefix := "l1_base_fee/source/" + name + "/"
+		sources[i] = &l1Source{
+			name:             name,
+			backend:          backend,
+			deviationGauge:   metrics.NewRegisteredGaugeFloat64(prefix+"deviation", ometrics.DefaultRegistry),
+			errorCounter:     metrics.NewRegisteredCounter(prefix+"errors", ometrics.DefaultRegistry),
+			staleCounter:     metrics.NewRegisteredCounter(prefix+"stale", ometrics.DefaultRegistry),
+			outlierCounter:   metrics.NewRegisteredCounter(prefix+"outliers", ometrics.DefaultRegistry),
+			baseFeeGauge:     metrics.NewRegisteredGauge(prefix+"base_fee", ometrics.DefaultRegistry),
+			blockNumberGauge: metrics.NewRegisteredGauge(prefix+"block_number", ometrics.DefaultRegistry),
+		}
+	}
+
+	return &L1BaseFeeSources{
+		sources: sources,
+		params:  params,
+		now:     time.Now,
+	}, nil
+}
+
+// l1SourceName names a source after the host of its URL, so that
+// credentials in the path or query are not exposed in metrics and logs
+func l1SourceName(i int, rawurl string) string {
+	u, err := url.Parse(rawurl)
+	if err != nil || u.Host == "" {
+		return fmt.Sprintf("source-%d", i)
+	}
+	return u.Host
+}
+
+// BaseFee returns the median of the base fees of the tips of the sources,
+// ignoring sources that failed, are stale or deviate too much from the
+// median
+func (s *L1BaseFeeSources) BaseFee() (*big.Int, error) {
+	observations := s.fetch()
+
+	fresh := s.rejectStale(observations)
+	median, accepted := s.rejectOutliers(fresh)
+	if len(accepted) < s.params.MinSources {
+		return nil, fmt.Errorf("%w: %d usable out of %d, need %d",
+			errNotEnoughL1Sources, len(accepted), len(s.sources), s.params.MinSources)
+	}
+
+	for _, obs := range observations {
+		s.sources[obs.source].deviationGauge.Update(deviation(obs.baseFee, median))
+	}
+	l1BaseFeeMedianGauge.Update(median.Int64())
+	l1BaseFeeSourcesGauge.Update(int64(len(accepted)))
+
+	log.Debug("aggregated L1 base fee", "median", median, "sources", len(accepted),
+		"fresh", len(fresh), "responses", len(observations))
+	return median, nil
+}
+
+// fetch concurrently reads the tip of every source
+func (s *L1BaseFeeSources) fetch() []l1Observation {
+	var (
+		wg           sync.WaitGroup
+		mu           sync.Mutex
+		observations []l1Observation
+	)
+	for i, source := range s.sources {
+		wg.Add(1)
+		go func(i int, source *l1Source) {
+			defer wg.Done()
+
+			ctx, cancel := context.WithTimeout(context.Background(), l1SourceTimeout)
+			defer cancel()
+			tip, err := source.backend.HeaderByNumber(ctx, nil)
+			if err == nil && tip.BaseFee == nil {
+				err = errNoBaseFee
+			}
+			if err != nil {
+				log.Warn("cannot fetch L1 tip", "source", source.name, "message", err)
+				source.errorCounter.Inc(1)
+				return
+			}
+
+			source.baseFeeGauge.Update(tip.BaseFee.Int64())
+			source.blockNumberGauge.Update(tip.Number.Int64())
+
+			mu.Lock()
+			defer mu.Unlock()
+			observations = append(observations, l1Observation{
+				source:      i,
+				blockNumber: tip.Number.Uint64(),
+				timestamp:   tip.Time,
+				baseFee:     tip.BaseFee,
+			})
+		}(i, source)
+	}
+	wg.Wait()
+
+	sort.Slice(observations, func(i, j int) bool {
+		return observations[i].source < observations[j].source
+	})
+	return observations
+}
+
+// rejectStale removes the observations of sources whose tip is too far
+// behind the median tip, or too old. The median is used rather than the
+// highest tip so that a single source can't make the others look stale.
+func (s *L1BaseFeeSources) rejectStale(observations []l1Observation) []l1Observation {
+	numbers := make([]uint64, len(observations))
+	for i, obs := range observations {
+		numbers[i] = obs.blockNumber
+	}
+	sort.Slice(numbers, func(i, j int) bool {
+		return numbers[i] < numbers[j]
+	})
+	var median uint64
+	if len(numbers) > 0 {
+		median = numbers[len(numbers)/2]
+	}
+
+	now := s.now()
+	fresh := make([]l1Observation, 0, len(observations))
+	for _, obs := range observations {
+		source := s.sources[obs.source]
+		if obs.blockNumber+s.params.MaxHeadLag < median {
+			log.Warn("rejecting stale L1 source", "source", source.name,
+				"block-number", obs.blockNumber, "median", median)
+			source.staleCounter.Inc(1)
+			continue
+		}
+		age := now.Sub(time.Unix(int64(obs.timestamp), 0))
+		if s.params.MaxHeadAge > 0 && age > s.params.MaxHeadAge {
+			log.Warn("rejecting stale L1 source", "source", source.name,
+				"block-number", obs.blockNumber, "age", age)
+			source.staleCounter.Inc(1)
+			continue
+		}
+		fresh = append(fresh, obs)
+	}
+	return fresh
+}
+
+// rejectOutliers removes the observations whose base fee deviates from the
+// median by more than the max deviation, and returns the median of the
+// remaining observations
+func (s *L1BaseFeeSources) rejectOutliers(observations []l1Observation) (*big.Int, []l1Observation) {
+	if len(observations) == 0 {
+		return nil, nil
+	}
+
+	median := medianBaseFee(observations)
+	accepted := make([]l1Observation, 0, len(observations))
+	for _, obs := range observations {
+		if deviation(obs.baseFee, median) > s.params.MaxDeviation {
+			source := s.sources[obs.source]
+			log.Warn("rejecting outlier L1 source", "source", source.name,
+				"base-fee", obs.baseFee, "median", median)
+			source.outlierCounter.Inc(1)
+			continue
+		}
+		accepted = append(accepted, obs)
+	}
+	if len(accepted) == 0 {
+		return nil, nil
+	}
+	return medianBaseFee(accepted), accepted
+}
+
+// medianBaseFee returns the median base fee of the observations, rounding
+// down the mean of the two middle base fees of an even number of
+// observations
+func medianBaseFee(observations []l1Observation) *big.Int {
+	fees := make([]*big.Int, len(observations))
+	for i, obs := range observations {
+		fees[i] = obs.baseFee
+	}
+	sort.Slice(fees, func(i, j int) bool {
+		return fees[i].Cmp(fees[j]) < 0
+	})
+
+	mid := len(fees) / 2
+	if len(fees)%2 == 1 {
+		return new(big.Int).Set(fees[mid])
+	}
+	median := new(big.Int).Add(fees[mid-1], fees[mid])
+	return median.Rsh(median, 1)
+}
+
+// deviation returns the relative difference of a base fee to the median
+func deviation(baseFee, median *big.Int) float64 {
+	if median == nil {
+		return 0
+	}
+	if median.Sign() == 0 {
+		if baseFee.Sign() == 0 {
+			return 0
+		}
+		return 1
+	}
+	diff := new(big.Float).SetInt(new(big.Int).Sub(baseFee, median))
+	ratio, _ := new(big.Float).Quo(diff.Abs(diff), new(big.Float).SetInt(median)).Float64()
+	return ratio
+}
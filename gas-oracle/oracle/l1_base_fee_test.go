@@ -0,0 +1,190 @@
+package oracle
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+type mockL1HeaderReader struct {
+	header *types.Header
+	err    error
+}
+
+func (m *mockL1HeaderReader) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	return m.header, m.err
+}
+
+func newMockL1Source(number, timestamp, baseFee uint64) L1HeaderReader {
+	return &mockL1HeaderReader{
+		header: &types.Header{
+			Number:  new(big.Int).SetUint64(number),
+			Time:    timestamp,
+			BaseFee: new(big.Int).SetUint64(baseFee),
+		},
+	}
+}
+
+func TestL1BaseFeeSources(t *testing.T) {
+	now := time.Unix(1000, 0)
+	params := L1BaseFeeParams{
+		MaxHeadLag:   2,
+		MaxHeadAge:   time.Minute,
+		MaxDeviation: 0.25,
+		MinSources:   2,
+	}
+
+	tests := []struct {
+		name     string
+		backends []L1HeaderReader
+		expected uint64
+		err      error
+	}{
+		{
+			name: "median of agreeing sources",
+			backends: []L1HeaderReader{
+				newMockL1Source(100, 990, 100),
+				newMockL1Source(100, 990, 110),
+				newMockL1Source(101, 995, 120),
+			},
+			expected: 110,
+		},
+		{
+			name: "mean of the middle base fees of an even number of sources",
+			backends: []L1HeaderReader{
+				newMockL1Source(100, 990, 100),
+				newMockL1Source(101, 995, 111),
+			},
+			expected: 105,
+		},
+		{
+			name: "outlier is ignored",
+			backends: []L1HeaderReader{
+				newMockL1Source(100, 990, 100),
+				newMockL1Source(100, 990, 110),
+				newMockL1Source(100, 990, 10_000),
+			},
+			expected: 105,
+		},
+		{
+			name: "source behind the median tip is ignored",
+			backends: []L1HeaderReader{
+				newMockL1Source(100, 990, 100),
+				newMockL1Source(100, 990, 110),
+				newMockL1Source(97, 950, 60),
+			},
+			expected: 105,
+		},
+		{
+			name: "source ahead of the other sources does not make them stale",
+			backends: []L1HeaderReader{
+				newMockL1Source(100, 990, 100),
+				newMockL1Source(100, 990, 110),
+				newMockL1Source(1_000_000, 990, 105),
+			},
+			expected: 105,
+		},
+		{
+			name: "old tip is ignored",
+			backends: []L1HeaderReader{
+				newMockL1Source(100, 990, 100),
+				newMockL1Source(100, 990, 110),
+				newMockL1Source(100, 900, 120),
+			},
+			expected: 105,
+		},
+		{
+			name: "failing source is ignored",
+			backends: []L1HeaderReader{
+				newMockL1Source(100, 990, 100),
+				newMockL1Source(100, 990, 110),
+				&mockL1HeaderReader{err: errors.New("unavailable")},
+			},
+			expected: 105,
+		},
+		{
+			name: "not enough usable sources",
+			backends: []L1HeaderReader{
+				newMockL1Source(100, 990, 100),
+				newMockL1Source(100, 900, 110),
+				&mockL1HeaderReader{err: errors.New("unavailable")},
+			},
+			err: errNotEnoughL1Sources,
+		},
+		{
+			name: "pre eip1559 tips are not usable",
+			backends: []L1HeaderReader{
+				newMockL1Source(100, 990, 100),
+				&mockL1HeaderReader{header: &types.Header{Number: big.NewInt(100), Time: 990}},
+			},
+			err: errNotEnoughL1Sources,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			urls := make([]string, len(test.backends))
+			sources, err := NewL1BaseFeeSources(urls, test.backends, params)
+			if err != nil {
+				t.Fatal(err)
+			}
+			sources.now = func() time.Time { return now }
+
+			baseFee, err := sources.BaseFee()
+			if !errors.Is(err, test.err) {
+				t.Fatalf("expected error %v, got %v", test.err, err)
+			}
+			if test.err == nil && baseFee.Uint64() != test.expected {
+				t.Fatalf("expected base fee %d, got %d", test.expected, baseFee)
+			}
+		})
+	}
+}
+
+func TestNewL1BaseFeeSourcesChecksParams(t *testing.T) {
+	backends := []L1HeaderReader{newMockL1Source(1, 1, 1)}
+	urls := []string{"https://eth.example.com/secret-key"}
+
+	if _, err := NewL1BaseFeeSources(urls, backends, L1BaseFeeParams{MaxDeviation: 0.1}); err == nil {
+		t.Fatal("expected zero min sources to be rejected")
+	}
+	if _, err := NewL1BaseFeeSources(urls, backends, L1BaseFeeParams{MaxDeviation: 0.1, MinSources: 2}); err == nil {
+		t.Fatal("expected min sources above the number of sources to be rejected")
+	}
+	if _, err := NewL1BaseFeeSources(urls, backends, L1BaseFeeParams{MinSources: 1}); err == nil {
+		t.Fatal("expected zero max deviation to be rejected")
+	}
+
+	sources, err := NewL1BaseFeeSources(urls, backends, L1BaseFeeParams{MaxDeviation: 0.1, MinSources: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sources.sources[0].name != "eth.example.com" {
+		t.Fatalf("unexpected source name %s", sources.sources[0].name)
+	}
+}
+
+func TestDeviation(t *testing.T) {
+	tests := []struct {
+		baseFee  int64
+		median   int64
+		expected float64
+	}{
+		{baseFee: 100, median: 100, expected: 0},
+		{baseFee: 125, median: 100, expected: 0.25},
+		{baseFee: 75, median: 100, expected: 0.25},
+		{baseFee: 0, median: 0, expected: 0},
+		{baseFee: 1, median: 0, expected: 1},
+	}
+	for _, test := range tests {
+		got := deviation(big.NewInt(test.baseFee), big.NewInt(test.median))
+		if got != test.expected {
+			t.Fatalf("deviation of %d from %d: expected %f, got %f",
+				test.baseFee, test.median, test.expected, got)
+		}
+	}
+}
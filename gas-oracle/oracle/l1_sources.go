@@ -0,0 +1,146 @@
+package oracle
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// l1ConnectAttempts bounds the number of times the L1 sources are tried at
+// startup before giving up
+const l1ConnectAttempts = 90
+
+// L1Backend is implemented by the `ethclient.Client` of each L1 source
+type L1Backend interface {
+	L1HeaderReader
+	ChainID(ctx context.Context) (*big.Int, error)
+}
+
+// connectL1Sources waits until at least minSources of the L1 sources are
+// reachable, retrying every interval, and checks that the reachable sources
+// are on the same chain. The chain is the expected chain if not nil, and the
+// chain of the first reachable source otherwise. Sources that are still
+// unreachable are logged and checked once they respond, so that a single
+// source being down doesn't prevent the oracle from starting.
+func connectL1Sources(
+	ctx context.Context,
+	urls []string,
+	backends []L1Backend,
+	minSources int,
+	expected *big.Int,
+	interval time.Duration,
+) (*big.Int, []L1HeaderReader, error) {
+
+	if minSources < 1 || minSources > len(backends) {
+		return nil, nil, fmt.Errorf("min L1 sources must be between 1 and %d, got %d",
+			len(backends), minSources)
+	}
+
+	chainIDs := make([]*big.Int, len(backends))
+	reachable := 0
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for attempt := 1; ; attempt++ {
+		for i, backend := range backends {
+			if chainIDs[i] != nil {
+				continue
+			}
+			chainID, err := fetchChainID(ctx, backend)
+			if err != nil {
+				log.Debug("cannot reach L1 source", "source", l1SourceName(i, urls[i]), "message", err)
+				continue
+			}
+			chainIDs[i] = chainID
+			reachable++
+		}
+		if reachable >= minSources {
+			break
+		}
+		if attempt >= l1ConnectAttempts {
+			return nil, nil, fmt.Errorf("%w: %d reachable out of %d, need %d",
+				errNotEnoughL1Sources, reachable, len(backends), minSources)
+		}
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		}
+	}
+
+	chainID := expected
+	for _, id := range chainIDs {
+		if chainID == nil && id != nil {
+			chainID = id
+		}
+	}
+
+	sources := make([]L1HeaderReader, len(backends))
+	for i, backend := range backends {
+		name := l1SourceName(i, urls[i])
+		if chainIDs[i] == nil {
+			log.Warn("L1 source unreachable, checking its chain id once it responds", "source", name)
+			sources[i] = &chainCheckedL1Source{name: name, backend: backend, chainID: chainID}
+			continue
+		}
+		if chainIDs[i].Cmp(chainID) != 0 {
+			return nil, nil, fmt.Errorf("%w: L1 source %s: got %d, expected %d",
+				errWrongChainID, name, chainIDs[i], chainID)
+		}
+		sources[i] = backend
+	}
+
+	return chainID, sources, nil
+}
+
+// fetchChainID reads the chain id of an L1 source, bounded by the timeout of
+// a single source
+func fetchChainID(ctx context.Context, backend L1Backend) (*big.Int, error) {
+	ctx, cancel := context.WithTimeout(ctx, l1SourceTimeout)
+	defer cancel()
+	return backend.ChainID(ctx)
+}
+
+// chainCheckedL1Source is an L1 source that was unreachable at startup. Its
+// headers are only read once its chain id was checked against the chain of
+// the other sources.
+type chainCheckedL1Source struct {
+	name    string
+	backend L1Backend
+	chainID *big.Int
+
+	mu      sync.Mutex
+	checked bool
+}
+
+func (s *chainCheckedL1Source) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	if err := s.checkChainID(ctx); err != nil {
+		return nil, err
+	}
+	return s.backend.HeaderByNumber(ctx, number)
+}
+
+// checkChainID fetches the chain id of the source until it succeeds once
+func (s *chainCheckedL1Source) checkChainID(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.checked {
+		return nil
+	}
+	chainID, err := s.backend.ChainID(ctx)
+	if err != nil {
+		return err
+	}
+	if chainID.Cmp(s.chainID) != 0 {
+		return fmt.Errorf("%w: L1 source %s: got %d, expected %d",
+			errWrongChainID, s.name, chainID, s.chainID)
+	}
+	log.Info("L1 source reachable", "source", s.name, "chainID", chainID)
+	s.checked = true
+	return nil
+}
@@ -0,0 +1,102 @@
+package oracle
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+type mockL1Backend struct {
+	mockL1HeaderReader
+	chainID *big.Int
+	err     error
+}
+
+func (m *mockL1Backend) ChainID(ctx context.Context) (*big.Int, error) {
+	return m.chainID, m.err
+}
+
+func newMockL1Backend(chainID int64) *mockL1Backend {
+	return &mockL1Backend{
+		mockL1HeaderReader: mockL1HeaderReader{
+			header: &types.Header{Number: big.NewInt(100), BaseFee: big.NewInt(10)},
+		},
+		chainID: big.NewInt(chainID),
+	}
+}
+
+func TestConnectL1Sources(t *testing.T) {
+	urls := []string{"http://a:8545", "http://b:8545", "http://c:8545"}
+	errDown := errors.New("connection refused")
+
+	t.Run("quorum reachable", func(t *testing.T) {
+		down := newMockL1Backend(1)
+		down.err = errDown
+		backends := []L1Backend{newMockL1Backend(1), down, newMockL1Backend(1)}
+
+		chainID, sources, err := connectL1Sources(context.Background(), urls, backends, 2, nil, time.Millisecond)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if chainID.Cmp(big.NewInt(1)) != 0 {
+			t.Fatalf("Expected chain id 1, got %d", chainID)
+		}
+
+		// The unreachable source is only read once its chain id is checked
+		if _, err := sources[1].HeaderByNumber(context.Background(), nil); !errors.Is(err, errDown) {
+			t.Fatalf("Expected the unreachable source to fail, got %v", err)
+		}
+		down.err = nil
+		if _, err := sources[1].HeaderByNumber(context.Background(), nil); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("unreachable source on another chain", func(t *testing.T) {
+		down := newMockL1Backend(5)
+		down.err = errDown
+		backends := []L1Backend{newMockL1Backend(1), newMockL1Backend(1), down}
+
+		_, sources, err := connectL1Sources(context.Background(), urls, backends, 2, nil, time.Millisecond)
+		if err != nil {
+			t.Fatal(err)
+		}
+		down.err = nil
+		if _, err := sources[2].HeaderByNumber(context.Background(), nil); !errors.Is(err, errWrongChainID) {
+			t.Fatalf("Expected %v, got %v", errWrongChainID, err)
+		}
+	})
+
+	t.Run("reachable sources on different chains", func(t *testing.T) {
+		backends := []L1Backend{newMockL1Backend(1), newMockL1Backend(5), newMockL1Backend(1)}
+
+		_, _, err := connectL1Sources(context.Background(), urls, backends, 2, nil, time.Millisecond)
+		if !errors.Is(err, errWrongChainID) {
+			t.Fatalf("Expected %v, got %v", errWrongChainID, err)
+		}
+	})
+
+	t.Run("configured chain id", func(t *testing.T) {
+		backends := []L1Backend{newMockL1Backend(1), newMockL1Backend(1), newMockL1Backend(1)}
+
+		_, _, err := connectL1Sources(context.Background(), urls, backends, 2, big.NewInt(5), time.Millisecond)
+		if !errors.Is(err, errWrongChainID) {
+			t.Fatalf("Expected %v, got %v", errWrongChainID, err)
+		}
+	})
+
+	t.Run("quorum unreachable", func(t *testing.T) {
+		down1, down2 := newMockL1Backend(1), newMockL1Backend(1)
+		down1.err, down2.err = errDown, errDown
+		backends := []L1Backend{newMockL1Backend(1), down1, down2}
+
+		_, _, err := connectL1Sources(context.Background(), urls, backends, 2, nil, time.Millisecond)
+		if !errors.Is(err, errNotEnoughL1Sources) {
+			t.Fatalf("Expected %v, got %v", errNotEnoughL1Sources, err)
+		}
+	})
+}
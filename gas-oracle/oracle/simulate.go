@@ -0,0 +1,103 @@
+package oracle
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum-optimism/optimism/gas-oracle/gasprices"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// Simulate replays the L2 blocks from startBlockNumber to endBlockNumber
+// through each gas price controller, and writes the price set by each of
+// them at the end of every epoch to w as CSV
+func Simulate(cfg *Config, startBlockNumber, endBlockNumber, initialGasPrice uint64, w io.Writer) error {
+	l2Client, err := ethclient.Dial(cfg.layerTwoHttpUrl)
+	if err != nil {
+		return err
+	}
+
+	log.Info("Loading epochs", "start", startBlockNumber, "end", endBlockNumber,
+		"epochLengthSeconds", cfg.epochLengthSeconds)
+
+	getHeader := wrapGetCachedHeader(l2Client)
+	epochs, err := gasprices.LoadEpochs(
+		startBlockNumber,
+		endBlockNumber,
+		cfg.epochLengthSeconds,
+		func(number *big.Int) (uint64, error) {
+			header, err := getHeader(number)
+			if err != nil {
+				return 0, err
+			}
+			return header.GasUsed, nil
+		},
+		func(number *big.Int) (uint64, error) {
+			header, err := getHeader(number)
+			if err != nil {
+				return 0, err
+			}
+			return header.Time, nil
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	prices := make([][]uint64, 0, len(gasprices.Controllers))
+	for _, name := range gasprices.Controllers {
+		controller, err := newGasPriceController(name, initialGasPrice, cfg)
+		if err != nil {
+			return fmt.Errorf("cannot create %s controller: %w", name, err)
+		}
+		controllerPrices, err := gasprices.Simulate(controller, epochs)
+		if err != nil {
+			return fmt.Errorf("cannot simulate %s controller: %w", name, err)
+		}
+		prices = append(prices, controllerPrices)
+	}
+
+	header := append([]string{"epoch", "blocks", "gas_used", "avg_gas_per_second"}, gasprices.Controllers...)
+	if _, err := fmt.Fprintln(w, strings.Join(header, ",")); err != nil {
+		return err
+	}
+	for i, epoch := range epochs {
+		row := []string{
+			fmt.Sprint(i),
+			fmt.Sprint(len(epoch.GasUsedByBlock)),
+			fmt.Sprint(epoch.TotalGasUsed()),
+			fmt.Sprintf("%.2f", epoch.AvgGasPerSecond()),
+		}
+		for _, controllerPrices := range prices {
+			row = append(row, fmt.Sprint(controllerPrices[i]))
+		}
+		if _, err := fmt.Fprintln(w, strings.Join(row, ",")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// wrapGetCachedHeader returns a function fetching headers by number, which
+// caches the last header so that consecutive lookups of the same block only
+// query the backend once
+func wrapGetCachedHeader(backend bind.ContractBackend) func(*big.Int) (*types.Header, error) {
+	var last *types.Header
+	return func(number *big.Int) (*types.Header, error) {
+		if last != nil && last.Number.Cmp(number) == 0 {
+			return last, nil
+		}
+		header, err := backend.HeaderByNumber(context.Background(), number)
+		if err != nil {
+			return nil, err
+		}
+		last = header
+		return header, nil
+	}
+}
@@ -0,0 +1,104 @@
+package oracle
+
+import (
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// PriceStatus compares the last price computed by the gas-oracle with the
+// price in the GasPriceOracle contract at that time
+type PriceStatus struct {
+	Computed *big.Int `json:"computed"`
+	OnChain  *big.Int `json:"on_chain"`
+	// Update is whether the computed price differs significantly enough
+	// from the on-chain price to send a transaction. In shadow mode the
+	// transaction is not actually sent.
+	Update    bool      `json:"update"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// StatusResponse is the body served by the status endpoint
+type StatusResponse struct {
+	ShadowMode bool         `json:"shadow_mode"`
+	L2GasPrice *PriceStatus `json:"l2_gas_price"`
+	L1BaseFee  *PriceStatus `json:"l1_base_fee"`
+}
+
+// Status keeps track of the prices computed by the update loops
+type Status struct {
+	mu         sync.RWMutex
+	shadowMode bool
+	l2GasPrice *PriceStatus
+	l1BaseFee  *PriceStatus
+}
+
+// NewStatus creates an empty Status
+func NewStatus(shadowMode bool) *Status {
+	return &Status{
+		shadowMode: shadowMode,
+	}
+}
+
+func (s *Status) setL2GasPrice(computed, onChain *big.Int, update bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.l2GasPrice = &PriceStatus{
+		Computed:  computed,
+		OnChain:   onChain,
+		Update:    update,
+		UpdatedAt: time.Now(),
+	}
+}
+
+func (s *Status) setL1BaseFee(computed, onChain *big.Int, update bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.l1BaseFee = &PriceStatus{
+		Computed:  computed,
+		OnChain:   onChain,
+		Update:    update,
+		UpdatedAt: time.Now(),
+	}
+}
+
+// Get returns the last computed prices. Prices are nil until their update
+// loop first ran.
+func (s *Status) Get() StatusResponse {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return StatusResponse{
+		ShadowMode: s.shadowMode,
+		L2GasPrice: s.l2GasPrice,
+		L1BaseFee:  s.l1BaseFee,
+	}
+}
+
+// ServeHTTP serves the last computed prices as JSON
+func (s *Status) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(s.Get()); err != nil {
+		log.Error("cannot write status", "message", err)
+	}
+}
+
+// SetupStatusServer starts a dedicated server for the status endpoint at the
+// given address
+func SetupStatusServer(address string, status *Status) {
+	m := http.NewServeMux()
+	m.Handle("/status", status)
+	log.Info("Starting status server", "addr", "http://"+address+"/status")
+	go func() {
+		if err := http.ListenAndServe(address, m); err != nil {
+			log.Error("Failure in running status server", "err", err)
+		}
+	}()
+}
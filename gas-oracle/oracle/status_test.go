@@ -0,0 +1,46 @@
+package oracle
+
+import (
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestStatusServeHTTP(t *testing.T) {
+	status := NewStatus(true)
+	status.setL1BaseFee(big.NewInt(30), big.NewInt(20), true)
+
+	rec := httptest.NewRecorder()
+	status.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/status", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("unexpected status code %d", rec.Code)
+	}
+
+	var res struct {
+		ShadowMode bool                       `json:"shadow_mode"`
+		L2GasPrice *PriceStatus               `json:"l2_gas_price"`
+		L1BaseFee  map[string]json.RawMessage `json:"l1_base_fee"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &res); err != nil {
+		t.Fatal(err)
+	}
+	if !res.ShadowMode {
+		t.Fatal("shadow mode not reported")
+	}
+	// The L2 gas price loop did not run yet
+	if res.L2GasPrice != nil {
+		t.Fatal("unexpected L2 gas price")
+	}
+	if string(res.L1BaseFee["computed"]) != "30" || string(res.L1BaseFee["on_chain"]) != "20" ||
+		string(res.L1BaseFee["update"]) != "true" {
+		t.Fatalf("unexpected L1 base fee %v", res.L1BaseFee)
+	}
+
+	rec = httptest.NewRecorder()
+	status.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/status", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("unexpected status code %d", rec.Code)
+	}
+}
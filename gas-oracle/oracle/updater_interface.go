@@ -22,6 +22,9 @@ var (
 	gasPriceGauge           = metrics.NewRegisteredGauge("gas_price", ometrics.DefaultRegistry)
 	txConfTimer             = metrics.NewRegisteredTimer("tx/confirmed", ometrics.DefaultRegistry)
 	txSendTimer             = metrics.NewRegisteredTimer("tx/send", ometrics.DefaultRegistry)
+	txShadowCounter         = metrics.NewRegisteredCounter("tx/shadow", ometrics.DefaultRegistry)
+	shadowGasPriceGauge     = metrics.NewRegisteredGauge("shadow/gas_price", ometrics.DefaultRegistry)
+	shadowL1BaseFeeGauge    = metrics.NewRegisteredGauge("shadow/l1_base_fee", ometrics.DefaultRegistry)
 )
 
 // getLatestBlockNumberFn is used by the GasPriceUpdater
@@ -62,26 +65,32 @@ type DeployContractBackend interface {
 // to update the L2 gas price
 // perhaps this should take an options struct along with the backend?
 // how can this continue to be decomposed?
-func wrapUpdateL2GasPriceFn(backend DeployContractBackend, cfg *Config) (func(uint64) error, error) {
-	if cfg.privateKey == nil {
-		return nil, errNoPrivateKey
-	}
-	if cfg.l2ChainID == nil {
-		return nil, errNoChainID
-	}
+// In shadow mode, the gas price that would have been set is only logged and
+// recorded in the status, and no transaction is sent.
+func wrapUpdateL2GasPriceFn(backend DeployContractBackend, cfg *Config, status *Status) (func(uint64) error, error) {
+	var opts *bind.TransactOpts
+	if !cfg.shadowMode {
+		if cfg.privateKey == nil {
+			return nil, errNoPrivateKey
+		}
+		if cfg.l2ChainID == nil {
+			return nil, errNoChainID
+		}
 
-	opts, err := bind.NewKeyedTransactorWithChainID(cfg.privateKey, cfg.l2ChainID)
-	if err != nil {
-		return nil, err
-	}
-	// Once https://github.com/ethereum/go-ethereum/pull/23062 is released
-	// then we can remove setting the context here
-	if opts.Context == nil {
-		opts.Context = context.Background()
+		var err error
+		opts, err = bind.NewKeyedTransactorWithChainID(cfg.privateKey, cfg.l2ChainID)
+		if err != nil {
+			return nil, err
+		}
+		// Once https://github.com/ethereum/go-ethereum/pull/23062 is released
+		// then we can remove setting the context here
+		if opts.Context == nil {
+			opts.Context = context.Background()
+		}
+		// Don't send the transaction using the `contract` so that we can inspect
+		// it beforehand
+		opts.NoSend = true
 	}
-	// Don't send the transaction using the `contract` so that we can inspect
-	// it beforehand
-	opts.NoSend = true
 
 	// Create a new contract bindings in scope of the updateL2GasPriceFn
 	// that is returned from this function
@@ -92,19 +101,6 @@ func wrapUpdateL2GasPriceFn(backend DeployContractBackend, cfg *Config) (func(ui
 
 	return func(updatedGasPrice uint64) error {
 		log.Trace("UpdateL2GasPriceFn", "gas-price", updatedGasPrice)
-		if cfg.gasPrice == nil {
-			// Set the gas price manually to use legacy transactions
-			gasPrice, err := backend.SuggestGasPrice(context.Background())
-			if err != nil {
-				log.Error("cannot fetch gas price", "message", err)
-				return err
-			}
-			log.Trace("fetched L2 tx.gasPrice", "gas-price", gasPrice)
-			opts.GasPrice = gasPrice
-		} else {
-			// Allow a configurable gas price to be set
-			opts.GasPrice = cfg.gasPrice
-		}
 
 		// Query the current L2 gas price
 		currentPrice, err := contract.GasPrice(&bind.CallOpts{
@@ -115,6 +111,22 @@ func wrapUpdateL2GasPriceFn(backend DeployContractBackend, cfg *Config) (func(ui
 			return err
 		}
 
+		// Only update the gas price when it must be changed by at least
+		// a paramaterizable amount.
+		significant := currentPrice.Uint64() != updatedGasPrice &&
+			isDifferenceSignificant(currentPrice.Uint64(), updatedGasPrice, cfg.l2GasPriceSignificanceFactor)
+		status.setL2GasPrice(new(big.Int).SetUint64(updatedGasPrice), currentPrice, significant)
+
+		if cfg.shadowMode {
+			log.Info("shadow L2 gas price", "current-price", currentPrice,
+				"next-price", updatedGasPrice, "would-update", significant)
+			shadowGasPriceGauge.Update(int64(updatedGasPrice))
+			if significant {
+				txShadowCounter.Inc(1)
+			}
+			return nil
+		}
+
 		// no need to update when they are the same
 		if currentPrice.Uint64() == updatedGasPrice {
 			log.Info("gas price did not change", "gas-price", updatedGasPrice)
@@ -122,15 +134,27 @@ func wrapUpdateL2GasPriceFn(backend DeployContractBackend, cfg *Config) (func(ui
 			return nil
 		}
 
-		// Only update the gas price when it must be changed by at least
-		// a paramaterizable amount.
-		if !isDifferenceSignificant(currentPrice.Uint64(), updatedGasPrice, cfg.l2GasPriceSignificanceFactor) {
+		if !significant {
 			log.Info("gas price did not significantly change", "min-factor", cfg.l2GasPriceSignificanceFactor,
 				"current-price", currentPrice, "next-price", updatedGasPrice)
 			txNotSignificantCounter.Inc(1)
 			return nil
 		}
 
+		if cfg.gasPrice == nil {
+			// Set the gas price manually to use legacy transactions
+			gasPrice, err := backend.SuggestGasPrice(context.Background())
+			if err != nil {
+				log.Error("cannot fetch gas price", "message", err)
+				return err
+			}
+			log.Trace("fetched L2 tx.gasPrice", "gas-price", gasPrice)
+			opts.GasPrice = gasPrice
+		} else {
+			// Allow a configurable gas price to be set
+			opts.GasPrice = cfg.gasPrice
+		}
+
 		// Set the gas price by sending a transaction
 		tx, err := contract.SetGasPrice(opts, new(big.Int).SetUint64(updatedGasPrice))
 		if err != nil {
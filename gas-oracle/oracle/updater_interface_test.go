@@ -69,7 +69,7 @@ func TestWrapUpdateL2GasPriceFn(t *testing.T) {
 		gasPrice:              big.NewInt(783460975),
 	}
 
-	updateL2GasPriceFn, err := wrapUpdateL2GasPriceFn(sim, cfg)
+	updateL2GasPriceFn, err := wrapUpdateL2GasPriceFn(sim, cfg, NewStatus(false))
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -110,7 +110,7 @@ func TestWrapUpdateL2GasPriceFnNoUpdates(t *testing.T) {
 		// the new gas price must change be 50% for it to actually update
 		l2GasPriceSignificanceFactor: 0.5,
 	}
-	updateL2GasPriceFn, err := wrapUpdateL2GasPriceFn(sim, cfg)
+	updateL2GasPriceFn, err := wrapUpdateL2GasPriceFn(sim, cfg, NewStatus(false))
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -167,6 +167,50 @@ func TestWrapUpdateL2GasPriceFnNoUpdates(t *testing.T) {
 	tryUpdate(1, true)
 }
 
+func TestWrapUpdateL2GasPriceFnShadowMode(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sim, _ := newSimulatedBackend(key)
+
+	opts, _ := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	addr, _, gpo, err := bindings.DeployGasPriceOracle(opts, sim, opts.From)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+
+	// No private key is needed in shadow mode
+	cfg := &Config{
+		gasPriceOracleAddress: addr,
+		shadowMode:            true,
+	}
+	status := NewStatus(cfg.shadowMode)
+	updateL2GasPriceFn, err := wrapUpdateL2GasPriceFn(sim, cfg, status)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := updateL2GasPriceFn(10); err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+
+	gasPrice, err := gpo.GasPrice(&bind.CallOpts{Context: context.Background()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gasPrice.Uint64() != 0 {
+		t.Fatalf("gas price updated in shadow mode, got %d", gasPrice)
+	}
+
+	l2GasPrice := status.Get().L2GasPrice
+	if l2GasPrice == nil {
+		t.Fatal("computed gas price not recorded")
+	}
+	if l2GasPrice.Computed.Uint64() != 10 || l2GasPrice.OnChain.Uint64() != 0 || !l2GasPrice.Update {
+		t.Fatalf("unexpected status %+v", l2GasPrice)
+	}
+}
+
 func TestIsDifferenceSignificant(t *testing.T) {
 	tests := []struct {
 		name   string
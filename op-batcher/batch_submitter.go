@@ -27,11 +27,10 @@ import (
 	"github.com/ethereum-optimism/optimism/op-batcher/sequencer"
 	"github.com/ethereum-optimism/optimism/op-node/eth"
 	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
-	"github.com/ethereum-optimism/optimism/op-proposer/txmgr"
+	"github.com/ethereum-optimism/optimism/op-service/txmgr"
 	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core"
-	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/log"
@@ -58,7 +57,10 @@ func Main(version string) func(cliCtx *cli.Context) error {
 		l := oplog.NewLogger(cfg.LogConfig)
 		l.Info("Initializing Batch Submitter")
 
-		batchSubmitter, err := NewBatchSubmitter(cfg, l)
+		registry := opmetrics.NewRegistry()
+		pricingMetrics := txmgr.NewPromPricingMetrics(registry, "op_batcher")
+
+		batchSubmitter, err := NewBatchSubmitter(cfg, l, pricingMetrics)
 		if err != nil {
 			l.Error("Unable to create Batch Submitter", "error", err)
 			return err
@@ -85,7 +87,6 @@ func Main(version string) func(cliCtx *cli.Context) error {
 			}()
 		}
 
-		registry := opmetrics.NewRegistry()
 		metricsCfg := cfg.MetricsConfig
 		if metricsCfg.Enabled {
 			l.Info("starting metrics server", "addr", metricsCfg.ListenAddr, "port", metricsCfg.ListenPort)
@@ -124,7 +125,7 @@ func Main(version string) func(cliCtx *cli.Context) error {
 // BatchSubmitter encapsulates a service responsible for submitting L2 tx
 // batches to L1 for availability.
 type BatchSubmitter struct {
-	txMgr txmgr.TxManager
+	txMgr *txmgr.Manager
 	cfg   sequencer.Config
 	wg    sync.WaitGroup
 	done  chan struct{}
@@ -136,11 +137,13 @@ type BatchSubmitter struct {
 	lastSubmittedBlock eth.BlockID
 
 	ch *derive.ChannelOut
+	// chUrgency is the inclusion window of the frames of the current channel.
+	chUrgency txmgr.Urgency
 }
 
 // NewBatchSubmitter initializes the BatchSubmitter, gathering any resources
 // that will be needed during operation.
-func NewBatchSubmitter(cfg Config, l log.Logger) (*BatchSubmitter, error) {
+func NewBatchSubmitter(cfg Config, l log.Logger, m txmgr.PricingMetrics) (*BatchSubmitter, error) {
 	ctx := context.Background()
 
 	var err error
@@ -240,11 +243,19 @@ func NewBatchSubmitter(cfg Config, l log.Logger) (*BatchSubmitter, error) {
 		PollInterval:      cfg.PollInterval,
 	}
 
+	txMgr := txmgr.NewManager(txmgr.ManagerConfig{
+		Config:      txManagerConfig,
+		ChainID:     chainID,
+		From:        addr,
+		Signer:      txmgr.PrivateKeySignerFn(sequencerPrivKey, chainID),
+		JournalPath: cfg.TxMgrConfig.JournalPath,
+	}, l1Client, txmgr.NewFeePricer(l, cfg.TxMgrConfig.Pricing, l1Client, m))
+
 	ctx, cancel := context.WithCancel(context.Background())
 
 	return &BatchSubmitter{
 		cfg:   batcherCfg,
-		txMgr: txmgr.NewSimpleTxManager("batcher", txManagerConfig, l1Client),
+		txMgr: txMgr,
 		done:  make(chan struct{}),
 		log:   l,
 		// TODO: this context only exists because the even loop doesn't reach done
@@ -255,6 +266,11 @@ func NewBatchSubmitter(cfg Config, l log.Logger) (*BatchSubmitter, error) {
 }
 
 func (l *BatchSubmitter) Start() error {
+	ctx, cancel := context.WithTimeout(l.ctx, time.Second*10)
+	defer cancel()
+	if err := l.txMgr.Start(ctx); err != nil {
+		return fmt.Errorf("unable to start tx manager: %w", err)
+	}
 	l.wg.Add(1)
 	go l.loop()
 	return nil
@@ -264,6 +280,7 @@ func (l *BatchSubmitter) Stop() {
 	l.cancel()
 	close(l.done)
 	l.wg.Wait()
+	l.txMgr.Close()
 }
 
 func (l *BatchSubmitter) loop() {
@@ -308,6 +325,13 @@ mainLoop:
 				continue
 			} else {
 				l.ch = ch
+				// Frames have to be included within the channel timeout,
+				// the fee urgency rises as the end of the timeout approaches.
+				now := time.Now()
+				l.chUrgency = txmgr.Urgency{
+					Start:    now,
+					Deadline: now.Add(time.Second * time.Duration(l.cfg.ChannelTimeout)),
+				}
 			}
 			prevID := l.lastSubmittedBlock
 			for i := l.lastSubmittedBlock.Number + 1; i <= syncStatus.UnsafeL2.Number; i++ {
@@ -348,40 +372,35 @@ mainLoop:
 					continue mainLoop
 				}
 
-				// Query for the submitter's current nonce.
-				walletAddr := crypto.PubkeyToAddress(l.cfg.PrivKey.PublicKey)
-				ctx, cancel = context.WithTimeout(l.ctx, time.Second*10)
-				nonce, err := l.cfg.L1Client.NonceAt(ctx, walletAddr, nil)
-				cancel()
+				candidate, err := l.CraftTx(data.Bytes())
 				if err != nil {
-					l.log.Error("unable to get current nonce", "err", err)
+					l.log.Error("unable to craft tx", "err", err)
 					continue mainLoop
 				}
 
-				// Create the transaction
-				ctx, cancel = context.WithTimeout(l.ctx, time.Second*10)
-				tx, err := l.CraftTx(ctx, data.Bytes(), nonce)
-				cancel()
+				// Wait until one of our submitted transactions confirms. The tx manager
+				// assigns the nonce, and replaces the transaction at a bumped gas price
+				// if it is not mined in time.
+				ctx, cancel = context.WithTimeout(l.ctx, time.Second*time.Duration(l.cfg.ChannelTimeout))
+				q, err := l.txMgr.Queue(ctx, candidate)
 				if err != nil {
-					l.log.Error("unable to craft tx", "err", err)
+					cancel()
+					l.log.Warn("unable to queue tx", "err", err)
 					continue mainLoop
 				}
-
-				// Construct the a closure that will update the txn with the current gas prices.
-				updateGasPrice := func(ctx context.Context) (*types.Transaction, error) {
-					l.log.Debug("updating batch tx gas price")
-					return l.UpdateGasPrice(ctx, tx)
-				}
-
-				// Wait until one of our submitted transactions confirms. If no
-				// receipt is received it's likely our gas price was too low.
-				// TODO: does the tx manager nicely replace the tx?
-				//  (submit a new one, that's within the channel timeout, but higher fee than previously submitted tx? Or use a cheap cancel tx?)
-				ctx, cancel = context.WithTimeout(l.ctx, time.Second*time.Duration(l.cfg.ChannelTimeout))
-				receipt, err := l.txMgr.Send(ctx, updateGasPrice, l.cfg.L1Client.SendTransaction)
+				receipt, err := q.Wait(ctx)
 				cancel()
-				if err != nil {
-					l.log.Warn("unable to publish tx", "err", err)
+				if err != nil && receipt == nil && !errors.Is(err, txmgr.ErrNonceUsed) {
+					// The tx is still pending in the tx manager: it timed out with the channel, or it cannot be
+					// replaced within the fee limits. Cancel it, so it does not keep being bumped, and does not
+					// hold up the nonces of the frames of the next channel.
+					l.log.Warn("unable to publish tx, canceling it", "nonce", q.Nonce(), "err", err)
+					if err := l.txMgr.Cancel(q.Nonce()); err != nil {
+						l.log.Warn("unable to cancel tx", "nonce", q.Nonce(), "err", err)
+					}
+					continue mainLoop
+				} else if err != nil {
+					l.log.Warn("unable to publish tx", "nonce", q.Nonce(), "err", err)
 					continue mainLoop
 				}
 
@@ -407,73 +426,19 @@ mainLoop:
 	}
 }
 
-// NOTE: This method SHOULD NOT publish the resulting transaction.
-func (l *BatchSubmitter) CraftTx(ctx context.Context, data []byte, nonce uint64) (*types.Transaction, error) {
-	gasTipCap, err := l.cfg.L1Client.SuggestGasTipCap(ctx)
-	if err != nil {
-		return nil, err
-	}
-
-	head, err := l.cfg.L1Client.HeaderByNumber(ctx, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	gasFeeCap := txmgr.CalcGasFeeCap(head.BaseFee, gasTipCap)
-
-	rawTx := &types.DynamicFeeTx{
-		ChainID:   l.cfg.ChainID,
-		Nonce:     nonce,
-		To:        &l.cfg.BatchInboxAddress,
-		GasTipCap: gasTipCap,
-		GasFeeCap: gasFeeCap,
-		Data:      data,
-	}
-	l.log.Debug("creating tx", "to", rawTx.To, "from", crypto.PubkeyToAddress(l.cfg.PrivKey.PublicKey))
-
-	gas, err := core.IntrinsicGas(rawTx.Data, nil, false, true, true)
-	if err != nil {
-		return nil, err
-	}
-	rawTx.Gas = gas
-
-	return types.SignNewTx(l.cfg.PrivKey, types.LatestSignerForChainID(l.cfg.ChainID), rawTx)
-}
-
-// UpdateGasPrice signs an otherwise identical txn to the one provided but with
-// updated gas prices sampled from the existing network conditions.
-//
-// NOTE: Thie method SHOULD NOT publish the resulting transaction.
-func (l *BatchSubmitter) UpdateGasPrice(ctx context.Context, tx *types.Transaction) (*types.Transaction, error) {
-	gasTipCap, err := l.cfg.L1Client.SuggestGasTipCap(ctx)
+// CraftTx creates the candidate of a batch tx with the given data,
+// priced by the tx manager with the urgency of the current channel.
+func (l *BatchSubmitter) CraftTx(data []byte) (txmgr.TxCandidate, error) {
+	gas, err := core.IntrinsicGas(data, nil, false, true, true)
 	if err != nil {
-		return nil, err
-	}
-
-	head, err := l.cfg.L1Client.HeaderByNumber(ctx, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	gasFeeCap := txmgr.CalcGasFeeCap(head.BaseFee, gasTipCap)
-
-	rawTx := &types.DynamicFeeTx{
-		ChainID:   l.cfg.ChainID,
-		Nonce:     tx.Nonce(),
-		To:        tx.To(),
-		GasTipCap: gasTipCap,
-		GasFeeCap: gasFeeCap,
-		Gas:       tx.Gas(),
-		Data:      tx.Data(),
+		return txmgr.TxCandidate{}, err
 	}
-
-	return types.SignNewTx(l.cfg.PrivKey, types.LatestSignerForChainID(l.cfg.ChainID), rawTx)
-}
-
-// SendTransaction injects a signed transaction into the pending pool for
-// execution.
-func (l *BatchSubmitter) SendTransaction(ctx context.Context, tx *types.Transaction) error {
-	return l.cfg.L1Client.SendTransaction(ctx, tx)
+	return txmgr.TxCandidate{
+		To:       &l.cfg.BatchInboxAddress,
+		TxData:   data,
+		GasLimit: gas,
+		Urgency:  l.chUrgency,
+	}, nil
 }
 
 // dialEthClientWithTimeout attempts to dial the L1 provider using the provided
@@ -10,6 +10,7 @@ import (
 	opmetrics "github.com/ethereum-optimism/optimism/op-service/metrics"
 	oppprof "github.com/ethereum-optimism/optimism/op-service/pprof"
 	oprpc "github.com/ethereum-optimism/optimism/op-service/rpc"
+	"github.com/ethereum-optimism/optimism/op-service/txmgr"
 )
 
 type Config struct {
@@ -71,6 +72,9 @@ type Config struct {
 
 	/* Optional Params */
 
+	// TxMgrConfig configures the journal and the fee limits of the tx manager.
+	TxMgrConfig txmgr.CLIConfig
+
 	LogConfig oplog.CLIConfig
 
 	MetricsConfig opmetrics.CLIConfig
@@ -91,6 +95,9 @@ func (c Config) Check() error {
 	if err := c.PprofConfig.Check(); err != nil {
 		return err
 	}
+	if err := c.TxMgrConfig.Check(); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -112,6 +119,7 @@ func NewConfig(ctx *cli.Context) Config {
 		SequencerHDPath:            ctx.GlobalString(flags.SequencerHDPathFlag.Name),
 		PrivateKey:                 ctx.GlobalString(flags.PrivateKeyFlag.Name),
 		SequencerBatchInboxAddress: ctx.GlobalString(flags.SequencerBatchInboxAddressFlag.Name),
+		TxMgrConfig:                txmgr.ReadCLIConfig(ctx),
 		RPCConfig:                  oprpc.ReadCLIConfig(ctx),
 		LogConfig:                  oplog.ReadCLIConfig(ctx),
 		MetricsConfig:              opmetrics.ReadCLIConfig(ctx),
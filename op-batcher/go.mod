@@ -4,7 +4,6 @@ go 1.18
 
 require (
 	github.com/ethereum-optimism/optimism/op-node v0.8.6
-	github.com/ethereum-optimism/optimism/op-proposer v0.8.6
 	github.com/ethereum-optimism/optimism/op-service v0.8.6
 	github.com/ethereum/go-ethereum v1.10.23
 	github.com/miguelmota/go-ethereum-hdwallet v0.1.1
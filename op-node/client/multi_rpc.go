@@ -0,0 +1,357 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/ethereum-optimism/optimism/op-node/metrics"
+)
+
+// ErrCrossCheckMismatch is returned when two endpoints disagree on the contents of the same block.
+var ErrCrossCheckMismatch = errors.New("endpoints returned conflicting block data")
+
+const defaultHeadPollInterval = 4 * time.Second
+
+type MultiRPCConfig struct {
+	// MaxHeadLag is the number of blocks an endpoint may fall behind the best head seen across all endpoints,
+	// before it is considered unhealthy. Lag detection is disabled if 0.
+	MaxHeadLag uint64
+	// HeadPollInterval is how often the head of each endpoint is checked. Defaults to 4 seconds if 0.
+	HeadPollInterval time.Duration
+	// CrossCheck enables verifying block hashes and receipt roots of blocks and headers
+	// fetched from one endpoint against those returned by another endpoint.
+	CrossCheck bool
+}
+
+type endpoint struct {
+	name string
+	rpc  RPC
+
+	// head is the latest block number reported by the endpoint
+	head uint64
+	// failed is set when the endpoint failed to serve a request, and cleared again when it serves one
+	failed bool
+}
+
+// MultiRPC is an RPC that spreads requests over multiple endpoints of the same chain.
+// Endpoints are used in order of priority: requests go to the first healthy endpoint,
+// and fail over to the next one when an endpoint fails to respond or lags too far behind.
+// Optionally responses with block data are cross-checked against a second endpoint.
+type MultiRPC struct {
+	log log.Logger
+	m   *metrics.Metrics
+	cfg MultiRPCConfig
+
+	mu        sync.Mutex
+	endpoints []*endpoint
+	bestHead  uint64
+	// active is the name of the endpoint that served the last successful request
+	active string
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+var _ RPC = (*MultiRPC)(nil)
+
+// NewMultiRPC creates a MultiRPC over the given endpoints, in order of priority.
+// Endpoint names are used in logs and metrics, and must not contain secrets such as API keys.
+// The head of each endpoint is polled in the background until the MultiRPC is closed.
+func NewMultiRPC(log log.Logger, m *metrics.Metrics, cfg MultiRPCConfig, names []string, rpcs []RPC) (*MultiRPC, error) {
+	if len(rpcs) == 0 {
+		return nil, errors.New("no endpoints")
+	}
+	if len(names) != len(rpcs) {
+		return nil, fmt.Errorf("got %d names for %d endpoints", len(names), len(rpcs))
+	}
+	if cfg.HeadPollInterval == 0 {
+		cfg.HeadPollInterval = defaultHeadPollInterval
+	}
+	endpoints := make([]*endpoint, len(rpcs))
+	for i, r := range rpcs {
+		endpoints[i] = &endpoint{name: names[i], rpc: r}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	mr := &MultiRPC{
+		log:       log,
+		m:         m,
+		cfg:       cfg,
+		endpoints: endpoints,
+		active:    names[0],
+		cancel:    cancel,
+	}
+	mr.wg.Add(1)
+	go mr.pollHeads(ctx)
+	return mr, nil
+}
+
+func (mr *MultiRPC) Close() {
+	mr.cancel()
+	mr.wg.Wait()
+	for _, ep := range mr.endpoints {
+		ep.rpc.Close()
+	}
+}
+
+func (mr *MultiRPC) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	var used *endpoint
+	var raw json.RawMessage
+	err := mr.try(ctx, func(ep *endpoint) error {
+		used = ep
+		return ep.rpc.CallContext(ctx, &raw, method, args...)
+	})
+	if err != nil {
+		return err
+	}
+	if mr.cfg.CrossCheck && crossCheckable(method, args) {
+		if err := mr.crossCheck(ctx, used, raw, method, args...); err != nil {
+			return err
+		}
+	}
+	if result == nil {
+		return nil
+	}
+	return json.Unmarshal(raw, result)
+}
+
+// BatchCallContext fails over the batch as a whole. Batch responses are not cross-checked.
+func (mr *MultiRPC) BatchCallContext(ctx context.Context, b []rpc.BatchElem) error {
+	return mr.try(ctx, func(ep *endpoint) error {
+		for i := range b {
+			b[i].Error = nil
+		}
+		return ep.rpc.BatchCallContext(ctx, b)
+	})
+}
+
+// EthSubscribe subscribes with the first healthy endpoint.
+// Subscriptions do not fail over: the caller is expected to resubscribe when the subscription errors.
+func (mr *MultiRPC) EthSubscribe(ctx context.Context, channel interface{}, args ...interface{}) (*rpc.ClientSubscription, error) {
+	var sub *rpc.ClientSubscription
+	err := mr.try(ctx, func(ep *endpoint) (err error) {
+		sub, err = ep.rpc.EthSubscribe(ctx, channel, args...)
+		return err
+	})
+	return sub, err
+}
+
+// try runs fn against the endpoints in order of preference, until one serves it without an endpoint error.
+func (mr *MultiRPC) try(ctx context.Context, fn func(ep *endpoint) error) error {
+	var err error
+	for _, ep := range mr.candidates() {
+		err = fn(ep)
+		if err == nil || !isEndpointError(err) {
+			mr.markServed(ep)
+			return err
+		}
+		if ctx.Err() != nil {
+			return err
+		}
+		mr.markFailed(ep, err)
+	}
+	return err
+}
+
+// candidates returns the healthy endpoints in order of priority,
+// followed by the unhealthy endpoints as a last resort.
+func (mr *MultiRPC) candidates() []*endpoint {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+	out := make([]*endpoint, 0, len(mr.endpoints))
+	for _, ep := range mr.endpoints {
+		if mr.healthy(ep) {
+			out = append(out, ep)
+		}
+	}
+	for _, ep := range mr.endpoints {
+		if !mr.healthy(ep) {
+			out = append(out, ep)
+		}
+	}
+	return out
+}
+
+// healthy returns whether the endpoint is responsive and keeping up. mr.mu must be held.
+func (mr *MultiRPC) healthy(ep *endpoint) bool {
+	if ep.failed {
+		return false
+	}
+	return mr.cfg.MaxHeadLag == 0 || ep.head+mr.cfg.MaxHeadLag >= mr.bestHead
+}
+
+func (mr *MultiRPC) markServed(ep *endpoint) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+	ep.failed = false
+	if mr.active != ep.name {
+		mr.log.Warn("failed over to other L1 endpoint", "from", mr.active, "to", ep.name)
+		mr.m.RecordL1EndpointFailover(mr.active, ep.name)
+		mr.active = ep.name
+	}
+	mr.m.RecordL1EndpointHealth(ep.name, mr.healthy(ep), ep.head)
+}
+
+func (mr *MultiRPC) markFailed(ep *endpoint, err error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+	mr.log.Warn("L1 endpoint failed to serve request", "endpoint", ep.name, "err", err)
+	ep.failed = true
+	mr.m.RecordL1EndpointError(ep.name)
+	mr.m.RecordL1EndpointHealth(ep.name, false, ep.head)
+}
+
+func (mr *MultiRPC) pollHeads(ctx context.Context) {
+	defer mr.wg.Done()
+	ticker := time.NewTicker(mr.cfg.HeadPollInterval)
+	defer ticker.Stop()
+	for {
+		mr.updateHeads(ctx)
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// updateHeads fetches the head of every endpoint, and updates the health of the endpoints accordingly.
+func (mr *MultiRPC) updateHeads(ctx context.Context) {
+	type headResult struct {
+		head uint64
+		err  error
+	}
+	results := make([]headResult, len(mr.endpoints))
+	var wg sync.WaitGroup
+	for i, ep := range mr.endpoints {
+		wg.Add(1)
+		go func(i int, ep *endpoint) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(ctx, mr.cfg.HeadPollInterval)
+			defer cancel()
+			var head hexutil.Uint64
+			err := ep.rpc.CallContext(ctx, &head, "eth_blockNumber")
+			results[i] = headResult{head: uint64(head), err: err}
+		}(i, ep)
+	}
+	wg.Wait()
+	if ctx.Err() != nil {
+		return
+	}
+
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+	for i, ep := range mr.endpoints {
+		if err := results[i].err; err != nil {
+			if !ep.failed {
+				mr.log.Warn("failed to fetch head of L1 endpoint", "endpoint", ep.name, "err", err)
+			}
+			ep.failed = true
+			mr.m.RecordL1EndpointError(ep.name)
+			continue
+		}
+		ep.failed = false
+		ep.head = results[i].head
+		if ep.head > mr.bestHead {
+			mr.bestHead = ep.head
+		}
+	}
+	for _, ep := range mr.endpoints {
+		healthy := mr.healthy(ep)
+		if !healthy && !ep.failed {
+			mr.log.Warn("L1 endpoint is lagging behind", "endpoint", ep.name, "head", ep.head, "best", mr.bestHead)
+		}
+		mr.m.RecordL1EndpointHealth(ep.name, healthy, ep.head)
+	}
+}
+
+// crossCheckable returns whether the request is for a specific block, which all endpoints must agree on.
+// Requests for labeled blocks, like "latest", may legitimately differ between endpoints and are not checked.
+func crossCheckable(method string, args []interface{}) bool {
+	switch method {
+	case "eth_getBlockByHash":
+		return true
+	case "eth_getBlockByNumber":
+		if len(args) == 0 {
+			return false
+		}
+		num, ok := args[0].(string)
+		return ok && strings.HasPrefix(num, "0x")
+	default:
+		return false
+	}
+}
+
+type crossCheckFields struct {
+	Hash         *common.Hash `json:"hash"`
+	ReceiptsRoot *common.Hash `json:"receiptsRoot"`
+}
+
+// crossCheck repeats the request on another healthy endpoint than the one that served it,
+// and verifies the block hash and receipts root of both responses match.
+// The check is skipped if no other endpoint is available, or if it cannot serve the block.
+func (mr *MultiRPC) crossCheck(ctx context.Context, used *endpoint, raw json.RawMessage, method string, args ...interface{}) error {
+	var checker *endpoint
+	mr.mu.Lock()
+	for _, ep := range mr.endpoints {
+		if ep != used && mr.healthy(ep) {
+			checker = ep
+			break
+		}
+	}
+	mr.mu.Unlock()
+	if checker == nil || isNull(raw) {
+		return nil
+	}
+	var other json.RawMessage
+	if err := checker.rpc.CallContext(ctx, &other, method, args...); err != nil {
+		mr.log.Debug("unable to cross-check response", "method", method, "endpoint", checker.name, "err", err)
+		return nil
+	}
+	if isNull(other) {
+		return nil
+	}
+	var a, b crossCheckFields
+	if err := json.Unmarshal(raw, &a); err != nil {
+		return fmt.Errorf("failed to decode %s response of %s for cross-check: %w", method, used.name, err)
+	}
+	if err := json.Unmarshal(other, &b); err != nil {
+		return fmt.Errorf("failed to decode %s response of %s for cross-check: %w", method, checker.name, err)
+	}
+	if !equalHash(a.Hash, b.Hash) || !equalHash(a.ReceiptsRoot, b.ReceiptsRoot) {
+		mr.log.Error("L1 endpoints disagree on block data", "method", method,
+			"endpoint", used.name, "hash", a.Hash, "receipts_root", a.ReceiptsRoot,
+			"other", checker.name, "other_hash", b.Hash, "other_receipts_root", b.ReceiptsRoot)
+		mr.m.RecordL1CrossCheckMismatch(method)
+		return fmt.Errorf("%w: %s and %s differ on %s response", ErrCrossCheckMismatch, used.name, checker.name, method)
+	}
+	return nil
+}
+
+func isNull(raw json.RawMessage) bool {
+	return len(raw) == 0 || bytes.Equal(raw, []byte("null"))
+}
+
+func equalHash(a, b *common.Hash) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// isEndpointError returns whether the error is likely caused by the endpoint, rather than by the request.
+// JSON-RPC error responses are attributed to the request: the endpoint is alive and responded.
+func isEndpointError(err error) bool {
+	var rpcErr rpc.Error
+	return !errors.As(err, &rpcErr)
+}
@@ -0,0 +1,187 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-node/metrics"
+	"github.com/ethereum-optimism/optimism/op-node/testlog"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+type rpcError struct{}
+
+func (rpcError) Error() string  { return "execution reverted" }
+func (rpcError) ErrorCode() int { return 3 }
+
+// fakeRPC serves a fixed head and fixed responses per method, and counts the calls per method.
+type fakeRPC struct {
+	mu        sync.Mutex
+	head      uint64
+	err       error
+	responses map[string]interface{}
+	calls     map[string]int
+}
+
+func newFakeRPC(head uint64) *fakeRPC {
+	return &fakeRPC{head: head, responses: make(map[string]interface{}), calls: make(map[string]int)}
+}
+
+func (f *fakeRPC) Close() {}
+
+func (f *fakeRPC) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls[method]++
+	if f.err != nil {
+		return f.err
+	}
+	var res interface{}
+	if method == "eth_blockNumber" {
+		res = hexutil.Uint64(f.head)
+	} else {
+		res = f.responses[method]
+	}
+	data, err := json.Marshal(res)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, result)
+}
+
+func (f *fakeRPC) BatchCallContext(ctx context.Context, b []rpc.BatchElem) error {
+	for i := range b {
+		b[i].Error = f.CallContext(ctx, b[i].Result, b[i].Method, b[i].Args...)
+	}
+	return nil
+}
+
+func (f *fakeRPC) EthSubscribe(ctx context.Context, channel interface{}, args ...interface{}) (*rpc.ClientSubscription, error) {
+	return nil, errors.New("not supported")
+}
+
+func (f *fakeRPC) Calls(method string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls[method]
+}
+
+func (f *fakeRPC) Set(head uint64, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.head = head
+	f.err = err
+}
+
+func newTestMultiRPC(t *testing.T, cfg MultiRPCConfig, rpcs ...*fakeRPC) *MultiRPC {
+	names := make([]string, len(rpcs))
+	endpoints := make([]RPC, len(rpcs))
+	for i, r := range rpcs {
+		names[i] = string(rune('a' + i))
+		endpoints[i] = r
+	}
+	cfg.HeadPollInterval = time.Hour // heads are updated manually by the tests
+	mr, err := NewMultiRPC(testlog.Logger(t, log.LvlError), metrics.NewMetrics(""), cfg, names, endpoints)
+	require.NoError(t, err)
+	t.Cleanup(mr.Close)
+	// wait for the initial head poll to complete
+	require.Eventually(t, func() bool {
+		for _, r := range rpcs {
+			if r.Calls("eth_blockNumber") == 0 {
+				return false
+			}
+		}
+		return true
+	}, time.Second, time.Millisecond)
+	mr.updateHeads(context.Background())
+	return mr
+}
+
+func TestMultiRPCFailover(t *testing.T) {
+	primary, fallback := newFakeRPC(100), newFakeRPC(100)
+	primary.responses["eth_chainId"] = hexutil.Uint64(1)
+	fallback.responses["eth_chainId"] = hexutil.Uint64(1)
+	mr := newTestMultiRPC(t, MultiRPCConfig{}, primary, fallback)
+
+	var id hexutil.Uint64
+	require.NoError(t, mr.CallContext(context.Background(), &id, "eth_chainId"))
+	require.Equal(t, 1, primary.Calls("eth_chainId"))
+	require.Equal(t, 0, fallback.Calls("eth_chainId"))
+
+	// transport errors fail over to the fallback
+	primary.Set(100, errors.New("connection refused"))
+	require.NoError(t, mr.CallContext(context.Background(), &id, "eth_chainId"))
+	require.Equal(t, 2, primary.Calls("eth_chainId"))
+	require.Equal(t, 1, fallback.Calls("eth_chainId"))
+
+	// the failed primary is not tried first anymore
+	require.NoError(t, mr.CallContext(context.Background(), &id, "eth_chainId"))
+	require.Equal(t, 2, primary.Calls("eth_chainId"))
+	require.Equal(t, 2, fallback.Calls("eth_chainId"))
+
+	// once the primary recovers, it is preferred again
+	primary.Set(100, nil)
+	mr.updateHeads(context.Background())
+	require.NoError(t, mr.CallContext(context.Background(), &id, "eth_chainId"))
+	require.Equal(t, 3, primary.Calls("eth_chainId"))
+	require.Equal(t, 2, fallback.Calls("eth_chainId"))
+
+	// JSON-RPC error responses are returned as-is, without failing over
+	primary.Set(100, rpcError{})
+	require.ErrorIs(t, mr.CallContext(context.Background(), &id, "eth_chainId"), rpcError{})
+	require.Equal(t, 2, fallback.Calls("eth_chainId"))
+}
+
+func TestMultiRPCLag(t *testing.T) {
+	primary, fallback := newFakeRPC(90), newFakeRPC(100)
+	primary.responses["eth_chainId"] = hexutil.Uint64(1)
+	fallback.responses["eth_chainId"] = hexutil.Uint64(1)
+	mr := newTestMultiRPC(t, MultiRPCConfig{MaxHeadLag: 5}, primary, fallback)
+
+	var id hexutil.Uint64
+	require.NoError(t, mr.CallContext(context.Background(), &id, "eth_chainId"))
+	require.Equal(t, 0, primary.Calls("eth_chainId"), "lagging primary is skipped")
+	require.Equal(t, 1, fallback.Calls("eth_chainId"))
+
+	primary.Set(96, nil)
+	mr.updateHeads(context.Background())
+	require.NoError(t, mr.CallContext(context.Background(), &id, "eth_chainId"))
+	require.Equal(t, 1, primary.Calls("eth_chainId"), "primary is within max lag again")
+}
+
+func TestMultiRPCCrossCheck(t *testing.T) {
+	block := map[string]interface{}{
+		"hash":         common.Hash{1},
+		"receiptsRoot": common.Hash{2},
+	}
+	primary, fallback := newFakeRPC(100), newFakeRPC(100)
+	primary.responses["eth_getBlockByHash"] = block
+	fallback.responses["eth_getBlockByHash"] = block
+	mr := newTestMultiRPC(t, MultiRPCConfig{CrossCheck: true}, primary, fallback)
+
+	var out map[string]interface{}
+	require.NoError(t, mr.CallContext(context.Background(), &out, "eth_getBlockByHash", common.Hash{1}, false))
+	require.Equal(t, common.Hash{1}.String(), out["hash"])
+	require.Equal(t, 1, fallback.Calls("eth_getBlockByHash"), "response is cross-checked")
+
+	fallback.responses["eth_getBlockByHash"] = map[string]interface{}{
+		"hash":         common.Hash{1},
+		"receiptsRoot": common.Hash{3},
+	}
+	err := mr.CallContext(context.Background(), &out, "eth_getBlockByHash", common.Hash{1}, false)
+	require.ErrorIs(t, err, ErrCrossCheckMismatch)
+
+	// labeled blocks may differ between endpoints, and are not cross-checked
+	primary.responses["eth_getBlockByNumber"] = block
+	require.NoError(t, mr.CallContext(context.Background(), &out, "eth_getBlockByNumber", "latest", false))
+	require.Equal(t, 0, fallback.Calls("eth_getBlockByNumber"))
+}
@@ -0,0 +1,111 @@
+package batchdecoder
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/urfave/cli"
+
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	"github.com/ethereum-optimism/optimism/op-node/sources"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+var Subcommands = cli.Commands{
+	{
+		Name:  "decode",
+		Usage: "Scan a range of L1 blocks for batcher transactions, and print the channels and batches they carry as JSON",
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "l1",
+				Usage: "Address of L1 User JSON-RPC endpoint to use (eth namespace required)",
+			},
+			cli.BoolFlag{
+				Name:  "l1.trustrpc",
+				Usage: "Trust the L1 RPC, fetch faster at risk of malicious/buggy RPC providing bad or inconsistent L1 data",
+			},
+			cli.StringFlag{
+				Name:  "rollup.config",
+				Usage: "Rollup chain parameters",
+			},
+			cli.Uint64Flag{
+				Name:  "l1.start",
+				Usage: "Number of the first L1 block to scan",
+			},
+			cli.Uint64Flag{
+				Name:  "l1.end",
+				Usage: "Number of the last L1 block to scan",
+			},
+			cli.StringFlag{
+				Name:  "l1.disk-cache",
+				Usage: "Directory to persist fetched L1 blocks in, so that scanning the same blocks again only fetches their headers. Disabled if empty.",
+			},
+			cli.Uint64Flag{
+				Name:  "l1.disk-cache-size",
+				Usage: "Maximum size of the L1 disk cache in MiB, the oldest cached blocks are evicted first",
+				Value: 1024,
+			},
+			cli.StringFlag{
+				Name:  "log.level",
+				Usage: "The lowest log level that will be output to stderr",
+				Value: "warn",
+			},
+		},
+		Action: func(ctx *cli.Context) error {
+			lvl, err := log.LvlFromString(ctx.String("log.level"))
+			if err != nil {
+				return err
+			}
+			// The report is written to stdout, so log to stderr.
+			logger := log.New()
+			logger.SetHandler(log.LvlFilterHandler(lvl, log.StreamHandler(os.Stderr, log.TerminalFormat(false))))
+
+			cfg, err := loadRollupConfig(ctx.String("rollup.config"))
+			if err != nil {
+				return err
+			}
+			if !ctx.IsSet("l1.start") || !ctx.IsSet("l1.end") {
+				return errors.New("missing L1 block range")
+			}
+
+			rpcClient, err := rpc.DialContext(context.Background(), ctx.String("l1"))
+			if err != nil {
+				return fmt.Errorf("failed to dial L1 address: %w", err)
+			}
+			l1Cfg := sources.L1ClientDefaultConfig(cfg, ctx.Bool("l1.trustrpc"))
+			l1Cfg.DiskCachePath = ctx.String("l1.disk-cache")
+			l1Cfg.DiskCacheSize = ctx.Uint64("l1.disk-cache-size") * 1024 * 1024
+			l1, err := sources.NewL1Client(rpcClient, logger, nil, l1Cfg)
+			if err != nil {
+				return fmt.Errorf("failed to create L1 source: %w", err)
+			}
+			defer l1.Close()
+
+			report, err := Decode(context.Background(), logger, cfg, l1, ctx.Uint64("l1.start"), ctx.Uint64("l1.end"))
+			if err != nil {
+				return err
+			}
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			return enc.Encode(report)
+		},
+	},
+}
+
+func loadRollupConfig(path string) (*rollup.Config, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rollup config: %w", err)
+	}
+	defer file.Close()
+
+	var cfg rollup.Config
+	if err := json.NewDecoder(file).Decode(&cfg); err != nil {
+		return nil, fmt.Errorf("failed to decode rollup config: %w", err)
+	}
+	return &cfg, cfg.Check()
+}
@@ -0,0 +1,261 @@
+package batchdecoder
+
+import (
+	"compress/zlib"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/ethereum-optimism/optimism/op-node/eth"
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// maxUncompressedSize bounds the decompressed size of a channel, like the RLP reader of the derivation pipeline.
+const maxUncompressedSize = 10_000_000
+
+// L1Source fetches the L1 blocks to scan. Blocks are looked up by number, and then fetched by hash so that their
+// transactions can be served from the disk cache of the L1 client.
+type L1Source interface {
+	InfoByNumber(ctx context.Context, number uint64) (eth.BlockInfo, error)
+	InfoAndTxsByHash(ctx context.Context, hash common.Hash) (eth.BlockInfo, types.Transactions, error)
+}
+
+// Report describes the batcher data posted in a range of L1 blocks.
+type Report struct {
+	L1Start uint64 `json:"l1_start"`
+	L1End   uint64 `json:"l1_end"`
+	// InboxTxs is the number of transactions sent to the batch inbox by the batch sender.
+	InboxTxs int `json:"inbox_txs"`
+	// InvalidTxs are the transactions to the batch inbox that the derivation pipeline ignores.
+	InvalidTxs []*InvalidTx     `json:"invalid_txs"`
+	Channels   []*ChannelReport `json:"channels"`
+}
+
+type InvalidTx struct {
+	TxHash  common.Hash `json:"tx_hash"`
+	L1Block uint64      `json:"l1_block"`
+	Reason  string      `json:"reason"`
+}
+
+type FrameReport struct {
+	TxHash      common.Hash `json:"tx_hash"`
+	L1Block     uint64      `json:"l1_block"`
+	FrameNumber uint16      `json:"frame_number"`
+	Size        int         `json:"size"`
+	IsLast      bool        `json:"is_last"`
+	// Error is set if the frame could not be added to its channel, e.g. because it is a duplicate.
+	Error string `json:"error,omitempty"`
+}
+
+type BatchReport struct {
+	// L2Block is the number of the L2 block derived from the batch.
+	L2Block      uint64        `json:"l2_block"`
+	Timestamp    uint64        `json:"timestamp"`
+	ParentHash   common.Hash   `json:"parent_hash"`
+	Epoch        eth.BlockID   `json:"epoch"`
+	TxCount      int           `json:"tx_count"`
+	Transactions []common.Hash `json:"transactions"`
+}
+
+// ChannelReport describes a channel, from the L1 blocks its frames were included in to the L2 blocks its batches
+// cover.
+type ChannelReport struct {
+	ID derive.ChannelID `json:"id"`
+	// Ready is true if all the frames of the channel were found in the scanned L1 blocks.
+	Ready bool `json:"ready"`
+	// TimedOut is true if the last frame of the channel was included after the channel timeout.
+	TimedOut bool           `json:"timed_out"`
+	Frames   []*FrameReport `json:"frames"`
+
+	OpenL1Block  eth.BlockID `json:"open_l1_block"`
+	OpenL1Time   uint64      `json:"open_l1_time"`
+	CloseL1Block eth.BlockID `json:"close_l1_block"`
+	CloseL1Time  uint64      `json:"close_l1_time"`
+
+	CompressedSize   uint64  `json:"compressed_size"`
+	UncompressedSize uint64  `json:"uncompressed_size"`
+	CompressionRatio float64 `json:"compression_ratio"`
+
+	Batches []*BatchReport `json:"batches"`
+	// L2Start and L2End are the first and last L2 blocks covered by the batches of the channel.
+	L2Start uint64 `json:"l2_start"`
+	L2End   uint64 `json:"l2_end"`
+	// SubmissionDelay is the time in seconds between the last L2 block of the channel and the inclusion of the last
+	// frame on L1.
+	SubmissionDelay int64 `json:"submission_delay"`
+
+	// DecodeError is set if the channel data could not be decompressed or decoded.
+	DecodeError string `json:"decode_error,omitempty"`
+}
+
+type channel struct {
+	ch     *derive.Channel
+	report *ChannelReport
+}
+
+// Decode scans the L1 blocks from start to end, inclusive, for batcher transactions, and reassembles and decodes
+// the channels they carry. Channels are reported in the order they were opened.
+func Decode(ctx context.Context, logger log.Logger, cfg *rollup.Config, l1 L1Source, start, end uint64) (*Report, error) {
+	if end < start {
+		return nil, fmt.Errorf("end block %d is before start block %d", end, start)
+	}
+	report := &Report{L1Start: start, L1End: end, InvalidTxs: []*InvalidTx{}, Channels: []*ChannelReport{}}
+	channels := make(map[derive.ChannelID]*channel)
+	signer := cfg.L1Signer()
+
+	for num := start; num <= end; num++ {
+		info, err := l1.InfoByNumber(ctx, num)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch L1 block %d: %w", num, err)
+		}
+		_, txs, err := l1.InfoAndTxsByHash(ctx, info.Hash())
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch transactions of L1 block %d: %w", num, err)
+		}
+		ref := eth.L1BlockRef{Hash: info.Hash(), Number: info.NumberU64(), ParentHash: info.ParentHash(), Time: info.Time()}
+
+		for _, tx := range txs {
+			if to := tx.To(); to == nil || *to != cfg.BatchInboxAddress {
+				continue
+			}
+			invalid := func(reason string) {
+				report.InvalidTxs = append(report.InvalidTxs, &InvalidTx{TxHash: tx.Hash(), L1Block: num, Reason: reason})
+			}
+			sender, err := signer.Sender(tx)
+			if err != nil {
+				invalid(fmt.Sprintf("invalid signature: %v", err))
+				continue
+			}
+			if sender != cfg.BatchSenderAddress {
+				invalid(fmt.Sprintf("unauthorized sender %s", sender))
+				continue
+			}
+			report.InboxTxs++
+
+			frames, err := derive.ParseFrames(tx.Data())
+			if err != nil {
+				invalid(fmt.Sprintf("invalid frames: %v", err))
+				continue
+			}
+			for _, frame := range frames {
+				c, ok := channels[frame.ID]
+				if !ok {
+					c = &channel{
+						ch: derive.NewChannel(frame.ID, ref),
+						report: &ChannelReport{
+							ID:          frame.ID,
+							Frames:      []*FrameReport{},
+							Batches:     []*BatchReport{},
+							OpenL1Block: ref.ID(),
+							OpenL1Time:  ref.Time,
+						},
+					}
+					channels[frame.ID] = c
+					report.Channels = append(report.Channels, c.report)
+				}
+				frameReport := &FrameReport{
+					TxHash:      tx.Hash(),
+					L1Block:     num,
+					FrameNumber: frame.FrameNumber,
+					Size:        len(frame.Data),
+					IsLast:      frame.IsLast,
+				}
+				c.report.Frames = append(c.report.Frames, frameReport)
+				if c.report.Ready {
+					frameReport.Error = "channel is already complete"
+					continue
+				}
+				if err := c.ch.AddFrame(frame, ref); err != nil {
+					frameReport.Error = err.Error()
+					continue
+				}
+				c.report.CompressedSize += uint64(len(frame.Data))
+				c.report.CloseL1Block = ref.ID()
+				c.report.CloseL1Time = ref.Time
+				if c.ch.IsReady() {
+					c.report.Ready = true
+					c.report.TimedOut = num > c.ch.OpenBlockNumber()+cfg.ChannelTimeout
+					decodeChannel(cfg, c.ch, ref, c.report)
+				}
+			}
+		}
+	}
+
+	for _, c := range report.Channels {
+		if !c.Ready {
+			logger.Warn("incomplete channel", "id", c.ID, "frames", len(c.Frames), "open_l1_block", c.OpenL1Block)
+		} else if c.DecodeError != "" {
+			logger.Warn("failed to decode channel", "id", c.ID, "err", c.DecodeError)
+		}
+	}
+	return report, nil
+}
+
+// decodeChannel decompresses and decodes the batches of a ready channel into its report.
+func decodeChannel(cfg *rollup.Config, ch *derive.Channel, closeBlock eth.L1BlockRef, report *ChannelReport) {
+	zr, err := zlib.NewReader(ch.Reader())
+	if err != nil {
+		report.DecodeError = fmt.Sprintf("failed to decompress: %v", err)
+		return
+	}
+	n, err := io.Copy(io.Discard, io.LimitReader(zr, maxUncompressedSize))
+	report.UncompressedSize = uint64(n)
+	if err != nil {
+		report.DecodeError = fmt.Sprintf("failed to decompress: %v", err)
+		return
+	}
+	if report.CompressedSize > 0 {
+		report.CompressionRatio = float64(report.UncompressedSize) / float64(report.CompressedSize)
+	}
+
+	next, err := derive.BatchReader(ch.Reader(), closeBlock)
+	if err != nil {
+		report.DecodeError = fmt.Sprintf("failed to read batches: %v", err)
+		return
+	}
+	for {
+		batch, err := next()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			report.DecodeError = fmt.Sprintf("failed to decode batch %d: %v", len(report.Batches), err)
+			return
+		}
+		report.Batches = append(report.Batches, newBatchReport(cfg, batch.Batch))
+	}
+
+	if len(report.Batches) > 0 {
+		first, last := report.Batches[0], report.Batches[len(report.Batches)-1]
+		report.L2Start = first.L2Block
+		report.L2End = last.L2Block
+		report.SubmissionDelay = int64(report.CloseL1Time) - int64(last.Timestamp)
+	}
+}
+
+func newBatchReport(cfg *rollup.Config, batch *derive.BatchData) *BatchReport {
+	report := &BatchReport{
+		Timestamp:    batch.Timestamp,
+		ParentHash:   batch.ParentHash,
+		Epoch:        batch.Epoch(),
+		TxCount:      len(batch.Transactions),
+		Transactions: make([]common.Hash, 0, len(batch.Transactions)),
+	}
+	if batch.Timestamp >= cfg.Genesis.L2Time {
+		report.L2Block = cfg.Genesis.L2.Number + (batch.Timestamp-cfg.Genesis.L2Time)/cfg.BlockTime
+	}
+	for _, data := range batch.Transactions {
+		var tx types.Transaction
+		if err := tx.UnmarshalBinary(data); err != nil {
+			// keep the position of undecodable transactions
+			report.Transactions = append(report.Transactions, common.Hash{})
+			continue
+		}
+		report.Transactions = append(report.Transactions, tx.Hash())
+	}
+	return report
+}
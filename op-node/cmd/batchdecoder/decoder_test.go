@@ -0,0 +1,208 @@
+package batchdecoder
+
+import (
+	"bytes"
+	"compress/zlib"
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-node/eth"
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
+	"github.com/ethereum-optimism/optimism/op-node/testlog"
+	"github.com/ethereum-optimism/optimism/op-node/testutils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+type testL1 struct {
+	infos map[uint64]*testutils.MockBlockInfo
+	txs   map[common.Hash]types.Transactions
+}
+
+func (l *testL1) InfoByNumber(ctx context.Context, number uint64) (eth.BlockInfo, error) {
+	info, ok := l.infos[number]
+	if !ok {
+		return nil, fmt.Errorf("unknown block %d", number)
+	}
+	return info, nil
+}
+
+func (l *testL1) InfoAndTxsByHash(ctx context.Context, hash common.Hash) (eth.BlockInfo, types.Transactions, error) {
+	for _, info := range l.infos {
+		if info.InfoHash == hash {
+			return info, l.txs[hash], nil
+		}
+	}
+	return nil, nil, fmt.Errorf("unknown block %s", hash)
+}
+
+type decoderTest struct {
+	t      *testing.T
+	cfg    *rollup.Config
+	key    *ecdsa.PrivateKey
+	nonce  uint64
+	blocks [][]*types.Transaction
+}
+
+func newDecoderTest(t *testing.T, l1Blocks int) *decoderTest {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	cfg := &rollup.Config{
+		Genesis: rollup.Genesis{
+			L2:     eth.BlockID{Number: 100},
+			L2Time: 1000,
+		},
+		BlockTime:          2,
+		ChannelTimeout:     2,
+		L1ChainID:          big.NewInt(900),
+		BatchInboxAddress:  common.Address{0x03},
+		BatchSenderAddress: crypto.PubkeyToAddress(key.PublicKey),
+	}
+	return &decoderTest{t: t, cfg: cfg, key: key, blocks: make([][]*types.Transaction, l1Blocks)}
+}
+
+// addTx includes a transaction with the given data to the batch inbox in an L1 block.
+func (d *decoderTest) addTx(block int, key *ecdsa.PrivateKey, data []byte) *types.Transaction {
+	tx, err := types.SignNewTx(key, d.cfg.L1Signer(), &types.DynamicFeeTx{
+		ChainID:   d.cfg.L1ChainID,
+		Nonce:     d.nonce,
+		GasTipCap: big.NewInt(1),
+		GasFeeCap: big.NewInt(10),
+		Gas:       100_000,
+		To:        &d.cfg.BatchInboxAddress,
+		Data:      data,
+	})
+	require.NoError(d.t, err)
+	d.nonce++
+	d.blocks[block] = append(d.blocks[block], tx)
+	return tx
+}
+
+// addFrames includes frames in an L1 block, in a single batcher transaction.
+func (d *decoderTest) addFrames(block int, frames ...derive.Frame) *types.Transaction {
+	var buf bytes.Buffer
+	buf.WriteByte(derive.DerivationVersion0)
+	for _, f := range frames {
+		require.NoError(d.t, f.MarshalBinary(&buf))
+	}
+	return d.addTx(block, d.key, buf.Bytes())
+}
+
+func (d *decoderTest) decode(start, end uint64) *Report {
+	l1 := &testL1{infos: make(map[uint64]*testutils.MockBlockInfo), txs: make(map[common.Hash]types.Transactions)}
+	for i, txs := range d.blocks {
+		info := &testutils.MockBlockInfo{
+			InfoHash: common.Hash{0xaa, byte(i)},
+			InfoNum:  uint64(i),
+			InfoTime: 1000 + 12*uint64(i),
+		}
+		l1.infos[uint64(i)] = info
+		l1.txs[info.InfoHash] = txs
+	}
+	report, err := Decode(context.Background(), testlog.Logger(d.t, log.LvlError), d.cfg, l1, start, end)
+	require.NoError(d.t, err)
+	return report
+}
+
+// channelData compresses batches for the given L2 timestamps, like the batcher does.
+func channelData(t *testing.T, timestamps ...uint64) []byte {
+	var buf bytes.Buffer
+	zw := zlib.NewWriter(&buf)
+	for _, ts := range timestamps {
+		batch := &derive.BatchData{BatchV1: derive.BatchV1{
+			EpochNum:     1,
+			EpochHash:    common.Hash{0xaa, 1},
+			Timestamp:    ts,
+			Transactions: []hexutil.Bytes{{0x01}},
+		}}
+		require.NoError(t, rlp.Encode(zw, batch))
+	}
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func TestDecode(t *testing.T) {
+	d := newDecoderTest(t, 5)
+
+	data := channelData(t, 1000, 1002, 1004)
+	id := derive.ChannelID{0x01}
+	half := len(data) / 2
+	frame0 := d.addFrames(1, derive.Frame{ID: id, FrameNumber: 0, Data: data[:half]})
+	frame1 := d.addFrames(2, derive.Frame{ID: id, FrameNumber: 1, Data: data[half:], IsLast: true})
+	d.addFrames(3, derive.Frame{ID: derive.ChannelID{0x02}, FrameNumber: 0, Data: []byte{0x01}})
+
+	otherKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	unauthorized := d.addTx(3, otherKey, []byte{derive.DerivationVersion0})
+	garbage := d.addTx(4, d.key, []byte{0xff})
+
+	report := d.decode(0, 4)
+	require.Equal(t, 4, report.InboxTxs)
+	require.Len(t, report.InvalidTxs, 2)
+	require.Equal(t, unauthorized.Hash(), report.InvalidTxs[0].TxHash)
+	require.Contains(t, report.InvalidTxs[0].Reason, "unauthorized sender")
+	require.Equal(t, garbage.Hash(), report.InvalidTxs[1].TxHash)
+	require.Contains(t, report.InvalidTxs[1].Reason, "invalid frames")
+
+	require.Len(t, report.Channels, 2)
+	ch := report.Channels[0]
+	require.Equal(t, id, ch.ID)
+	require.True(t, ch.Ready)
+	require.False(t, ch.TimedOut)
+	require.Empty(t, ch.DecodeError)
+	require.Len(t, ch.Frames, 2)
+	require.Equal(t, frame0.Hash(), ch.Frames[0].TxHash)
+	require.Equal(t, frame1.Hash(), ch.Frames[1].TxHash)
+	require.Equal(t, uint64(1), ch.OpenL1Block.Number)
+	require.Equal(t, uint64(2), ch.CloseL1Block.Number)
+	require.Equal(t, uint64(len(data)), ch.CompressedSize)
+	require.NotZero(t, ch.UncompressedSize)
+	require.Equal(t, float64(ch.UncompressedSize)/float64(len(data)), ch.CompressionRatio)
+
+	require.Len(t, ch.Batches, 3)
+	require.Equal(t, uint64(100), ch.L2Start)
+	require.Equal(t, uint64(102), ch.L2End)
+	require.Equal(t, eth.BlockID{Hash: common.Hash{0xaa, 1}, Number: 1}, ch.Batches[0].Epoch)
+	require.Equal(t, 1, ch.Batches[0].TxCount)
+	// the last frame is included at 1024, 20 seconds after the last L2 block
+	require.Equal(t, int64(20), ch.SubmissionDelay)
+
+	incomplete := report.Channels[1]
+	require.False(t, incomplete.Ready)
+	require.Empty(t, incomplete.Batches)
+}
+
+func TestDecodeChannelErrors(t *testing.T) {
+	d := newDecoderTest(t, 5)
+
+	id := derive.ChannelID{0x01}
+	data := channelData(t, 1000)
+	d.addFrames(0, derive.Frame{ID: id, FrameNumber: 0, Data: data[:1]})
+	d.addFrames(1, derive.Frame{ID: id, FrameNumber: 0, Data: data[:1]})
+	d.addFrames(4, derive.Frame{ID: id, FrameNumber: 1, Data: data[1:], IsLast: true})
+
+	corrupt := derive.ChannelID{0x02}
+	d.addFrames(0, derive.Frame{ID: corrupt, FrameNumber: 0, Data: []byte("not zlib"), IsLast: true})
+
+	report := d.decode(0, 4)
+	require.Len(t, report.Channels, 2)
+
+	ch := report.Channels[0]
+	require.True(t, ch.Ready)
+	require.True(t, ch.TimedOut, "closed at L1 block 4, after the timeout of a channel opened at 0")
+	require.Len(t, ch.Frames, 3)
+	require.Equal(t, derive.DuplicateErr.Error(), ch.Frames[1].Error)
+	require.Len(t, ch.Batches, 1)
+
+	require.Contains(t, report.Channels[1].DecodeError, "failed to decompress")
+}
@@ -50,24 +50,11 @@ var Subcommands = cli.Commands{
 				return err
 			}
 
-			l1Genesis, err := genesis.BuildL1DeveloperGenesis(config)
+			l1Genesis, l2Genesis, rollupConfig, err := buildDevnet(config)
 			if err != nil {
 				return err
 			}
 
-			l1StartBlock := l1Genesis.ToBlock()
-			l2Addrs := &genesis.L2Addresses{
-				ProxyAdmin:                  predeploys.DevProxyAdminAddr,
-				L1StandardBridgeProxy:       predeploys.DevL1StandardBridgeAddr,
-				L1CrossDomainMessengerProxy: predeploys.DevL1CrossDomainMessengerAddr,
-			}
-			l2Genesis, err := genesis.BuildL2DeveloperGenesis(config, l1StartBlock, l2Addrs)
-			if err != nil {
-				return err
-			}
-
-			rollupConfig := makeRollupConfig(config, l1StartBlock, l2Genesis, predeploys.DevOptimismPortalAddr)
-
 			if err := writeGenesisFile(ctx.String("outfile.l1"), l1Genesis); err != nil {
 				return err
 			}
@@ -170,6 +157,28 @@ var Subcommands = cli.Commands{
 	},
 }
 
+// buildDevnet builds the L1 and L2 genesis and the rollup config of a local devnet.
+func buildDevnet(config *genesis.DeployConfig) (*core.Genesis, *core.Genesis, *rollup.Config, error) {
+	l1Genesis, err := genesis.BuildL1DeveloperGenesis(config)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	l1StartBlock := l1Genesis.ToBlock()
+	l2Addrs := &genesis.L2Addresses{
+		ProxyAdmin:                  predeploys.DevProxyAdminAddr,
+		L1StandardBridgeProxy:       predeploys.DevL1StandardBridgeAddr,
+		L1CrossDomainMessengerProxy: predeploys.DevL1CrossDomainMessengerAddr,
+	}
+	l2Genesis, err := genesis.BuildL2DeveloperGenesis(config, l1StartBlock, l2Addrs)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	rollupConfig := makeRollupConfig(config, l1StartBlock, l2Genesis, predeploys.DevOptimismPortalAddr)
+	return l1Genesis, l2Genesis, rollupConfig, nil
+}
+
 func makeRollupConfig(
 	config *genesis.DeployConfig,
 	l1StartBlock *types.Block,
@@ -0,0 +1,25 @@
+package genesis
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-chain-ops/genesis"
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
+)
+
+// devnetL1GenesisTimestamp is the L1 genesis time of the devnet network, see rollup/networks/README.md.
+const devnetL1GenesisTimestamp = 0x63d3a840
+
+func TestDevnetNetwork(t *testing.T) {
+	config, err := genesis.NewDeployConfig("../../../packages/contracts-bedrock/deploy-config/devnetL1.json")
+	require.NoError(t, err)
+	config.L1GenesisBlockTimestamp = devnetL1GenesisTimestamp
+
+	_, _, rollupConfig, err := buildDevnet(config)
+	require.NoError(t, err)
+	network, err := rollup.LoadNetworkConfig("devnet")
+	require.NoError(t, err)
+	require.Equal(t, rollupConfig, network, "the devnet network must match the generated devnet")
+}
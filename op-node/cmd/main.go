@@ -12,8 +12,10 @@ import (
 	"github.com/urfave/cli"
 
 	opnode "github.com/ethereum-optimism/optimism/op-node"
+	"github.com/ethereum-optimism/optimism/op-node/cmd/batchdecoder"
 	"github.com/ethereum-optimism/optimism/op-node/cmd/genesis"
 	"github.com/ethereum-optimism/optimism/op-node/cmd/p2p"
+	"github.com/ethereum-optimism/optimism/op-node/cmd/replay"
 	"github.com/ethereum-optimism/optimism/op-node/flags"
 	"github.com/ethereum-optimism/optimism/op-node/heartbeat"
 	"github.com/ethereum-optimism/optimism/op-node/metrics"
@@ -68,6 +70,16 @@ func main() {
 			Name:        "genesis",
 			Subcommands: genesis.Subcommands,
 		},
+		{
+			Name:        "replay",
+			Usage:       "Record and replay the L1 data consumed by the derivation pipeline",
+			Subcommands: replay.Subcommands,
+		},
+		{
+			Name:        "batch-decoder",
+			Usage:       "Inspect the batcher data posted to L1",
+			Subcommands: batchdecoder.Subcommands,
+		},
 	}
 
 	err := app.Run(os.Args)
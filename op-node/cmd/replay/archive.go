@@ -0,0 +1,400 @@
+package replay
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+
+	"github.com/ethereum-optimism/optimism/op-node/eth"
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Archive is the L1 data that the derivation pipeline consumed, together with the rollup config it was derived with.
+type Archive struct {
+	Rollup *rollup.Config   `json:"rollup"`
+	Blocks []*ArchivedBlock `json:"blocks"`
+}
+
+// ArchivedBlock is an L1 block header, with the transactions and receipts of the block if they were consumed.
+type ArchivedBlock struct {
+	Hash        common.Hash    `json:"hash"`
+	ParentHash  common.Hash    `json:"parentHash"`
+	Coinbase    common.Address `json:"miner"`
+	Root        common.Hash    `json:"stateRoot"`
+	Number      hexutil.Uint64 `json:"number"`
+	Time        hexutil.Uint64 `json:"timestamp"`
+	MixDigest   common.Hash    `json:"mixHash"`
+	BaseFee     *hexutil.Big   `json:"baseFeePerGas"`
+	ReceiptHash common.Hash    `json:"receiptsRoot"`
+
+	// Transactions and Receipts are nil if they were not consumed, and empty if the block has none.
+	Transactions []hexutil.Bytes `json:"transactions"`
+	Receipts     types.Receipts  `json:"receipts"`
+}
+
+func newArchivedBlock(info eth.BlockInfo) *ArchivedBlock {
+	return &ArchivedBlock{
+		Hash:        info.Hash(),
+		ParentHash:  info.ParentHash(),
+		Coinbase:    info.Coinbase(),
+		Root:        info.Root(),
+		Number:      hexutil.Uint64(info.NumberU64()),
+		Time:        hexutil.Uint64(info.Time()),
+		MixDigest:   info.MixDigest(),
+		BaseFee:     (*hexutil.Big)(info.BaseFee()),
+		ReceiptHash: info.ReceiptHash(),
+	}
+}
+
+func (b *ArchivedBlock) info() eth.BlockInfo {
+	return archivedInfo{b}
+}
+
+func (b *ArchivedBlock) ref() eth.L1BlockRef {
+	return eth.L1BlockRef{Hash: b.Hash, Number: uint64(b.Number), ParentHash: b.ParentHash, Time: uint64(b.Time)}
+}
+
+func (b *ArchivedBlock) txs() (types.Transactions, error) {
+	txs := make(types.Transactions, len(b.Transactions))
+	for i, data := range b.Transactions {
+		var tx types.Transaction
+		if err := tx.UnmarshalBinary(data); err != nil {
+			return nil, fmt.Errorf("failed to decode tx %d of block %s: %w", i, b.Hash, err)
+		}
+		txs[i] = &tx
+	}
+	return txs, nil
+}
+
+// archivedInfo implements eth.BlockInfo, the methods conflict with the field names of ArchivedBlock.
+type archivedInfo struct {
+	b *ArchivedBlock
+}
+
+var _ eth.BlockInfo = archivedInfo{}
+
+func (a archivedInfo) Hash() common.Hash {
+	return a.b.Hash
+}
+
+func (a archivedInfo) ParentHash() common.Hash {
+	return a.b.ParentHash
+}
+
+func (a archivedInfo) Coinbase() common.Address {
+	return a.b.Coinbase
+}
+
+func (a archivedInfo) Root() common.Hash {
+	return a.b.Root
+}
+
+func (a archivedInfo) NumberU64() uint64 {
+	return uint64(a.b.Number)
+}
+
+func (a archivedInfo) Time() uint64 {
+	return uint64(a.b.Time)
+}
+
+func (a archivedInfo) MixDigest() common.Hash {
+	return a.b.MixDigest
+}
+
+func (a archivedInfo) BaseFee() *big.Int {
+	return (*big.Int)(a.b.BaseFee)
+}
+
+func (a archivedInfo) ID() eth.BlockID {
+	return eth.BlockID{Hash: a.b.Hash, Number: uint64(a.b.Number)}
+}
+
+func (a archivedInfo) ReceiptHash() common.Hash {
+	return a.b.ReceiptHash
+}
+
+// LoadArchive reads an archive that was written with Archive.Save.
+func LoadArchive(path string) (*Archive, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer f.Close()
+	var archive Archive
+	if err := json.NewDecoder(f).Decode(&archive); err != nil {
+		return nil, fmt.Errorf("failed to decode archive %s: %w", path, err)
+	}
+	if archive.Rollup == nil {
+		return nil, fmt.Errorf("archive %s is missing the rollup config", path)
+	}
+	return &archive, nil
+}
+
+// Save writes the archive to the given path, with the blocks ordered by number.
+func (a *Archive) Save(path string) error {
+	sort.SliceStable(a.Blocks, func(i, j int) bool {
+		return a.Blocks[i].Number < a.Blocks[j].Number
+	})
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create archive: %w", err)
+	}
+	defer f.Close()
+	if err := json.NewEncoder(f).Encode(a); err != nil {
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+	return nil
+}
+
+// ArchiveL1 serves the L1 data of an archive to the derivation pipeline.
+// Data that was not archived is reported as not found.
+type ArchiveL1 struct {
+	byHash   map[common.Hash]*ArchivedBlock
+	byNumber map[uint64]*ArchivedBlock
+	head     *ArchivedBlock
+}
+
+var _ derive.L1Fetcher = (*ArchiveL1)(nil)
+
+func NewArchiveL1(archive *Archive) *ArchiveL1 {
+	l1 := &ArchiveL1{
+		byHash:   make(map[common.Hash]*ArchivedBlock),
+		byNumber: make(map[uint64]*ArchivedBlock),
+	}
+	for _, b := range archive.Blocks {
+		l1.byHash[b.Hash] = b
+		l1.byNumber[uint64(b.Number)] = b
+		if l1.head == nil || b.Number > l1.head.Number {
+			l1.head = b
+		}
+	}
+	return l1
+}
+
+func (l *ArchiveL1) block(hash common.Hash) (*ArchivedBlock, error) {
+	b, ok := l.byHash[hash]
+	if !ok {
+		return nil, fmt.Errorf("block %s is not archived: %w", hash, ethereum.NotFound)
+	}
+	return b, nil
+}
+
+// L1BlockRefByLabel returns the latest archived block, regardless of the label.
+func (l *ArchiveL1) L1BlockRefByLabel(ctx context.Context, label eth.BlockLabel) (eth.L1BlockRef, error) {
+	if l.head == nil {
+		return eth.L1BlockRef{}, ethereum.NotFound
+	}
+	return l.head.ref(), nil
+}
+
+func (l *ArchiveL1) L1BlockRefByNumber(ctx context.Context, num uint64) (eth.L1BlockRef, error) {
+	b, ok := l.byNumber[num]
+	if !ok {
+		return eth.L1BlockRef{}, ethereum.NotFound
+	}
+	return b.ref(), nil
+}
+
+func (l *ArchiveL1) L1BlockRefByHash(ctx context.Context, hash common.Hash) (eth.L1BlockRef, error) {
+	b, err := l.block(hash)
+	if err != nil {
+		return eth.L1BlockRef{}, err
+	}
+	return b.ref(), nil
+}
+
+func (l *ArchiveL1) InfoByHash(ctx context.Context, hash common.Hash) (eth.BlockInfo, error) {
+	b, err := l.block(hash)
+	if err != nil {
+		return nil, err
+	}
+	return b.info(), nil
+}
+
+func (l *ArchiveL1) InfoAndTxsByHash(ctx context.Context, hash common.Hash) (eth.BlockInfo, types.Transactions, error) {
+	b, err := l.block(hash)
+	if err != nil {
+		return nil, nil, err
+	}
+	if b.Transactions == nil {
+		return nil, nil, fmt.Errorf("transactions of block %s are not archived: %w", hash, ethereum.NotFound)
+	}
+	txs, err := b.txs()
+	if err != nil {
+		return nil, nil, err
+	}
+	return b.info(), txs, nil
+}
+
+func (l *ArchiveL1) Fetch(ctx context.Context, blockHash common.Hash) (eth.BlockInfo, types.Transactions, eth.ReceiptsFetcher, error) {
+	info, txs, err := l.InfoAndTxsByHash(ctx, blockHash)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	b := l.byHash[blockHash]
+	if b.Receipts == nil {
+		return nil, nil, nil, fmt.Errorf("receipts of block %s are not archived: %w", blockHash, ethereum.NotFound)
+	}
+	return info, txs, eth.FetchedReceipts(b.Receipts), nil
+}
+
+// Recorder forwards the requests of the derivation pipeline to an L1 source,
+// and archives the consumed L1 data, up to and including the End block number.
+type Recorder struct {
+	src derive.L1Fetcher
+	End uint64
+
+	blocks map[common.Hash]*ArchivedBlock
+}
+
+var _ derive.L1Fetcher = (*Recorder)(nil)
+
+func NewRecorder(src derive.L1Fetcher, end uint64) *Recorder {
+	return &Recorder{src: src, End: end, blocks: make(map[common.Hash]*ArchivedBlock)}
+}
+
+// Archive returns the recorded L1 data.
+func (r *Recorder) Archive(cfg *rollup.Config) *Archive {
+	archive := &Archive{Rollup: cfg}
+	for _, b := range r.blocks {
+		archive.Blocks = append(archive.Blocks, b)
+	}
+	return archive
+}
+
+// recordInfo archives the header of the block, if it is within the recorded range.
+func (r *Recorder) recordInfo(info eth.BlockInfo) (*ArchivedBlock, bool) {
+	if info.NumberU64() > r.End {
+		return nil, false
+	}
+	b, ok := r.blocks[info.Hash()]
+	if !ok {
+		b = newArchivedBlock(info)
+		r.blocks[info.Hash()] = b
+	}
+	return b, true
+}
+
+func (r *Recorder) recordTxs(b *ArchivedBlock, txs types.Transactions) error {
+	b.Transactions = make([]hexutil.Bytes, len(txs))
+	for i, tx := range txs {
+		data, err := tx.MarshalBinary()
+		if err != nil {
+			return fmt.Errorf("failed to encode tx %d of block %s: %w", i, b.Hash, err)
+		}
+		b.Transactions[i] = data
+	}
+	return nil
+}
+
+// recordRef archives the header of the referenced block, and hides blocks past the recorded range.
+func (r *Recorder) recordRef(ctx context.Context, ref eth.L1BlockRef) (eth.L1BlockRef, error) {
+	if ref.Number > r.End {
+		return eth.L1BlockRef{}, ethereum.NotFound
+	}
+	if _, ok := r.blocks[ref.Hash]; ok {
+		return ref, nil
+	}
+	info, err := r.src.InfoByHash(ctx, ref.Hash)
+	if err != nil {
+		return eth.L1BlockRef{}, err
+	}
+	r.recordInfo(info)
+	return ref, nil
+}
+
+func (r *Recorder) L1BlockRefByLabel(ctx context.Context, label eth.BlockLabel) (eth.L1BlockRef, error) {
+	ref, err := r.src.L1BlockRefByLabel(ctx, label)
+	if err != nil {
+		return eth.L1BlockRef{}, err
+	}
+	if ref.Number > r.End {
+		return r.L1BlockRefByNumber(ctx, r.End)
+	}
+	return r.recordRef(ctx, ref)
+}
+
+func (r *Recorder) L1BlockRefByNumber(ctx context.Context, num uint64) (eth.L1BlockRef, error) {
+	if num > r.End {
+		return eth.L1BlockRef{}, ethereum.NotFound
+	}
+	ref, err := r.src.L1BlockRefByNumber(ctx, num)
+	if err != nil {
+		return eth.L1BlockRef{}, err
+	}
+	return r.recordRef(ctx, ref)
+}
+
+func (r *Recorder) L1BlockRefByHash(ctx context.Context, hash common.Hash) (eth.L1BlockRef, error) {
+	ref, err := r.src.L1BlockRefByHash(ctx, hash)
+	if err != nil {
+		return eth.L1BlockRef{}, err
+	}
+	return r.recordRef(ctx, ref)
+}
+
+func (r *Recorder) InfoByHash(ctx context.Context, hash common.Hash) (eth.BlockInfo, error) {
+	info, err := r.src.InfoByHash(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := r.recordInfo(info); !ok {
+		return nil, ethereum.NotFound
+	}
+	return info, nil
+}
+
+func (r *Recorder) InfoAndTxsByHash(ctx context.Context, hash common.Hash) (eth.BlockInfo, types.Transactions, error) {
+	info, txs, err := r.src.InfoAndTxsByHash(ctx, hash)
+	if err != nil {
+		return nil, nil, err
+	}
+	b, ok := r.recordInfo(info)
+	if !ok {
+		return nil, nil, ethereum.NotFound
+	}
+	if err := r.recordTxs(b, txs); err != nil {
+		return nil, nil, err
+	}
+	return info, txs, nil
+}
+
+func (r *Recorder) Fetch(ctx context.Context, blockHash common.Hash) (eth.BlockInfo, types.Transactions, eth.ReceiptsFetcher, error) {
+	info, txs, receipts, err := r.src.Fetch(ctx, blockHash)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	b, ok := r.recordInfo(info)
+	if !ok {
+		return nil, nil, nil, ethereum.NotFound
+	}
+	if err := r.recordTxs(b, txs); err != nil {
+		return nil, nil, nil, err
+	}
+	return info, txs, &recordingReceiptsFetcher{ReceiptsFetcher: receipts, block: b}, nil
+}
+
+// recordingReceiptsFetcher archives the receipts once they are all fetched.
+type recordingReceiptsFetcher struct {
+	eth.ReceiptsFetcher
+	block *ArchivedBlock
+}
+
+func (f *recordingReceiptsFetcher) Result() (types.Receipts, error) {
+	receipts, err := f.ReceiptsFetcher.Result()
+	if err == nil {
+		f.block.Receipts = receipts
+		if f.block.Receipts == nil {
+			f.block.Receipts = types.Receipts{}
+		}
+	}
+	return receipts, err
+}
@@ -0,0 +1,148 @@
+package replay
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/urfave/cli"
+
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	"github.com/ethereum-optimism/optimism/op-node/sources"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+var (
+	ArchiveFlag = cli.StringFlag{
+		Name:  "archive",
+		Usage: "Path to the archive of L1 data",
+	}
+	SnapshotFlag = cli.StringFlag{
+		Name:  "snapshot",
+		Usage: "Path to write snapshots of the derivation state to, for visualization with cmd/stateviz",
+	}
+	LogLevelFlag = cli.StringFlag{
+		Name:  "log.level",
+		Usage: "The lowest log level that will be output to stderr",
+		Value: "warn",
+	}
+)
+
+var Subcommands = cli.Commands{
+	{
+		Name:  "record",
+		Usage: "Derive the L2 chain from a live L1 node, and archive the consumed L1 data",
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "l1",
+				Usage: "Address of L1 User JSON-RPC endpoint to use (eth namespace required)",
+			},
+			cli.BoolFlag{
+				Name:  "l1.trustrpc",
+				Usage: "Trust the L1 RPC, sync faster at risk of malicious/buggy RPC providing bad or inconsistent L1 data",
+			},
+			cli.StringFlag{
+				Name:  "rollup.config",
+				Usage: "Rollup chain parameters",
+			},
+			cli.Uint64Flag{
+				Name:  "l1.end",
+				Usage: "Number of the last L1 block to derive from",
+			},
+			ArchiveFlag,
+			SnapshotFlag,
+			LogLevelFlag,
+		},
+		Action: func(ctx *cli.Context) error {
+			logger, snapshotLog, err := setupLoggers(ctx)
+			if err != nil {
+				return err
+			}
+			cfg, err := loadRollupConfig(ctx.String("rollup.config"))
+			if err != nil {
+				return err
+			}
+			archivePath := ctx.String(ArchiveFlag.Name)
+			if archivePath == "" {
+				return errors.New("missing archive path")
+			}
+			if !ctx.IsSet("l1.end") {
+				return errors.New("missing last L1 block number")
+			}
+
+			rpcClient, err := rpc.DialContext(context.Background(), ctx.String("l1"))
+			if err != nil {
+				return fmt.Errorf("failed to dial L1 address: %w", err)
+			}
+			l1, err := sources.NewL1Client(rpcClient, logger, nil, sources.L1ClientDefaultConfig(cfg, ctx.Bool("l1.trustrpc")))
+			if err != nil {
+				return fmt.Errorf("failed to create L1 source: %w", err)
+			}
+			defer l1.Close()
+
+			recorder := NewRecorder(l1, ctx.Uint64("l1.end"))
+			if err := Replay(context.Background(), logger, snapshotLog, cfg, recorder, os.Stdout); err != nil {
+				return err
+			}
+			return recorder.Archive(cfg).Save(archivePath)
+		},
+	},
+	{
+		Name:  "run",
+		Usage: "Derive the L2 chain from archived L1 data, and print every payload attribute and batch decision as JSON",
+		Flags: []cli.Flag{
+			ArchiveFlag,
+			SnapshotFlag,
+			LogLevelFlag,
+		},
+		Action: func(ctx *cli.Context) error {
+			logger, snapshotLog, err := setupLoggers(ctx)
+			if err != nil {
+				return err
+			}
+			archive, err := LoadArchive(ctx.String(ArchiveFlag.Name))
+			if err != nil {
+				return err
+			}
+			return Replay(context.Background(), logger, snapshotLog, archive.Rollup, NewArchiveL1(archive), os.Stdout)
+		},
+	},
+}
+
+// setupLoggers logs to stderr, since the replay output is written to stdout.
+func setupLoggers(ctx *cli.Context) (log.Logger, log.Logger, error) {
+	lvl, err := log.LvlFromString(ctx.String(LogLevelFlag.Name))
+	if err != nil {
+		return nil, nil, err
+	}
+	logger := log.New()
+	logger.SetHandler(log.LvlFilterHandler(lvl, log.StreamHandler(os.Stderr, log.TerminalFormat(false))))
+
+	snapshotLog := log.New()
+	snapshotLog.SetHandler(log.DiscardHandler())
+	if path := ctx.String(SnapshotFlag.Name); path != "" {
+		handler, err := log.FileHandler(path, log.JSONFormat())
+		if err != nil {
+			return nil, nil, err
+		}
+		snapshotLog.SetHandler(handler)
+	}
+	return logger, snapshotLog, nil
+}
+
+func loadRollupConfig(path string) (*rollup.Config, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rollup config: %w", err)
+	}
+	defer file.Close()
+
+	var cfg rollup.Config
+	if err := json.NewDecoder(file).Decode(&cfg); err != nil {
+		return nil, fmt.Errorf("failed to decode rollup config: %w", err)
+	}
+	return &cfg, cfg.Check()
+}
@@ -0,0 +1,176 @@
+package replay
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ethereum-optimism/optimism/op-node/eth"
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Engine is an in-memory L2 engine that accepts all payload attributes.
+// Transactions are not executed: a block is built by including the attributes as-is,
+// with a block hash that commits to the parent block and the attributes.
+type Engine struct {
+	cfg *rollup.Config
+
+	blocks    map[common.Hash]*eth.ExecutionPayload
+	canonical map[uint64]common.Hash
+
+	head      common.Hash
+	safe      common.Hash
+	finalized common.Hash
+
+	payloads  map[eth.PayloadID]*eth.ExecutionPayload
+	payloadID uint64
+
+	// onAttributes is called with the attributes of every block that is built, and the parent of the block.
+	onAttributes func(parent eth.L2BlockRef, attrs *eth.PayloadAttributes)
+}
+
+var _ derive.Engine = (*Engine)(nil)
+
+// NewEngine creates an engine with only the L2 genesis block.
+func NewEngine(cfg *rollup.Config, onAttributes func(parent eth.L2BlockRef, attrs *eth.PayloadAttributes)) *Engine {
+	genesis := &eth.ExecutionPayload{
+		BlockHash:   cfg.Genesis.L2.Hash,
+		BlockNumber: eth.Uint64Quantity(cfg.Genesis.L2.Number),
+		Timestamp:   eth.Uint64Quantity(cfg.Genesis.L2Time),
+	}
+	return &Engine{
+		cfg:          cfg,
+		blocks:       map[common.Hash]*eth.ExecutionPayload{genesis.BlockHash: genesis},
+		canonical:    map[uint64]common.Hash{uint64(genesis.BlockNumber): genesis.BlockHash},
+		head:         genesis.BlockHash,
+		safe:         genesis.BlockHash,
+		finalized:    genesis.BlockHash,
+		payloads:     make(map[eth.PayloadID]*eth.ExecutionPayload),
+		onAttributes: onAttributes,
+	}
+}
+
+func (e *Engine) GetPayload(ctx context.Context, payloadId eth.PayloadID) (*eth.ExecutionPayload, error) {
+	payload, ok := e.payloads[payloadId]
+	if !ok {
+		return nil, eth.InputError{Inner: fmt.Errorf("unknown payload %s", payloadId), Code: eth.UnknownPayload}
+	}
+	delete(e.payloads, payloadId)
+	return payload, nil
+}
+
+func (e *Engine) ForkchoiceUpdate(ctx context.Context, state *eth.ForkchoiceState, attr *eth.PayloadAttributes) (*eth.ForkchoiceUpdatedResult, error) {
+	for _, h := range []common.Hash{state.HeadBlockHash, state.SafeBlockHash, state.FinalizedBlockHash} {
+		if _, ok := e.blocks[h]; !ok {
+			return nil, eth.InputError{Inner: fmt.Errorf("unknown block %s", h), Code: eth.InvalidForkchoiceState}
+		}
+	}
+	e.setHead(state.HeadBlockHash)
+	e.safe = state.SafeBlockHash
+	e.finalized = state.FinalizedBlockHash
+
+	res := &eth.ForkchoiceUpdatedResult{PayloadStatus: eth.PayloadStatusV1{Status: eth.ExecutionValid}}
+	if attr == nil {
+		return res, nil
+	}
+	parent := e.blocks[state.HeadBlockHash]
+	parentRef, err := derive.PayloadToBlockRef(parent, &e.cfg.Genesis)
+	if err != nil {
+		return nil, fmt.Errorf("invalid parent block: %w", err)
+	}
+	if e.onAttributes != nil {
+		e.onAttributes(parentRef, attr)
+	}
+	payload, err := buildPayload(parent, attr)
+	if err != nil {
+		return nil, eth.InputError{Inner: err, Code: eth.InvalidPayloadAttributes}
+	}
+	var id eth.PayloadID
+	e.payloadID++
+	binary.BigEndian.PutUint64(id[:], e.payloadID)
+	e.payloads[id] = payload
+	res.PayloadID = &id
+	return res, nil
+}
+
+// setHead makes the given known block the head of the canonical chain.
+func (e *Engine) setHead(hash common.Hash) {
+	head := e.blocks[hash]
+	for n := uint64(head.BlockNumber) + 1; ; n++ {
+		if _, ok := e.canonical[n]; !ok {
+			break
+		}
+		delete(e.canonical, n)
+	}
+	for b := head; b != nil && e.canonical[uint64(b.BlockNumber)] != b.BlockHash; b = e.blocks[b.ParentHash] {
+		e.canonical[uint64(b.BlockNumber)] = b.BlockHash
+	}
+	e.head = hash
+}
+
+// buildPayload builds a block on top of the parent, with a block hash that commits to the parent and the attributes.
+func buildPayload(parent *eth.ExecutionPayload, attr *eth.PayloadAttributes) (*eth.ExecutionPayload, error) {
+	data, err := json.Marshal(attr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode attributes: %w", err)
+	}
+	return &eth.ExecutionPayload{
+		ParentHash:   parent.BlockHash,
+		FeeRecipient: attr.SuggestedFeeRecipient,
+		PrevRandao:   attr.PrevRandao,
+		BlockNumber:  parent.BlockNumber + 1,
+		Timestamp:    attr.Timestamp,
+		BlockHash:    crypto.Keccak256Hash(parent.BlockHash[:], data),
+		Transactions: attr.Transactions,
+	}, nil
+}
+
+func (e *Engine) NewPayload(ctx context.Context, payload *eth.ExecutionPayload) (*eth.PayloadStatusV1, error) {
+	if _, ok := e.blocks[payload.ParentHash]; !ok {
+		return &eth.PayloadStatusV1{Status: eth.ExecutionSyncing}, nil
+	}
+	e.blocks[payload.BlockHash] = payload
+	return &eth.PayloadStatusV1{Status: eth.ExecutionValid}, nil
+}
+
+func (e *Engine) PayloadByHash(ctx context.Context, hash common.Hash) (*eth.ExecutionPayload, error) {
+	payload, ok := e.blocks[hash]
+	if !ok {
+		return nil, ethereum.NotFound
+	}
+	return payload, nil
+}
+
+func (e *Engine) PayloadByNumber(ctx context.Context, num uint64) (*eth.ExecutionPayload, error) {
+	hash, ok := e.canonical[num]
+	if !ok {
+		return nil, ethereum.NotFound
+	}
+	return e.blocks[hash], nil
+}
+
+func (e *Engine) L2BlockRefByLabel(ctx context.Context, label eth.BlockLabel) (eth.L2BlockRef, error) {
+	switch label {
+	case eth.Unsafe:
+		return e.L2BlockRefByHash(ctx, e.head)
+	case eth.Safe:
+		return e.L2BlockRefByHash(ctx, e.safe)
+	case eth.Finalized:
+		return e.L2BlockRefByHash(ctx, e.finalized)
+	default:
+		return eth.L2BlockRef{}, fmt.Errorf("unsupported block label %q", label)
+	}
+}
+
+func (e *Engine) L2BlockRefByHash(ctx context.Context, hash common.Hash) (eth.L2BlockRef, error) {
+	payload, err := e.PayloadByHash(ctx, hash)
+	if err != nil {
+		return eth.L2BlockRef{}, err
+	}
+	return derive.PayloadToBlockRef(payload, &e.cfg.Genesis)
+}
@@ -0,0 +1,158 @@
+package replay
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/op-node/eth"
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const (
+	// maxStepAttempts is the number of consecutive failed pipeline steps before the replay is aborted.
+	maxStepAttempts = 10
+	// maxResets is the number of pipeline resets before the replay is aborted.
+	maxResets = 10
+)
+
+// EventType is the type of a replay Event.
+type EventType string
+
+const (
+	// EventAttributes is emitted for every L2 block that is derived, before the block is inserted.
+	EventAttributes EventType = "attributes"
+	// EventDropped is emitted for every batch, frame or channel that the pipeline dropped.
+	EventDropped EventType = "dropped"
+	// EventReset is emitted when the pipeline is reset.
+	EventReset EventType = "reset"
+)
+
+// Event is a single line of the JSON output of a replay.
+type Event struct {
+	Type EventType `json:"type"`
+	// L1Origin is the L1 block that the pipeline was at when the event happened.
+	L1Origin eth.L1BlockRef `json:"l1Origin"`
+
+	// Parent and Attributes are set for EventAttributes
+	Parent     *eth.L2BlockRef        `json:"parent,omitempty"`
+	Attributes *eth.PayloadAttributes `json:"attributes,omitempty"`
+
+	// Dropped is set for EventDropped
+	Dropped *derive.DropRecord `json:"dropped,omitempty"`
+
+	// Error is set for EventReset
+	Error string `json:"error,omitempty"`
+}
+
+// Replay runs the derivation pipeline from L2 genesis on the given L1 data, until the pipeline runs out of L1 data.
+// Every derived payload attribute and batch decision is written to out as a line of JSON.
+// Snapshots of the derivation state are written to snapshotLog, in the format of cmd/stateviz.
+func Replay(ctx context.Context, logger log.Logger, snapshotLog log.Logger, cfg *rollup.Config, l1 derive.L1Fetcher, out io.Writer) error {
+	enc := json.NewEncoder(out)
+	var encErr error
+	emit := func(ev *Event) {
+		if encErr == nil {
+			encErr = enc.Encode(ev)
+		}
+	}
+
+	var pipeline *derive.DerivationPipeline
+	engine := NewEngine(cfg, func(parent eth.L2BlockRef, attrs *eth.PayloadAttributes) {
+		emit(&Event{Type: EventAttributes, L1Origin: pipeline.Progress().Origin, Parent: &parent, Attributes: attrs})
+	})
+	pipeline = derive.NewDerivationPipeline(logger, cfg, l1, engine, noopMetrics{})
+	pipeline.Reset()
+
+	l1Head, err := l1.L1BlockRefByLabel(ctx, eth.Unsafe)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve L1 head: %w", err)
+	}
+	snapshot := func(event string) {
+		snapshotLog.Info("Rollup State Snapshot",
+			"event", event,
+			"l1Head", jsonString{l1Head},
+			"l1Current", jsonString{pipeline.Progress().Origin},
+			"l2Head", jsonString{pipeline.UnsafeL2Head()},
+			"l2SafeHead", jsonString{pipeline.SafeL2Head()},
+			"l2FinalizedHead", jsonString{pipeline.Finalized().ID()})
+	}
+
+	var drops uint64
+	var safeHead eth.L2BlockRef
+	attempts, resets := 0, 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		err := pipeline.Step(ctx)
+
+		var dropped []derive.DropRecord
+		dropped, drops = pipeline.DroppedInputsSince(drops)
+		for i := range dropped {
+			rec := dropped[i]
+			rec.Time = time.Time{} // the local time of the drop would make the output differ between runs
+			emit(&Event{Type: EventDropped, L1Origin: pipeline.Progress().Origin, Dropped: &rec})
+		}
+		if encErr != nil {
+			return fmt.Errorf("failed to write replay output: %w", encErr)
+		}
+
+		if err == io.EOF {
+			break
+		} else if err != nil && errors.Is(err, derive.ErrReset) {
+			resets++
+			if resets > maxResets {
+				return fmt.Errorf("derivation pipeline was reset too many times: %w", err)
+			}
+			logger.Warn("Derivation pipeline is reset", "err", err)
+			emit(&Event{Type: EventReset, L1Origin: pipeline.Progress().Origin, Error: err.Error()})
+			pipeline.Reset()
+			continue
+		} else if err != nil && errors.Is(err, derive.ErrCritical) {
+			return fmt.Errorf("derivation process critical error: %w", err)
+		} else if err != nil {
+			attempts++
+			if attempts >= maxStepAttempts {
+				return fmt.Errorf("derivation process failed after %d attempts: %w", attempts, err)
+			}
+			logger.Warn("Derivation process error", "attempts", attempts, "err", err)
+			continue
+		}
+		attempts = 0
+		if head := pipeline.SafeL2Head(); head != safeHead {
+			safeHead = head
+			snapshot("New safe head")
+		}
+	}
+	snapshot("Replay complete")
+	logger.Info("Replay complete", "l1_derived", pipeline.Progress().Origin, "l2_safe", pipeline.SafeL2Head(), "dropped", drops)
+	return nil
+}
+
+// jsonString formats the value as JSON string in the snapshot log, like the rollup driver does.
+type jsonString struct {
+	x any
+}
+
+func (v jsonString) String() string {
+	out, _ := json.Marshal(v.x)
+	return string(out)
+}
+
+type noopMetrics struct{}
+
+func (noopMetrics) RecordL1Ref(name string, ref eth.L1BlockRef)                                {}
+func (noopMetrics) RecordL2Ref(name string, ref eth.L2BlockRef)                                {}
+func (noopMetrics) RecordUnsafePayloadsBuffer(length uint64, memSize uint64, next eth.BlockID) {}
+func (noopMetrics) RecordDerivationDrop(kind string, reason string)                            {}
+func (noopMetrics) RecordStageStep(stage string, duration time.Duration)                       {}
+func (noopMetrics) RecordStageReset(stage string)                                              {}
+func (noopMetrics) RecordStageError(stage string)                                              {}
+func (noopMetrics) RecordChannelBank(channels int, frames int, memSize uint64)                 {}
+func (noopMetrics) RecordBatchQueue(batches int, l1Blocks int)                                 {}
@@ -0,0 +1,115 @@
+package replay
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-node/eth"
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
+	"github.com/ethereum-optimism/optimism/op-node/testlog"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// testArchive creates an archive of an L1 chain without any batches or deposits,
+// so the L2 chain is derived from the empty batches that fill the sequencing window.
+func testArchive(l1Blocks int) *Archive {
+	var blocks []*ArchivedBlock
+	parent := common.Hash{}
+	for i := 0; i < l1Blocks; i++ {
+		b := &ArchivedBlock{
+			Hash:         common.Hash{0xaa, byte(i)},
+			ParentHash:   parent,
+			Number:       hexutil.Uint64(i),
+			Time:         hexutil.Uint64(1000 + 12*i),
+			MixDigest:    common.Hash{0xbb, byte(i)},
+			BaseFee:      (*hexutil.Big)(big.NewInt(7)),
+			Transactions: []hexutil.Bytes{},
+			Receipts:     types.Receipts{},
+		}
+		blocks = append(blocks, b)
+		parent = b.Hash
+	}
+	return &Archive{
+		Rollup: &rollup.Config{
+			Genesis: rollup.Genesis{
+				L1:     eth.BlockID{Hash: blocks[0].Hash, Number: 0},
+				L2:     eth.BlockID{Hash: common.Hash{0xcc}, Number: 0},
+				L2Time: 1000,
+			},
+			BlockTime:              2,
+			MaxSequencerDrift:      10,
+			SeqWindowSize:          2,
+			ChannelTimeout:         2,
+			L1ChainID:              big.NewInt(900),
+			L2ChainID:              big.NewInt(901),
+			P2PSequencerAddress:    common.Address{0x01},
+			FeeRecipientAddress:    common.Address{0x02},
+			BatchInboxAddress:      common.Address{0x03},
+			BatchSenderAddress:     common.Address{0x04},
+			DepositContractAddress: common.Address{0x05},
+		},
+		Blocks: blocks,
+	}
+}
+
+func replayEvents(t *testing.T, cfg *rollup.Config, l1 derive.L1Fetcher) []Event {
+	logger := testlog.Logger(t, log.LvlError)
+	var out bytes.Buffer
+	require.NoError(t, Replay(context.Background(), logger, logger, cfg, l1, &out))
+
+	var events []Event
+	scanner := bufio.NewScanner(&out)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		var ev Event
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &ev))
+		events = append(events, ev)
+	}
+	require.NoError(t, scanner.Err())
+	return events
+}
+
+func TestReplay(t *testing.T) {
+	archive := testArchive(8)
+	events := replayEvents(t, archive.Rollup, NewArchiveL1(archive))
+	require.Len(t, events, 36, "L1 blocks 0 to 5 are fully derived, with 6 L2 blocks each")
+
+	parent := uint64(0)
+	for _, ev := range events {
+		require.Equal(t, EventAttributes, ev.Type)
+		require.Equal(t, parent, ev.Parent.Number, "blocks are derived in order")
+		require.Equal(t, ev.Parent.Time+archive.Rollup.BlockTime, uint64(ev.Attributes.Timestamp))
+		require.Len(t, ev.Attributes.Transactions, 1, "only the L1 info deposit is included")
+		parent++
+	}
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	live := testArchive(10)
+	cfg := live.Rollup
+	recorder := NewRecorder(NewArchiveL1(live), 7)
+	recorded := replayEvents(t, cfg, recorder)
+
+	path := filepath.Join(t.TempDir(), "archive.json")
+	require.NoError(t, recorder.Archive(cfg).Save(path))
+	archive, err := LoadArchive(path)
+	require.NoError(t, err)
+	for _, b := range archive.Blocks {
+		require.LessOrEqual(t, uint64(b.Number), recorder.End, "blocks past the end are not recorded")
+	}
+
+	replayed := replayEvents(t, archive.Rollup, NewArchiveL1(archive))
+	require.Equal(t, recorded, replayed, "replaying the archive derives the same output")
+	require.Equal(t, replayEvents(t, cfg, NewArchiveL1(testArchive(8))), replayed)
+}
@@ -0,0 +1,234 @@
+package conductor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/raft"
+	raftboltdb "github.com/hashicorp/raft-boltdb/v2"
+
+	"github.com/ethereum-optimism/optimism/op-node/eth"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// ErrNotLeader is returned when a payload is committed by a node that is not the active sequencer.
+var ErrNotLeader = errors.New("not the sequencer leader")
+
+// Conductor elects a single active sequencer among a Raft cluster of sequencer nodes,
+// and replicates the unsafe payloads of the active sequencer to the other nodes.
+//
+// The Raft log, vote and snapshots are persisted in the data dir, so that a restarted node
+// keeps its cluster membership and never votes twice in the same term.
+type Conductor struct {
+	log       log.Logger
+	cfg       *Config
+	transport raft.Transport
+	store     *raftboltdb.BoltStore
+	raft      *raft.Raft
+	fsm       *unsafeHeadFSM
+
+	// leadership changes of the raft node
+	leaderCh chan bool
+
+	// ready is set to 1 once this node is the leader and has applied all previously committed payloads
+	ready int32
+
+	closeOnce sync.Once
+	done      chan struct{}
+	wg        sync.WaitGroup
+}
+
+// NewConductor creates a Raft node with the given transport, which participates in the cluster once started.
+// onPayload is called with every replicated unsafe payload, including those proposed by this node.
+func NewConductor(log log.Logger, cfg *Config, transport raft.Transport, onPayload func(payload *eth.ExecutionPayload)) (*Conductor, error) {
+	if err := cfg.Check(); err != nil {
+		return nil, err
+	}
+	return &Conductor{
+		log:       log,
+		cfg:       cfg,
+		transport: transport,
+		fsm:       &unsafeHeadFSM{onPayload: onPayload},
+		leaderCh:  make(chan bool, 10),
+		done:      make(chan struct{}),
+	}, nil
+}
+
+// Start starts the Raft node from the state persisted in the data dir,
+// and bootstraps the cluster if configured to and no state was persisted yet.
+func (c *Conductor) Start() error {
+	raftCfg := raft.DefaultConfig()
+	raftCfg.LocalID = raft.ServerID(c.cfg.ServerID)
+	raftCfg.Logger = hclog.New(&hclog.LoggerOptions{
+		Name:   "raft",
+		Level:  hclog.Info,
+		Output: &logWriter{log: c.log},
+	})
+	raftCfg.NotifyCh = c.leaderCh
+	if c.cfg.HeartbeatTimeout != 0 {
+		raftCfg.HeartbeatTimeout = c.cfg.HeartbeatTimeout
+		raftCfg.ElectionTimeout = c.cfg.HeartbeatTimeout
+		raftCfg.LeaderLeaseTimeout = c.cfg.HeartbeatTimeout / 2
+	}
+
+	if err := os.MkdirAll(c.cfg.DataDir, 0o700); err != nil {
+		return fmt.Errorf("failed to create raft data dir: %w", err)
+	}
+	store, err := raftboltdb.NewBoltStore(filepath.Join(c.cfg.DataDir, "raft.db"))
+	if err != nil {
+		return fmt.Errorf("failed to open raft store: %w", err)
+	}
+	snapshots, err := raft.NewFileSnapshotStore(c.cfg.DataDir, 2, &logWriter{log: c.log})
+	if err != nil {
+		_ = store.Close()
+		return fmt.Errorf("failed to open raft snapshot store: %w", err)
+	}
+	existing, err := raft.HasExistingState(store, store, snapshots)
+	if err != nil {
+		_ = store.Close()
+		return fmt.Errorf("failed to read raft state: %w", err)
+	}
+	r, err := raft.NewRaft(raftCfg, c.fsm, store, store, snapshots, c.transport)
+	if err != nil {
+		_ = store.Close()
+		return fmt.Errorf("failed to start raft node: %w", err)
+	}
+	if existing {
+		c.log.Info("restored raft state", "data_dir", c.cfg.DataDir, "last_index", r.LastIndex())
+	} else if c.cfg.Bootstrap {
+		servers := []raft.Server{{ID: raftCfg.LocalID, Address: c.transport.LocalAddr()}}
+		for _, p := range c.cfg.Peers {
+			servers = append(servers, raft.Server{ID: raft.ServerID(p.ID), Address: raft.ServerAddress(p.Addr)})
+		}
+		if err := r.BootstrapCluster(raft.Configuration{Servers: servers}).Error(); err != nil && !errors.Is(err, raft.ErrCantBootstrap) {
+			_ = r.Shutdown().Error()
+			_ = store.Close()
+			return fmt.Errorf("failed to bootstrap raft cluster: %w", err)
+		}
+	}
+	c.store = store
+	c.raft = r
+	c.wg.Add(1)
+	go c.watchLeadership()
+	return nil
+}
+
+// NewTCPConductor creates a Raft node that communicates with its peers over TCP. See NewConductor.
+func NewTCPConductor(log log.Logger, cfg *Config, onPayload func(payload *eth.ExecutionPayload)) (*Conductor, error) {
+	advertise, err := net.ResolveTCPAddr("tcp", cfg.AdvertiseAddr())
+	if err != nil {
+		return nil, fmt.Errorf("invalid raft advertise address: %w", err)
+	}
+	transport, err := raft.NewTCPTransport(cfg.ListenAddr, advertise, 3, 10*time.Second, &logWriter{log: log})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create raft transport: %w", err)
+	}
+	c, err := NewConductor(log, cfg, transport, onPayload)
+	if err != nil {
+		_ = transport.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// watchLeadership marks this node as ready to sequence once it gains leadership,
+// and all payloads committed by previous leaders are applied locally.
+func (c *Conductor) watchLeadership() {
+	defer c.wg.Done()
+	for {
+		select {
+		case leader := <-c.leaderCh:
+			if !leader {
+				c.log.Warn("lost sequencer leadership")
+				atomic.StoreInt32(&c.ready, 0)
+				continue
+			}
+			c.log.Info("gained sequencer leadership, applying replicated payloads")
+			if err := c.raft.Barrier(10 * time.Second).Error(); err != nil {
+				c.log.Error("failed to apply replicated payloads after gaining leadership", "err", err)
+				continue
+			}
+			if c.raft.State() != raft.Leader {
+				continue
+			}
+			atomic.StoreInt32(&c.ready, 1)
+			c.log.Info("ready to sequence as leader")
+		case <-c.done:
+			return
+		}
+	}
+}
+
+// Leader returns whether this node is the active sequencer. Always false before the node is started.
+func (c *Conductor) Leader() bool {
+	return atomic.LoadInt32(&c.ready) == 1 && c.raft.State() == raft.Leader
+}
+
+// CommitUnsafePayload replicates the unsafe payload to a majority of the cluster.
+// The payload may only be published once it is committed.
+func (c *Conductor) CommitUnsafePayload(ctx context.Context, payload *eth.ExecutionPayload) error {
+	if !c.Leader() {
+		return ErrNotLeader
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode payload %s: %w", payload.ID(), err)
+	}
+	timeout := 10 * time.Second
+	if deadline, ok := ctx.Deadline(); ok {
+		timeout = time.Until(deadline)
+	}
+	f := c.raft.Apply(data, timeout)
+	if err := f.Error(); err != nil {
+		return fmt.Errorf("failed to replicate payload %s: %w", payload.ID(), err)
+	}
+	if err, ok := f.Response().(error); ok && err != nil {
+		return fmt.Errorf("failed to apply payload %s: %w", payload.ID(), err)
+	}
+	return nil
+}
+
+// UnsafePayloadsAfter returns the most recent replicated unsafe payloads after the given block number,
+// in order of block number.
+func (c *Conductor) UnsafePayloadsAfter(num uint64) []*eth.ExecutionPayload {
+	return c.fsm.payloadsAfter(num)
+}
+
+// Close stops participating in the cluster.
+func (c *Conductor) Close() error {
+	var err error
+	c.closeOnce.Do(func() {
+		close(c.done)
+		c.wg.Wait()
+		if c.raft != nil {
+			// shutting down raft closes the transport as well
+			err = c.raft.Shutdown().Error()
+			if closeErr := c.store.Close(); err == nil {
+				err = closeErr
+			}
+		} else if closer, ok := c.transport.(raft.WithClose); ok {
+			err = closer.Close()
+		}
+	})
+	return err
+}
+
+// logWriter forwards the log lines of the raft library to the op-node logger.
+type logWriter struct {
+	log log.Logger
+}
+
+func (w *logWriter) Write(p []byte) (int, error) {
+	w.log.Debug(strings.TrimSpace(string(p)))
+	return len(p), nil
+}
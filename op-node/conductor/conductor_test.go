@@ -0,0 +1,235 @@
+package conductor
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/raft"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-node/eth"
+	"github.com/ethereum-optimism/optimism/op-node/testlog"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+type testNode struct {
+	c         *Conductor
+	cfg       *Config
+	addr      raft.ServerAddress
+	transport *raft.InmemTransport
+
+	mu       sync.Mutex
+	received []*eth.ExecutionPayload
+}
+
+func (n *testNode) Received() []*eth.ExecutionPayload {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return append([]*eth.ExecutionPayload(nil), n.received...)
+}
+
+// newTestCluster starts a cluster of conductors, connected with in-memory transports.
+func newTestCluster(t *testing.T, size int) []*testNode {
+	addrs := make([]raft.ServerAddress, size)
+	transports := make([]*raft.InmemTransport, size)
+	for i := range transports {
+		addrs[i], transports[i] = raft.NewInmemTransport("")
+	}
+	for i, a := range transports {
+		for j, b := range transports {
+			if i != j {
+				a.Connect(addrs[j], b)
+			}
+		}
+	}
+	nodes := make([]*testNode, size)
+	for i := range nodes {
+		cfg := &Config{
+			ServerID:         fmt.Sprintf("node%d", i),
+			Bootstrap:        true,
+			DataDir:          t.TempDir(),
+			HeartbeatTimeout: 100 * time.Millisecond,
+		}
+		for j := range nodes {
+			if i != j {
+				cfg.Peers = append(cfg.Peers, Peer{ID: fmt.Sprintf("node%d", j), Addr: string(addrs[j])})
+			}
+		}
+		n := &testNode{cfg: cfg, addr: addrs[i], transport: transports[i]}
+		n.newConductor(t)
+		nodes[i] = n
+	}
+	for _, n := range nodes {
+		require.NoError(t, n.c.Start())
+	}
+	return nodes
+}
+
+func (n *testNode) newConductor(t *testing.T) {
+	c, err := NewConductor(testlog.Logger(t, log.LvlError).New("node", n.cfg.ServerID), n.cfg, n.transport, func(payload *eth.ExecutionPayload) {
+		n.mu.Lock()
+		defer n.mu.Unlock()
+		n.received = append(n.received, payload)
+	})
+	require.NoError(t, err)
+	n.c = c
+	t.Cleanup(func() {
+		_ = c.Close()
+	})
+}
+
+// restart stops the node, and starts it again with the same data dir and address.
+func (n *testNode) restart(t *testing.T, nodes []*testNode) {
+	require.NoError(t, n.c.Close())
+	n.mu.Lock()
+	n.received = nil
+	n.mu.Unlock()
+	_, n.transport = raft.NewInmemTransport(n.addr)
+	for _, other := range nodes {
+		if other != n {
+			other.transport.Connect(n.addr, n.transport)
+			n.transport.Connect(other.addr, other.transport)
+		}
+	}
+	n.newConductor(t)
+	require.NoError(t, n.c.Start())
+}
+
+func waitForLeader(t *testing.T, nodes []*testNode) *testNode {
+	var leader *testNode
+	require.Eventually(t, func() bool {
+		leader = nil
+		for _, n := range nodes {
+			if n.c.Leader() {
+				if leader != nil {
+					return false
+				}
+				leader = n
+			}
+		}
+		return leader != nil
+	}, 10*time.Second, 10*time.Millisecond, "expected a single leader")
+	return leader
+}
+
+// testPayload returns a payload of the test chain, in which block num has parent num-1.
+func testPayload(num uint64) *eth.ExecutionPayload {
+	return &eth.ExecutionPayload{
+		ParentHash:  common.Hash{byte(num - 1)},
+		BlockNumber: eth.Uint64Quantity(num),
+		BlockHash:   common.Hash{byte(num)},
+	}
+}
+
+func TestConductorFailover(t *testing.T) {
+	nodes := newTestCluster(t, 3)
+	ctx := context.Background()
+	leader := waitForLeader(t, nodes)
+
+	for _, n := range nodes {
+		if n != leader {
+			require.ErrorIs(t, n.c.CommitUnsafePayload(ctx, testPayload(1)), ErrNotLeader)
+		}
+	}
+
+	require.NoError(t, leader.c.CommitUnsafePayload(ctx, testPayload(1)))
+	require.NoError(t, leader.c.CommitUnsafePayload(ctx, testPayload(2)))
+	require.Error(t, leader.c.CommitUnsafePayload(ctx, testPayload(2)), "payload must extend the replicated unsafe head")
+	require.Error(t, leader.c.CommitUnsafePayload(ctx, testPayload(4)), "payload must not leave a gap")
+	fork := testPayload(3)
+	fork.ParentHash = common.Hash{0xff}
+	require.Error(t, leader.c.CommitUnsafePayload(ctx, fork), "payload must build on the replicated unsafe head")
+
+	// all nodes receive the replicated payloads
+	for _, n := range nodes {
+		require.Eventually(t, func() bool {
+			return len(n.Received()) == 2
+		}, 5*time.Second, 10*time.Millisecond)
+		require.Equal(t, testPayload(2).ID(), n.Received()[1].ID())
+	}
+
+	// stop the leader, the remaining nodes elect a new leader
+	require.NoError(t, leader.c.Close())
+	var remaining []*testNode
+	for _, n := range nodes {
+		if n != leader {
+			remaining = append(remaining, n)
+		}
+	}
+	newLeader := waitForLeader(t, remaining)
+
+	// the new leader continues from the last replicated unsafe head
+	payloads := newLeader.c.UnsafePayloadsAfter(1)
+	require.Len(t, payloads, 1)
+	require.Equal(t, testPayload(2).ID(), payloads[0].ID())
+	require.Empty(t, newLeader.c.UnsafePayloadsAfter(2))
+	require.NoError(t, newLeader.c.CommitUnsafePayload(ctx, testPayload(3)))
+}
+
+func TestConductorRestart(t *testing.T) {
+	nodes := newTestCluster(t, 3)
+	ctx := context.Background()
+	leader := waitForLeader(t, nodes)
+	require.NoError(t, leader.c.CommitUnsafePayload(ctx, testPayload(1)))
+	require.NoError(t, leader.c.CommitUnsafePayload(ctx, testPayload(2)))
+	for _, n := range nodes {
+		require.Eventually(t, func() bool {
+			return len(n.Received()) == 2
+		}, 5*time.Second, 10*time.Millisecond)
+	}
+
+	// restart a follower that voted in the election of the leader
+	var voter *testNode
+	var voteTerm uint64
+	for _, n := range nodes {
+		term, err := n.c.store.GetUint64([]byte("LastVoteTerm"))
+		require.NoError(t, err)
+		if n != leader && term > 0 {
+			voter, voteTerm = n, term
+			break
+		}
+	}
+	require.NotNil(t, voter, "expected a follower to have voted")
+	lastIndex := voter.c.raft.LastIndex()
+	voter.restart(t, nodes)
+
+	// the restarted node keeps its log instead of bootstrapping a new one
+	require.GreaterOrEqual(t, voter.c.raft.LastIndex(), lastIndex)
+	cfgFuture := voter.c.raft.GetConfiguration()
+	require.NoError(t, cfgFuture.Error())
+	require.Len(t, cfgFuture.Configuration().Servers, 3)
+
+	// the restarted node doesn't vote for another candidate in a term it already voted in
+	candidateAddr, candidate := raft.NewInmemTransport("")
+	candidate.Connect(voter.addr, voter.transport)
+	var resp raft.RequestVoteResponse
+	require.NoError(t, candidate.RequestVote(raft.ServerID(voter.cfg.ServerID), voter.addr, &raft.RequestVoteRequest{
+		RPCHeader:          raft.RPCHeader{ProtocolVersion: raft.ProtocolVersionMax, Addr: []byte(candidateAddr)},
+		Term:               voteTerm,
+		LastLogIndex:       math.MaxUint64,
+		LastLogTerm:        voteTerm,
+		LeadershipTransfer: true,
+	}, &resp))
+	require.False(t, resp.Granted, "restarted node must not vote twice in term %d", voteTerm)
+
+	// the restarted node applies the replicated payloads, and follows the cluster again
+	require.NoError(t, leader.c.CommitUnsafePayload(ctx, testPayload(3)))
+	require.Eventually(t, func() bool {
+		received := voter.Received()
+		return len(received) > 0 && received[len(received)-1].ID() == testPayload(3).ID()
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestParsePeers(t *testing.T) {
+	peers, err := ParsePeers([]string{"a=127.0.0.1:9290", "b=example.com:9290"})
+	require.NoError(t, err)
+	require.Equal(t, []Peer{{ID: "a", Addr: "127.0.0.1:9290"}, {ID: "b", Addr: "example.com:9290"}}, peers)
+
+	_, err = ParsePeers([]string{"127.0.0.1:9290"})
+	require.Error(t, err)
+}
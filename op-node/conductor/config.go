@@ -0,0 +1,78 @@
+package conductor
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type Peer struct {
+	// ID is the unique Raft server ID of the peer
+	ID string
+	// Addr is the host:port address the peer can be reached at for Raft traffic
+	Addr string
+}
+
+type Config struct {
+	// ServerID is the unique Raft server ID of this node
+	ServerID string
+
+	// ListenAddr is the host:port address to accept Raft traffic on
+	ListenAddr string
+
+	// Advertise is the host:port address peers can reach this node at. Defaults to ListenAddr if empty.
+	Advertise string
+
+	// Peers are the other sequencer nodes of the cluster, used when bootstrapping the cluster.
+	Peers []Peer
+
+	// Bootstrap the cluster with this node and its peers, if the cluster is not bootstrapped yet.
+	// Bootstrapping the same cluster configuration on all nodes is safe.
+	Bootstrap bool
+
+	// DataDir is the directory the Raft log, vote and snapshots are persisted in
+	DataDir string
+
+	// HeartbeatTimeout is the time without contact with the leader before an election is started.
+	// The Raft default is used if 0.
+	HeartbeatTimeout time.Duration
+}
+
+func (c *Config) Check() error {
+	if c.ServerID == "" {
+		return errors.New("missing raft server ID")
+	}
+	if c.DataDir == "" {
+		return errors.New("missing raft data dir")
+	}
+	for _, p := range c.Peers {
+		if p.ID == "" || p.Addr == "" {
+			return fmt.Errorf("invalid raft peer %q at %q", p.ID, p.Addr)
+		}
+		if p.ID == c.ServerID {
+			return fmt.Errorf("raft peer %q has the same ID as this node", p.ID)
+		}
+	}
+	return nil
+}
+
+func (c *Config) AdvertiseAddr() string {
+	if c.Advertise != "" {
+		return c.Advertise
+	}
+	return c.ListenAddr
+}
+
+// ParsePeers parses peers from "id=host:port" entries.
+func ParsePeers(entries []string) ([]Peer, error) {
+	peers := make([]Peer, 0, len(entries))
+	for _, e := range entries {
+		id, addr, ok := strings.Cut(e, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid raft peer %q, expected id=host:port", e)
+		}
+		peers = append(peers, Peer{ID: id, Addr: addr})
+	}
+	return peers, nil
+}
@@ -0,0 +1,101 @@
+package conductor
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/hashicorp/raft"
+
+	"github.com/ethereum-optimism/optimism/op-node/eth"
+)
+
+// maxReplicatedPayloads is the number of most recent unsafe payloads that is retained by the FSM,
+// for a new leader to catch up with before it continues sequencing.
+const maxReplicatedPayloads = 64
+
+// unsafeHeadFSM is the replicated state machine: the most recent unsafe payloads, in order of block number.
+type unsafeHeadFSM struct {
+	mu       sync.RWMutex
+	payloads []*eth.ExecutionPayload
+
+	// onPayload is called with every newly applied payload
+	onPayload func(payload *eth.ExecutionPayload)
+}
+
+var _ raft.FSM = (*unsafeHeadFSM)(nil)
+
+// Apply applies a committed unsafe payload. Payloads that are not the child of the latest payload, i.e. payloads that
+// leave a gap or fork the replicated chain, are ignored, and the resulting error is returned to the node that
+// proposed the payload.
+func (f *unsafeHeadFSM) Apply(l *raft.Log) interface{} {
+	var payload eth.ExecutionPayload
+	if err := json.Unmarshal(l.Data, &payload); err != nil {
+		return fmt.Errorf("failed to decode replicated payload: %w", err)
+	}
+	f.mu.Lock()
+	if n := len(f.payloads); n > 0 {
+		latest := f.payloads[n-1]
+		if payload.ParentHash != latest.BlockHash || payload.BlockNumber != latest.BlockNumber+1 {
+			f.mu.Unlock()
+			return fmt.Errorf("payload %s with parent %s does not extend replicated unsafe head %s",
+				payload.ID(), payload.ParentID(), latest.ID())
+		}
+	}
+	f.payloads = append(f.payloads, &payload)
+	if len(f.payloads) > maxReplicatedPayloads {
+		f.payloads = f.payloads[len(f.payloads)-maxReplicatedPayloads:]
+	}
+	f.mu.Unlock()
+
+	if f.onPayload != nil {
+		f.onPayload(&payload)
+	}
+	return nil
+}
+
+// payloadsAfter returns the replicated payloads with a block number larger than the given number.
+func (f *unsafeHeadFSM) payloadsAfter(num uint64) []*eth.ExecutionPayload {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	var out []*eth.ExecutionPayload
+	for _, p := range f.payloads {
+		if uint64(p.BlockNumber) > num {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+func (f *unsafeHeadFSM) Snapshot() (raft.FSMSnapshot, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return &unsafeHeadSnapshot{payloads: append([]*eth.ExecutionPayload(nil), f.payloads...)}, nil
+}
+
+func (f *unsafeHeadFSM) Restore(snapshot io.ReadCloser) error {
+	defer snapshot.Close()
+	var payloads []*eth.ExecutionPayload
+	if err := json.NewDecoder(snapshot).Decode(&payloads); err != nil {
+		return fmt.Errorf("failed to decode unsafe head snapshot: %w", err)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.payloads = payloads
+	return nil
+}
+
+type unsafeHeadSnapshot struct {
+	payloads []*eth.ExecutionPayload
+}
+
+func (s *unsafeHeadSnapshot) Persist(sink raft.SnapshotSink) error {
+	if err := json.NewEncoder(sink).Encode(s.payloads); err != nil {
+		_ = sink.Cancel()
+		return fmt.Errorf("failed to encode unsafe head snapshot: %w", err)
+	}
+	return sink.Close()
+}
+
+func (s *unsafeHeadSnapshot) Release() {}
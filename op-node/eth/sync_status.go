@@ -25,3 +25,13 @@ type SyncStatus struct {
 	// finalized L1 information, thus irreversible.
 	FinalizedL2 L2BlockRef `json:"finalized_l2"`
 }
+
+// DerivationResetEvent is published when the derivation pipeline of the driver is reset.
+type DerivationResetEvent struct {
+	// Reason describes what triggered the reset.
+	Reason string `json:"reason"`
+	// UnsafeL2 and SafeL2 are the L2 heads at the time of the reset,
+	// before the pipeline walks back to find a new safe starting point.
+	UnsafeL2 L2BlockRef `json:"unsafe_l2"`
+	SafeL2   L2BlockRef `json:"safe_l2"`
+}
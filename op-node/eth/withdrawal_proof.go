@@ -0,0 +1,38 @@
+package eth
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// WithdrawalProof contains everything the OptimismPortal needs to finalize a withdrawal,
+// proven against the earliest L2 output that includes the withdrawal.
+type WithdrawalProof struct {
+	// The withdrawal transaction, as initiated in the L2ToL1MessagePasser.
+	Nonce    *hexutil.Big   `json:"nonce"`
+	Sender   common.Address `json:"sender"`
+	Target   common.Address `json:"target"`
+	Value    *hexutil.Big   `json:"value"`
+	GasLimit *hexutil.Big   `json:"gasLimit"`
+	Data     hexutil.Bytes  `json:"data"`
+
+	// L2BlockNumber is the L2 block of the output that the withdrawal is proven against.
+	L2BlockNumber hexutil.Uint64 `json:"l2BlockNumber"`
+	// OutputRoot and OutputTimestamp are the contents of the output proposal in the L2OutputOracle.
+	// The withdrawal can be finalized once the finalization period has passed since the OutputTimestamp.
+	OutputRoot      Bytes32        `json:"outputRoot"`
+	OutputTimestamp hexutil.Uint64 `json:"outputTimestamp"`
+
+	OutputRootProof OutputRootProof `json:"outputRootProof"`
+	// WithdrawalProof is the RLP encoded list of trie nodes that prove the withdrawal
+	// in the storage of the L2ToL1MessagePasser.
+	WithdrawalProof hexutil.Bytes `json:"withdrawalProof"`
+}
+
+// OutputRootProof contains the preimage elements of an L2 output root.
+type OutputRootProof struct {
+	Version                  Bytes32     `json:"version"`
+	StateRoot                common.Hash `json:"stateRoot"`
+	MessagePasserStorageRoot common.Hash `json:"messagePasserStorageRoot"`
+	LatestBlockhash          common.Hash `json:"latestBlockhash"`
+}
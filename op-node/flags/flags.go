@@ -2,9 +2,12 @@ package flags
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/urfave/cli"
+
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
 )
 
 // Flags
@@ -30,7 +33,7 @@ var (
 	}
 	RollupConfig = cli.StringFlag{
 		Name:   "rollup.config",
-		Usage:  "Rollup chain parameters",
+		Usage:  "Rollup chain parameters. Required unless the chain is selected with the network flag",
 		EnvVar: prefixEnvVar("ROLLUP_CONFIG"),
 	}
 	RPCListenAddr = cli.StringFlag{
@@ -50,11 +53,37 @@ var (
 	}
 
 	/* Optional Flags */
+	Network = cli.StringFlag{
+		Name:   "network",
+		Usage:  fmt.Sprintf("Predefined network to use the rollup chain parameters of, instead of the rollup config file. Known networks: %s", strings.Join(rollup.NetworkNames(), ", ")),
+		EnvVar: prefixEnvVar("NETWORK"),
+	}
+	RollupCheckPeer = cli.StringFlag{
+		Name:   "rollup.check-peer",
+		Usage:  "Address of the RPC endpoint of a trusted rollup node, to compare the rollup chain parameters with at startup. The node does not start if the parameters differ",
+		EnvVar: prefixEnvVar("ROLLUP_CHECK_PEER"),
+	}
 	L1TrustRPC = cli.BoolFlag{
 		Name:   "l1.trustrpc",
 		Usage:  "Trust the L1 RPC, sync faster at risk of malicious/buggy RPC providing bad or inconsistent L1 data",
 		EnvVar: prefixEnvVar("L1_TRUST_RPC"),
 	}
+	L1FallbackAddrs = cli.StringSliceFlag{
+		Name:   "l1.fallback",
+		Usage:  "Address of an additional L1 User JSON-RPC endpoint to fail over to when the primary is failing or lagging behind. May be repeated, in order of priority",
+		EnvVar: prefixEnvVar("L1_FALLBACK_RPCS"),
+	}
+	L1MaxHeadLag = cli.Uint64Flag{
+		Name:   "l1.max-head-lag",
+		Usage:  "Number of blocks an L1 endpoint may lag behind the other L1 endpoints before failing over. 0 to disable.",
+		EnvVar: prefixEnvVar("L1_MAX_HEAD_LAG"),
+		Value:  5,
+	}
+	L1CrossCheck = cli.BoolFlag{
+		Name:   "l1.cross-check",
+		Usage:  "Cross-check block hashes and receipt roots of L1 blocks with a second L1 endpoint, requires a fallback endpoint",
+		EnvVar: prefixEnvVar("L1_CROSS_CHECK"),
+	}
 	L2EngineJWTSecret = cli.StringFlag{
 		Name:        "l2.jwt-secret",
 		Usage:       "Path to JWT secret key. Keys are 32 bytes, hex encoded in a file. A new key will be generated if left empty.",
@@ -82,6 +111,44 @@ var (
 		Required: false,
 		Value:    4,
 	}
+	SequencerFallbackMargin = cli.Uint64Flag{
+		Name:     "sequencer.fallback-margin",
+		Usage:    "Number of L1 blocks before the sequencing window of the oldest unsafe L2 block closes, at which the sequencer replaces its unsafe blocks with the deposit-only blocks derivation produces once the window closes, until the batch submitter catches up. Limited to half the sequencing window.",
+		EnvVar:   prefixEnvVar("SEQUENCER_FALLBACK_MARGIN"),
+		Required: false,
+		Value:    10,
+	}
+	SequencerRaftServerID = cli.StringFlag{
+		Name:   "sequencer.raft.server-id",
+		Usage:  "Unique ID of this sequencer in the Raft cluster that elects the active sequencer. Sequencer high-availability is disabled if empty.",
+		EnvVar: prefixEnvVar("SEQUENCER_RAFT_SERVER_ID"),
+	}
+	SequencerRaftListenAddr = cli.StringFlag{
+		Name:   "sequencer.raft.addr",
+		Usage:  "host:port address to listen on for Raft traffic of the sequencer cluster",
+		EnvVar: prefixEnvVar("SEQUENCER_RAFT_ADDR"),
+		Value:  "127.0.0.1:9290",
+	}
+	SequencerRaftAdvertiseAddr = cli.StringFlag{
+		Name:   "sequencer.raft.advertise",
+		Usage:  "host:port address the other sequencers can reach this node at for Raft traffic. Defaults to sequencer.raft.addr if empty.",
+		EnvVar: prefixEnvVar("SEQUENCER_RAFT_ADVERTISE"),
+	}
+	SequencerRaftPeers = cli.StringSliceFlag{
+		Name:   "sequencer.raft.peers",
+		Usage:  "Other sequencers of the Raft cluster, as id=host:port, used to bootstrap the cluster. May be repeated.",
+		EnvVar: prefixEnvVar("SEQUENCER_RAFT_PEERS"),
+	}
+	SequencerRaftBootstrap = cli.BoolFlag{
+		Name:   "sequencer.raft.bootstrap",
+		Usage:  "Bootstrap the Raft cluster with this node and its peers, if not bootstrapped yet",
+		EnvVar: prefixEnvVar("SEQUENCER_RAFT_BOOTSTRAP"),
+	}
+	SequencerRaftDataDir = cli.StringFlag{
+		Name:   "sequencer.raft.data-dir",
+		Usage:  "Directory to persist the Raft log, vote and snapshots of the sequencer cluster in. Required if sequencer high-availability is enabled.",
+		EnvVar: prefixEnvVar("SEQUENCER_RAFT_DATA_DIR"),
+	}
 	L1EpochPollIntervalFlag = cli.DurationFlag{
 		Name:     "l1.epoch-poll-interval",
 		Usage:    "Poll interval for retrieving new L1 epoch updates such as safe and finalized block changes. Disabled if 0 or negative.",
@@ -89,6 +156,17 @@ var (
 		Required: false,
 		Value:    time.Second * 12 * 32,
 	}
+	L1DiskCachePathFlag = cli.StringFlag{
+		Name:   "l1.disk-cache",
+		Usage:  "Directory to persist fetched L1 blocks and receipts in, to serve restarts and pipeline resets locally. Disabled if empty.",
+		EnvVar: prefixEnvVar("L1_DISK_CACHE"),
+	}
+	L1DiskCacheSizeFlag = cli.Uint64Flag{
+		Name:   "l1.disk-cache-size",
+		Usage:  "Maximum size of the L1 disk cache in MiB, the oldest cached blocks are evicted first",
+		EnvVar: prefixEnvVar("L1_DISK_CACHE_SIZE"),
+		Value:  1024,
+	}
 	LogLevelFlag = cli.StringFlag{
 		Name:   "log.level",
 		Usage:  "The lowest log level that will be output",
@@ -140,6 +218,22 @@ var (
 		Value:  6060,
 		EnvVar: prefixEnvVar("PPROF_PORT"),
 	}
+	OTelEndpointFlag = cli.StringFlag{
+		Name:   "otel.endpoint",
+		Usage:  "OTLP/HTTP endpoint (host:port) of an OpenTelemetry collector to export derivation pipeline traces to. Tracing is disabled if empty.",
+		EnvVar: prefixEnvVar("OTEL_ENDPOINT"),
+	}
+	OTelInsecureFlag = cli.BoolFlag{
+		Name:   "otel.insecure",
+		Usage:  "Export traces to the OpenTelemetry collector over plain HTTP instead of HTTPS",
+		EnvVar: prefixEnvVar("OTEL_INSECURE"),
+	}
+	OTelSampleRatioFlag = cli.Float64Flag{
+		Name:   "otel.sample-ratio",
+		Usage:  "Ratio of derivation pipeline steps to trace, between 0 and 1",
+		Value:  1,
+		EnvVar: prefixEnvVar("OTEL_SAMPLE_RATIO"),
+	}
 	SnapshotLog = cli.StringFlag{
 		Name:   "snapshotlog.file",
 		Usage:  "Path to the snapshot log file",
@@ -172,12 +266,26 @@ var requiredFlags = []cli.Flag{
 }
 
 var optionalFlags = append([]cli.Flag{
+	Network,
+	RollupCheckPeer,
 	L1TrustRPC,
+	L1FallbackAddrs,
+	L1MaxHeadLag,
+	L1CrossCheck,
 	L2EngineJWTSecret,
 	VerifierL1Confs,
 	SequencerEnabledFlag,
 	SequencerL1Confs,
+	SequencerFallbackMargin,
+	SequencerRaftServerID,
+	SequencerRaftListenAddr,
+	SequencerRaftAdvertiseAddr,
+	SequencerRaftPeers,
+	SequencerRaftBootstrap,
+	SequencerRaftDataDir,
 	L1EpochPollIntervalFlag,
+	L1DiskCachePathFlag,
+	L1DiskCacheSizeFlag,
 	LogLevelFlag,
 	LogFormatFlag,
 	LogColorFlag,
@@ -188,6 +296,9 @@ var optionalFlags = append([]cli.Flag{
 	PprofEnabledFlag,
 	PprofAddrFlag,
 	PprofPortFlag,
+	OTelEndpointFlag,
+	OTelInsecureFlag,
+	OTelSampleRatioFlag,
 	SnapshotLog,
 	HeartbeatEnabledFlag,
 	HeartbeatMonikerFlag,
@@ -207,8 +318,12 @@ func CheckRequired(ctx *cli.Context) error {
 		return fmt.Errorf("flag %s is required", L2EngineAddr.Name)
 	}
 	rollupConfig := ctx.GlobalString(RollupConfig.Name)
-	if rollupConfig == "" {
-		return fmt.Errorf("flag %s is required", RollupConfig.Name)
+	network := ctx.GlobalString(Network.Name)
+	if rollupConfig == "" && network == "" {
+		return fmt.Errorf("flag %s or %s is required", RollupConfig.Name, Network.Name)
+	}
+	if rollupConfig != "" && network != "" {
+		return fmt.Errorf("flags %s and %s cannot both be set", RollupConfig.Name, Network.Name)
 	}
 	rpcListenAddr := ctx.GlobalString(RPCListenAddr.Name)
 	if rpcListenAddr == "" {
@@ -200,6 +200,14 @@ var (
 		Value:     "",
 		EnvVar:    p2pEnv("SEQUENCER_KEY"),
 	}
+	SequencerP2PSignersFile = cli.StringFlag{
+		Name:      "p2p.sequencer.signers-file",
+		Usage:     "File path of a JSON list of sequencer signers authorized to sign p2p blocks, with activation and expiry L2 block numbers. Replaces the p2p sequencer address of the rollup config. Reloaded every minute and on SIGHUP.",
+		Required:  false,
+		TakesFile: true,
+		Value:     "",
+		EnvVar:    p2pEnv("SEQUENCER_SIGNERS_FILE"),
+	}
 )
 
 // None of these flags are strictly required.
@@ -230,4 +238,5 @@ var p2pFlags = []cli.Flag{
 	PeerstorePath,
 	DiscoveryPath,
 	SequencerP2PKeyFlag,
+	SequencerP2PSignersFile,
 }
@@ -24,8 +24,11 @@ import (
 const (
 	Namespace = "op_node"
 
-	RPCServerSubsystem = "rpc_server"
-	RPCClientSubsystem = "rpc_client"
+	RPCServerSubsystem  = "rpc_server"
+	RPCClientSubsystem  = "rpc_client"
+	L1EndpointSubsystem = "l1_endpoint"
+	P2PSubsystem        = "p2p"
+	DerivationSubsystem = "derivation"
 
 	BatchMethod = "<batch>"
 )
@@ -40,6 +43,12 @@ type Metrics struct {
 	RPCClientRequestDurationSeconds *prometheus.HistogramVec
 	RPCClientResponsesTotal         *prometheus.CounterVec
 
+	L1EndpointHealthy         *prometheus.GaugeVec
+	L1EndpointHead            *prometheus.GaugeVec
+	L1EndpointErrorsTotal     *prometheus.CounterVec
+	L1EndpointFailoversTotal  *prometheus.CounterVec
+	L1CrossCheckMismatchTotal *prometheus.CounterVec
+
 	L1SourceCache *CacheMetrics
 	L2SourceCache *CacheMetrics
 
@@ -54,6 +63,17 @@ type Metrics struct {
 	UnsafePayloadsBufferLen     prometheus.Gauge
 	UnsafePayloadsBufferMemSize prometheus.Gauge
 
+	DerivationDropsTotal *prometheus.CounterVec
+
+	DerivationStageStepSeconds *prometheus.HistogramVec
+	DerivationStageResetsTotal *prometheus.CounterVec
+	DerivationStageErrorsTotal *prometheus.CounterVec
+	ChannelBankChannels        prometheus.Gauge
+	ChannelBankFrames          prometheus.Gauge
+	ChannelBankMemSize         prometheus.Gauge
+	BatchQueueBatches          prometheus.Gauge
+	BatchQueueL1Blocks         prometheus.Gauge
+
 	RefsNumber  *prometheus.GaugeVec
 	RefsTime    *prometheus.GaugeVec
 	RefsHash    *prometheus.GaugeVec
@@ -67,6 +87,12 @@ type Metrics struct {
 
 	TransactionsSequencedTotal prometheus.Counter
 
+	SequencerBatchWindowRemaining prometheus.Gauge
+	SequencerFallback             prometheus.Gauge
+	SequencerFallbackBlocksTotal  prometheus.Counter
+
+	PeerSyncStates *prometheus.GaugeVec
+
 	registry *prometheus.Registry
 }
 
@@ -137,6 +163,48 @@ func NewMetrics(procName string) *Metrics {
 			"error",
 		}),
 
+		L1EndpointHealthy: promauto.With(registry).NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: ns,
+			Subsystem: L1EndpointSubsystem,
+			Name:      "healthy",
+			Help:      "1 if the L1 endpoint is responsive and keeping up with the other endpoints",
+		}, []string{
+			"endpoint",
+		}),
+		L1EndpointHead: promauto.With(registry).NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: ns,
+			Subsystem: L1EndpointSubsystem,
+			Name:      "head",
+			Help:      "Latest block number reported by the L1 endpoint",
+		}, []string{
+			"endpoint",
+		}),
+		L1EndpointErrorsTotal: promauto.With(registry).NewCounterVec(prometheus.CounterOpts{
+			Namespace: ns,
+			Subsystem: L1EndpointSubsystem,
+			Name:      "errors_total",
+			Help:      "Count of requests the L1 endpoint failed to serve",
+		}, []string{
+			"endpoint",
+		}),
+		L1EndpointFailoversTotal: promauto.With(registry).NewCounterVec(prometheus.CounterOpts{
+			Namespace: ns,
+			Subsystem: L1EndpointSubsystem,
+			Name:      "failovers_total",
+			Help:      "Count of switches from one L1 endpoint to another",
+		}, []string{
+			"from",
+			"to",
+		}),
+		L1CrossCheckMismatchTotal: promauto.With(registry).NewCounterVec(prometheus.CounterOpts{
+			Namespace: ns,
+			Subsystem: L1EndpointSubsystem,
+			Name:      "cross_check_mismatch_total",
+			Help:      "Count of responses with block data that another L1 endpoint disagreed with",
+		}, []string{
+			"method",
+		}),
+
 		L1SourceCache: NewCacheMetrics(registry, ns, "l1_source_cache", "L1 Source cache"),
 		L2SourceCache: NewCacheMetrics(registry, ns, "l2_source_cache", "L2 Source cache"),
 
@@ -163,6 +231,71 @@ func NewMetrics(procName string) *Metrics {
 			Help:      "Total estimated memory size of buffered L2 unsafe payloads",
 		}),
 
+		DerivationDropsTotal: promauto.With(registry).NewCounterVec(prometheus.CounterOpts{
+			Namespace: ns,
+			Name:      "derivation_drops_total",
+			Help:      "Count of batches, frames and channels dropped by the derivation pipeline, by reason",
+		}, []string{
+			"kind",
+			"reason",
+		}),
+
+		DerivationStageStepSeconds: promauto.With(registry).NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: ns,
+			Subsystem: DerivationSubsystem,
+			Name:      "stage_step_seconds",
+			Buckets:   []float64{.0001, .0005, .001, .005, .01, .05, .1, .5, 1, 5},
+			Help:      "Histogram of the duration of steps of the derivation pipeline stages",
+		}, []string{
+			"stage",
+		}),
+		DerivationStageResetsTotal: promauto.With(registry).NewCounterVec(prometheus.CounterOpts{
+			Namespace: ns,
+			Subsystem: DerivationSubsystem,
+			Name:      "stage_resets_total",
+			Help:      "Count of reset steps of the derivation pipeline stages",
+		}, []string{
+			"stage",
+		}),
+		DerivationStageErrorsTotal: promauto.With(registry).NewCounterVec(prometheus.CounterOpts{
+			Namespace: ns,
+			Subsystem: DerivationSubsystem,
+			Name:      "stage_errors_total",
+			Help:      "Count of errors of the derivation pipeline stages, in regular and reset steps",
+		}, []string{
+			"stage",
+		}),
+		ChannelBankChannels: promauto.With(registry).NewGauge(prometheus.GaugeOpts{
+			Namespace: ns,
+			Subsystem: DerivationSubsystem,
+			Name:      "channel_bank_channels",
+			Help:      "Number of channels buffered in the channel bank",
+		}),
+		ChannelBankFrames: promauto.With(registry).NewGauge(prometheus.GaugeOpts{
+			Namespace: ns,
+			Subsystem: DerivationSubsystem,
+			Name:      "channel_bank_frames",
+			Help:      "Number of frames buffered in the channel bank",
+		}),
+		ChannelBankMemSize: promauto.With(registry).NewGauge(prometheus.GaugeOpts{
+			Namespace: ns,
+			Subsystem: DerivationSubsystem,
+			Name:      "channel_bank_mem_size",
+			Help:      "Total estimated memory size of the channels buffered in the channel bank",
+		}),
+		BatchQueueBatches: promauto.With(registry).NewGauge(prometheus.GaugeOpts{
+			Namespace: ns,
+			Subsystem: DerivationSubsystem,
+			Name:      "batch_queue_batches",
+			Help:      "Number of batches buffered in the batch queue",
+		}),
+		BatchQueueL1Blocks: promauto.With(registry).NewGauge(prometheus.GaugeOpts{
+			Namespace: ns,
+			Subsystem: DerivationSubsystem,
+			Name:      "batch_queue_l1_blocks",
+			Help:      "Number of L1 blocks buffered in the batch queue",
+		}),
+
 		RefsNumber: promauto.With(registry).NewGaugeVec(prometheus.GaugeOpts{
 			Namespace: ns,
 			Name:      "refs_number",
@@ -217,6 +350,31 @@ func NewMetrics(procName string) *Metrics {
 			Help:      "Count of total transactions sequenced",
 		}),
 
+		SequencerBatchWindowRemaining: promauto.With(registry).NewGauge(prometheus.GaugeOpts{
+			Namespace: ns,
+			Name:      "sequencer_batch_window_remaining",
+			Help:      "Number of L1 blocks left before the sequencing window of the oldest unsafe L2 block closes",
+		}),
+		SequencerFallback: promauto.With(registry).NewGauge(prometheus.GaugeOpts{
+			Namespace: ns,
+			Name:      "sequencer_fallback",
+			Help:      "1 if the sequencer fell back to deposit-only blocks, because batch submission stalled",
+		}),
+		SequencerFallbackBlocksTotal: promauto.With(registry).NewCounter(prometheus.CounterOpts{
+			Namespace: ns,
+			Name:      "sequencer_fallback_blocks_total",
+			Help:      "Count of deposit-only blocks sequenced while batch submission stalled, matching the blocks derivation produces once the sequencing window closes",
+		}),
+
+		PeerSyncStates: promauto.With(registry).NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: ns,
+			Subsystem: P2PSubsystem,
+			Name:      "peer_sync_states",
+			Help:      "Number of peers by their exchanged sync status: synced, lagging or conflicting with our chain",
+		}, []string{
+			"state",
+		}),
+
 		registry: registry,
 	}
 }
@@ -280,6 +438,28 @@ func (m *Metrics) RecordRPCClientResponse(method string, err error) {
 	m.RPCClientResponsesTotal.WithLabelValues(method, errStr).Inc()
 }
 
+// RecordL1EndpointHealth records whether the named L1 endpoint is healthy, and the latest head it reported.
+func (m *Metrics) RecordL1EndpointHealth(endpoint string, healthy bool, head uint64) {
+	var val float64
+	if healthy {
+		val = 1
+	}
+	m.L1EndpointHealthy.WithLabelValues(endpoint).Set(val)
+	m.L1EndpointHead.WithLabelValues(endpoint).Set(float64(head))
+}
+
+func (m *Metrics) RecordL1EndpointError(endpoint string) {
+	m.L1EndpointErrorsTotal.WithLabelValues(endpoint).Inc()
+}
+
+func (m *Metrics) RecordL1EndpointFailover(from string, to string) {
+	m.L1EndpointFailoversTotal.WithLabelValues(from, to).Inc()
+}
+
+func (m *Metrics) RecordL1CrossCheckMismatch(method string) {
+	m.L1CrossCheckMismatchTotal.WithLabelValues(method).Inc()
+}
+
 func (m *Metrics) SetDerivationIdle(status bool) {
 	var val float64
 	if status {
@@ -340,14 +520,60 @@ func (m *Metrics) RecordUnsafePayloadsBuffer(length uint64, memSize uint64, next
 	m.UnsafePayloadsBufferMemSize.Set(float64(memSize))
 }
 
+func (m *Metrics) RecordDerivationDrop(kind string, reason string) {
+	m.DerivationDropsTotal.WithLabelValues(kind, reason).Inc()
+}
+
+func (m *Metrics) RecordStageStep(stage string, duration time.Duration) {
+	m.DerivationStageStepSeconds.WithLabelValues(stage).Observe(duration.Seconds())
+}
+
+func (m *Metrics) RecordStageReset(stage string) {
+	m.DerivationStageResetsTotal.WithLabelValues(stage).Inc()
+}
+
+func (m *Metrics) RecordStageError(stage string) {
+	m.DerivationStageErrorsTotal.WithLabelValues(stage).Inc()
+}
+
+func (m *Metrics) RecordChannelBank(channels int, frames int, memSize uint64) {
+	m.ChannelBankChannels.Set(float64(channels))
+	m.ChannelBankFrames.Set(float64(frames))
+	m.ChannelBankMemSize.Set(float64(memSize))
+}
+
+func (m *Metrics) RecordBatchQueue(batches int, l1Blocks int) {
+	m.BatchQueueBatches.Set(float64(batches))
+	m.BatchQueueL1Blocks.Set(float64(l1Blocks))
+}
+
 func (m *Metrics) CountSequencedTxs(count int) {
 	m.TransactionsSequencedTotal.Add(float64(count))
 }
 
+func (m *Metrics) RecordSequencerBatchWindow(remaining int64, fallback bool) {
+	m.SequencerBatchWindowRemaining.Set(float64(remaining))
+	if fallback {
+		m.SequencerFallback.Set(1)
+	} else {
+		m.SequencerFallback.Set(0)
+	}
+}
+
+func (m *Metrics) RecordSequencerFallbackBlock() {
+	m.SequencerFallbackBlocksTotal.Inc()
+}
+
 func (m *Metrics) RecordL1ReorgDepth(d uint64) {
 	m.L1ReorgDepth.Observe(float64(d))
 }
 
+func (m *Metrics) RecordPeerSyncStates(synced, lagging, conflicting int) {
+	m.PeerSyncStates.WithLabelValues("synced").Set(float64(synced))
+	m.PeerSyncStates.WithLabelValues("lagging").Set(float64(lagging))
+	m.PeerSyncStates.WithLabelValues("conflicting").Set(float64(conflicting))
+}
+
 // Serve starts the metrics server on the given hostname and port.
 // The server will be closed when the passed-in context is cancelled.
 func (m *Metrics) Serve(ctx context.Context, hostname string, port int) error {
@@ -3,14 +3,22 @@ package node
 import (
 	"context"
 	"fmt"
+	"math/big"
 
+	"github.com/ethereum-optimism/optimism/op-bindings/bindings"
 	"github.com/ethereum-optimism/optimism/op-bindings/predeploys"
 	"github.com/ethereum-optimism/optimism/op-node/eth"
 	"github.com/ethereum-optimism/optimism/op-node/metrics"
 	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
 	"github.com/ethereum-optimism/optimism/op-node/version"
+	"github.com/ethereum-optimism/optimism/op-node/withdrawals"
 	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/rpc"
 )
@@ -24,6 +32,13 @@ type l2EthClient interface {
 type driverClient interface {
 	SyncStatus(ctx context.Context) (*eth.SyncStatus, error)
 	ResetDerivationPipeline(context.Context) error
+	DerivationDiagnostics(ctx context.Context) ([]derive.DropRecord, error)
+
+	SubscribeUnsafeHead(ch chan<- eth.L2BlockRef) event.Subscription
+	SubscribeSafeHead(ch chan<- eth.L2BlockRef) event.Subscription
+	SubscribeFinalizedHead(ch chan<- eth.L2BlockRef) event.Subscription
+	SubscribeL1Head(ch chan<- eth.L1BlockRef) event.Subscription
+	SubscribeDerivationReset(ch chan<- eth.DerivationResetEvent) event.Subscription
 }
 
 type adminAPI struct {
@@ -44,6 +59,13 @@ func (n *adminAPI) ResetDerivationPipeline(ctx context.Context) error {
 	return n.dr.ResetDerivationPipeline(ctx)
 }
 
+// DerivationDiagnostics returns the most recent batches, frames and channels that were dropped by the derivation pipeline.
+func (n *adminAPI) DerivationDiagnostics(ctx context.Context) ([]derive.DropRecord, error) {
+	recordDur := n.m.RecordRPCServerRequest("admin_derivationDiagnostics")
+	defer recordDur()
+	return n.dr.DerivationDiagnostics(ctx)
+}
+
 type nodeAPI struct {
 	config *rollup.Config
 	client l2EthClient
@@ -114,6 +136,184 @@ func (n *nodeAPI) Version(ctx context.Context) (string, error) {
 	return version.Version + "-" + version.Meta, nil
 }
 
+// UnsafeHead is the "unsafeHead" subscription of optimism_subscribe, notifying of every new unsafe L2 head.
+func (n *nodeAPI) UnsafeHead(ctx context.Context) (*rpc.Subscription, error) {
+	recordDur := n.m.RecordRPCServerRequest("optimism_subscribe")
+	defer recordDur()
+	return notifySubscription(ctx, n.dr.SubscribeUnsafeHead)
+}
+
+// SafeHead is the "safeHead" subscription of optimism_subscribe, notifying of every new safe L2 head.
+func (n *nodeAPI) SafeHead(ctx context.Context) (*rpc.Subscription, error) {
+	recordDur := n.m.RecordRPCServerRequest("optimism_subscribe")
+	defer recordDur()
+	return notifySubscription(ctx, n.dr.SubscribeSafeHead)
+}
+
+// FinalizedHead is the "finalizedHead" subscription of optimism_subscribe, notifying of every new finalized L2 head.
+func (n *nodeAPI) FinalizedHead(ctx context.Context) (*rpc.Subscription, error) {
+	recordDur := n.m.RecordRPCServerRequest("optimism_subscribe")
+	defer recordDur()
+	return notifySubscription(ctx, n.dr.SubscribeFinalizedHead)
+}
+
+// L1Head is the "l1Head" subscription of optimism_subscribe, notifying of every new L1 head seen by the driver.
+func (n *nodeAPI) L1Head(ctx context.Context) (*rpc.Subscription, error) {
+	recordDur := n.m.RecordRPCServerRequest("optimism_subscribe")
+	defer recordDur()
+	return notifySubscription(ctx, n.dr.SubscribeL1Head)
+}
+
+// DerivationReset is the "derivationReset" subscription of optimism_subscribe, notifying of every derivation pipeline reset.
+func (n *nodeAPI) DerivationReset(ctx context.Context) (*rpc.Subscription, error) {
+	recordDur := n.m.RecordRPCServerRequest("optimism_subscribe")
+	defer recordDur()
+	return notifySubscription(ctx, n.dr.SubscribeDerivationReset)
+}
+
+// notifySubscription creates a RPC subscription that forwards all events of the given event subscription,
+// until the RPC subscription is closed by the client or the connection is closed.
+func notifySubscription[T any](ctx context.Context, subscribe func(ch chan<- T) event.Subscription) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return nil, rpc.ErrNotificationsUnsupported
+	}
+	rpcSub := notifier.CreateSubscription()
+
+	events := make(chan T, 10)
+	sub := subscribe(events)
+	go func() {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case ev := <-events:
+				// errors are due to a closed connection, which is handled by the Err channel of the subscription
+				_ = notifier.Notify(rpcSub.ID, ev)
+			case <-sub.Err():
+				return
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+	return rpcSub, nil
+}
+
+type withdrawalProofClient interface {
+	withdrawals.ProofClient
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+}
+
+type withdrawalsAPI struct {
+	config *rollup.Config
+	l1     bind.ContractCaller
+	l2     withdrawalProofClient
+	log    log.Logger
+	m      *metrics.Metrics
+}
+
+func newWithdrawalsAPI(config *rollup.Config, l1 bind.ContractCaller, l2 withdrawalProofClient, log log.Logger, m *metrics.Metrics) *withdrawalsAPI {
+	return &withdrawalsAPI{
+		config: config,
+		l1:     l1,
+		l2:     l2,
+		log:    log,
+		m:      m,
+	}
+}
+
+// WithdrawalProof finds the earliest L2 output in the L2OutputOracle that includes the block of the given withdrawal
+// transaction, and returns the parameters to finalize the withdrawal with in the OptimismPortal, proven against that output.
+func (w *withdrawalsAPI) WithdrawalProof(ctx context.Context, txHash common.Hash) (*eth.WithdrawalProof, error) {
+	recordDur := w.m.RecordRPCServerRequest("optimism_withdrawalProof")
+	defer recordDur()
+
+	receipt, err := w.l2.TransactionReceipt(ctx, txHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get withdrawal receipt: %w", err)
+	}
+
+	opts := &bind.CallOpts{Context: ctx}
+	portal, err := bindings.NewOptimismPortalCaller(w.config.DepositContractAddress, w.l1)
+	if err != nil {
+		return nil, err
+	}
+	oracleAddr, err := portal.L2ORACLE(opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get L2 output oracle address: %w", err)
+	}
+	oracle, err := bindings.NewL2OutputOracleCaller(oracleAddr, w.l1)
+	if err != nil {
+		return nil, err
+	}
+	startingBlock, err := oracle.STARTINGBLOCKNUMBER(opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get starting block number of output oracle: %w", err)
+	}
+	interval, err := oracle.SUBMISSIONINTERVAL(opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get submission interval of output oracle: %w", err)
+	}
+	outputBlock, err := withdrawals.OutputBlockNumber(startingBlock.Uint64(), interval.Uint64(), receipt.BlockNumber.Uint64())
+	if err != nil {
+		return nil, err
+	}
+	latest, err := oracle.LatestBlockNumber(opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest output block number: %w", err)
+	}
+	if outputBlock > latest.Uint64() {
+		return nil, fmt.Errorf("withdrawal in L2 block %d is not included in an output yet, expected output at L2 block %d, latest output is at L2 block %d",
+			receipt.BlockNumber.Uint64(), outputBlock, latest.Uint64())
+	}
+	outputBlockNum := new(big.Int).SetUint64(outputBlock)
+	proposal, err := oracle.GetL2Output(opts, outputBlockNum)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get output at L2 block %d: %w", outputBlock, err)
+	}
+
+	header, err := w.l2.HeaderByNumber(ctx, outputBlockNum)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get L2 block %d of output: %w", outputBlock, err)
+	}
+	// This verifies the account and storage proofs against the state root of the header.
+	params, err := withdrawals.FinalizeWithdrawalParameters(ctx, w.l2, txHash, header)
+	if err != nil {
+		w.log.Error("failed to build withdrawal proof", "tx", txHash, "l2_block", outputBlock, "err", err)
+		return nil, fmt.Errorf("failed to build withdrawal proof: %w", err)
+	}
+
+	// The proof is only useful if our view of the L2 chain matches the output that was proposed to L1.
+	rootProof := params.OutputRootProof
+	outputRoot := rollup.ComputeL2OutputRoot(rootProof.Version, rootProof.LatestBlockhash, rootProof.StateRoot, rootProof.MessagePasserStorageRoot)
+	if outputRoot != proposal.OutputRoot {
+		w.log.Error("local L2 output does not match proposed output", "l2_block", outputBlock, "local", outputRoot, "proposed", eth.Bytes32(proposal.OutputRoot))
+		return nil, fmt.Errorf("local output root %s at L2 block %d does not match proposed output root %s",
+			outputRoot, outputBlock, eth.Bytes32(proposal.OutputRoot))
+	}
+
+	return &eth.WithdrawalProof{
+		Nonce:           (*hexutil.Big)(params.Nonce),
+		Sender:          params.Sender,
+		Target:          params.Target,
+		Value:           (*hexutil.Big)(params.Value),
+		GasLimit:        (*hexutil.Big)(params.GasLimit),
+		Data:            params.Data,
+		L2BlockNumber:   hexutil.Uint64(outputBlock),
+		OutputRoot:      proposal.OutputRoot,
+		OutputTimestamp: hexutil.Uint64(proposal.Timestamp.Uint64()),
+		OutputRootProof: eth.OutputRootProof{
+			Version:                  rootProof.Version,
+			StateRoot:                rootProof.StateRoot,
+			MessagePasserStorageRoot: rootProof.MessagePasserStorageRoot,
+			LatestBlockhash:          rootProof.LatestBlockhash,
+		},
+		WithdrawalProof: params.WithdrawalProof,
+	}, nil
+}
+
 func toBlockNumArg(number rpc.BlockNumber) string {
 	// never returns an error
 	out, _ := number.MarshalText()
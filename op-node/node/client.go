@@ -6,6 +6,7 @@ import (
 	"fmt"
 
 	"github.com/ethereum-optimism/optimism/op-node/backoff"
+	"github.com/ethereum-optimism/optimism/op-node/client"
 	"github.com/ethereum/go-ethereum/log"
 	gn "github.com/ethereum/go-ethereum/node"
 	"github.com/ethereum/go-ethereum/rpc"
@@ -20,6 +21,9 @@ type L2EndpointSetup interface {
 type L1EndpointSetup interface {
 	// Setup a RPC client to a L1 node to pull rollup input-data from.
 	Setup(ctx context.Context, log log.Logger) (cl *rpc.Client, trust bool, err error)
+	// SetupFallbacks sets up RPC clients to additional L1 nodes, to fail over to when the primary L1 node
+	// is failing or lagging behind. No clients are returned if there are no fallbacks.
+	SetupFallbacks(ctx context.Context, log log.Logger) (cls []*rpc.Client, cfg client.MultiRPCConfig, err error)
 }
 
 type L2EndpointConfig struct {
@@ -78,6 +82,15 @@ type L1EndpointConfig struct {
 	// against block hashes, or cached transaction sender addresses.
 	// Thus we can sync faster at the risk of the source RPC being wrong.
 	L1TrustRPC bool
+
+	// L1FallbackAddrs are the addresses of additional L1 JSON-RPC endpoints to fail over to, in order of priority.
+	L1FallbackAddrs []string
+
+	// L1MaxHeadLag is the number of blocks an L1 endpoint may lag behind the others before failing over. 0 to disable.
+	L1MaxHeadLag uint64
+
+	// L1CrossCheck enables verifying block hashes and receipt roots between L1 endpoints.
+	L1CrossCheck bool
 }
 
 var _ L1EndpointSetup = (*L1EndpointConfig)(nil)
@@ -90,6 +103,24 @@ func (cfg *L1EndpointConfig) Setup(ctx context.Context, log log.Logger) (cl *rpc
 	return l1Node, cfg.L1TrustRPC, nil
 }
 
+func (cfg *L1EndpointConfig) SetupFallbacks(ctx context.Context, log log.Logger) (cls []*rpc.Client, multiCfg client.MultiRPCConfig, err error) {
+	multiCfg = client.MultiRPCConfig{
+		MaxHeadLag: cfg.L1MaxHeadLag,
+		CrossCheck: cfg.L1CrossCheck,
+	}
+	for i, addr := range cfg.L1FallbackAddrs {
+		cl, err := dialRPCClientWithBackoff(ctx, log, addr)
+		if err != nil {
+			for _, c := range cls {
+				c.Close()
+			}
+			return nil, multiCfg, fmt.Errorf("failed to dial L1 fallback %d: %w", i+1, err)
+		}
+		cls = append(cls, cl)
+	}
+	return cls, multiCfg, nil
+}
+
 // PreparedL1Endpoint enables testing with an in-process pre-setup RPC connection to L1
 type PreparedL1Endpoint struct {
 	Client   *rpc.Client
@@ -102,6 +133,10 @@ func (p *PreparedL1Endpoint) Setup(ctx context.Context, log log.Logger) (cl *rpc
 	return p.Client, p.TrustRPC, nil
 }
 
+func (p *PreparedL1Endpoint) SetupFallbacks(ctx context.Context, log log.Logger) (cls []*rpc.Client, cfg client.MultiRPCConfig, err error) {
+	return nil, client.MultiRPCConfig{}, nil
+}
+
 // Dials a JSON-RPC endpoint repeatedly, with a backoff, until a client connection is established. Auth is optional.
 func dialRPCClientWithBackoff(ctx context.Context, log log.Logger, addr string, opts ...rpc.ClientOption) (*rpc.Client, error) {
 	bOff := backoff.Exponential()
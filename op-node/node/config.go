@@ -6,6 +6,7 @@ import (
 	"math"
 	"time"
 
+	"github.com/ethereum-optimism/optimism/op-node/conductor"
 	"github.com/ethereum-optimism/optimism/op-node/p2p"
 	"github.com/ethereum-optimism/optimism/op-node/rollup"
 	"github.com/ethereum-optimism/optimism/op-node/rollup/driver"
@@ -19,10 +20,18 @@ type Config struct {
 
 	Rollup rollup.Config
 
+	// RollupCheckPeer is the RPC address of a trusted rollup node to compare the rollup config with at startup.
+	// The check is disabled if empty.
+	RollupCheckPeer string
+
 	// P2PSigner will be used for signing off on published content
 	// if the node is sequencing and if the p2p stack is enabled
 	P2PSigner p2p.SignerSetup
 
+	// P2PAuthorizedSigners are the sequencer signers that p2p blocks are accepted from.
+	// Only the P2PSequencerAddress of the rollup config is accepted if nil.
+	P2PAuthorizedSigners p2p.AuthorizedSigners
+
 	RPC RPCConfig
 
 	P2P p2p.SetupP2P
@@ -31,12 +40,22 @@ type Config struct {
 
 	Pprof PprofConfig
 
+	OTel OTelConfig
+
 	// Used to poll the L1 for new finalized or safe blocks
 	L1EpochPollInterval time.Duration
 
+	// Directory of the persistent L1 blocks and receipts cache. Disabled if empty.
+	L1DiskCachePath string
+	// Maximum size of the persistent L1 cache, in bytes
+	L1DiskCacheSize uint64
+
 	// Optional
 	Tracer    Tracer
 	Heartbeat HeartbeatConfig
+
+	// Conductor configures the Raft cluster that elects the active sequencer. Disabled if nil.
+	Conductor *conductor.Config
 }
 
 type RPCConfig struct {
@@ -77,6 +96,23 @@ func (p PprofConfig) Check() error {
 	return nil
 }
 
+type OTelConfig struct {
+	// Endpoint is the OTLP/HTTP endpoint to export traces to. Tracing is disabled if empty.
+	Endpoint    string
+	Insecure    bool
+	SampleRatio float64
+}
+
+func (o OTelConfig) Check() error {
+	if o.Endpoint == "" {
+		return nil
+	}
+	if o.SampleRatio < 0 || o.SampleRatio > 1 {
+		return fmt.Errorf("invalid trace sample ratio: %f", o.SampleRatio)
+	}
+	return nil
+}
+
 type HeartbeatConfig struct {
 	Enabled bool
 	Moniker string
@@ -97,10 +133,21 @@ func (cfg *Config) Check() error {
 	if err := cfg.Pprof.Check(); err != nil {
 		return fmt.Errorf("pprof config error: %w", err)
 	}
+	if err := cfg.OTel.Check(); err != nil {
+		return fmt.Errorf("otel config error: %w", err)
+	}
 	if cfg.P2P != nil {
 		if err := cfg.P2P.Check(); err != nil {
 			return fmt.Errorf("p2p config error: %w", err)
 		}
 	}
+	if cfg.Conductor != nil {
+		if !cfg.Driver.SequencerEnabled {
+			return errors.New("sequencer raft cluster requires the sequencer to be enabled")
+		}
+		if err := cfg.Conductor.Check(); err != nil {
+			return fmt.Errorf("sequencer raft config error: %w", err)
+		}
+	}
 	return nil
 }
@@ -7,16 +7,20 @@ import (
 
 	"github.com/hashicorp/go-multierror"
 	"github.com/libp2p/go-libp2p-core/peer"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 
 	"github.com/ethereum-optimism/optimism/op-node/client"
+	"github.com/ethereum-optimism/optimism/op-node/conductor"
 	"github.com/ethereum-optimism/optimism/op-node/eth"
 	"github.com/ethereum-optimism/optimism/op-node/metrics"
 	"github.com/ethereum-optimism/optimism/op-node/p2p"
 	"github.com/ethereum-optimism/optimism/op-node/rollup/driver"
 	"github.com/ethereum-optimism/optimism/op-node/sources"
+	"github.com/ethereum-optimism/optimism/op-node/withdrawals"
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
 )
 
 type OpNode struct {
@@ -29,12 +33,17 @@ type OpNode struct {
 	l1FinalizedSub ethereum.Subscription // Subscription to get L1 safe blocks, a.k.a. justified data (polling)
 
 	l1Source  *sources.L1Client     // L1 Client to fetch data from
+	l1Calls   client.Client         // L1 Client to read L1 contract state with
 	l2Driver  *driver.Driver        // L2 Engine to Sync
 	l2Source  *sources.EngineClient // L2 Execution Engine RPC bindings
+	l2Proofs  *withdrawals.Client   // L2 Client to build withdrawal proofs with
 	server    *rpcServer            // RPC server hosting the rollup-node API
 	p2pNode   *p2p.NodeP2P          // P2P node functionality
 	p2pSigner p2p.Signer            // p2p gogssip application messages will be signed with this signer
 	tracer    Tracer                // tracer to get events for testing/debugging
+	conductor *conductor.Conductor  // Raft node to elect the active sequencer with, nil if disabled
+
+	traceProvider *sdktrace.TracerProvider // OpenTelemetry trace exporter, nil if disabled
 
 	// some resources cannot be stopped directly, like the p2p gossipsub router (not our design),
 	// and depend on this ctx to be closed.
@@ -73,6 +82,12 @@ func (n *OpNode) init(ctx context.Context, cfg *Config, snapshotLog log.Logger)
 	if err := n.initTracer(ctx, cfg); err != nil {
 		return err
 	}
+	if err := n.initOTel(ctx, cfg); err != nil {
+		return err
+	}
+	if err := n.checkRollupConfig(ctx, cfg); err != nil {
+		return err
+	}
 	if err := n.initL1(ctx, cfg); err != nil {
 		return err
 	}
@@ -104,15 +119,68 @@ func (n *OpNode) initTracer(ctx context.Context, cfg *Config) error {
 	return nil
 }
 
+// checkRollupConfig compares the rollup config with the config of a trusted peer, if configured,
+// to detect a misconfigured chain before it results in a silent fork.
+func (n *OpNode) checkRollupConfig(ctx context.Context, cfg *Config) error {
+	if cfg.RollupCheckPeer == "" {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	rpcClient, err := rpc.DialContext(ctx, cfg.RollupCheckPeer)
+	if err != nil {
+		return fmt.Errorf("failed to dial rollup config peer: %w", err)
+	}
+	defer rpcClient.Close()
+	peerCfg, err := sources.NewRollupClient(rpcClient).RollupConfig(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve rollup config of peer: %w", err)
+	}
+	diff, err := cfg.Rollup.Diff(peerCfg)
+	if err != nil {
+		return err
+	}
+	if len(diff) > 0 {
+		for _, d := range diff {
+			n.log.Error("Rollup config differs from peer (local != peer)", "field", d)
+		}
+		return fmt.Errorf("rollup config differs from peer %s in %d fields", cfg.RollupCheckPeer, len(diff))
+	}
+	n.log.Info("Rollup config matches peer", "peer", cfg.RollupCheckPeer)
+	return nil
+}
+
 func (n *OpNode) initL1(ctx context.Context, cfg *Config) error {
 	l1Node, trustRPC, err := cfg.L1.Setup(ctx, n.log)
 	if err != nil {
 		return fmt.Errorf("failed to get L1 RPC client: %w", err)
 	}
 
-	n.l1Source, err = sources.NewL1Client(
-		client.NewInstrumentedRPC(l1Node, n.metrics), n.log, n.metrics.L1SourceCache,
-		sources.L1ClientDefaultConfig(&cfg.Rollup, trustRPC))
+	l1RPC := client.NewInstrumentedRPC(l1Node, n.metrics)
+	// contract calls are only made to the primary L1 node
+	n.l1Calls = l1RPC.Client()
+
+	var l1SourceRPC client.RPC = l1RPC
+	fallbacks, multiCfg, err := cfg.L1.SetupFallbacks(ctx, n.log)
+	if err != nil {
+		return fmt.Errorf("failed to get L1 fallback RPC clients: %w", err)
+	}
+	if len(fallbacks) > 0 {
+		names := []string{"primary"}
+		rpcs := []client.RPC{l1RPC}
+		for i, fallback := range fallbacks {
+			names = append(names, fmt.Sprintf("fallback_%d", i+1))
+			rpcs = append(rpcs, client.NewInstrumentedRPC(fallback, n.metrics))
+		}
+		l1SourceRPC, err = client.NewMultiRPC(n.log, n.metrics, multiCfg, names, rpcs)
+		if err != nil {
+			return fmt.Errorf("failed to create L1 multi-endpoint RPC client: %w", err)
+		}
+	}
+	l1SourceCfg := sources.L1ClientDefaultConfig(&cfg.Rollup, trustRPC)
+	l1SourceCfg.DiskCachePath = cfg.L1DiskCachePath
+	l1SourceCfg.DiskCacheSize = cfg.L1DiskCacheSize
+	n.l1Source, err = sources.NewL1Client(l1SourceRPC, n.log, n.metrics.L1SourceCache, l1SourceCfg)
 	if err != nil {
 		return fmt.Errorf("failed to create L1 source: %w", err)
 	}
@@ -154,8 +222,24 @@ func (n *OpNode) initL2(ctx context.Context, cfg *Config, snapshotLog log.Logger
 	if err != nil {
 		return fmt.Errorf("failed to create Engine client: %w", err)
 	}
+	n.l2Proofs = withdrawals.NewClient(rpcClient)
 
-	n.l2Driver = driver.NewDriver(&cfg.Driver, &cfg.Rollup, n.l2Source, n.l1Source, n, n.log, snapshotLog, n.metrics)
+	var seqConductor driver.SequencerConductor
+	if cfg.Conductor != nil {
+		n.conductor, err = conductor.NewTCPConductor(n.log, cfg.Conductor, n.onReplicatedPayload)
+		if err != nil {
+			return fmt.Errorf("failed to create sequencer raft node: %w", err)
+		}
+		seqConductor = n.conductor
+	}
+
+	n.l2Driver = driver.NewDriver(&cfg.Driver, &cfg.Rollup, n.l2Source, n.l1Source, n, seqConductor, n.log, snapshotLog, n.metrics)
+
+	if n.conductor != nil {
+		if err := n.conductor.Start(); err != nil {
+			return fmt.Errorf("failed to start sequencer raft node: %w", err)
+		}
+	}
 
 	return nil
 }
@@ -166,6 +250,7 @@ func (n *OpNode) initRPCServer(ctx context.Context, cfg *Config) error {
 	if err != nil {
 		return err
 	}
+	n.server.EnableWithdrawalProofs(newWithdrawalsAPI(&cfg.Rollup, n.l1Calls, n.l2Proofs, n.log, n.metrics))
 	if n.p2pNode != nil {
 		n.server.EnableP2P(p2p.NewP2PAPIBackend(n.p2pNode, n.log, n.metrics))
 	}
@@ -195,7 +280,10 @@ func (n *OpNode) initMetricsServer(ctx context.Context, cfg *Config) error {
 
 func (n *OpNode) initP2P(ctx context.Context, cfg *Config) error {
 	if cfg.P2P != nil {
-		p2pNode, err := p2p.NewNodeP2P(n.resourcesCtx, &cfg.Rollup, n.log, cfg.P2P, n)
+		if signers, ok := cfg.P2PAuthorizedSigners.(*p2p.SignerListFile); ok {
+			signers.StartReloads(n.resourcesCtx, n.log, p2p.SignerListReloadInterval)
+		}
+		p2pNode, err := p2p.NewNodeP2P(n.resourcesCtx, &cfg.Rollup, n.log, cfg.P2P, n, cfg.P2PAuthorizedSigners, &p2pSyncStatus{n.l2Driver, n.l2Source}, n.metrics)
 		if err != nil || p2pNode == nil {
 			return err
 		}
@@ -207,6 +295,21 @@ func (n *OpNode) initP2P(ctx context.Context, cfg *Config) error {
 	return nil
 }
 
+// p2pSyncStatus provides the sync status of the driver and the canonical L2 chain of the engine
+// to exchange with and compare against p2p peers.
+type p2pSyncStatus struct {
+	driver *driver.Driver
+	engine *sources.EngineClient
+}
+
+func (s *p2pSyncStatus) SyncStatus(ctx context.Context) (*eth.SyncStatus, error) {
+	return s.driver.SyncStatus(ctx)
+}
+
+func (s *p2pSyncStatus) L2BlockRefByNumber(ctx context.Context, num uint64) (eth.L2BlockRef, error) {
+	return s.engine.L2BlockRefByNumber(ctx, num)
+}
+
 func (n *OpNode) initP2PSigner(ctx context.Context, cfg *Config) error {
 	// the p2p signer setup is optional
 	if cfg.P2PSigner == nil {
@@ -306,6 +409,20 @@ func (n *OpNode) OnUnsafeL2Payload(ctx context.Context, from peer.ID, payload *e
 	return nil
 }
 
+// onReplicatedPayload passes the unsafe payloads sequenced by the active sequencer on to the L2 engine.
+func (n *OpNode) onReplicatedPayload(payload *eth.ExecutionPayload) {
+	// the active sequencer already processed the payloads it sequenced itself
+	if n.conductor.Leader() {
+		return
+	}
+	n.log.Info("Received replicated execution payload from sequencer cluster", "id", payload.ID())
+	ctx, cancel := context.WithTimeout(n.resourcesCtx, time.Second*10)
+	defer cancel()
+	if err := n.l2Driver.OnUnsafeL2Payload(ctx, payload); err != nil {
+		n.log.Warn("failed to notify engine driver of replicated L2 payload", "err", err, "id", payload.ID())
+	}
+}
+
 func (n *OpNode) P2P() p2p.Node {
 	return n.p2pNode
 }
@@ -337,6 +454,13 @@ func (n *OpNode) Close() error {
 		n.l1HeadsSub.Unsubscribe()
 	}
 
+	// stop participating in the sequencer cluster
+	if n.conductor != nil {
+		if err := n.conductor.Close(); err != nil {
+			result = multierror.Append(result, fmt.Errorf("failed to close sequencer raft node: %w", err))
+		}
+	}
+
 	// close L2 driver
 	if n.l2Driver != nil {
 		if err := n.l2Driver.Close(); err != nil {
@@ -353,5 +477,12 @@ func (n *OpNode) Close() error {
 	if n.l1Source != nil {
 		n.l1Source.Close()
 	}
+
+	// flush the remaining traces
+	if n.traceProvider != nil {
+		if err := n.traceProvider.Shutdown(context.Background()); err != nil {
+			result = multierror.Append(result, fmt.Errorf("failed to shut down trace exporter: %w", err))
+		}
+	}
 	return result.ErrorOrNil()
 }
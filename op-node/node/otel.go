@@ -0,0 +1,40 @@
+package node
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+)
+
+// initOTel registers the global tracer provider that exports the traces of the derivation pipeline,
+// if an OpenTelemetry collector endpoint is configured.
+func (n *OpNode) initOTel(ctx context.Context, cfg *Config) error {
+	if cfg.OTel.Endpoint == "" {
+		return nil
+	}
+	opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(cfg.OTel.Endpoint)}
+	if cfg.OTel.Insecure {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+	exporter, err := otlptracehttp.New(ctx, opts...)
+	if err != nil {
+		return fmt.Errorf("failed to create OTLP trace exporter: %w", err)
+	}
+	res := resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceNameKey.String("op-node"),
+		semconv.ServiceVersionKey.String(n.appVersion),
+	)
+	n.traceProvider = sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.OTel.SampleRatio))),
+	)
+	otel.SetTracerProvider(n.traceProvider)
+	n.log.Info("exporting traces to OpenTelemetry collector", "endpoint", cfg.OTel.Endpoint, "sample_ratio", cfg.OTel.SampleRatio)
+	return nil
+}
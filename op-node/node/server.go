@@ -6,6 +6,7 @@ import (
 	"net"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/ethereum-optimism/optimism/op-node/metrics"
 	"github.com/ethereum-optimism/optimism/op-node/p2p"
@@ -22,6 +23,7 @@ type rpcServer struct {
 	endpoint   string
 	apis       []rpc.API
 	httpServer *http.Server
+	srv        *rpc.Server
 	appVersion string
 	listenAddr net.Addr
 	log        log.Logger
@@ -56,6 +58,16 @@ func (s *rpcServer) EnableAdminAPI(api *adminAPI) {
 	})
 }
 
+// EnableWithdrawalProofs extends the optimism namespace with the optimism_withdrawalProof method.
+func (s *rpcServer) EnableWithdrawalProofs(api *withdrawalsAPI) {
+	s.apis = append(s.apis, rpc.API{
+		Namespace:     "optimism",
+		Service:       api,
+		Public:        true,
+		Authenticated: false,
+	})
+}
+
 func (s *rpcServer) EnableP2P(backend *p2p.APIBackend) {
 	s.apis = append(s.apis, rpc.API{
 		Namespace:     p2p.NamespaceRPC,
@@ -77,9 +89,17 @@ func (s *rpcServer) Start() error {
 	// defaults to localhost, which will prevent containers from
 	// calling into the opnode without an "invalid host" error.
 	nodeHandler := node.NewHTTPHandlerStack(srv, []string{"*"}, []string{"*"}, nil)
+	// Websocket connections are served on the same endpoint, to support optimism_subscribe.
+	wsHandler := node.NewWSHandlerStack(srv.WebsocketHandler([]string{"*"}), nil)
 
 	mux := http.NewServeMux()
-	mux.Handle("/", nodeHandler)
+	mux.Handle("/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isWebsocket(r) {
+			wsHandler.ServeHTTP(w, r)
+			return
+		}
+		nodeHandler.ServeHTTP(w, r)
+	}))
 	mux.HandleFunc("/healthz", healthzHandler(s.appVersion))
 
 	listener, err := net.Listen("tcp", s.endpoint)
@@ -88,6 +108,7 @@ func (s *rpcServer) Start() error {
 	}
 	s.listenAddr = listener.Addr()
 
+	s.srv = srv
 	s.httpServer = &http.Server{Handler: mux}
 	go func() {
 		if err := s.httpServer.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) { // todo improve error handling
@@ -99,12 +120,20 @@ func (s *rpcServer) Start() error {
 
 func (r *rpcServer) Stop() {
 	_ = r.httpServer.Shutdown(context.Background())
+	// hijacked websocket connections are not closed by the http server shutdown
+	r.srv.Stop()
 }
 
 func (r *rpcServer) Addr() net.Addr {
 	return r.listenAddr
 }
 
+// isWebsocket checks the headers of a http request for a websocket upgrade request.
+func isWebsocket(r *http.Request) bool {
+	return strings.EqualFold(r.Header.Get("Upgrade"), "websocket") &&
+		strings.Contains(strings.ToLower(r.Header.Get("Connection")), "upgrade")
+}
+
 func healthzHandler(appVersion string) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		_, _ = w.Write([]byte(appVersion))
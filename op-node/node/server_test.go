@@ -3,8 +3,10 @@ package node
 import (
 	"context"
 	"encoding/json"
+	"math/big"
 	"math/rand"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -13,10 +15,12 @@ import (
 	"github.com/ethereum-optimism/optimism/op-node/eth"
 	"github.com/ethereum-optimism/optimism/op-node/metrics"
 	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
 	"github.com/ethereum-optimism/optimism/op-node/testlog"
 	"github.com/ethereum-optimism/optimism/op-node/testutils"
 	"github.com/ethereum-optimism/optimism/op-node/version"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/rpc"
 )
@@ -136,6 +140,33 @@ func TestVersion(t *testing.T) {
 	assert.Equal(t, version.Version+"-"+version.Meta, out)
 }
 
+func TestCheckRollupConfig(t *testing.T) {
+	log := testlog.Logger(t, log.LvlError)
+	rpcCfg := &RPCConfig{
+		ListenAddr: "localhost",
+		ListenPort: 0,
+	}
+	rollupCfg := &rollup.Config{
+		Genesis:   rollup.Genesis{L2: eth.BlockID{Number: 100}},
+		BlockTime: 2,
+		L2ChainID: big.NewInt(901),
+	}
+	server, err := newRPCServer(context.Background(), rpcCfg, rollupCfg, &testutils.MockL2Client{}, &mockDriverClient{}, log, "0.0", metrics.NewMetrics(""))
+	assert.NoError(t, err)
+	assert.NoError(t, server.Start())
+	defer server.Stop()
+
+	n := &OpNode{log: log}
+	cfg := &Config{Rollup: *rollupCfg, RollupCheckPeer: "http://" + server.Addr().String()}
+	assert.NoError(t, n.checkRollupConfig(context.Background(), cfg))
+
+	cfg.Rollup.Genesis.L2.Number = 101
+	assert.ErrorContains(t, n.checkRollupConfig(context.Background(), cfg), "differs from peer")
+
+	cfg.RollupCheckPeer = ""
+	assert.NoError(t, n.checkRollupConfig(context.Background(), cfg), "check is disabled without peer")
+}
+
 func TestSyncStatus(t *testing.T) {
 	log := testlog.Logger(t, log.LvlError)
 	l2Client := &testutils.MockL2Client{}
@@ -171,8 +202,49 @@ func TestSyncStatus(t *testing.T) {
 	assert.Equal(t, &status, out)
 }
 
+func TestSubscribeUnsafeHead(t *testing.T) {
+	log := testlog.Logger(t, log.LvlError)
+	l2Client := &testutils.MockL2Client{}
+	drClient := &mockDriverClient{}
+	rpcCfg := &RPCConfig{
+		ListenAddr: "localhost",
+		ListenPort: 0,
+	}
+	rollupCfg := &rollup.Config{
+		// ignore other rollup config info in this test
+	}
+	server, err := newRPCServer(context.Background(), rpcCfg, rollupCfg, l2Client, drClient, log, "0.0", metrics.NewMetrics(""))
+	assert.NoError(t, err)
+	assert.NoError(t, server.Start())
+	defer server.Stop()
+
+	client, err := dialRPCClientWithBackoff(context.Background(), log, "ws://"+server.Addr().String())
+	assert.NoError(t, err)
+	defer client.Close()
+
+	heads := make(chan eth.L2BlockRef, 1)
+	sub, err := client.Subscribe(context.Background(), "optimism", heads, "unsafeHead")
+	assert.NoError(t, err)
+	defer sub.Unsubscribe()
+
+	rng := rand.New(rand.NewSource(1234))
+	head := testutils.RandomL2BlockRef(rng)
+	// the server subscribes to the driver before confirming the subscription, so the event cannot be missed
+	assert.Equal(t, 1, drClient.unsafeHeadFeed.Send(head))
+	select {
+	case got := <-heads:
+		assert.Equal(t, head, got)
+	case err := <-sub.Err():
+		t.Fatalf("subscription failed: %v", err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for unsafe head notification")
+	}
+}
+
 type mockDriverClient struct {
 	mock.Mock
+
+	unsafeHeadFeed event.Feed
 }
 
 func (c *mockDriverClient) SyncStatus(ctx context.Context) (*eth.SyncStatus, error) {
@@ -182,3 +254,27 @@ func (c *mockDriverClient) SyncStatus(ctx context.Context) (*eth.SyncStatus, err
 func (c *mockDriverClient) ResetDerivationPipeline(ctx context.Context) error {
 	return c.Mock.MethodCalled("ResetDerivationPipeline").Get(0).(error)
 }
+
+func (c *mockDriverClient) DerivationDiagnostics(ctx context.Context) ([]derive.DropRecord, error) {
+	return c.Mock.MethodCalled("DerivationDiagnostics").Get(0).([]derive.DropRecord), nil
+}
+
+func (c *mockDriverClient) SubscribeUnsafeHead(ch chan<- eth.L2BlockRef) event.Subscription {
+	return c.unsafeHeadFeed.Subscribe(ch)
+}
+
+func (c *mockDriverClient) SubscribeSafeHead(ch chan<- eth.L2BlockRef) event.Subscription {
+	return c.Mock.MethodCalled("SubscribeSafeHead", ch).Get(0).(event.Subscription)
+}
+
+func (c *mockDriverClient) SubscribeFinalizedHead(ch chan<- eth.L2BlockRef) event.Subscription {
+	return c.Mock.MethodCalled("SubscribeFinalizedHead", ch).Get(0).(event.Subscription)
+}
+
+func (c *mockDriverClient) SubscribeL1Head(ch chan<- eth.L1BlockRef) event.Subscription {
+	return c.Mock.MethodCalled("SubscribeL1Head", ch).Get(0).(event.Subscription)
+}
+
+func (c *mockDriverClient) SubscribeDerivationReset(ch chan<- eth.DerivationResetEvent) event.Subscription {
+	return c.Mock.MethodCalled("SubscribeDerivationReset", ch).Get(0).(event.Subscription)
+}
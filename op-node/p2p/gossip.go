@@ -186,7 +186,12 @@ func (sb *seenBlocks) markSeen(h common.Hash) {
 	sb.blockHashes = append(sb.blockHashes, h)
 }
 
-func BuildBlocksValidator(log log.Logger, cfg *rollup.Config) pubsub.ValidatorEx {
+// BuildBlocksValidator builds the validator of gossiped blocks.
+// Blocks must be signed by one of the signers, or by the P2PSequencerAddress of the config if the signers are nil.
+func BuildBlocksValidator(log log.Logger, cfg *rollup.Config, signers AuthorizedSigners) pubsub.ValidatorEx {
+	if signers == nil {
+		signers = ConfigSigners(cfg)
+	}
 
 	// Seen block hashes per block height
 	// uint64 -> *seenBlocks
@@ -233,12 +238,6 @@ func BuildBlocksValidator(log log.Logger, cfg *rollup.Config) pubsub.ValidatorEx
 		}
 		addr := crypto.PubkeyToAddress(*pub)
 
-		// TODO: in the future we can support multiple valid p2p addresses.
-		if addr != cfg.P2PSequencerAddress {
-			log.Warn("unexpected block author", "err", err, "peer", id)
-			return pubsub.ValidationReject
-		}
-
 		// [REJECT] if the block encoding is not valid
 		var payload eth.ExecutionPayload
 		if err := payload.UnmarshalSSZ(uint32(len(payloadBytes)), bytes.NewReader(payloadBytes)); err != nil {
@@ -246,6 +245,12 @@ func BuildBlocksValidator(log log.Logger, cfg *rollup.Config) pubsub.ValidatorEx
 			return pubsub.ValidationReject
 		}
 
+		// [REJECT] if the signer is not authorized to sign blocks at the height of the payload
+		if !signers.IsAuthorized(addr, uint64(payload.BlockNumber)) {
+			log.Warn("unexpected block author", "author", addr, "height", uint64(payload.BlockNumber), "peer", id)
+			return pubsub.ValidationReject
+		}
+
 		// rounding down to seconds is fine here.
 		now := uint64(time.Now().Unix())
 
@@ -350,8 +355,8 @@ func (p *publisher) Close() error {
 	return p.blocksTopic.Close()
 }
 
-func JoinGossip(p2pCtx context.Context, self peer.ID, ps *pubsub.PubSub, log log.Logger, cfg *rollup.Config, gossipIn GossipIn) (GossipOut, error) {
-	val := logValidationResult(self, "validated block", log, BuildBlocksValidator(log, cfg))
+func JoinGossip(p2pCtx context.Context, self peer.ID, ps *pubsub.PubSub, log log.Logger, cfg *rollup.Config, gossipIn GossipIn, signers AuthorizedSigners) (GossipOut, error) {
+	val := logValidationResult(self, "validated block", log, BuildBlocksValidator(log, cfg, signers))
 	blocksTopicName := blocksTopicV1(cfg)
 	err := ps.RegisterTopicValidator(blocksTopicName,
 		val,
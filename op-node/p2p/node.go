@@ -24,18 +24,19 @@ type NodeP2P struct {
 	gater   ConnectionGater     // p2p gater, to ban/unban peers with, may be nil even with p2p enabled
 	connMgr connmgr.ConnManager // p2p conn manager, to keep a reliable number of peers, may be nil even with p2p enabled
 	// the below components are all optional, and may be nil. They require the host to not be nil.
-	dv5Local *enode.LocalNode // p2p discovery identity
-	dv5Udp   *discover.UDPv5  // p2p discovery service
-	gs       *pubsub.PubSub   // p2p gossip router
-	gsOut    GossipOut        // p2p gossip application interface for publishing
+	dv5Local *enode.LocalNode    // p2p discovery identity
+	dv5Udp   *discover.UDPv5     // p2p discovery service
+	gs       *pubsub.PubSub      // p2p gossip router
+	gsOut    GossipOut           // p2p gossip application interface for publishing
+	syncEx   *SyncStatusExchange // p2p sync status exchange with peers
 }
 
-func NewNodeP2P(resourcesCtx context.Context, rollupCfg *rollup.Config, log log.Logger, setup SetupP2P, gossipIn GossipIn) (*NodeP2P, error) {
+func NewNodeP2P(resourcesCtx context.Context, rollupCfg *rollup.Config, log log.Logger, setup SetupP2P, gossipIn GossipIn, signers AuthorizedSigners, syncStatus SyncStatusBackend, m SyncStatusMetrics) (*NodeP2P, error) {
 	if setup == nil {
 		return nil, errors.New("p2p node cannot be created without setup")
 	}
 	var n NodeP2P
-	if err := n.init(resourcesCtx, rollupCfg, log, setup, gossipIn); err != nil {
+	if err := n.init(resourcesCtx, rollupCfg, log, setup, gossipIn, signers, syncStatus, m); err != nil {
 		closeErr := n.Close()
 		if closeErr != nil {
 			log.Error("failed to close p2p after starting with err", "closeErr", closeErr, "err", err)
@@ -48,7 +49,7 @@ func NewNodeP2P(resourcesCtx context.Context, rollupCfg *rollup.Config, log log.
 	return &n, nil
 }
 
-func (n *NodeP2P) init(resourcesCtx context.Context, rollupCfg *rollup.Config, log log.Logger, setup SetupP2P, gossipIn GossipIn) error {
+func (n *NodeP2P) init(resourcesCtx context.Context, rollupCfg *rollup.Config, log log.Logger, setup SetupP2P, gossipIn GossipIn, signers AuthorizedSigners, syncStatus SyncStatusBackend, m SyncStatusMetrics) error {
 	var err error
 	// nil if disabled.
 	n.host, err = setup.Host(log)
@@ -74,10 +75,15 @@ func (n *NodeP2P) init(resourcesCtx context.Context, rollupCfg *rollup.Config, l
 			return fmt.Errorf("failed to start gossipsub router: %w", err)
 		}
 
-		n.gsOut, err = JoinGossip(resourcesCtx, n.host.ID(), n.gs, log, rollupCfg, gossipIn)
+		n.gsOut, err = JoinGossip(resourcesCtx, n.host.ID(), n.gs, log, rollupCfg, gossipIn, signers)
 		if err != nil {
 			return fmt.Errorf("failed to join blocks gossip topic: %w", err)
 		}
+		// nil backend if there is no local sync status to exchange with peers.
+		if syncStatus != nil {
+			n.syncEx = NewSyncStatusExchange(log.New("p2p", "syncstatus"), rollupCfg, n.host, n.connMgr, syncStatus, m)
+			n.syncEx.Start()
+		}
 		log.Info("started p2p host", "addrs", n.host.Addrs(), "peerID", n.host.ID().Pretty())
 
 		tcpPort, err := FindActiveTCPPort(n.host)
@@ -122,8 +128,15 @@ func (n *NodeP2P) ConnectionManager() connmgr.ConnManager {
 	return n.connMgr
 }
 
+func (n *NodeP2P) SyncStatusExchange() *SyncStatusExchange {
+	return n.syncEx
+}
+
 func (n *NodeP2P) Close() error {
 	var result *multierror.Error
+	if n.syncEx != nil {
+		n.syncEx.Close()
+	}
 	if n.dv5Udp != nil {
 		n.dv5Udp.Close()
 	}
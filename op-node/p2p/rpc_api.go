@@ -28,6 +28,8 @@ type PeerInfo struct {
 	Latency       time.Duration         `json:"latency"`
 
 	GossipBlocks bool `json:"gossipBlocks"` // if the peer is in our gossip topic
+
+	SyncStatus *PeerSyncStatus `json:"syncStatus,omitempty"` // last sync status exchanged with the peer, if any
 }
 
 type PeerDump struct {
@@ -49,6 +49,8 @@ type Node interface {
 	ConnectionGater() ConnectionGater
 	// ConnectionManager returns the connection manager, to protect peers with, may be nil
 	ConnectionManager() connmgr.ConnManager
+	// SyncStatusExchange returns the exchange of sync status with peers, may be nil
+	SyncStatusExchange() *SyncStatusExchange
 }
 
 type APIBackend struct {
@@ -179,6 +181,11 @@ func (s *APIBackend) Peers(ctx context.Context, connected bool) (*PeerDump, erro
 			dump.TotalConnected += 1
 		}
 	}
+	if ex := s.node.SyncStatusExchange(); ex != nil {
+		for _, p := range dump.Peers {
+			p.SyncStatus = ex.PeerSyncStatus(p.PeerID)
+		}
+	}
 	for _, id := range s.node.GossipOut().BlocksTopicPeers() {
 		if p, ok := dump.Peers[id.String()]; ok {
 			p.GossipBlocks = true
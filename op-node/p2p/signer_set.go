@@ -0,0 +1,166 @@
+package p2p
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"reflect"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/urfave/cli"
+
+	"github.com/ethereum-optimism/optimism/op-node/flags"
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// SignerListReloadInterval is the interval at which the authorized signers file is reloaded.
+const SignerListReloadInterval = time.Minute
+
+// AuthorizedSigner is a sequencer key that is authorized to sign blocks
+// from the activation block number up to, but not including, the expiry block number.
+type AuthorizedSigner struct {
+	Address common.Address `json:"address"`
+	// ActivationBlock is the first L2 block number the signer is authorized for.
+	ActivationBlock uint64 `json:"activationBlock"`
+	// ExpiryBlock is the first L2 block number the signer is no longer authorized for. Zero if the signer does not expire.
+	ExpiryBlock uint64 `json:"expiryBlock,omitempty"`
+}
+
+func (s *AuthorizedSigner) Authorizes(addr common.Address, blockNum uint64) bool {
+	return s.Address == addr && blockNum >= s.ActivationBlock && (s.ExpiryBlock == 0 || blockNum < s.ExpiryBlock)
+}
+
+// AuthorizedSigners decides which signers are authorized to sign the block of the given L2 block number.
+type AuthorizedSigners interface {
+	IsAuthorized(addr common.Address, blockNum uint64) bool
+}
+
+// SignerList is a fixed list of authorized signers. Overlapping ranges of signers are allowed,
+// to rotate keys without interruption.
+type SignerList []AuthorizedSigner
+
+func (l SignerList) IsAuthorized(addr common.Address, blockNum uint64) bool {
+	for i := range l {
+		if l[i].Authorizes(addr, blockNum) {
+			return true
+		}
+	}
+	return false
+}
+
+func (l SignerList) Check() error {
+	if len(l) == 0 {
+		return errors.New("no authorized signers")
+	}
+	for i, s := range l {
+		if s.Address == (common.Address{}) {
+			return fmt.Errorf("signer %d has no address", i)
+		}
+		if s.ExpiryBlock != 0 && s.ExpiryBlock <= s.ActivationBlock {
+			return fmt.Errorf("signer %s expires at block %d before activation at block %d", s.Address, s.ExpiryBlock, s.ActivationBlock)
+		}
+	}
+	return nil
+}
+
+// ConfigSigners authorizes only the P2PSequencerAddress of the rollup config, at any block number.
+func ConfigSigners(cfg *rollup.Config) SignerList {
+	return SignerList{{Address: cfg.P2PSequencerAddress}}
+}
+
+// LoadSignerListFile loads a JSON list of authorized signers.
+func LoadSignerListFile(path string) (SignerList, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read authorized signers file: %w", err)
+	}
+	var out SignerList
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("failed to decode authorized signers file: %w", err)
+	}
+	if err := out.Check(); err != nil {
+		return nil, fmt.Errorf("invalid authorized signers file: %w", err)
+	}
+	return out, nil
+}
+
+// SignerListFile is a SignerList loaded from a file, which can be reloaded to rotate signers without a restart.
+type SignerListFile struct {
+	path string
+
+	mu      sync.RWMutex
+	signers SignerList
+}
+
+// NewSignerListFile loads the authorized signers of the file.
+func NewSignerListFile(path string) (*SignerListFile, error) {
+	signers, err := LoadSignerListFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return &SignerListFile{path: path, signers: signers}, nil
+}
+
+func (f *SignerListFile) IsAuthorized(addr common.Address, blockNum uint64) bool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.signers.IsAuthorized(addr, blockNum)
+}
+
+// Reload loads the file again, and returns whether the signers changed.
+// The previous signers are kept if the file is invalid.
+func (f *SignerListFile) Reload() (bool, error) {
+	signers, err := LoadSignerListFile(f.path)
+	if err != nil {
+		return false, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if reflect.DeepEqual(f.signers, signers) {
+		return false, nil
+	}
+	f.signers = signers
+	return true, nil
+}
+
+// StartReloads reloads the file on SIGHUP and at every interval, until the context is done.
+func (f *SignerListFile) StartReloads(ctx context.Context, log log.Logger, interval time.Duration) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	go func() {
+		defer signal.Stop(hup)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-hup:
+			case <-ticker.C:
+			}
+			changed, err := f.Reload()
+			if err != nil {
+				log.Error("failed to reload authorized signers, keeping the previous signers", "file", f.path, "err", err)
+			} else if changed {
+				log.Info("reloaded authorized signers", "file", f.path)
+			}
+		}
+	}()
+}
+
+// LoadAuthorizedSigners loads the authorized signers that gossiped blocks are validated against.
+// The signers are nil if only the P2PSequencerAddress of the rollup config is authorized.
+func LoadAuthorizedSigners(ctx *cli.Context) (AuthorizedSigners, error) {
+	file := ctx.GlobalString(flags.SequencerP2PSignersFile.Name)
+	if file == "" {
+		return nil, nil
+	}
+	return NewSignerListFile(file)
+}
@@ -0,0 +1,103 @@
+package p2p
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	"github.com/ethereum-optimism/optimism/op-node/testlog"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+func TestSignerList(t *testing.T) {
+	a, b := common.Address{0xaa}, common.Address{0xbb}
+	// key a is rotated out for key b, with an overlap of blocks 100 to 109
+	signers := SignerList{
+		{Address: a, ActivationBlock: 0, ExpiryBlock: 110},
+		{Address: b, ActivationBlock: 100},
+	}
+	require.NoError(t, signers.Check())
+
+	require.True(t, signers.IsAuthorized(a, 0))
+	require.True(t, signers.IsAuthorized(a, 109))
+	require.False(t, signers.IsAuthorized(a, 110), "expired")
+	require.False(t, signers.IsAuthorized(b, 99), "not activated yet")
+	require.True(t, signers.IsAuthorized(b, 100))
+	require.True(t, signers.IsAuthorized(b, 1_000_000), "no expiry")
+	require.False(t, signers.IsAuthorized(common.Address{0xcc}, 100))
+
+	require.ErrorContains(t, SignerList{}.Check(), "no authorized signers")
+	require.ErrorContains(t, SignerList{{Address: a, ActivationBlock: 10, ExpiryBlock: 10}}.Check(), "expires")
+	require.ErrorContains(t, SignerList{{ActivationBlock: 10}}.Check(), "no address")
+
+	cfg := &rollup.Config{P2PSequencerAddress: a}
+	require.True(t, ConfigSigners(cfg).IsAuthorized(a, 12345))
+	require.False(t, ConfigSigners(cfg).IsAuthorized(b, 12345))
+}
+
+func TestLoadSignerListFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "signers.json")
+	data := `[
+		{"address": "0xaa00000000000000000000000000000000000000", "activationBlock": 0, "expiryBlock": 110},
+		{"address": "0xbb00000000000000000000000000000000000000", "activationBlock": 100}
+	]`
+	require.NoError(t, os.WriteFile(path, []byte(data), 0644))
+	signers, err := LoadSignerListFile(path)
+	require.NoError(t, err)
+	require.Equal(t, SignerList{
+		{Address: common.Address{0xaa}, ActivationBlock: 0, ExpiryBlock: 110},
+		{Address: common.Address{0xbb}, ActivationBlock: 100},
+	}, signers)
+
+	require.NoError(t, os.WriteFile(path, []byte(`[]`), 0644))
+	_, err = LoadSignerListFile(path)
+	require.ErrorContains(t, err, "no authorized signers")
+}
+
+func TestSignerListFileReload(t *testing.T) {
+	a, b, c := common.Address{0xaa}, common.Address{0xbb}, common.Address{0xcc}
+	path := filepath.Join(t.TempDir(), "signers.json")
+	require.NoError(t, os.WriteFile(path, []byte(`[{"address": "0xaa00000000000000000000000000000000000000"}]`), 0644))
+	signers, err := NewSignerListFile(path)
+	require.NoError(t, err)
+	require.True(t, signers.IsAuthorized(a, 100))
+	require.False(t, signers.IsAuthorized(b, 100))
+
+	// key a is rotated out for key b without a restart
+	require.NoError(t, os.WriteFile(path, []byte(`[
+		{"address": "0xaa00000000000000000000000000000000000000", "expiryBlock": 110},
+		{"address": "0xbb00000000000000000000000000000000000000", "activationBlock": 100}
+	]`), 0644))
+	changed, err := signers.Reload()
+	require.NoError(t, err)
+	require.True(t, changed)
+	require.True(t, signers.IsAuthorized(b, 100))
+	require.False(t, signers.IsAuthorized(a, 110))
+
+	changed, err = signers.Reload()
+	require.NoError(t, err)
+	require.False(t, changed)
+
+	// an invalid file keeps the previous signers
+	require.NoError(t, os.WriteFile(path, []byte(`[]`), 0644))
+	_, err = signers.Reload()
+	require.ErrorContains(t, err, "no authorized signers")
+	require.True(t, signers.IsAuthorized(b, 100))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	signers.StartReloads(ctx, testlog.Logger(t, log.LvlCrit), time.Hour)
+	require.NoError(t, os.WriteFile(path, []byte(`[{"address": "0xcc00000000000000000000000000000000000000"}]`), 0644))
+	require.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGHUP))
+	require.Eventually(t, func() bool {
+		return signers.IsAuthorized(c, 100)
+	}, 5*time.Second, 10*time.Millisecond, "reloaded on SIGHUP")
+	require.False(t, signers.IsAuthorized(b, 100))
+}
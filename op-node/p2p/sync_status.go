@@ -0,0 +1,372 @@
+package p2p
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/golang-lru/simplelru"
+	"github.com/libp2p/go-libp2p-core/connmgr"
+	"github.com/libp2p/go-libp2p-core/host"
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p-core/protocol"
+	"golang.org/x/time/rate"
+
+	"github.com/ethereum-optimism/optimism/op-node/eth"
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const (
+	// syncStatusInterval is the interval at which the sync status is exchanged with every connected peer.
+	syncStatusInterval = 30 * time.Second
+	// syncStatusTimeout is the maximum duration of a single sync status exchange.
+	syncStatusTimeout = 10 * time.Second
+	// maxSyncStatusSize is the maximum size of an encoded sync status.
+	maxSyncStatusSize = 4096
+	// peerSyncStatusRate and peerSyncStatusBurst limit the sync status requests that are served per peer.
+	// Peers request every syncStatusInterval, the burst leaves room for reconnects.
+	peerSyncStatusRate  = rate.Limit(1.0 / 10)
+	peerSyncStatusBurst = 3
+	// globalSyncStatusRate and globalSyncStatusBurst limit the sync status requests that are served
+	// of all peers combined, since every request is served with local RPC calls.
+	globalSyncStatusRate  = rate.Limit(10)
+	globalSyncStatusBurst = 20
+	// maxRateLimitedPeers is the number of peers that request rate limits are tracked for.
+	maxRateLimitedPeers = 1000
+	// maxSyncedLag is the number of L2 blocks that the unsafe head of a peer may lag behind ours,
+	// for the peer to still be considered in sync.
+	maxSyncedLag = 32
+
+	// syncStatusTag is the connection manager tag that scores peers by their sync state:
+	// conflicting and lagging peers are the first to be pruned when there are too many peers.
+	syncStatusTag = "optimism-sync"
+	syncedScore   = 20
+	lagScore      = 0
+	conflictScore = -100
+)
+
+// SyncState classifies a peer by its sync status relative to ours.
+type SyncState string
+
+const (
+	// PeerSynced peers are on the same chain, and not far behind our unsafe head.
+	PeerSynced SyncState = "synced"
+	// PeerLagging peers are on the same chain, but their unsafe head lags behind ours.
+	PeerLagging SyncState = "lagging"
+	// PeerConflicting peers have a head that conflicts with our chain.
+	PeerConflicting SyncState = "conflicting"
+)
+
+// PeerSyncStatus is the last sync status that was exchanged with a peer.
+type PeerSyncStatus struct {
+	Status    eth.SyncStatus `json:"status"`
+	UpdatedAt time.Time      `json:"updatedAt"`
+	State     SyncState      `json:"state"`
+	// UnsafeLag is the number of L2 blocks the unsafe head of the peer was behind ours, negative if the peer is ahead.
+	UnsafeLag int64 `json:"unsafeLag"`
+}
+
+// SyncStatusBackend provides the local sync status to share with peers,
+// and the local L2 chain to compare the heads of peers with.
+type SyncStatusBackend interface {
+	SyncStatus(ctx context.Context) (*eth.SyncStatus, error)
+	L2BlockRefByNumber(ctx context.Context, num uint64) (eth.L2BlockRef, error)
+}
+
+type SyncStatusMetrics interface {
+	RecordPeerSyncStates(synced, lagging, conflicting int)
+}
+
+func SyncStatusProtocolID(cfg *rollup.Config) protocol.ID {
+	return protocol.ID(fmt.Sprintf("/optimism/%s/syncstatus/1.0.0", cfg.L2ChainID.String()))
+}
+
+// SyncStatusExchange periodically exchanges the sync status with all connected peers that support the protocol.
+// The requesting peer sends its status, and the responding peer replies with its own status.
+type SyncStatusExchange struct {
+	log        log.Logger
+	host       host.Host
+	connMgr    connmgr.ConnManager // may be nil
+	protocolID protocol.ID
+	backend    SyncStatusBackend
+	metrics    SyncStatusMetrics
+
+	mu    sync.RWMutex
+	peers map[peer.ID]*PeerSyncStatus
+
+	// rate limits of serving sync status requests, globally and per peer
+	limitsMu      sync.Mutex
+	globalLimiter *rate.Limiter
+	peerLimiters  *simplelru.LRU
+	peerRate      rate.Limit
+	peerBurst     int
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewSyncStatusExchange registers the sync status protocol with the host. Peers are only requested once started.
+func NewSyncStatusExchange(log log.Logger, cfg *rollup.Config, h host.Host, connMgr connmgr.ConnManager, backend SyncStatusBackend, metrics SyncStatusMetrics) *SyncStatusExchange {
+	ctx, cancel := context.WithCancel(context.Background())
+	peerLimiters, _ := simplelru.NewLRU(maxRateLimitedPeers, nil) // only errors on a non-positive size
+	ex := &SyncStatusExchange{
+		log:           log,
+		host:          h,
+		connMgr:       connMgr,
+		protocolID:    SyncStatusProtocolID(cfg),
+		backend:       backend,
+		metrics:       metrics,
+		peers:         make(map[peer.ID]*PeerSyncStatus),
+		globalLimiter: rate.NewLimiter(globalSyncStatusRate, globalSyncStatusBurst),
+		peerLimiters:  peerLimiters,
+		peerRate:      peerSyncStatusRate,
+		peerBurst:     peerSyncStatusBurst,
+		ctx:           ctx,
+		cancel:        cancel,
+	}
+	h.SetStreamHandler(ex.protocolID, ex.handleStream)
+	return ex
+}
+
+// Start periodically requests the sync status of connected peers.
+func (ex *SyncStatusExchange) Start() {
+	ex.wg.Add(1)
+	go ex.loop()
+}
+
+func (ex *SyncStatusExchange) loop() {
+	defer ex.wg.Done()
+	ticker := time.NewTicker(syncStatusInterval)
+	defer ticker.Stop()
+	for {
+		ex.RequestAll()
+		select {
+		case <-ticker.C:
+		case <-ex.ctx.Done():
+			return
+		}
+	}
+}
+
+// RequestAll exchanges the sync status with all connected peers that support the protocol,
+// and forgets the status of disconnected peers.
+func (ex *SyncStatusExchange) RequestAll() {
+	connected := make(map[peer.ID]struct{})
+	var wg sync.WaitGroup
+	for _, id := range ex.host.Network().Peers() {
+		connected[id] = struct{}{}
+		if protocols, err := ex.host.Peerstore().SupportsProtocols(id, string(ex.protocolID)); err != nil || len(protocols) == 0 {
+			continue
+		}
+		wg.Add(1)
+		go func(id peer.ID) {
+			defer wg.Done()
+			if err := ex.Request(ex.ctx, id); err != nil {
+				ex.log.Debug("failed to exchange sync status", "peer", id, "err", err)
+			}
+		}(id)
+	}
+	wg.Wait()
+
+	ex.mu.Lock()
+	for id := range ex.peers {
+		if _, ok := connected[id]; !ok {
+			delete(ex.peers, id)
+		}
+	}
+	ex.mu.Unlock()
+	ex.recordMetrics()
+}
+
+// Request sends our sync status to the peer, and processes the sync status the peer replies with.
+func (ex *SyncStatusExchange) Request(ctx context.Context, id peer.ID) error {
+	ctx, cancel := context.WithTimeout(ctx, syncStatusTimeout)
+	defer cancel()
+	local, err := ex.backend.SyncStatus(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get local sync status: %w", err)
+	}
+	s, err := ex.host.NewStream(ctx, id, ex.protocolID)
+	if err != nil {
+		return fmt.Errorf("failed to open stream: %w", err)
+	}
+	defer s.Close()
+	_ = s.SetDeadline(time.Now().Add(syncStatusTimeout))
+	if err := json.NewEncoder(s).Encode(local); err != nil {
+		_ = s.Reset()
+		return fmt.Errorf("failed to write sync status: %w", err)
+	}
+	if err := s.CloseWrite(); err != nil {
+		_ = s.Reset()
+		return fmt.Errorf("failed to close write side of stream: %w", err)
+	}
+	remote, err := readSyncStatus(s)
+	if err != nil {
+		_ = s.Reset()
+		return err
+	}
+	ex.update(ctx, id, local, remote)
+	return nil
+}
+
+// allowRequest checks if a sync status request of the peer may be served, within the per-peer and global rate limits.
+func (ex *SyncStatusExchange) allowRequest(id peer.ID) bool {
+	ex.limitsMu.Lock()
+	defer ex.limitsMu.Unlock()
+	var limiter *rate.Limiter
+	if l, ok := ex.peerLimiters.Get(id); ok {
+		limiter = l.(*rate.Limiter)
+	} else {
+		limiter = rate.NewLimiter(ex.peerRate, ex.peerBurst)
+		ex.peerLimiters.Add(id, limiter)
+	}
+	// check the peer limit first, so that a single peer does not use up the global limit
+	return limiter.Allow() && ex.globalLimiter.Allow()
+}
+
+// handleStream replies to a sync status request with our sync status.
+// Requests exceeding the rate limits are refused by resetting the stream.
+func (ex *SyncStatusExchange) handleStream(s network.Stream) {
+	defer s.Close()
+	id := s.Conn().RemotePeer()
+	if !ex.allowRequest(id) {
+		ex.log.Debug("refusing sync status request, rate limit exceeded", "peer", id)
+		_ = s.Reset()
+		return
+	}
+	_ = s.SetDeadline(time.Now().Add(syncStatusTimeout))
+	remote, err := readSyncStatus(s)
+	if err != nil {
+		ex.log.Debug("received invalid sync status request", "peer", id, "err", err)
+		_ = s.Reset()
+		return
+	}
+	ctx, cancel := context.WithTimeout(ex.ctx, syncStatusTimeout)
+	defer cancel()
+	local, err := ex.backend.SyncStatus(ctx)
+	if err != nil {
+		ex.log.Warn("failed to get local sync status to reply to peer with", "peer", id, "err", err)
+		_ = s.Reset()
+		return
+	}
+	if err := json.NewEncoder(s).Encode(local); err != nil {
+		ex.log.Debug("failed to reply with sync status", "peer", id, "err", err)
+		_ = s.Reset()
+		return
+	}
+	ex.update(ctx, id, local, remote)
+	ex.recordMetrics()
+}
+
+func readSyncStatus(r io.Reader) (*eth.SyncStatus, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxSyncStatusSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sync status: %w", err)
+	}
+	if len(data) > maxSyncStatusSize {
+		return nil, fmt.Errorf("sync status exceeds max size of %d bytes", maxSyncStatusSize)
+	}
+	var status eth.SyncStatus
+	if err := json.Unmarshal(data, &status); err != nil {
+		return nil, fmt.Errorf("failed to decode sync status: %w", err)
+	}
+	return &status, nil
+}
+
+// update classifies the peer by its sync status, and scores the peer accordingly.
+func (ex *SyncStatusExchange) update(ctx context.Context, id peer.ID, local, remote *eth.SyncStatus) {
+	res := &PeerSyncStatus{
+		Status:    *remote,
+		UpdatedAt: time.Now(),
+		UnsafeLag: int64(local.UnsafeL2.Number) - int64(remote.UnsafeL2.Number),
+	}
+	if ex.conflicts(ctx, local, remote) {
+		res.State = PeerConflicting
+	} else if res.UnsafeLag > maxSyncedLag {
+		res.State = PeerLagging
+	} else {
+		res.State = PeerSynced
+	}
+
+	ex.mu.Lock()
+	prev := ex.peers[id]
+	ex.peers[id] = res
+	ex.mu.Unlock()
+
+	if res.State == PeerConflicting && (prev == nil || prev.State != PeerConflicting) {
+		ex.log.Warn("peer is on a conflicting chain", "peer", id, "unsafe", remote.UnsafeL2, "safe", remote.SafeL2, "finalized", remote.FinalizedL2)
+	}
+	if ex.connMgr != nil {
+		switch res.State {
+		case PeerSynced:
+			ex.connMgr.TagPeer(id, syncStatusTag, syncedScore)
+		case PeerLagging:
+			ex.connMgr.TagPeer(id, syncStatusTag, lagScore)
+		case PeerConflicting:
+			ex.connMgr.TagPeer(id, syncStatusTag, conflictScore)
+		}
+	}
+}
+
+// conflicts checks if any of the L2 heads of the peer conflicts with our chain,
+// for those heads that are not ahead of our unsafe head.
+func (ex *SyncStatusExchange) conflicts(ctx context.Context, local, remote *eth.SyncStatus) bool {
+	for _, head := range []eth.L2BlockRef{remote.FinalizedL2, remote.SafeL2, remote.UnsafeL2} {
+		if head == (eth.L2BlockRef{}) || head.Number > local.UnsafeL2.Number {
+			continue
+		}
+		ours, err := ex.backend.L2BlockRefByNumber(ctx, head.Number)
+		if err != nil {
+			ex.log.Debug("failed to get local L2 block to compare peer head with", "number", head.Number, "err", err)
+			continue
+		}
+		if ours.Hash != head.Hash {
+			return true
+		}
+	}
+	return false
+}
+
+func (ex *SyncStatusExchange) recordMetrics() {
+	if ex.metrics == nil {
+		return
+	}
+	var synced, lagging, conflicting int
+	ex.mu.RLock()
+	for _, p := range ex.peers {
+		switch p.State {
+		case PeerSynced:
+			synced++
+		case PeerLagging:
+			lagging++
+		case PeerConflicting:
+			conflicting++
+		}
+	}
+	ex.mu.RUnlock()
+	ex.metrics.RecordPeerSyncStates(synced, lagging, conflicting)
+}
+
+// PeerSyncStatus returns the last exchanged sync status of the peer, or nil if there is none.
+func (ex *SyncStatusExchange) PeerSyncStatus(id peer.ID) *PeerSyncStatus {
+	ex.mu.RLock()
+	defer ex.mu.RUnlock()
+	if p, ok := ex.peers[id]; ok {
+		out := *p
+		return &out
+	}
+	return nil
+}
+
+// Close stops exchanging sync status with peers.
+func (ex *SyncStatusExchange) Close() {
+	ex.host.RemoveStreamHandler(ex.protocolID)
+	ex.cancel()
+	ex.wg.Wait()
+}
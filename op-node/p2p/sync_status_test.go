@@ -0,0 +1,156 @@
+package p2p
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+
+	mocknet "github.com/libp2p/go-libp2p/p2p/net/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+
+	"github.com/ethereum-optimism/optimism/op-node/eth"
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	"github.com/ethereum-optimism/optimism/op-node/testlog"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// mockSyncChain is a sync status backend with an L2 chain of the given length,
+// identified by the fork byte in the block hashes.
+type mockSyncChain struct {
+	fork   byte
+	length uint64
+}
+
+func (m *mockSyncChain) ref(num uint64) eth.L2BlockRef {
+	return eth.L2BlockRef{Hash: common.Hash{m.fork, byte(num)}, Number: num}
+}
+
+func (m *mockSyncChain) SyncStatus(ctx context.Context) (*eth.SyncStatus, error) {
+	return &eth.SyncStatus{
+		UnsafeL2:    m.ref(m.length - 1),
+		SafeL2:      m.ref(m.length / 2),
+		FinalizedL2: m.ref(m.length / 4),
+	}, nil
+}
+
+func (m *mockSyncChain) L2BlockRefByNumber(ctx context.Context, num uint64) (eth.L2BlockRef, error) {
+	if num >= m.length {
+		return eth.L2BlockRef{}, fmt.Errorf("block %d not found", num)
+	}
+	return m.ref(num), nil
+}
+
+type mockSyncMetrics struct {
+	synced, lagging, conflicting int
+}
+
+func (m *mockSyncMetrics) RecordPeerSyncStates(synced, lagging, conflicting int) {
+	m.synced, m.lagging, m.conflicting = synced, lagging, conflicting
+}
+
+func TestSyncStatusExchange(t *testing.T) {
+	mnet, err := mocknet.FullMeshConnected(4)
+	require.NoError(t, err, "failed to setup mocknet")
+	defer mnet.Close()
+	hosts := mnet.Hosts()
+	cfg := &rollup.Config{L2ChainID: big.NewInt(901)}
+	logger := testlog.Logger(t, log.LvlError)
+
+	backends := []*mockSyncChain{
+		{fork: 0xaa, length: 100}, // local node
+		{fork: 0xaa, length: 90},  // same chain, slightly behind
+		{fork: 0xaa, length: 20},  // same chain, lagging far behind
+		{fork: 0xbb, length: 100}, // conflicting chain
+	}
+	var exchanges []*SyncStatusExchange
+	for i, h := range hosts {
+		ex := NewSyncStatusExchange(logger, cfg, h, nil, backends[i], nil)
+		defer ex.Close()
+		exchanges = append(exchanges, ex)
+	}
+	m := &mockSyncMetrics{}
+	local := exchanges[0]
+	local.metrics = m
+
+	for _, h := range hosts[1:] {
+		require.NoError(t, local.Request(context.Background(), h.ID()))
+	}
+
+	synced := local.PeerSyncStatus(hosts[1].ID())
+	require.NotNil(t, synced)
+	require.Equal(t, PeerSynced, synced.State)
+	require.Equal(t, int64(10), synced.UnsafeLag)
+	require.Equal(t, backends[1].ref(89), synced.Status.UnsafeL2)
+
+	lagging := local.PeerSyncStatus(hosts[2].ID())
+	require.NotNil(t, lagging)
+	require.Equal(t, PeerLagging, lagging.State)
+	require.Equal(t, int64(80), lagging.UnsafeLag)
+
+	conflicting := local.PeerSyncStatus(hosts[3].ID())
+	require.NotNil(t, conflicting)
+	require.Equal(t, PeerConflicting, conflicting.State)
+
+	// the responding peers learned the status of the requesting peer as well
+	remote := exchanges[1].PeerSyncStatus(hosts[0].ID())
+	require.NotNil(t, remote)
+	require.Equal(t, PeerSynced, remote.State, "peer ahead of us on the same chain is in sync")
+	require.Equal(t, int64(-10), remote.UnsafeLag)
+	require.Equal(t, PeerConflicting, exchanges[3].PeerSyncStatus(hosts[0].ID()).State)
+
+	// peers that support the protocol are requested, and disconnected peers are forgotten
+	require.NoError(t, mnet.DisconnectPeers(hosts[0].ID(), hosts[3].ID()))
+	local.RequestAll()
+	require.Nil(t, local.PeerSyncStatus(hosts[3].ID()))
+	require.NotNil(t, local.PeerSyncStatus(hosts[1].ID()))
+	require.Equal(t, mockSyncMetrics{synced: 1, lagging: 1}, *m)
+}
+
+// countingSyncChain counts the local sync status lookups.
+type countingSyncChain struct {
+	mockSyncChain
+	calls int
+}
+
+func (m *countingSyncChain) SyncStatus(ctx context.Context) (*eth.SyncStatus, error) {
+	m.calls++
+	return m.mockSyncChain.SyncStatus(ctx)
+}
+
+func TestSyncStatusRateLimit(t *testing.T) {
+	mnet, err := mocknet.FullMeshConnected(3)
+	require.NoError(t, err, "failed to setup mocknet")
+	defer mnet.Close()
+	hosts := mnet.Hosts()
+	cfg := &rollup.Config{L2ChainID: big.NewInt(901)}
+	logger := testlog.Logger(t, log.LvlError)
+
+	serverChain := &countingSyncChain{mockSyncChain: mockSyncChain{fork: 0xaa, length: 100}}
+	server := NewSyncStatusExchange(logger, cfg, hosts[0], nil, serverChain, nil)
+	defer server.Close()
+	var clients []*SyncStatusExchange
+	for _, h := range hosts[1:] {
+		ex := NewSyncStatusExchange(logger, cfg, h, nil, &mockSyncChain{fork: 0xaa, length: 100}, nil)
+		defer ex.Close()
+		clients = append(clients, ex)
+	}
+	ctx := context.Background()
+
+	for i := 0; i < peerSyncStatusBurst; i++ {
+		require.NoError(t, clients[0].Request(ctx, hosts[0].ID()))
+	}
+	require.Error(t, clients[0].Request(ctx, hosts[0].ID()), "excess requests of a peer are refused")
+	require.Equal(t, peerSyncStatusBurst, serverChain.calls, "refused requests are not served")
+
+	// other peers have their own limit
+	require.NoError(t, clients[1].Request(ctx, hosts[0].ID()))
+
+	// and all peers combined are limited as well
+	server.globalLimiter = rate.NewLimiter(0, 0)
+	server.peerLimiters.Purge()
+	require.Error(t, clients[1].Request(ctx, hosts[0].ID()))
+	require.Equal(t, peerSyncStatusBurst+1, serverChain.calls)
+}
@@ -44,14 +44,17 @@ type BatchQueue struct {
 
 	// batches in order of when we've first seen them, grouped by L2 timestamp
 	batches map[uint64][]*BatchWithL1InclusionBlock
+
+	diag *DropDiagnostics
 }
 
 // NewBatchQueue creates a BatchQueue, which should be Reset(origin) before use.
-func NewBatchQueue(log log.Logger, cfg *rollup.Config, next BatchQueueOutput) *BatchQueue {
+func NewBatchQueue(log log.Logger, cfg *rollup.Config, next BatchQueueOutput, diag *DropDiagnostics) *BatchQueue {
 	return &BatchQueue{
 		log:    log,
 		config: cfg,
 		next:   next,
+		diag:   diag,
 	}
 }
 
@@ -59,6 +62,14 @@ func (bq *BatchQueue) Progress() Progress {
 	return bq.progress
 }
 
+// Buffered returns the number of buffered batches and L1 blocks.
+func (bq *BatchQueue) Buffered() (batches int, l1Blocks int) {
+	for _, b := range bq.batches {
+		batches += len(b)
+	}
+	return batches, len(bq.l1Blocks)
+}
+
 func (bq *BatchQueue) Step(ctx context.Context, outer Progress) error {
 	if changed, err := bq.progress.Update(outer); err != nil {
 		return err
@@ -91,7 +102,8 @@ func (bq *BatchQueue) ResetStep(ctx context.Context, l1Fetcher L1Fetcher) error
 	return io.EOF
 }
 
-func (bq *BatchQueue) AddBatch(batch *BatchData) {
+// AddBatch adds a batch, read from the given channel, to the queue.
+func (bq *BatchQueue) AddBatch(batch *BatchData, channel ChannelID) {
 	if bq.progress.Closed {
 		panic("write batch while closed")
 	}
@@ -100,11 +112,14 @@ func (bq *BatchQueue) AddBatch(batch *BatchData) {
 	}
 	data := BatchWithL1InclusionBlock{
 		L1InclusionBlock: bq.progress.Origin,
+		ChannelID:        channel,
 		Batch:            batch,
 	}
-	validity := CheckBatch(bq.config, bq.log, bq.l1Blocks, bq.next.SafeL2Head(), &data)
+	validity, reason := CheckBatch(bq.config, bq.log, bq.l1Blocks, bq.next.SafeL2Head(), &data)
 	if validity == BatchDrop {
-		return // if we do drop the batch, CheckBatch will log the drop reason with WARN level.
+		// if we do drop the batch, CheckBatch will log the drop reason with WARN level.
+		bq.diag.recordBatch(reason, channel, &data)
+		return
 	}
 	bq.batches[batch.Timestamp] = append(bq.batches[batch.Timestamp], &data)
 }
@@ -136,7 +151,7 @@ func (bq *BatchQueue) deriveNextBatch(ctx context.Context) (*BatchData, error) {
 	candidates := bq.batches[nextTimestamp]
 batchLoop:
 	for i, batch := range candidates {
-		validity := CheckBatch(bq.config, bq.log.New("batch_index", i), bq.l1Blocks, l2SafeHead, batch)
+		validity, reason := CheckBatch(bq.config, bq.log.New("batch_index", i), bq.l1Blocks, l2SafeHead, batch)
 		switch validity {
 		case BatchFuture:
 			return nil, NewCriticalError(fmt.Errorf("found batch with timestamp %d marked as future batch, but expected timestamp %d", batch.Batch.Timestamp, nextTimestamp))
@@ -148,7 +163,10 @@ batchLoop:
 				"txs", len(batch.Batch.Transactions),
 				"l2_safe_head", l2SafeHead.ID(),
 				"l2_safe_head_time", l2SafeHead.Time,
+				"channel", batch.ChannelID,
+				"reason", reason,
 			)
+			bq.diag.recordBatch(reason, batch.ChannelID, batch)
 			continue
 		case BatchAccept:
 			nextBatch = batch
@@ -116,7 +116,7 @@ func TestBatchQueueEager(t *testing.T) {
 		SeqWindowSize:     30,
 	}
 
-	bq := NewBatchQueue(log, cfg, next)
+	bq := NewBatchQueue(log, cfg, next, NewDropDiagnostics(MaxDropRecords, &TestMetrics{}))
 	require.Equal(t, io.EOF, bq.ResetStep(context.Background(), nil), "reset should complete without l1 fetcher, single step")
 
 	// We start with an open L1 origin as progress in the first step
@@ -126,7 +126,7 @@ func TestBatchQueueEager(t *testing.T) {
 	// Add batches
 	batches := []*BatchData{b(12, l1[0]), b(14, l1[0])}
 	for _, batch := range batches {
-		bq.AddBatch(batch)
+		bq.AddBatch(batch, ChannelID{})
 	}
 	// Step
 	require.NoError(t, RepeatStep(t, bq.Step, progress, 10))
@@ -161,7 +161,7 @@ func TestBatchQueueFull(t *testing.T) {
 		SeqWindowSize:     2,
 	}
 
-	bq := NewBatchQueue(log, cfg, next)
+	bq := NewBatchQueue(log, cfg, next, NewDropDiagnostics(MaxDropRecords, &TestMetrics{}))
 	require.Equal(t, io.EOF, bq.ResetStep(context.Background(), nil), "reset should complete without l1 fetcher, single step")
 
 	// We start with an open L1 origin as progress in the first step
@@ -171,7 +171,7 @@ func TestBatchQueueFull(t *testing.T) {
 	// Add batches
 	batches := []*BatchData{b(14, l1[0]), b(16, l1[0]), b(18, l1[1])}
 	for _, batch := range batches {
-		bq.AddBatch(batch)
+		bq.AddBatch(batch, ChannelID{})
 	}
 	// Missing first batch
 	err := bq.Step(context.Background(), progress)
@@ -205,7 +205,7 @@ func TestBatchQueueFull(t *testing.T) {
 
 	// Finally add batch
 	firstBatch := b(12, l1[0])
-	bq.AddBatch(firstBatch)
+	bq.AddBatch(firstBatch, ChannelID{})
 
 	// Close the origin
 	progress.Closed = true
@@ -249,7 +249,7 @@ func TestBatchQueueMissing(t *testing.T) {
 		SeqWindowSize:     2,
 	}
 
-	bq := NewBatchQueue(log, cfg, next)
+	bq := NewBatchQueue(log, cfg, next, NewDropDiagnostics(MaxDropRecords, &TestMetrics{}))
 	require.Equal(t, io.EOF, bq.ResetStep(context.Background(), nil), "reset should complete without l1 fetcher, single step")
 
 	// We start with an open L1 origin as progress in the first step
@@ -261,7 +261,7 @@ func TestBatchQueueMissing(t *testing.T) {
 	// Due to the large sequencer time drift 16 is perfectly valid to have epoch 0 as origin.
 	batches := []*BatchData{b(16, l1[0]), b(22, l1[1])}
 	for _, batch := range batches {
-		bq.AddBatch(batch)
+		bq.AddBatch(batch, ChannelID{})
 	}
 	// Missing first batches with timestamp 12 and 14, nothing to do yet.
 	err := bq.Step(context.Background(), progress)
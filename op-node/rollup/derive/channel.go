@@ -95,6 +95,11 @@ func (ch *Channel) Size() uint64 {
 	return ch.size
 }
 
+// FrameCount returns the number of frames buffered in the channel.
+func (ch *Channel) FrameCount() int {
+	return len(ch.inputs)
+}
+
 // IsReady returns true iff the channel is ready to be read.
 func (ch *Channel) IsReady() bool {
 	// Must see the last frame before the channel is ready to be read
@@ -24,7 +24,7 @@ import (
 
 type ChannelBankOutput interface {
 	StageProgress
-	WriteChannel(data []byte)
+	WriteChannel(id ChannelID, data []byte)
 }
 
 // ChannelBank buffers channel frames, and emits full channel data
@@ -38,18 +38,21 @@ type ChannelBank struct {
 	progress Progress
 
 	next ChannelBankOutput
+
+	diag *DropDiagnostics
 }
 
 var _ Stage = (*ChannelBank)(nil)
 
 // NewChannelBank creates a ChannelBank, which should be Reset(origin) before use.
-func NewChannelBank(log log.Logger, cfg *rollup.Config, next ChannelBankOutput) *ChannelBank {
+func NewChannelBank(log log.Logger, cfg *rollup.Config, next ChannelBankOutput, diag *DropDiagnostics) *ChannelBank {
 	return &ChannelBank{
 		log:          log,
 		cfg:          cfg,
 		channels:     make(map[ChannelID]*Channel),
 		channelQueue: make([]ChannelID, 0, 10),
 		next:         next,
+		diag:         diag,
 	}
 }
 
@@ -57,6 +60,15 @@ func (ib *ChannelBank) Progress() Progress {
 	return ib.progress
 }
 
+// Buffered returns the number of buffered channels and frames, and the estimated memory size of the channels.
+func (ib *ChannelBank) Buffered() (channels int, frames int, memSize uint64) {
+	for _, ch := range ib.channels {
+		frames += ch.FrameCount()
+		memSize += ch.Size()
+	}
+	return len(ib.channels), frames, memSize
+}
+
 func (ib *ChannelBank) prune() {
 	// check total size
 	totalSize := uint64(0)
@@ -70,6 +82,7 @@ func (ib *ChannelBank) prune() {
 		ib.channelQueue = ib.channelQueue[1:]
 		delete(ib.channels, id)
 		totalSize -= ch.size
+		ib.diag.recordChannel(DropChannelPruned, ib.progress.Origin, id)
 	}
 }
 
@@ -87,6 +100,7 @@ func (ib *ChannelBank) IngestData(data []byte) {
 	frames, err := ParseFrames(data)
 	if err != nil {
 		ib.log.Warn("malformed frame", "err", err)
+		ib.diag.recordFrame(DropMalformedFrames, ib.progress.Origin, ChannelID{}, 0)
 		return
 	}
 
@@ -103,12 +117,14 @@ func (ib *ChannelBank) IngestData(data []byte) {
 		// check if the channel is not timed out
 		if currentCh.OpenBlockNumber()+ib.cfg.ChannelTimeout < ib.progress.Origin.Number {
 			ib.log.Warn("channel is timed out, ignore frame", "channel", f.ID, "frame", f.FrameNumber)
+			ib.diag.recordFrame(DropChannelTimedOut, ib.progress.Origin, f.ID, f.FrameNumber)
 			continue
 		}
 
 		ib.log.Trace("ingesting frame", "channel", f.ID, "frame_number", f.FrameNumber, "length", len(f.Data))
 		if err := currentCh.AddFrame(f, ib.progress.Origin); err != nil {
 			ib.log.Warn("failed to ingest frame into channel", "channel", f.ID, "frame_number", f.FrameNumber, "err", err)
+			ib.diag.recordFrame(DropInvalidFrame, ib.progress.Origin, f.ID, f.FrameNumber)
 			continue
 		}
 	}
@@ -116,21 +132,22 @@ func (ib *ChannelBank) IngestData(data []byte) {
 
 // Read the raw data of the first channel, if it's timed-out or closed.
 // Read returns io.EOF if there is nothing new to read.
-func (ib *ChannelBank) Read() (data []byte, err error) {
+func (ib *ChannelBank) Read() (id ChannelID, data []byte, err error) {
 	if len(ib.channelQueue) == 0 {
-		return nil, io.EOF
+		return ChannelID{}, nil, io.EOF
 	}
 	first := ib.channelQueue[0]
 	ch := ib.channels[first]
 	timedOut := ch.OpenBlockNumber()+ib.cfg.ChannelTimeout < ib.progress.Origin.Number
 	if timedOut {
 		ib.log.Debug("channel timed out", "channel", first, "frames", len(ch.inputs))
+		ib.diag.recordChannel(DropChannelTimedOut, ib.progress.Origin, first)
 		delete(ib.channels, first)
 		ib.channelQueue = ib.channelQueue[1:]
-		return nil, io.EOF
+		return ChannelID{}, nil, io.EOF
 	}
 	if !ch.IsReady() {
-		return nil, io.EOF
+		return ChannelID{}, nil, io.EOF
 	}
 
 	delete(ib.channels, first)
@@ -138,7 +155,7 @@ func (ib *ChannelBank) Read() (data []byte, err error) {
 	r := ch.Reader()
 	// Suprress error here. io.ReadAll does return nil instead of io.EOF though.
 	data, _ = io.ReadAll(r)
-	return data, nil
+	return first, data, nil
 }
 
 func (ib *ChannelBank) Step(ctx context.Context, outer Progress) error {
@@ -149,18 +166,18 @@ func (ib *ChannelBank) Step(ctx context.Context, outer Progress) error {
 	// If the bank is behind the channel reader, then we are replaying old data to prepare the bank.
 	// Read if we can, and drop if it gives anything
 	if ib.next.Progress().Origin.Number > ib.progress.Origin.Number {
-		_, err := ib.Read()
+		_, _, err := ib.Read()
 		return err
 	}
 
 	// otherwise, read the next channel data from the bank
-	data, err := ib.Read()
+	id, data, err := ib.Read()
 	if err == io.EOF { // need new L1 data in the bank before we can read more channel data
 		return io.EOF
 	} else if err != nil {
 		return err
 	}
-	ib.next.WriteChannel(data)
+	ib.next.WriteChannel(id, data)
 	return nil
 }
 
@@ -21,7 +21,7 @@ type MockChannelBankOutput struct {
 	MockOriginStage
 }
 
-func (m *MockChannelBankOutput) WriteChannel(data []byte) {
+func (m *MockChannelBankOutput) WriteChannel(id ChannelID, data []byte) {
 	m.MethodCalled("WriteChannel", data)
 }
 
@@ -69,7 +69,7 @@ func (ct *channelBankTestCase) Run(t *testing.T) {
 	}
 
 	bt.out = &MockChannelBankOutput{MockOriginStage{progress: Progress{Origin: bt.origins[ct.nextStartsAt], Closed: false}}}
-	bt.cb = NewChannelBank(testlog.Logger(t, log.LvlError), cfg, bt.out)
+	bt.cb = NewChannelBank(testlog.Logger(t, log.LvlError), cfg, bt.out, NewDropDiagnostics(MaxDropRecords, &TestMetrics{}))
 
 	ct.fn(bt)
 }
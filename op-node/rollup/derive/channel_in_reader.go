@@ -15,24 +15,28 @@ import (
 
 type BatchQueueStage interface {
 	StageProgress
-	AddBatch(batch *BatchData)
+	AddBatch(batch *BatchData, channel ChannelID)
 }
 
 type ChannelInReader struct {
 	log log.Logger
 
 	nextBatchFn func() (BatchWithL1InclusionBlock, error)
+	// channel that batches are currently being read from
+	channel ChannelID
 
 	progress Progress
 
 	next BatchQueueStage
+
+	diag *DropDiagnostics
 }
 
 var _ ChannelBankOutput = (*ChannelInReader)(nil)
 
 // NewChannelInReader creates a ChannelInReader, which should be Reset(origin) before use.
-func NewChannelInReader(log log.Logger, next BatchQueueStage) *ChannelInReader {
-	return &ChannelInReader{log: log, next: next}
+func NewChannelInReader(log log.Logger, next BatchQueueStage, diag *DropDiagnostics) *ChannelInReader {
+	return &ChannelInReader{log: log, next: next, diag: diag}
 }
 
 func (cr *ChannelInReader) Progress() Progress {
@@ -40,14 +44,16 @@ func (cr *ChannelInReader) Progress() Progress {
 }
 
 // TODO: Take full channel for better logging
-func (cr *ChannelInReader) WriteChannel(data []byte) {
+func (cr *ChannelInReader) WriteChannel(id ChannelID, data []byte) {
 	if cr.progress.Closed {
 		panic("write channel while closed")
 	}
 	if f, err := BatchReader(bytes.NewBuffer(data), cr.progress.Origin); err == nil {
 		cr.nextBatchFn = f
+		cr.channel = id
 	} else {
-		cr.log.Error("Error creating batch reader from channel data", "err", err)
+		cr.log.Error("Error creating batch reader from channel data", "channel", id, "err", err)
+		cr.diag.recordChannel(DropInvalidBatchData, cr.progress.Origin, id)
 	}
 }
 
@@ -73,11 +79,12 @@ func (cr *ChannelInReader) Step(ctx context.Context, outer Progress) error {
 	if err == io.EOF {
 		return io.EOF
 	} else if err != nil {
-		cr.log.Warn("failed to read batch from channel reader, skipping to next channel now", "err", err)
+		cr.log.Warn("failed to read batch from channel reader, skipping to next channel now", "channel", cr.channel, "err", err)
+		cr.diag.recordChannel(DropInvalidBatchData, cr.progress.Origin, cr.channel)
 		cr.NextChannel()
 		return nil
 	}
-	cr.next.AddBatch(batch.Batch)
+	cr.next.AddBatch(batch.Batch, cr.channel)
 	return nil
 }
 
@@ -0,0 +1,205 @@
+package derive
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/op-node/eth"
+)
+
+// MaxDropRecords is the number of most recent dropped derivation inputs that the pipeline keeps for diagnostics.
+const MaxDropRecords = 256
+
+// DropKind is the kind of derivation input that was dropped.
+type DropKind string
+
+const (
+	DroppedBatch   DropKind = "batch"
+	DroppedFrame   DropKind = "frame"
+	DroppedChannel DropKind = "channel"
+)
+
+// DropReason describes why derivation input was dropped.
+type DropReason string
+
+const (
+	// Batch drop reasons, see CheckBatch
+	DropOldTimestamp       DropReason = "old_timestamp"
+	DropParentMismatch     DropReason = "parent_hash_mismatch"
+	DropIncludedTooLate    DropReason = "included_too_late"
+	DropEpochTooOld        DropReason = "epoch_too_old"
+	DropEpochTooFarAhead   DropReason = "epoch_too_far_ahead"
+	DropEpochHashMismatch  DropReason = "epoch_hash_mismatch"
+	DropSequencerDrift     DropReason = "sequencer_drift_exceeded"
+	DropEmptyTransaction   DropReason = "empty_transaction"
+	DropDepositTransaction DropReason = "deposit_transaction"
+
+	// Frame drop reasons, see ChannelBank.IngestData
+	DropMalformedFrames DropReason = "malformed_frames"
+	DropInvalidFrame    DropReason = "invalid_frame"
+
+	// Channel drop reasons (and frames of timed out channels)
+	DropChannelTimedOut  DropReason = "channel_timed_out"
+	DropChannelPruned    DropReason = "channel_pruned"
+	DropInvalidBatchData DropReason = "invalid_batch_data"
+)
+
+// DropRecord describes a batch, frame or channel that was dropped by the derivation pipeline.
+type DropRecord struct {
+	Kind   DropKind   `json:"kind"`
+	Reason DropReason `json:"reason"`
+	// L1InclusionBlock is the L1 block that the dropped data was included in.
+	// For dropped channels this is the L1 block at which the channel was dropped.
+	L1InclusionBlock eth.L1BlockRef `json:"l1_inclusion_block"`
+	// ChannelID is zeroed if the dropped data could not be attributed to a channel.
+	ChannelID ChannelID `json:"channel_id"`
+	// FrameNumber is only set for dropped frames.
+	FrameNumber uint16 `json:"frame_number,omitempty"`
+	// Epoch and Timestamp are only set for dropped batches.
+	Epoch     eth.BlockID `json:"epoch"`
+	Timestamp uint64      `json:"timestamp,omitempty"`
+	// Time is the local time at which the data was dropped.
+	Time time.Time `json:"time"`
+}
+
+// dropKey identifies a dropped derivation input, regardless of when it was dropped.
+type dropKey struct {
+	kind        DropKind
+	reason      DropReason
+	l1Block     eth.BlockID
+	channelID   ChannelID
+	frameNumber uint16
+	epoch       eth.BlockID
+	timestamp   uint64
+}
+
+func (rec *DropRecord) key() dropKey {
+	return dropKey{
+		kind:        rec.Kind,
+		reason:      rec.Reason,
+		l1Block:     rec.L1InclusionBlock.ID(),
+		channelID:   rec.ChannelID,
+		frameNumber: rec.FrameNumber,
+		epoch:       rec.Epoch,
+		timestamp:   rec.Timestamp,
+	}
+}
+
+// DropDiagnostics is a bounded in-memory store of the most recently dropped derivation inputs.
+// It is safe for concurrent use, so it can be inspected while the pipeline is running.
+type DropDiagnostics struct {
+	mu sync.Mutex
+
+	// ring buffer of records, next is the index that the next record is written to.
+	records []DropRecord
+	next    int
+	full    bool
+
+	// keys of the stored records. After a pipeline reset, the pipeline replays L1 data that it already derived
+	// from, and drops the same inputs again. These drops are not stored or metered again.
+	stored map[dropKey]struct{}
+
+	// total number of records ever stored
+	total uint64
+
+	metrics Metrics
+}
+
+// NewDropDiagnostics creates a DropDiagnostics store that keeps the last size records.
+func NewDropDiagnostics(size int, metrics Metrics) *DropDiagnostics {
+	return &DropDiagnostics{
+		records: make([]DropRecord, size),
+		stored:  make(map[dropKey]struct{}, size),
+		metrics: metrics,
+	}
+}
+
+// Record stores the record, evicting the oldest record if the store is full, and meters the drop.
+// Records of inputs that are still stored, e.g. because they were dropped again while replaying L1 data after a
+// pipeline reset, are ignored.
+func (d *DropDiagnostics) Record(rec DropRecord) {
+	if rec.Time.IsZero() {
+		rec.Time = time.Now()
+	}
+	key := rec.key()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if len(d.records) == 0 {
+		d.metrics.RecordDerivationDrop(string(rec.Kind), string(rec.Reason))
+		return
+	}
+	if _, ok := d.stored[key]; ok {
+		return
+	}
+	d.metrics.RecordDerivationDrop(string(rec.Kind), string(rec.Reason))
+	if d.full {
+		evicted := d.records[d.next]
+		delete(d.stored, evicted.key())
+	}
+	d.stored[key] = struct{}{}
+	d.records[d.next] = rec
+	d.total++
+	d.next = (d.next + 1) % len(d.records)
+	if d.next == 0 {
+		d.full = true
+	}
+}
+
+// Records returns a copy of the stored records, oldest first.
+func (d *DropDiagnostics) Records() []DropRecord {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.copyRecords()
+}
+
+func (d *DropDiagnostics) copyRecords() []DropRecord {
+	if !d.full {
+		return append([]DropRecord{}, d.records[:d.next]...)
+	}
+	out := make([]DropRecord, 0, len(d.records))
+	out = append(out, d.records[d.next:]...)
+	return append(out, d.records[:d.next]...)
+}
+
+// RecordsSince returns the records that were stored after the given total number of records, oldest first,
+// and the new total. Records that were already evicted are omitted.
+func (d *DropDiagnostics) RecordsSince(total uint64) ([]DropRecord, uint64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	records := d.copyRecords()
+	if n := d.total - total; n < uint64(len(records)) {
+		records = records[uint64(len(records))-n:]
+	}
+	return records, d.total
+}
+
+func (d *DropDiagnostics) recordBatch(reason DropReason, channel ChannelID, batch *BatchWithL1InclusionBlock) {
+	d.Record(DropRecord{
+		Kind:             DroppedBatch,
+		Reason:           reason,
+		L1InclusionBlock: batch.L1InclusionBlock,
+		ChannelID:        channel,
+		Epoch:            batch.Batch.Epoch(),
+		Timestamp:        batch.Batch.Timestamp,
+	})
+}
+
+func (d *DropDiagnostics) recordFrame(reason DropReason, l1InclusionBlock eth.L1BlockRef, channel ChannelID, frameNumber uint16) {
+	d.Record(DropRecord{
+		Kind:             DroppedFrame,
+		Reason:           reason,
+		L1InclusionBlock: l1InclusionBlock,
+		ChannelID:        channel,
+		FrameNumber:      frameNumber,
+	})
+}
+
+func (d *DropDiagnostics) recordChannel(reason DropReason, l1Block eth.L1BlockRef, channel ChannelID) {
+	d.Record(DropRecord{
+		Kind:             DroppedChannel,
+		Reason:           reason,
+		L1InclusionBlock: l1Block,
+		ChannelID:        channel,
+	})
+}
@@ -0,0 +1,91 @@
+package derive
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-node/eth"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestDropDiagnostics(t *testing.T) {
+	drops := make(map[string]int)
+	m := &TestMetrics{recordDrop: func(kind string, reason string) {
+		drops[kind+"/"+reason] += 1
+	}}
+	diag := NewDropDiagnostics(3, m)
+	require.Empty(t, diag.Records())
+
+	for i := 0; i < 5; i++ {
+		diag.recordFrame(DropInvalidFrame, eth.L1BlockRef{Number: uint64(i)}, ChannelID{byte(i)}, uint16(i))
+	}
+	diag.recordChannel(DropChannelTimedOut, eth.L1BlockRef{Number: 5}, ChannelID{5})
+
+	records := diag.Records()
+	require.Len(t, records, 3, "only the most recent records are kept")
+	for i, rec := range records {
+		require.Equal(t, ChannelID{byte(i + 3)}, rec.ChannelID, "records are returned oldest first")
+		require.Equal(t, uint64(i+3), rec.L1InclusionBlock.Number)
+		require.False(t, rec.Time.IsZero())
+	}
+	require.Equal(t, DroppedChannel, records[2].Kind)
+	require.Equal(t, 5, drops["frame/invalid_frame"], "all drops are metered, including evicted ones")
+	require.Equal(t, 1, drops["channel/channel_timed_out"])
+
+	since, total := diag.RecordsSince(4)
+	require.Equal(t, uint64(6), total)
+	require.Equal(t, records[1:], since, "only records after the given total are returned")
+	since, _ = diag.RecordsSince(0)
+	require.Equal(t, records, since, "evicted records are omitted")
+	since, _ = diag.RecordsSince(total)
+	require.Empty(t, since)
+}
+
+func TestDropRecordJSON(t *testing.T) {
+	rec := DropRecord{
+		Kind:             DroppedBatch,
+		Reason:           DropParentMismatch,
+		L1InclusionBlock: eth.L1BlockRef{Number: 42},
+		ChannelID:        ChannelID{0xab, 0xcd},
+		Timestamp:        1234,
+	}
+	data, err := json.Marshal(&rec)
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"channel_id":"0xabcd0000000000000000000000000000"`)
+
+	var out DropRecord
+	require.NoError(t, json.Unmarshal(data, &out))
+	require.Equal(t, rec.ChannelID, out.ChannelID)
+	require.Equal(t, rec.Reason, out.Reason)
+	require.Equal(t, rec.L1InclusionBlock, out.L1InclusionBlock)
+}
+
+func TestDropDiagnosticsReplay(t *testing.T) {
+	drops := 0
+	m := &TestMetrics{recordDrop: func(kind string, reason string) {
+		drops++
+	}}
+	diag := NewDropDiagnostics(2, m)
+
+	origin := eth.L1BlockRef{Hash: common.Hash{0x01}, Number: 1}
+	diag.recordChannel(DropChannelTimedOut, origin, ChannelID{1})
+	diag.recordFrame(DropInvalidFrame, origin, ChannelID{1}, 0)
+	// replaying the same L1 data after a pipeline reset drops the same inputs again
+	diag.recordChannel(DropChannelTimedOut, origin, ChannelID{1})
+	diag.recordFrame(DropInvalidFrame, origin, ChannelID{1}, 0)
+	require.Len(t, diag.Records(), 2)
+	require.Equal(t, 2, drops, "replayed drops are not metered")
+	_, total := diag.RecordsSince(0)
+	require.Equal(t, uint64(2), total)
+
+	diag.recordFrame(DropInvalidFrame, origin, ChannelID{1}, 1)
+	require.Equal(t, 3, drops, "other frames of the same channel are recorded")
+
+	// the dropped channel record was evicted, so dropping it again is recorded
+	diag.recordChannel(DropChannelTimedOut, origin, ChannelID{1})
+	records := diag.Records()
+	require.Equal(t, 4, drops)
+	require.Equal(t, DroppedChannel, records[1].Kind)
+}
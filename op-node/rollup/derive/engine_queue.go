@@ -339,7 +339,7 @@ func (eq *EngineQueue) forceNextSafeAttributes(ctx context.Context) error {
 		FinalizedBlockHash: eq.finalized.Hash,
 	}
 	attrs := eq.safeAttributes[0]
-	payload, errType, err := InsertHeadBlock(ctx, eq.log, eq.engine, fc, attrs, true)
+	payload, errType, err := InsertHeadBlock(ctx, eq.log, eq.engine, fc, attrs, true, nil)
 	if err != nil {
 		switch errType {
 		case BlockInsertTemporaryErr:
@@ -78,8 +78,10 @@ const (
 // It first uses the given FC to start the block creation process and then after the payload is executed,
 // sets the FC to the same safe and finalized hashes, but updates the head hash to the new block.
 // If updateSafe is true, the head block is considered to be the safe head as well as the head.
+// If commit is not nil, it is called with the executed payload before the head hash is updated. If commit fails,
+// the payload is not made canonical, and the error is returned as a temporary error.
 // It returns the payload, an RPC error (if the payload might still be valid), and a payload error (if the payload was not valid)
-func InsertHeadBlock(ctx context.Context, log log.Logger, eng Engine, fc eth.ForkchoiceState, attrs *eth.PayloadAttributes, updateSafe bool) (out *eth.ExecutionPayload, errTyp BlockInsertionErrType, err error) {
+func InsertHeadBlock(ctx context.Context, log log.Logger, eng Engine, fc eth.ForkchoiceState, attrs *eth.PayloadAttributes, updateSafe bool, commit func(payload *eth.ExecutionPayload) error) (out *eth.ExecutionPayload, errTyp BlockInsertionErrType, err error) {
 	fcRes, err := eng.ForkchoiceUpdate(ctx, &fc, attrs)
 	if err != nil {
 		var inputErr eth.InputError
@@ -130,6 +132,12 @@ func InsertHeadBlock(ctx context.Context, log log.Logger, eng Engine, fc eth.For
 		return nil, BlockInsertTemporaryErr, eth.NewPayloadErr(payload, status)
 	}
 
+	if commit != nil {
+		if err := commit(payload); err != nil {
+			return nil, BlockInsertTemporaryErr, fmt.Errorf("failed to commit execution payload: %w", err)
+		}
+	}
+
 	fc.HeadBlockHash = payload.BlockHash
 	if updateSafe {
 		fc.SafeBlockHash = payload.BlockHash
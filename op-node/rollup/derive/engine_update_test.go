@@ -0,0 +1,71 @@
+package derive
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-node/eth"
+	"github.com/ethereum-optimism/optimism/op-node/testlog"
+	"github.com/ethereum-optimism/optimism/op-node/testutils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+func TestInsertHeadBlockCommit(t *testing.T) {
+	depositTx, err := types.NewTx(&types.DepositTx{}).MarshalBinary()
+	require.NoError(t, err)
+	payload := &eth.ExecutionPayload{
+		ParentHash:   common.Hash{0x01},
+		BlockHash:    common.Hash{0x02},
+		BlockNumber:  2,
+		Transactions: []eth.Data{depositTx},
+	}
+	fc := eth.ForkchoiceState{HeadBlockHash: payload.ParentHash}
+	attrs := &eth.PayloadAttributes{}
+	payloadID := eth.PayloadID{0x03}
+	valid := eth.PayloadStatusV1{Status: eth.ExecutionValid}
+
+	build := func(eng *testutils.MockEngine) {
+		eng.ExpectForkchoiceUpdate(&fc, attrs, &eth.ForkchoiceUpdatedResult{PayloadStatus: valid, PayloadID: &payloadID}, nil)
+		eng.ExpectGetPayload(payloadID, payload, nil)
+		eng.ExpectNewPayload(payload, &valid, nil)
+	}
+
+	t.Run("commit fails", func(t *testing.T) {
+		eng := &testutils.MockEngine{}
+		build(eng)
+		commitErr := errors.New("not the leader")
+		_, errType, err := InsertHeadBlock(context.Background(), testlog.Logger(t, log.LvlError), eng, fc, attrs, false,
+			func(p *eth.ExecutionPayload) error {
+				require.Equal(t, payload, p)
+				return commitErr
+			})
+		require.ErrorIs(t, err, commitErr)
+		require.Equal(t, BlockInsertTemporaryErr, errType)
+		// the payload was not made canonical: the mock fails on any unexpected forkchoice update
+		eng.AssertExpectations(t)
+	})
+
+	t.Run("commit succeeds", func(t *testing.T) {
+		eng := &testutils.MockEngine{}
+		build(eng)
+		headFc := fc
+		headFc.HeadBlockHash = payload.BlockHash
+		eng.ExpectForkchoiceUpdate(&headFc, nil, &eth.ForkchoiceUpdatedResult{PayloadStatus: valid}, nil)
+		committed := false
+		out, errType, err := InsertHeadBlock(context.Background(), testlog.Logger(t, log.LvlError), eng, fc, attrs, false,
+			func(p *eth.ExecutionPayload) error {
+				committed = true
+				return nil
+			})
+		require.NoError(t, err)
+		require.Equal(t, BlockInsertOK, errType)
+		require.Equal(t, payload, out)
+		require.True(t, committed)
+		eng.AssertExpectations(t)
+	})
+}
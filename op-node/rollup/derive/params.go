@@ -3,6 +3,8 @@ package derive
 import (
 	"errors"
 	"fmt"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
 )
 
 // count the tagging info as 200 in terms of buffer size.
@@ -32,3 +34,11 @@ func (id ChannelID) String() string {
 func (id ChannelID) TerminalString() string {
 	return fmt.Sprintf("%x..%x", id[:3], id[13:])
 }
+
+func (id ChannelID) MarshalText() ([]byte, error) {
+	return hexutil.Bytes(id[:]).MarshalText()
+}
+
+func (id *ChannelID) UnmarshalText(text []byte) error {
+	return hexutil.UnmarshalFixedText("ChannelID", text, id[:])
+}
@@ -4,16 +4,30 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 
 	"github.com/ethereum-optimism/optimism/op-node/eth"
 	"github.com/ethereum-optimism/optimism/op-node/rollup"
 	"github.com/ethereum/go-ethereum/log"
 )
 
+// tracer traces the steps of the derivation pipeline. It is a no-op, unless a global tracer provider is registered.
+var tracer = otel.Tracer("github.com/ethereum-optimism/optimism/op-node/rollup/derive")
+
 type Metrics interface {
 	RecordL1Ref(name string, ref eth.L1BlockRef)
 	RecordL2Ref(name string, ref eth.L2BlockRef)
 	RecordUnsafePayloadsBuffer(length uint64, memSize uint64, next eth.BlockID)
+	RecordDerivationDrop(kind string, reason string)
+	RecordStageStep(stage string, duration time.Duration)
+	RecordStageReset(stage string)
+	RecordStageError(stage string)
+	RecordChannelBank(channels int, frames int, memSize uint64)
+	RecordBatchQueue(batches int, l1Blocks int)
 }
 
 type L1Fetcher interface {
@@ -75,9 +89,17 @@ type DerivationPipeline struct {
 
 	// stages in execution order. A stage Step that:
 	stages []Stage
+	// names of the stages, in the same order, to identify the stages with in metrics and traces
+	stageNames []string
 
 	eng EngineQueueStage
 
+	// buffering stages, to report the buffer sizes of
+	bank       *ChannelBank
+	batchQueue *BatchQueue
+
+	diag *DropDiagnostics
+
 	metrics Metrics
 }
 
@@ -85,23 +107,29 @@ type DerivationPipeline struct {
 func NewDerivationPipeline(log log.Logger, cfg *rollup.Config, l1Fetcher L1Fetcher, engine Engine, metrics Metrics) *DerivationPipeline {
 	eng := NewEngineQueue(log, cfg, engine, metrics)
 	attributesQueue := NewAttributesQueue(log, cfg, l1Fetcher, eng)
-	batchQueue := NewBatchQueue(log, cfg, attributesQueue)
-	chInReader := NewChannelInReader(log, batchQueue)
-	bank := NewChannelBank(log, cfg, chInReader)
+	diag := NewDropDiagnostics(MaxDropRecords, metrics)
+	batchQueue := NewBatchQueue(log, cfg, attributesQueue, diag)
+	chInReader := NewChannelInReader(log, batchQueue, diag)
+	bank := NewChannelBank(log, cfg, chInReader, diag)
 	dataSrc := NewCalldataSource(log, cfg, l1Fetcher)
 	l1Src := NewL1Retrieval(log, dataSrc, bank)
 	l1Traversal := NewL1Traversal(log, l1Fetcher, l1Src)
 	stages := []Stage{eng, attributesQueue, batchQueue, chInReader, bank, l1Src, l1Traversal}
+	stageNames := []string{"engine_queue", "attributes_queue", "batch_queue", "channel_in_reader", "channel_bank", "l1_retrieval", "l1_traversal"}
 
 	return &DerivationPipeline{
-		log:       log,
-		cfg:       cfg,
-		l1Fetcher: l1Fetcher,
-		resetting: 0,
-		active:    0,
-		stages:    stages,
-		eng:       eng,
-		metrics:   metrics,
+		log:        log,
+		cfg:        cfg,
+		l1Fetcher:  l1Fetcher,
+		resetting:  0,
+		active:     0,
+		stages:     stages,
+		stageNames: stageNames,
+		eng:        eng,
+		bank:       bank,
+		batchQueue: batchQueue,
+		diag:       diag,
+		metrics:    metrics,
 	}
 }
 
@@ -134,6 +162,16 @@ func (dp *DerivationPipeline) SetUnsafeHead(head eth.L2BlockRef) {
 	dp.eng.SetUnsafeHead(head)
 }
 
+// DroppedInputs returns the most recent batches, frames and channels that were dropped during derivation, oldest first.
+func (dp *DerivationPipeline) DroppedInputs() []DropRecord {
+	return dp.diag.Records()
+}
+
+// DroppedInputsSince returns the dropped inputs after the given total number of drops, and the new total.
+func (dp *DerivationPipeline) DroppedInputsSince(total uint64) ([]DropRecord, uint64) {
+	return dp.diag.RecordsSince(total)
+}
+
 // AddUnsafePayload schedules an execution payload to be processed, ahead of deriving it from L1
 func (dp *DerivationPipeline) AddUnsafePayload(payload *eth.ExecutionPayload) {
 	dp.eng.AddUnsafePayload(payload)
@@ -145,16 +183,31 @@ func (dp *DerivationPipeline) AddUnsafePayload(payload *eth.ExecutionPayload) {
 // Any other error is critical and the derivation pipeline should be reset.
 // An error is expected when the underlying source closes.
 // When Step returns nil, it should be called again, to continue the derivation process.
-func (dp *DerivationPipeline) Step(ctx context.Context) error {
-	defer dp.metrics.RecordL1Ref("l1_derived", dp.Progress().Origin)
+func (dp *DerivationPipeline) Step(ctx context.Context) (err error) {
+	ctx, span := tracer.Start(ctx, "DerivationPipeline.Step")
+	defer func() {
+		origin := dp.Progress().Origin
+		dp.metrics.RecordL1Ref("l1_derived", origin)
+		dp.recordBuffers()
+		span.SetAttributes(attribute.Int64("origin", int64(origin.Number)))
+		if err != nil && err != io.EOF {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}()
 
 	// if any stages need to be reset, do that first.
 	if dp.resetting < len(dp.stages) {
+		name := dp.stageNames[dp.resetting]
+		span.SetAttributes(attribute.String("stage", name), attribute.Bool("reset", true))
+		dp.metrics.RecordStageReset(name)
 		if err := dp.stages[dp.resetting].ResetStep(ctx, dp.l1Fetcher); err == io.EOF {
 			dp.log.Debug("reset of stage completed", "stage", dp.resetting, "origin", dp.stages[dp.resetting].Progress().Origin)
 			dp.resetting += 1
 			return nil
 		} else if err != nil {
+			dp.metrics.RecordStageError(name)
 			return fmt.Errorf("stage %d failed resetting: %w", dp.resetting, err)
 		} else {
 			return nil
@@ -166,13 +219,25 @@ func (dp *DerivationPipeline) Step(ctx context.Context) error {
 		if i+1 < len(dp.stages) {
 			outer = dp.stages[i+1].Progress()
 		}
-		if err := stage.Step(ctx, outer); err == io.EOF {
+		name := dp.stageNames[i]
+		start := time.Now()
+		err := stage.Step(ctx, outer)
+		dp.metrics.RecordStageStep(name, time.Since(start))
+		if err == io.EOF {
 			continue
 		} else if err != nil {
+			dp.metrics.RecordStageError(name)
+			span.SetAttributes(attribute.String("stage", name))
 			return fmt.Errorf("stage %d failed: %w", i, err)
 		} else {
+			span.SetAttributes(attribute.String("stage", name))
 			return nil
 		}
 	}
 	return io.EOF
 }
+
+func (dp *DerivationPipeline) recordBuffers() {
+	dp.metrics.RecordChannelBank(dp.bank.Buffered())
+	dp.metrics.RecordBatchQueue(dp.batchQueue.Buffered())
+}
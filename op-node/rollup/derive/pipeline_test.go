@@ -2,13 +2,19 @@ package derive
 
 import (
 	"context"
+	"errors"
 	"io"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
 	"github.com/ethereum-optimism/optimism/op-node/eth"
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	"github.com/ethereum-optimism/optimism/op-node/testlog"
 	"github.com/ethereum-optimism/optimism/op-node/testutils"
+	"github.com/ethereum/go-ethereum/log"
 )
 
 var _ Engine = (*testutils.MockEngine)(nil)
@@ -66,6 +72,10 @@ type TestMetrics struct {
 	recordL1Ref          func(name string, ref eth.L1BlockRef)
 	recordL2Ref          func(name string, ref eth.L2BlockRef)
 	recordUnsafePayloads func(length uint64, memSize uint64, next eth.BlockID)
+	recordDrop           func(kind string, reason string)
+	recordStageStep      func(stage string, duration time.Duration)
+	recordStageReset     func(stage string)
+	recordStageError     func(stage string)
 }
 
 func (t *TestMetrics) RecordL1Ref(name string, ref eth.L1BlockRef) {
@@ -86,4 +96,95 @@ func (t *TestMetrics) RecordUnsafePayloadsBuffer(length uint64, memSize uint64,
 	}
 }
 
+func (t *TestMetrics) RecordDerivationDrop(kind string, reason string) {
+	if t.recordDrop != nil {
+		t.recordDrop(kind, reason)
+	}
+}
+
+func (t *TestMetrics) RecordStageStep(stage string, duration time.Duration) {
+	if t.recordStageStep != nil {
+		t.recordStageStep(stage, duration)
+	}
+}
+
+func (t *TestMetrics) RecordStageReset(stage string) {
+	if t.recordStageReset != nil {
+		t.recordStageReset(stage)
+	}
+}
+
+func (t *TestMetrics) RecordStageError(stage string) {
+	if t.recordStageError != nil {
+		t.recordStageError(stage)
+	}
+}
+
+func (t *TestMetrics) RecordChannelBank(channels int, frames int, memSize uint64) {}
+
+func (t *TestMetrics) RecordBatchQueue(batches int, l1Blocks int) {}
+
 var _ Metrics = (*TestMetrics)(nil)
+
+// stepStage is a stage that returns the next of the given results on every step or reset step.
+type stepStage struct {
+	results []error
+}
+
+func (s *stepStage) Progress() Progress {
+	return Progress{}
+}
+
+func (s *stepStage) next() error {
+	err := s.results[0]
+	s.results = s.results[1:]
+	return err
+}
+
+func (s *stepStage) Step(ctx context.Context, outer Progress) error {
+	return s.next()
+}
+
+func (s *stepStage) ResetStep(ctx context.Context, l1Fetcher L1Fetcher) error {
+	return s.next()
+}
+
+func TestPipelineStageMetrics(t *testing.T) {
+	steps := make(map[string]int)
+	resets := make(map[string]int)
+	errs := make(map[string]int)
+	m := &TestMetrics{
+		recordStageStep:  func(stage string, duration time.Duration) { steps[stage]++ },
+		recordStageReset: func(stage string) { resets[stage]++ },
+		recordStageError: func(stage string) { errs[stage]++ },
+	}
+	logger := testlog.Logger(t, log.LvlError)
+	cfg := &rollup.Config{}
+	errTest := errors.New("test err")
+	a := &stepStage{results: []error{io.EOF, nil, io.EOF, io.EOF}}
+	b := &stepStage{results: []error{errTest, io.EOF, nil}}
+	dp := &DerivationPipeline{
+		log:        logger,
+		cfg:        cfg,
+		stages:     []Stage{a, b},
+		stageNames: []string{"a", "b"},
+		eng:        NewEngineQueue(logger, cfg, nil, m),
+		bank:       NewChannelBank(logger, cfg, nil, NewDropDiagnostics(MaxDropRecords, m)),
+		batchQueue: NewBatchQueue(logger, cfg, nil, NewDropDiagnostics(MaxDropRecords, m)),
+		metrics:    m,
+	}
+
+	// reset: a completes, b errors, then b completes after the error
+	require.NoError(t, dp.Step(context.Background()))
+	require.ErrorIs(t, dp.Step(context.Background()), errTest)
+	require.NoError(t, dp.Step(context.Background()))
+	require.Equal(t, map[string]int{"a": 1, "b": 2}, resets)
+	require.Equal(t, map[string]int{"b": 1}, errs)
+	require.Empty(t, steps)
+
+	// a step that a progresses stops at a, a step that a does not progress continues with b
+	require.NoError(t, dp.Step(context.Background()))
+	require.NoError(t, dp.Step(context.Background()))
+	require.Equal(t, map[string]int{"a": 2, "b": 1}, steps)
+	require.Equal(t, map[string]int{"b": 1}, errs)
+}
@@ -0,0 +1,62 @@
+package rollup
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// Diff returns the differences between the two configs, one per differing field,
+// named by the JSON path of the field. The result is empty if the configs are equal.
+func (cfg *Config) Diff(other *Config) ([]string, error) {
+	a, err := configFields(cfg)
+	if err != nil {
+		return nil, err
+	}
+	b, err := configFields(other)
+	if err != nil {
+		return nil, err
+	}
+	var out []string
+	diffFields("", a, b, &out)
+	sort.Strings(out)
+	return out, nil
+}
+
+// configFields decodes the JSON encoding of the config, to compare configs by JSON field.
+func configFields(cfg *Config) (map[string]any, error) {
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode rollup config: %w", err)
+	}
+	// decode numbers as json.Number, to not lose precision of big chain IDs
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var out map[string]any
+	if err := dec.Decode(&out); err != nil {
+		return nil, fmt.Errorf("failed to decode rollup config: %w", err)
+	}
+	return out, nil
+}
+
+func diffFields(prefix string, a, b map[string]any, out *[]string) {
+	keys := make(map[string]struct{})
+	for k := range a {
+		keys[k] = struct{}{}
+	}
+	for k := range b {
+		keys[k] = struct{}{}
+	}
+	for k := range keys {
+		va, vb := a[k], b[k]
+		ma, okA := va.(map[string]any)
+		mb, okB := vb.(map[string]any)
+		if okA && okB {
+			diffFields(prefix+k+".", ma, mb, out)
+		} else if !reflect.DeepEqual(va, vb) {
+			*out = append(*out, fmt.Sprintf("%s%s: %v != %v", prefix, k, va, vb))
+		}
+	}
+}
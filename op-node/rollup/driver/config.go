@@ -13,4 +13,14 @@ type Config struct {
 
 	// SequencerEnabled is true when the driver should sequence new blocks.
 	SequencerEnabled bool `json:"sequencer_enabled"`
+
+	// SequencerFallbackMargin is the number of L1 blocks before the sequencing window of the oldest
+	// unsafe L2 block closes, at which the sequencer falls back to deposit-only blocks:
+	// if batch submission stalls, derivation replaces the unsafe blocks with deposit-only blocks built on the safe head
+	// once the window closes. The sequencer then builds the same deposit-only blocks on the safe head, dropping its
+	// unsafe blocks after the safe head, so that the blocks it produces are not reorged out later.
+	// With a sequencer conductor the replicated unsafe chain cannot be reorged, and the deposit-only blocks extend
+	// the unsafe head instead, to be replaced by derivation as well.
+	// The margin is limited to half the sequencing window.
+	SequencerFallbackMargin uint64 `json:"sequencer_fallback_margin"`
 }
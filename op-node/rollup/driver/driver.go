@@ -2,12 +2,14 @@ package driver
 
 import (
 	"context"
+	"time"
 
 	"github.com/ethereum-optimism/optimism/op-node/eth"
 	"github.com/ethereum-optimism/optimism/op-node/rollup"
 	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/log"
 )
 
@@ -28,10 +30,20 @@ type Metrics interface {
 
 	RecordUnsafePayloadsBuffer(length uint64, memSize uint64, next eth.BlockID)
 
+	RecordDerivationDrop(kind string, reason string)
+	RecordStageStep(stage string, duration time.Duration)
+	RecordStageReset(stage string)
+	RecordStageError(stage string)
+	RecordChannelBank(channels int, frames int, memSize uint64)
+	RecordBatchQueue(batches int, l1Blocks int)
+
 	SetDerivationIdle(idle bool)
 
 	RecordL1ReorgDepth(d uint64)
 	CountSequencedTxs(count int)
+
+	RecordSequencerBatchWindow(remaining int64, fallback bool)
+	RecordSequencerFallbackBlock()
 }
 
 type Downloader interface {
@@ -60,11 +72,14 @@ type DerivationPipeline interface {
 	SafeL2Head() eth.L2BlockRef
 	UnsafeL2Head() eth.L2BlockRef
 	Progress() derive.Progress
+	DroppedInputs() []derive.DropRecord
 }
 
 type outputInterface interface {
 	// createNewBlock builds a new block based on the L2 Head, L1 Origin, and the current mempool.
-	createNewBlock(ctx context.Context, l2Head eth.L2BlockRef, l2SafeHead eth.BlockID, l2Finalized eth.BlockID, l1Origin eth.L1BlockRef) (eth.L2BlockRef, *eth.ExecutionPayload, error)
+	// The mempool is not used if depositsOnly is true.
+	// If commit is not nil, the new block only becomes the head of the engine if commit succeeds.
+	createNewBlock(ctx context.Context, l2Head eth.L2BlockRef, l2SafeHead eth.BlockID, l2Finalized eth.BlockID, l1Origin eth.L1BlockRef, depositsOnly bool, commit func(payload *eth.ExecutionPayload) error) (eth.L2BlockRef, *eth.ExecutionPayload, error)
 }
 
 type Network interface {
@@ -72,7 +87,19 @@ type Network interface {
 	PublishL2Payload(ctx context.Context, payload *eth.ExecutionPayload) error
 }
 
-func NewDriver(driverCfg *Config, cfg *rollup.Config, l2 L2Chain, l1 L1Chain, network Network, log log.Logger, snapshotLog log.Logger, metrics Metrics) *Driver {
+// SequencerConductor coordinates which of multiple sequencer nodes is actively sequencing.
+type SequencerConductor interface {
+	// Leader returns true if this node is the active sequencer.
+	Leader() bool
+	// CommitUnsafePayload replicates a newly sequenced payload to the other sequencers,
+	// before it becomes the unsafe head of the engine and is published.
+	CommitUnsafePayload(ctx context.Context, payload *eth.ExecutionPayload) error
+	// UnsafePayloadsAfter returns the replicated unsafe payloads after the given block number, in order.
+	UnsafePayloadsAfter(num uint64) []*eth.ExecutionPayload
+}
+
+// NewDriver creates a new driver. The network and conductor are optional and may be nil.
+func NewDriver(driverCfg *Config, cfg *rollup.Config, l2 L2Chain, l1 L1Chain, network Network, conductor SequencerConductor, log log.Logger, snapshotLog log.Logger, metrics Metrics) *Driver {
 	output := &outputImpl{
 		Config: cfg,
 		dl:     l1,
@@ -83,7 +110,7 @@ func NewDriver(driverCfg *Config, cfg *rollup.Config, l2 L2Chain, l1 L1Chain, ne
 	var state *state
 	verifConfDepth := NewConfDepth(driverCfg.VerifierConfDepth, func() eth.L1BlockRef { return state.l1Head }, l1)
 	derivationPipeline := derive.NewDerivationPipeline(log, cfg, verifConfDepth, l2, metrics)
-	state = NewState(driverCfg, log, snapshotLog, cfg, l1, l2, output, derivationPipeline, network, metrics)
+	state = NewState(driverCfg, log, snapshotLog, cfg, l1, l2, output, derivationPipeline, network, conductor, metrics)
 	return &Driver{s: state}
 }
 
@@ -107,10 +134,35 @@ func (d *Driver) ResetDerivationPipeline(ctx context.Context) error {
 	return d.s.ResetDerivationPipeline(ctx)
 }
 
+// DerivationDiagnostics returns the most recent inputs that were dropped by the derivation pipeline, oldest first.
+func (d *Driver) DerivationDiagnostics(ctx context.Context) ([]derive.DropRecord, error) {
+	return d.s.derivation.DroppedInputs(), nil
+}
+
 func (d *Driver) SyncStatus(ctx context.Context) (*eth.SyncStatus, error) {
 	return d.s.SyncStatus(ctx)
 }
 
+func (d *Driver) SubscribeUnsafeHead(ch chan<- eth.L2BlockRef) event.Subscription {
+	return d.s.SubscribeUnsafeHead(ch)
+}
+
+func (d *Driver) SubscribeSafeHead(ch chan<- eth.L2BlockRef) event.Subscription {
+	return d.s.SubscribeSafeHead(ch)
+}
+
+func (d *Driver) SubscribeFinalizedHead(ch chan<- eth.L2BlockRef) event.Subscription {
+	return d.s.SubscribeFinalizedHead(ch)
+}
+
+func (d *Driver) SubscribeL1Head(ch chan<- eth.L1BlockRef) event.Subscription {
+	return d.s.SubscribeL1Head(ch)
+}
+
+func (d *Driver) SubscribeDerivationReset(ch chan<- eth.DerivationResetEvent) event.Subscription {
+	return d.s.SubscribeDerivationReset(ch)
+}
+
 func (d *Driver) Start(ctx context.Context) error {
 	return d.s.Start(ctx)
 }
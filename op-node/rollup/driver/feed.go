@@ -0,0 +1,52 @@
+package driver
+
+import (
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// subscriptionBufferSize is the number of events that are buffered for a subscriber before events are dropped.
+const subscriptionBufferSize = 64
+
+// subscribeBuffered subscribes ch to the feed through a forwarding goroutine, so that a slow subscriber cannot block
+// the sender of the feed, i.e. the driver event loop. Up to subscriptionBufferSize events are buffered for the
+// subscriber, after which the oldest buffered events are dropped.
+func subscribeBuffered[T any](feed *event.Feed, ch chan<- T, log log.Logger) event.Subscription {
+	in := make(chan T)
+	sub := feed.Subscribe(in)
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		var queue []T
+		dropped := 0
+		for {
+			// only try to forward if there is something to forward, sending on a nil channel blocks forever
+			var out chan<- T
+			var next T
+			if len(queue) > 0 {
+				out = ch
+				next = queue[0]
+			}
+			select {
+			case ev := <-in:
+				if len(queue) >= subscriptionBufferSize {
+					queue = queue[1:]
+					dropped++
+					if dropped == 1 {
+						log.Warn("Subscriber is too slow, dropping events")
+					}
+				}
+				queue = append(queue, ev)
+			case out <- next:
+				queue = queue[1:]
+				if dropped > 0 && len(queue) == 0 {
+					log.Info("Subscriber caught up", "dropped", dropped)
+					dropped = 0
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	})
+}
@@ -0,0 +1,55 @@
+package driver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-node/testlog"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+func TestSubscribeBufferedSlowSubscriber(t *testing.T) {
+	var feed event.Feed
+	slow := make(chan int)
+	sub := subscribeBuffered(&feed, slow, testlog.Logger(t, log.LvlError))
+	defer sub.Unsubscribe()
+
+	// The subscriber does not read, sending must not block on it.
+	total := subscriptionBufferSize + 10
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < total; i++ {
+			feed.Send(i)
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("feed send blocked on slow subscriber")
+	}
+
+	// The oldest events were dropped, the latest ones are delivered in order.
+	for i := total - subscriptionBufferSize; i < total; i++ {
+		select {
+		case ev := <-slow:
+			require.Equal(t, i, ev)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("missing event %d", i)
+		}
+	}
+}
+
+func TestSubscribeBufferedUnsubscribe(t *testing.T) {
+	var feed event.Feed
+	sub := subscribeBuffered(&feed, make(chan int), testlog.Logger(t, log.LvlError))
+	feed.Send(1)
+	sub.Unsubscribe()
+	_, ok := <-sub.Err()
+	require.False(t, ok, "error channel is closed on unsubscribe")
+	require.Eventually(t, func() bool { return feed.Send(2) == 0 }, 5*time.Second, 10*time.Millisecond,
+		"forwarding goroutine unsubscribed from the feed")
+}
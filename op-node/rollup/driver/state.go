@@ -13,9 +13,14 @@ import (
 	"github.com/ethereum-optimism/optimism/op-node/eth"
 	"github.com/ethereum-optimism/optimism/op-node/rollup"
 	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
+	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/log"
 )
 
+// maxCatchUpStalls is the number of block creation attempts that a newly elected sequencer waits for
+// the queued replicated unsafe payloads to apply, before it queues them again.
+const maxCatchUpStalls = 10
+
 // Deprecated: use eth.SyncStatus instead.
 type SyncStatus = eth.SyncStatus
 
@@ -58,11 +63,37 @@ type state struct {
 	// L2 Signals:
 	unsafeL2Payloads chan *eth.ExecutionPayload
 
+	// Feeds of head changes and pipeline resets, published from the event loop. Subscribers are served by
+	// forwarding goroutines, see subscribeBuffered, so slow subscribers do not block the event loop.
+	unsafeHeadFeed    event.Feed
+	safeHeadFeed      event.Feed
+	finalizedHeadFeed event.Feed
+	l1HeadFeed        event.Feed
+	resetFeed         event.Feed
+
+	// The L2 heads that were last published to the feeds, to only publish changes.
+	publishedUnsafeL2    eth.L2BlockRef
+	publishedSafeL2      eth.L2BlockRef
+	publishedFinalizedL2 eth.L2BlockRef
+
 	l1      L1Chain
 	l2      L2Chain
 	output  outputInterface
 	network Network // may be nil, network for is optional
 
+	conductor SequencerConductor // may be nil, sequencing is not coordinated with other sequencers if nil
+
+	// Progress of catching up with replicated unsafe payloads: the number of the last queued payload,
+	// and the number of catch-up attempts that the unsafe head did not change for.
+	catchUpQueued uint64
+	catchUpHead   eth.L2BlockRef
+	catchUpStalls int
+
+	// True while the sequencer only produces deposit-only blocks, because batch submission stalled.
+	sequencerFallback bool
+	// The last deposit-only block built while falling back, to continue the deposit-only chain from.
+	fallbackHead eth.L2BlockRef
+
 	metrics     Metrics
 	log         log.Logger
 	snapshotLog log.Logger
@@ -74,7 +105,7 @@ type state struct {
 // NewState creates a new driver state. State changes take effect though
 // the given output, derivation pipeline and network interfaces.
 func NewState(driverCfg *Config, log log.Logger, snapshotLog log.Logger, config *rollup.Config, l1Chain L1Chain, l2Chain L2Chain,
-	output outputInterface, derivationPipeline DerivationPipeline, network Network, metrics Metrics) *state {
+	output outputInterface, derivationPipeline DerivationPipeline, network Network, conductor SequencerConductor, metrics Metrics) *state {
 	return &state{
 		derivation:       derivationPipeline,
 		idleDerivation:   false,
@@ -89,6 +120,7 @@ func NewState(driverCfg *Config, log log.Logger, snapshotLog log.Logger, config
 		l2:               l2Chain,
 		output:           output,
 		network:          network,
+		conductor:        conductor,
 		metrics:          metrics,
 		l1HeadSig:        make(chan eth.L1BlockRef, 10),
 		l1SafeSig:        make(chan eth.L1BlockRef, 10),
@@ -174,9 +206,40 @@ func (s *state) handleNewL1HeadBlock(head eth.L1BlockRef) {
 	}
 	s.snapshot("New L1 Head")
 	s.metrics.RecordL1Ref("l1_head", head)
+	if s.l1Head != head {
+		s.l1HeadFeed.Send(head)
+	}
 	s.l1Head = head
 }
 
+// publishL2Heads publishes the L2 heads of the derivation pipeline to the subscribers of the head feeds,
+// if they changed since they were last published.
+func (s *state) publishL2Heads() {
+	if unsafe := s.derivation.UnsafeL2Head(); unsafe != s.publishedUnsafeL2 {
+		s.publishedUnsafeL2 = unsafe
+		s.unsafeHeadFeed.Send(unsafe)
+	}
+	if safe := s.derivation.SafeL2Head(); safe != s.publishedSafeL2 {
+		s.publishedSafeL2 = safe
+		s.safeHeadFeed.Send(safe)
+	}
+	if finalized := s.derivation.Finalized(); finalized != s.publishedFinalizedL2 {
+		s.publishedFinalizedL2 = finalized
+		s.finalizedHeadFeed.Send(finalized)
+	}
+}
+
+// resetDerivation resets the derivation pipeline and notifies the reset subscribers.
+func (s *state) resetDerivation(reason string) {
+	s.derivation.Reset()
+	s.metrics.RecordPipelineReset()
+	s.resetFeed.Send(eth.DerivationResetEvent{
+		Reason:   reason,
+		UnsafeL2: s.derivation.UnsafeL2Head(),
+		SafeL2:   s.derivation.SafeL2Head(),
+	})
+}
+
 func (s *state) handleNewL1SafeBlock(safe eth.L1BlockRef) {
 	s.log.Info("New L1 safe block", "l1_safe", safe)
 	s.metrics.RecordL1Ref("l1_safe", safe)
@@ -237,11 +300,95 @@ func (s *state) findL1Origin(ctx context.Context) (eth.L1BlockRef, error) {
 	return currentOrigin, nil
 }
 
+// findDepositOnlyL1Origin determines the L1 origin of the block after l2Head the same way derivation does for the
+// deposit-only blocks it generates when the sequencing window closes without batches: the origin advances to the next
+// L1 block as soon as the next L2 block time reaches the time of that L1 block, regardless of the confirmation depth.
+// It returns false if the next L1 block is not known yet, as the origin cannot be determined until it is.
+func (s *state) findDepositOnlyL1Origin(ctx context.Context, l2Head eth.L2BlockRef) (eth.L1BlockRef, bool, error) {
+	if l2Head.L1Origin.Number >= s.l1Head.Number {
+		return eth.L1BlockRef{}, false, nil
+	}
+	currentOrigin, err := s.l1.L1BlockRefByHash(ctx, l2Head.L1Origin.Hash)
+	if err != nil {
+		return eth.L1BlockRef{}, false, err
+	}
+	nextOrigin, err := s.l1.L1BlockRefByNumber(ctx, currentOrigin.Number+1)
+	if err != nil {
+		return eth.L1BlockRef{}, false, err
+	}
+	if nextOrigin.ParentHash != currentOrigin.Hash {
+		return eth.L1BlockRef{}, false, fmt.Errorf("next L1 origin %s does not build on current origin %s", nextOrigin, currentOrigin)
+	}
+	if l2Head.Time+s.Config.BlockTime >= nextOrigin.Time {
+		return nextOrigin, true, nil
+	}
+	return currentOrigin, true, nil
+}
+
+// checkBatchWindow checks if the sequencing window of the oldest unsafe L2 block is about to close,
+// in which case the sequencer should fall back to deposit-only blocks.
+// The safe head L1 origin is used as the L1 origin of the oldest unsafe block, which is conservative by at most one block.
+func (s *state) checkBatchWindow() bool {
+	unsafe, safe := s.derivation.UnsafeL2Head(), s.derivation.SafeL2Head()
+	remaining := int64(s.Config.SeqWindowSize)
+	if unsafe.Number > safe.Number {
+		remaining = int64(safe.L1Origin.Number+s.Config.SeqWindowSize) - int64(s.l1Head.Number)
+	}
+	margin := s.DriverConfig.SequencerFallbackMargin
+	if max := s.Config.SeqWindowSize / 2; margin > max {
+		margin = max
+	}
+	fallback := remaining <= int64(margin)
+	if fallback && !s.sequencerFallback {
+		s.log.Warn("Batch submission stalled, falling back to deposit-only blocks",
+			"l2_unsafe", unsafe, "l2_safe", safe, "l1_head", s.l1Head, "window_remaining", remaining)
+	} else if !fallback && s.sequencerFallback {
+		s.log.Info("Batch submission caught up, resuming regular sequencing",
+			"l2_unsafe", unsafe, "l2_safe", safe, "l1_head", s.l1Head, "window_remaining", remaining)
+	}
+	s.sequencerFallback = fallback
+	s.metrics.RecordSequencerBatchWindow(remaining, fallback)
+	return fallback
+}
+
+// depositOnlyParent returns the block to build the next deposit-only block on.
+// When the sequencing window closes without batches, derivation replaces the unsafe blocks with deposit-only blocks
+// built on the safe head. The sequencer builds the same blocks, so that they are not reorged out:
+// the first deposit-only block is built on the safe head, dropping any unsafe blocks after it,
+// and the next deposit-only blocks extend the previous one.
+// The replicated unsafe chain of a sequencer conductor cannot be reorged, so with a conductor the deposit-only blocks
+// extend the unsafe head instead, and are replaced by derivation like the unsafe blocks before them.
+func (s *state) depositOnlyParent() eth.L2BlockRef {
+	unsafe := s.derivation.UnsafeL2Head()
+	if s.conductor != nil || unsafe == s.fallbackHead {
+		return unsafe
+	}
+	safe := s.derivation.SafeL2Head()
+	if unsafe != safe {
+		s.log.Warn("Replacing unsafe blocks with deposit-only blocks built on the safe head", "l2_unsafe", unsafe, "l2_safe", safe)
+	}
+	return safe
+}
+
 // createNewL2Block builds a L2 block on top of the L2 Head (unsafe). Used by Sequencer nodes to
 // construct new L2 blocks. Verifier nodes will use handleEpoch instead.
 func (s *state) createNewL2Block(ctx context.Context) error {
 	// Figure out which L1 origin block we're going to be building on top of.
-	l1Origin, err := s.findL1Origin(ctx)
+	depositsOnly := s.checkBatchWindow()
+	l2Head := s.derivation.UnsafeL2Head()
+	var l1Origin eth.L1BlockRef
+	var err error
+	if depositsOnly {
+		l2Head = s.depositOnlyParent()
+		var ok bool
+		l1Origin, ok, err = s.findDepositOnlyL1Origin(ctx, l2Head)
+		if err == nil && !ok {
+			s.log.Info("Waiting for next L1 block to determine L1 origin of deposit-only block", "l2_parent", l2Head, "l1_head", s.l1Head)
+			return nil
+		}
+	} else {
+		l1Origin, err = s.findL1Origin(ctx)
+	}
 	if err != nil {
 		s.log.Error("Error finding next L1 Origin", "err", err)
 		return err
@@ -254,7 +401,6 @@ func (s *state) createNewL2Block(ctx context.Context) error {
 		return nil
 	}
 
-	l2Head := s.derivation.UnsafeL2Head()
 	l2Safe := s.derivation.SafeL2Head()
 	l2Finalized := s.derivation.Finalized()
 
@@ -267,8 +413,17 @@ func (s *state) createNewL2Block(ctx context.Context) error {
 			l2Head, nextL2Time, l1Origin, l1Origin.Time)
 	}
 
+	// Replicate the block to the other sequencers before the engine adopts it as unsafe head.
+	// If this fails, the engine keeps the previous unsafe head, and the next block is built on top of it instead.
+	var commit func(payload *eth.ExecutionPayload) error
+	if s.conductor != nil {
+		commit = func(payload *eth.ExecutionPayload) error {
+			return s.conductor.CommitUnsafePayload(ctx, payload)
+		}
+	}
+
 	// Actually create the new block.
-	newUnsafeL2Head, payload, err := s.output.createNewBlock(ctx, l2Head, l2Safe.ID(), l2Finalized.ID(), l1Origin)
+	newUnsafeL2Head, payload, err := s.output.createNewBlock(ctx, l2Head, l2Safe.ID(), l2Finalized.ID(), l1Origin, depositsOnly, commit)
 	if err != nil {
 		s.log.Error("Could not extend chain as sequencer", "err", err, "l2_parent", l2Head, "l1_origin", l1Origin)
 		return err
@@ -279,6 +434,10 @@ func (s *state) createNewL2Block(ctx context.Context) error {
 
 	s.log.Info("Sequenced new l2 block", "l2_unsafe", newUnsafeL2Head, "l1_origin", newUnsafeL2Head.L1Origin, "txs", len(payload.Transactions), "time", newUnsafeL2Head.Time)
 	s.metrics.CountSequencedTxs(len(payload.Transactions))
+	if depositsOnly {
+		s.fallbackHead = newUnsafeL2Head
+		s.metrics.RecordSequencerFallbackBlock()
+	}
 
 	if s.network != nil {
 		if err := s.network.PublishL2Payload(ctx, payload); err != nil {
@@ -291,6 +450,77 @@ func (s *state) createNewL2Block(ctx context.Context) error {
 	return nil
 }
 
+// catchUpReplicatedPayloads queues the replicated unsafe payloads that are ahead of the unsafe head,
+// such that a newly elected sequencer continues from the last replicated unsafe head.
+// Every replicated payload is queued once. If the unsafe head does not progress for maxCatchUpStalls calls, e.g.
+// because the replicated payloads do not build on the local unsafe chain, the payloads are queued again.
+// It returns true if there are any payloads to catch up with.
+func (s *state) catchUpReplicatedPayloads() bool {
+	l2Head := s.derivation.UnsafeL2Head()
+	payloads := s.conductor.UnsafePayloadsAfter(l2Head.Number)
+	if len(payloads) == 0 {
+		s.catchUpQueued = 0
+		s.catchUpStalls = 0
+		return false
+	}
+	target := payloads[len(payloads)-1].ID()
+
+	if l2Head == s.catchUpHead {
+		s.catchUpStalls++
+	} else {
+		s.catchUpStalls = 0
+	}
+	s.catchUpHead = l2Head
+	if s.catchUpStalls >= maxCatchUpStalls {
+		s.log.Error("Replicated unsafe payloads do not apply onto the unsafe head, queueing them again",
+			"l2_unsafe", l2Head, "replicated", target, "stalls", s.catchUpStalls)
+		s.metrics.RecordSequencingError()
+		s.catchUpQueued = 0
+		s.catchUpStalls = 0
+	}
+
+	queued := 0
+	for _, payload := range payloads {
+		if uint64(payload.BlockNumber) <= s.catchUpQueued {
+			continue
+		}
+		s.derivation.AddUnsafePayload(payload)
+		s.catchUpQueued = uint64(payload.BlockNumber)
+		queued++
+	}
+	if queued > 0 {
+		s.log.Info("Catching up with replicated unsafe payloads before sequencing", "l2_unsafe", l2Head, "replicated", target, "queued", queued)
+	}
+	return true
+}
+
+// sequencerAction is the action of the sequencer upon a block creation request.
+type sequencerAction int
+
+const (
+	// sequencerIdle: no block is created, e.g. because this node is not the active sequencer.
+	sequencerIdle sequencerAction = iota
+	// sequencerCatchUp: replicated unsafe payloads are queued, and the derivation pipeline needs to apply them first.
+	sequencerCatchUp
+	// sequencerCreate: this node is the active sequencer and creates a block.
+	sequencerCreate
+)
+
+// nextSequencerAction determines whether the sequencer can create a block, given the state of the conductor.
+func (s *state) nextSequencerAction() sequencerAction {
+	if s.conductor == nil {
+		return sequencerCreate
+	}
+	if !s.conductor.Leader() {
+		s.log.Trace("not creating block, node is not the active sequencer")
+		return sequencerIdle
+	}
+	if s.catchUpReplicatedPayloads() {
+		return sequencerCatchUp
+	}
+	return sequencerCreate
+}
+
 // the eventLoop responds to L1 changes and internal timers to produce L2 blocks.
 func (s *state) eventLoop() {
 	defer s.wg.Done()
@@ -363,6 +593,9 @@ func (s *state) eventLoop() {
 	reqStep()
 
 	for {
+		// Publish any head changes of the previous event before waiting for the next event.
+		s.publishL2Heads()
+
 		select {
 		case <-l2BlockCreationTickerCh:
 			s.log.Trace("L2 Creation Ticker")
@@ -375,6 +608,12 @@ func (s *state) eventLoop() {
 				s.log.Warn("not creating block, node is deriving new l2 data", "head_l1", s.l1Head)
 				break
 			}
+			if action := s.nextSequencerAction(); action == sequencerIdle {
+				break
+			} else if action == sequencerCatchUp {
+				reqStep()
+				break
+			}
 			ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 			err := s.createNewL2Block(ctx)
 			cancel()
@@ -430,8 +669,7 @@ func (s *state) eventLoop() {
 			} else if err != nil && errors.Is(err, derive.ErrReset) {
 				// If the pipeline corrupts, e.g. due to a reorg, simply reset it
 				s.log.Warn("Derivation pipeline is reset", "err", err)
-				s.derivation.Reset()
-				s.metrics.RecordPipelineReset()
+				s.resetDerivation(err.Error())
 				continue
 			} else if err != nil && errors.Is(err, derive.ErrTemporary) {
 				s.log.Warn("Derivation process temporary error", "attempts", stepAttempts, "err", err)
@@ -460,8 +698,7 @@ func (s *state) eventLoop() {
 			}
 		case respCh := <-s.forceReset:
 			s.log.Warn("Derivation pipeline is manually reset")
-			s.derivation.Reset()
-			s.metrics.RecordPipelineReset()
+			s.resetDerivation("manual reset")
 			close(respCh)
 		case <-s.done:
 			return
@@ -502,6 +739,31 @@ func (s *state) SyncStatus(ctx context.Context) (*eth.SyncStatus, error) {
 	}
 }
 
+// SubscribeUnsafeHead subscribes to changes of the unsafe L2 head.
+func (s *state) SubscribeUnsafeHead(ch chan<- eth.L2BlockRef) event.Subscription {
+	return subscribeBuffered(&s.unsafeHeadFeed, ch, s.log)
+}
+
+// SubscribeSafeHead subscribes to changes of the safe L2 head.
+func (s *state) SubscribeSafeHead(ch chan<- eth.L2BlockRef) event.Subscription {
+	return subscribeBuffered(&s.safeHeadFeed, ch, s.log)
+}
+
+// SubscribeFinalizedHead subscribes to changes of the finalized L2 head.
+func (s *state) SubscribeFinalizedHead(ch chan<- eth.L2BlockRef) event.Subscription {
+	return subscribeBuffered(&s.finalizedHeadFeed, ch, s.log)
+}
+
+// SubscribeL1Head subscribes to changes of the L1 head, as signalled to the driver.
+func (s *state) SubscribeL1Head(ch chan<- eth.L1BlockRef) event.Subscription {
+	return subscribeBuffered(&s.l1HeadFeed, ch, s.log)
+}
+
+// SubscribeDerivationReset subscribes to resets of the derivation pipeline.
+func (s *state) SubscribeDerivationReset(ch chan<- eth.DerivationResetEvent) event.Subscription {
+	return subscribeBuffered(&s.resetFeed, ch, s.log)
+}
+
 // deferJSONString helps avoid a JSON-encoding performance hit if the snapshot logger does not run
 type deferJSONString struct {
 	x any
@@ -0,0 +1,438 @@
+package driver
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-node/eth"
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
+	"github.com/ethereum-optimism/optimism/op-node/testlog"
+	"github.com/ethereum-optimism/optimism/op-node/testutils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// fakeHeads is a derivation pipeline that only reports the given L2 heads.
+type fakeHeads struct {
+	DerivationPipeline
+	unsafe, safe eth.L2BlockRef
+}
+
+func (f *fakeHeads) UnsafeL2Head() eth.L2BlockRef {
+	return f.unsafe
+}
+
+func (f *fakeHeads) SafeL2Head() eth.L2BlockRef {
+	return f.safe
+}
+
+// fallbackMetrics records the batch window metrics, other metrics are not implemented.
+type fallbackMetrics struct {
+	Metrics
+	remaining int64
+	fallback  bool
+}
+
+func (m *fallbackMetrics) RecordSequencerBatchWindow(remaining int64, fallback bool) {
+	m.remaining, m.fallback = remaining, fallback
+}
+
+func TestCheckBatchWindow(t *testing.T) {
+	m := &fallbackMetrics{}
+	heads := &fakeHeads{}
+	s := &state{
+		derivation:   heads,
+		Config:       &rollup.Config{SeqWindowSize: 100},
+		DriverConfig: &Config{SequencerFallbackMargin: 10},
+		log:          testlog.Logger(t, log.LvlError),
+		metrics:      m,
+	}
+
+	// all blocks are safe
+	heads.safe = eth.L2BlockRef{Number: 50, L1Origin: eth.BlockID{Number: 20}}
+	heads.unsafe = heads.safe
+	s.l1Head = eth.L1BlockRef{Number: 200}
+	require.False(t, s.checkBatchWindow())
+	require.Equal(t, int64(100), m.remaining)
+
+	// unsafe blocks, with plenty of time to submit them
+	heads.unsafe = eth.L2BlockRef{Number: 60, L1Origin: eth.BlockID{Number: 22}}
+	s.l1Head = eth.L1BlockRef{Number: 30}
+	require.False(t, s.checkBatchWindow())
+	require.Equal(t, int64(90), m.remaining)
+	require.False(t, m.fallback)
+
+	// batch submission stalled until the margin is reached
+	s.l1Head = eth.L1BlockRef{Number: 110}
+	require.True(t, s.checkBatchWindow())
+	require.Equal(t, int64(10), m.remaining)
+	require.True(t, m.fallback)
+
+	// and past the end of the window
+	s.l1Head = eth.L1BlockRef{Number: 130}
+	require.True(t, s.checkBatchWindow())
+	require.Equal(t, int64(-10), m.remaining)
+
+	// batch submission caught up
+	heads.safe = heads.unsafe
+	require.False(t, s.checkBatchWindow())
+	require.False(t, m.fallback)
+
+	// the margin is limited to half the sequencing window
+	s.DriverConfig.SequencerFallbackMargin = 1000
+	heads.safe = eth.L2BlockRef{Number: 50, L1Origin: eth.BlockID{Number: 20}}
+	s.l1Head = eth.L1BlockRef{Number: 69}
+	require.False(t, s.checkBatchWindow())
+	s.l1Head = eth.L1BlockRef{Number: 70}
+	require.True(t, s.checkBatchWindow())
+}
+
+func TestFindDepositOnlyL1Origin(t *testing.T) {
+	current := eth.L1BlockRef{Hash: common.Hash{0x01}, Number: 10, Time: 1000}
+	next := eth.L1BlockRef{Hash: common.Hash{0x02}, Number: 11, ParentHash: current.Hash, Time: 1012}
+	l1 := &testutils.MockL1Source{}
+	heads := &fakeHeads{}
+	s := &state{
+		derivation: heads,
+		Config:     &rollup.Config{BlockTime: 2},
+		l1:         l1,
+	}
+
+	// the next L1 block is not known yet, so the origin of the next L2 block cannot be determined
+	heads.unsafe = eth.L2BlockRef{Number: 100, Time: 1008, L1Origin: current.ID()}
+	s.l1Head = current
+	_, ok, err := s.findDepositOnlyL1Origin(context.Background(), heads.unsafe)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	// the next L2 block is before the next L1 block, and stays on the current origin,
+	// regardless of the confirmation depth of the sequencer
+	s.l1Head = next
+	s.DriverConfig = &Config{SequencerConfDepth: 10}
+	l1.ExpectL1BlockRefByHash(current.Hash, current, nil)
+	l1.ExpectL1BlockRefByNumber(next.Number, next, nil)
+	origin, ok, err := s.findDepositOnlyL1Origin(context.Background(), heads.unsafe)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, current, origin)
+
+	// the next L2 block reaches the time of the next L1 block, and advances the origin
+	heads.unsafe.Time = 1010
+	l1.ExpectL1BlockRefByHash(current.Hash, current, nil)
+	l1.ExpectL1BlockRefByNumber(next.Number, next, nil)
+	origin, ok, err = s.findDepositOnlyL1Origin(context.Background(), heads.unsafe)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, next, origin)
+	l1.AssertExpectations(t)
+}
+
+// fakeConductor is a SequencerConductor with a fixed leadership and replicated chain.
+type fakeConductor struct {
+	leader     bool
+	replicated []*eth.ExecutionPayload
+	commitErr  error
+}
+
+func (c *fakeConductor) Leader() bool {
+	return c.leader
+}
+
+func (c *fakeConductor) CommitUnsafePayload(ctx context.Context, payload *eth.ExecutionPayload) error {
+	if c.commitErr != nil {
+		return c.commitErr
+	}
+	c.replicated = append(c.replicated, payload)
+	return nil
+}
+
+func (c *fakeConductor) UnsafePayloadsAfter(num uint64) []*eth.ExecutionPayload {
+	var out []*eth.ExecutionPayload
+	for _, p := range c.replicated {
+		if uint64(p.BlockNumber) > num {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// fakeSequencerPipeline records the unsafe payloads that are queued, and the unsafe heads that are set.
+type fakeSequencerPipeline struct {
+	fakeHeads
+	queued []*eth.ExecutionPayload
+}
+
+func (f *fakeSequencerPipeline) AddUnsafePayload(payload *eth.ExecutionPayload) {
+	f.queued = append(f.queued, payload)
+}
+
+func (f *fakeSequencerPipeline) SetUnsafeHead(head eth.L2BlockRef) {
+	f.unsafe = head
+}
+
+func (f *fakeSequencerPipeline) Finalized() eth.L2BlockRef {
+	return eth.L2BlockRef{}
+}
+
+// apply applies the next queued payload onto the unsafe head, like the engine queue does.
+func (f *fakeSequencerPipeline) apply() {
+	for len(f.queued) > 0 {
+		p := f.queued[0]
+		f.queued = f.queued[1:]
+		if p.ParentHash == f.unsafe.Hash {
+			f.unsafe = payloadRef(p, f.unsafe.L1Origin)
+			return
+		}
+	}
+}
+
+// fakeEngineOutput builds blocks on the given head, and makes them the engine head only if they are committed.
+type fakeEngineOutput struct {
+	engineHead eth.L2BlockRef
+}
+
+func (o *fakeEngineOutput) createNewBlock(ctx context.Context, l2Head eth.L2BlockRef, l2SafeHead eth.BlockID, l2Finalized eth.BlockID, l1Origin eth.L1BlockRef, depositsOnly bool, commit func(payload *eth.ExecutionPayload) error) (eth.L2BlockRef, *eth.ExecutionPayload, error) {
+	payload := &eth.ExecutionPayload{
+		ParentHash:  l2Head.Hash,
+		BlockHash:   common.Hash{0xbb, byte(l2Head.Number + 1)},
+		BlockNumber: eth.Uint64Quantity(l2Head.Number + 1),
+		Timestamp:   eth.Uint64Quantity(l2Head.Time + 2),
+	}
+	if commit != nil {
+		if err := commit(payload); err != nil {
+			return l2Head, nil, err
+		}
+	}
+	o.engineHead = payloadRef(payload, l1Origin.ID())
+	return o.engineHead, payload, nil
+}
+
+func payloadRef(p *eth.ExecutionPayload, l1Origin eth.BlockID) eth.L2BlockRef {
+	return eth.L2BlockRef{
+		Hash:       p.BlockHash,
+		Number:     uint64(p.BlockNumber),
+		ParentHash: p.ParentHash,
+		Time:       uint64(p.Timestamp),
+		L1Origin:   l1Origin,
+	}
+}
+
+// sequencerMetrics implements the metrics used by the sequencer, other metrics are not implemented.
+type sequencerMetrics struct {
+	fallbackMetrics
+	sequencingErrors int
+}
+
+func (m *sequencerMetrics) RecordSequencingError() {
+	m.sequencingErrors++
+}
+
+func (m *sequencerMetrics) CountSequencedTxs(count int) {}
+
+func (m *sequencerMetrics) RecordSequencerFallbackBlock() {}
+
+func newSequencerTest(t *testing.T, conductor *fakeConductor) (*state, *fakeSequencerPipeline, *fakeEngineOutput, *sequencerMetrics) {
+	l1Head := eth.L1BlockRef{Hash: common.Hash{0xaa}, Number: 5, Time: 1000}
+	head := eth.L2BlockRef{Hash: common.Hash{0xbb, 10}, Number: 10, Time: 1000, L1Origin: l1Head.ID()}
+	pipeline := &fakeSequencerPipeline{fakeHeads: fakeHeads{unsafe: head, safe: head}}
+	output := &fakeEngineOutput{engineHead: head}
+	m := &sequencerMetrics{}
+	s := &state{
+		l1Head:       l1Head,
+		derivation:   pipeline,
+		Config:       &rollup.Config{BlockTime: 2, SeqWindowSize: 100},
+		DriverConfig: &Config{SequencerEnabled: true},
+		output:       output,
+		conductor:    conductor,
+		log:          testlog.Logger(t, log.LvlError),
+		metrics:      m,
+	}
+	return s, pipeline, output, m
+}
+
+func TestSequencerLeaderHandoff(t *testing.T) {
+	conductor := &fakeConductor{}
+	s, pipeline, output, _ := newSequencerTest(t, conductor)
+	ctx := context.Background()
+
+	// another node is the leader, and replicates blocks 11 and 12
+	leaderOutput := &fakeEngineOutput{}
+	head := pipeline.unsafe
+	for i := 0; i < 2; i++ {
+		var payload *eth.ExecutionPayload
+		var err error
+		head, payload, err = leaderOutput.createNewBlock(ctx, head, eth.BlockID{}, eth.BlockID{}, s.l1Head, false, nil)
+		require.NoError(t, err)
+		conductor.replicated = append(conductor.replicated, payload)
+	}
+	// the follower does not sequence
+	require.Equal(t, sequencerIdle, s.nextSequencerAction())
+	require.Empty(t, pipeline.queued)
+
+	// this node becomes the leader, and catches up with the replicated blocks before sequencing
+	conductor.leader = true
+	require.Equal(t, sequencerCatchUp, s.nextSequencerAction())
+	require.Equal(t, conductor.replicated, pipeline.queued)
+	require.Equal(t, sequencerCatchUp, s.nextSequencerAction())
+	require.Len(t, pipeline.queued, 2, "payloads are only queued once")
+
+	pipeline.apply()
+	require.Equal(t, sequencerCatchUp, s.nextSequencerAction())
+	pipeline.apply()
+	require.Equal(t, head, pipeline.unsafe)
+	require.Empty(t, pipeline.queued)
+
+	// caught up: the new leader continues the replicated chain
+	require.Equal(t, sequencerCreate, s.nextSequencerAction())
+	require.NoError(t, s.createNewL2Block(ctx))
+	require.Equal(t, uint64(13), pipeline.unsafe.Number)
+	require.Equal(t, head.Hash, pipeline.unsafe.ParentHash)
+	require.Equal(t, pipeline.unsafe, output.engineHead)
+	require.Len(t, conductor.replicated, 3)
+	require.Equal(t, pipeline.unsafe.Hash, conductor.replicated[2].BlockHash)
+
+	// the old leader lost leadership, and follows
+	conductor.leader = false
+	require.Equal(t, sequencerIdle, s.nextSequencerAction())
+}
+
+func TestSequencerCommitFailure(t *testing.T) {
+	conductor := &fakeConductor{leader: true, commitErr: errors.New("leadership lost")}
+	s, pipeline, output, _ := newSequencerTest(t, conductor)
+	head := pipeline.unsafe
+
+	require.ErrorIs(t, s.createNewL2Block(context.Background()), conductor.commitErr)
+	require.Equal(t, head, pipeline.unsafe, "unsafe head is not updated")
+	require.Equal(t, head, output.engineHead, "engine does not adopt the uncommitted block")
+	require.Empty(t, conductor.replicated)
+}
+
+func TestSequencerCatchUpStall(t *testing.T) {
+	// the replicated chain does not build on the local unsafe chain
+	conductor := &fakeConductor{leader: true, replicated: []*eth.ExecutionPayload{
+		{ParentHash: common.Hash{0xff}, BlockHash: common.Hash{0xcc, 11}, BlockNumber: 11},
+	}}
+	s, pipeline, _, m := newSequencerTest(t, conductor)
+
+	for i := 0; i < maxCatchUpStalls; i++ {
+		require.Equal(t, sequencerCatchUp, s.nextSequencerAction())
+		pipeline.apply()
+	}
+	require.Empty(t, pipeline.queued)
+	require.Zero(t, m.sequencingErrors)
+
+	// the payloads are not re-queued at every attempt, only after the unsafe head stalled for a while
+	queued := 0
+	pipeline.queued = nil
+	for i := 0; i < 2*maxCatchUpStalls; i++ {
+		require.Equal(t, sequencerCatchUp, s.nextSequencerAction())
+		queued += len(pipeline.queued)
+		pipeline.queued = nil
+	}
+	require.Equal(t, 2, queued)
+	require.Equal(t, 2, m.sequencingErrors)
+}
+
+// fakeSafeOutput is the stage after the batch queue, it applies every batch onto the safe head,
+// with the same block hashes as fakeEngineOutput.
+type fakeSafeOutput struct {
+	progress derive.Progress
+	safe     eth.L2BlockRef
+	batches  []*derive.BatchData
+}
+
+func (f *fakeSafeOutput) Progress() derive.Progress {
+	return f.progress
+}
+
+func (f *fakeSafeOutput) AddBatch(batch *derive.BatchData) {
+	f.batches = append(f.batches, batch)
+	f.safe = eth.L2BlockRef{
+		Hash:       common.Hash{0xbb, byte(f.safe.Number + 1)},
+		Number:     f.safe.Number + 1,
+		ParentHash: batch.ParentHash,
+		Time:       batch.Timestamp,
+		L1Origin:   eth.BlockID{Hash: batch.EpochHash, Number: uint64(batch.EpochNum)},
+	}
+}
+
+func (f *fakeSafeOutput) SafeL2Head() eth.L2BlockRef {
+	return f.safe
+}
+
+func TestSequencerFallbackMatchesDerivation(t *testing.T) {
+	var l1Chain []eth.L1BlockRef
+	for i := uint64(0); i < 5; i++ {
+		ref := eth.L1BlockRef{Hash: common.Hash{0xaa, byte(i)}, Number: i, Time: 10 + 3*i}
+		if i > 0 {
+			ref.ParentHash = l1Chain[i-1].Hash
+		}
+		l1Chain = append(l1Chain, ref)
+	}
+	cfg := &rollup.Config{BlockTime: 2, SeqWindowSize: 4, MaxSequencerDrift: 600}
+	safe := eth.L2BlockRef{Hash: common.Hash{0xbb, 0}, Number: 0, Time: 10, L1Origin: l1Chain[0].ID()}
+	// unsafe blocks were sequenced, but batch submission stalled
+	unsafe := eth.L2BlockRef{Hash: common.Hash{0xcc, 3}, Number: 3, Time: 16, L1Origin: l1Chain[0].ID()}
+
+	l1 := &testutils.MockL1Source{}
+	pipeline := &fakeSequencerPipeline{fakeHeads: fakeHeads{unsafe: unsafe, safe: safe}}
+	output := &fakeEngineOutput{engineHead: unsafe}
+	s := &state{
+		l1:           l1,
+		l1Head:       l1Chain[2],
+		derivation:   pipeline,
+		Config:       cfg,
+		DriverConfig: &Config{SequencerEnabled: true, SequencerFallbackMargin: 2},
+		output:       output,
+		log:          testlog.Logger(t, log.LvlError),
+		metrics:      &sequencerMetrics{},
+	}
+
+	// the window of the oldest unsafe block is about to close: the sequencer builds deposit-only blocks on the safe head,
+	// and then continues from the deposit-only blocks
+	var sequenced []eth.L2BlockRef
+	for i := 0; i < 2; i++ {
+		// both parents have the first L1 block as origin, the second deposit-only block adopts the next origin
+		l1.ExpectL1BlockRefByHash(l1Chain[0].Hash, l1Chain[0], nil)
+		l1.ExpectL1BlockRefByNumber(1, l1Chain[1], nil)
+		require.NoError(t, s.createNewL2Block(context.Background()))
+		sequenced = append(sequenced, pipeline.unsafe)
+	}
+	l1.AssertExpectations(t)
+	require.Equal(t, safe.Hash, sequenced[0].ParentHash)
+	require.Equal(t, sequenced[0].Hash, sequenced[1].ParentHash)
+
+	// the batch submitter does not recover, and the window expires:
+	// derivation replaces the unsafe chain with the same deposit-only blocks
+	next := &fakeSafeOutput{progress: derive.Progress{Origin: l1Chain[0]}, safe: safe}
+	bq := derive.NewBatchQueue(testlog.Logger(t, log.LvlError), cfg, next, derive.NewDropDiagnostics(derive.MaxDropRecords, nil))
+	require.Equal(t, io.EOF, bq.ResetStep(context.Background(), nil))
+	step := func(progress derive.Progress) {
+		for {
+			err := bq.Step(context.Background(), progress)
+			if err == io.EOF {
+				return
+			}
+			require.NoError(t, err)
+		}
+	}
+	for i, origin := range l1Chain {
+		if i > 0 {
+			step(derive.Progress{Origin: origin})
+		}
+		step(derive.Progress{Origin: origin, Closed: true})
+	}
+	require.Len(t, next.batches, 2)
+	for i, batch := range next.batches {
+		require.Empty(t, batch.Transactions)
+		require.Equal(t, sequenced[i].ParentHash, batch.ParentHash)
+		require.Equal(t, sequenced[i].Time, batch.Timestamp)
+		require.Equal(t, sequenced[i].L1Origin, eth.BlockID{Hash: batch.EpochHash, Number: uint64(batch.EpochNum)})
+	}
+	require.Equal(t, sequenced[1], next.safe, "derived safe head matches the sequenced deposit-only chain")
+}
@@ -0,0 +1,51 @@
+package rollup
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+)
+
+//go:embed networks
+var embeddedNetworks embed.FS
+
+// networks is the registry of known rollup configs, see networks/README.md.
+var networks fs.FS = embeddedNetworks
+
+const networksDir = "networks"
+
+// NetworkNames returns the names of the networks in the registry, in alphabetical order.
+func NetworkNames() []string {
+	entries, err := fs.ReadDir(networks, networksDir)
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for _, entry := range entries {
+		if name := entry.Name(); !entry.IsDir() && strings.HasSuffix(name, ".json") {
+			names = append(names, strings.TrimSuffix(name, ".json"))
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LoadNetworkConfig loads the rollup config of the named network from the registry.
+func LoadNetworkConfig(name string) (*Config, error) {
+	data, err := fs.ReadFile(networks, path.Join(networksDir, name+".json"))
+	if err != nil {
+		return nil, fmt.Errorf("unknown network %q, known networks: %s", name, strings.Join(NetworkNames(), ", "))
+	}
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to decode rollup config of network %q: %w", name, err)
+	}
+	if err := cfg.Check(); err != nil {
+		return nil, fmt.Errorf("invalid rollup config of network %q: %w", name, err)
+	}
+	return &cfg, nil
+}
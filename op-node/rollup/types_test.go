@@ -2,9 +2,12 @@ package rollup
 
 import (
 	"encoding/json"
+	"io/fs"
 	"math/big"
 	"math/rand"
+	"strings"
 	"testing"
+	"testing/fstest"
 	"time"
 
 	"github.com/stretchr/testify/assert"
@@ -46,3 +49,58 @@ func TestConfigJSON(t *testing.T) {
 	assert.NoError(t, json.Unmarshal(data, &roundTripped))
 	assert.Equal(t, &roundTripped, config)
 }
+
+func TestConfigDiff(t *testing.T) {
+	a := randConfig()
+	b := *a
+	diff, err := a.Diff(&b)
+	assert.NoError(t, err)
+	assert.Empty(t, diff)
+
+	b.Genesis.L2.Number += 1
+	b.BatchSenderAddress = common.Address{0x01}
+	b.L1ChainID = new(big.Int).Lsh(big.NewInt(1), 64)
+	diff, err = a.Diff(&b)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"batch_sender_address: " + strings.ToLower(a.BatchSenderAddress.Hex()) + " != 0x0100000000000000000000000000000000000000",
+		"genesis.l2.number: 1337 != 1338",
+		"l1_chain_id: 900 != 18446744073709551616",
+	}, diff)
+}
+
+func TestNetworkRegistry(t *testing.T) {
+	cfg := randConfig()
+	cfg.ChannelTimeout = 10
+	cfg.L2ChainID = big.NewInt(901)
+	cfg.P2PSequencerAddress = common.Address{0x02}
+	cfg.DepositContractAddress = common.Address{0x03}
+	data, err := json.Marshal(cfg)
+	assert.NoError(t, err)
+
+	defer func(prev fs.FS) { networks = prev }(networks)
+	networks = fstest.MapFS{
+		"networks/README.md":    {Data: []byte("registry")},
+		"networks/testnet.json": {Data: data},
+		"networks/broken.json":  {Data: []byte("{}")},
+	}
+	assert.Equal(t, []string{"broken", "testnet"}, NetworkNames())
+
+	loaded, err := LoadNetworkConfig("testnet")
+	assert.NoError(t, err)
+	assert.Equal(t, cfg, loaded)
+
+	_, err = LoadNetworkConfig("broken")
+	assert.ErrorContains(t, err, "invalid rollup config")
+	_, err = LoadNetworkConfig("unknown")
+	assert.ErrorContains(t, err, "known networks: broken, testnet")
+}
+
+func TestEmbeddedNetworks(t *testing.T) {
+	names := NetworkNames()
+	assert.Contains(t, names, "devnet")
+	for _, name := range names {
+		_, err := LoadNetworkConfig(name)
+		assert.NoError(t, err, "network %s", name)
+	}
+}
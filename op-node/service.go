@@ -10,6 +10,7 @@ import (
 
 	"github.com/urfave/cli"
 
+	"github.com/ethereum-optimism/optimism/op-node/conductor"
 	"github.com/ethereum-optimism/optimism/op-node/flags"
 	"github.com/ethereum-optimism/optimism/op-node/node"
 	"github.com/ethereum-optimism/optimism/op-node/p2p"
@@ -41,6 +42,11 @@ func NewConfig(ctx *cli.Context, log log.Logger) (*node.Config, error) {
 		return nil, fmt.Errorf("failed to load p2p signer: %w", err)
 	}
 
+	p2pAuthorizedSigners, err := p2p.LoadAuthorizedSigners(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load authorized p2p signers: %w", err)
+	}
+
 	p2pConfig, err := p2p.NewConfig(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load p2p config: %w", err)
@@ -56,11 +62,17 @@ func NewConfig(ctx *cli.Context, log log.Logger) (*node.Config, error) {
 		return nil, fmt.Errorf("failed to load l2 endpoints info: %w", err)
 	}
 
+	conductorConfig, err := NewConductorConfig(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load sequencer raft config: %w", err)
+	}
+
 	cfg := &node.Config{
-		L1:     l1Endpoint,
-		L2:     l2Endpoint,
-		Rollup: *rollupConfig,
-		Driver: *driverConfig,
+		L1:              l1Endpoint,
+		L2:              l2Endpoint,
+		Rollup:          *rollupConfig,
+		RollupCheckPeer: ctx.GlobalString(flags.RollupCheckPeer.Name),
+		Driver:          *driverConfig,
 		RPC: node.RPCConfig{
 			ListenAddr:  ctx.GlobalString(flags.RPCListenAddr.Name),
 			ListenPort:  ctx.GlobalInt(flags.RPCListenPort.Name),
@@ -76,9 +88,18 @@ func NewConfig(ctx *cli.Context, log log.Logger) (*node.Config, error) {
 			ListenAddr: ctx.GlobalString(flags.PprofAddrFlag.Name),
 			ListenPort: ctx.GlobalString(flags.PprofPortFlag.Name),
 		},
-		P2P:                 p2pConfig,
-		P2PSigner:           p2pSignerSetup,
-		L1EpochPollInterval: ctx.GlobalDuration(flags.L1EpochPollIntervalFlag.Name),
+		OTel: node.OTelConfig{
+			Endpoint:    ctx.GlobalString(flags.OTelEndpointFlag.Name),
+			Insecure:    ctx.GlobalBool(flags.OTelInsecureFlag.Name),
+			SampleRatio: ctx.GlobalFloat64(flags.OTelSampleRatioFlag.Name),
+		},
+		P2P:                  p2pConfig,
+		P2PSigner:            p2pSignerSetup,
+		P2PAuthorizedSigners: p2pAuthorizedSigners,
+		Conductor:            conductorConfig,
+		L1EpochPollInterval:  ctx.GlobalDuration(flags.L1EpochPollIntervalFlag.Name),
+		L1DiskCachePath:      ctx.GlobalString(flags.L1DiskCachePathFlag.Name),
+		L1DiskCacheSize:      ctx.GlobalUint64(flags.L1DiskCacheSizeFlag.Name) * 1024 * 1024,
 		Heartbeat: node.HeartbeatConfig{
 			Enabled: ctx.GlobalBool(flags.HeartbeatEnabledFlag.Name),
 			Moniker: ctx.GlobalString(flags.HeartbeatMonikerFlag.Name),
@@ -93,8 +114,31 @@ func NewConfig(ctx *cli.Context, log log.Logger) (*node.Config, error) {
 
 func NewL1EndpointConfig(ctx *cli.Context) (*node.L1EndpointConfig, error) {
 	return &node.L1EndpointConfig{
-		L1NodeAddr: ctx.GlobalString(flags.L1NodeAddr.Name),
-		L1TrustRPC: ctx.GlobalBool(flags.L1TrustRPC.Name),
+		L1NodeAddr:      ctx.GlobalString(flags.L1NodeAddr.Name),
+		L1TrustRPC:      ctx.GlobalBool(flags.L1TrustRPC.Name),
+		L1FallbackAddrs: ctx.GlobalStringSlice(flags.L1FallbackAddrs.Name),
+		L1MaxHeadLag:    ctx.GlobalUint64(flags.L1MaxHeadLag.Name),
+		L1CrossCheck:    ctx.GlobalBool(flags.L1CrossCheck.Name),
+	}, nil
+}
+
+// NewConductorConfig creates the sequencer Raft cluster config, or nil if sequencer high-availability is disabled.
+func NewConductorConfig(ctx *cli.Context) (*conductor.Config, error) {
+	serverID := ctx.GlobalString(flags.SequencerRaftServerID.Name)
+	if serverID == "" {
+		return nil, nil
+	}
+	peers, err := conductor.ParsePeers(ctx.GlobalStringSlice(flags.SequencerRaftPeers.Name))
+	if err != nil {
+		return nil, err
+	}
+	return &conductor.Config{
+		ServerID:   serverID,
+		ListenAddr: ctx.GlobalString(flags.SequencerRaftListenAddr.Name),
+		Advertise:  ctx.GlobalString(flags.SequencerRaftAdvertiseAddr.Name),
+		Peers:      peers,
+		Bootstrap:  ctx.GlobalBool(flags.SequencerRaftBootstrap.Name),
+		DataDir:    ctx.GlobalString(flags.SequencerRaftDataDir.Name),
 	}, nil
 }
 
@@ -130,13 +174,17 @@ func NewL2EndpointConfig(ctx *cli.Context, log log.Logger) (*node.L2EndpointConf
 
 func NewDriverConfig(ctx *cli.Context) (*driver.Config, error) {
 	return &driver.Config{
-		VerifierConfDepth:  ctx.GlobalUint64(flags.VerifierL1Confs.Name),
-		SequencerConfDepth: ctx.GlobalUint64(flags.SequencerL1Confs.Name),
-		SequencerEnabled:   ctx.GlobalBool(flags.SequencerEnabledFlag.Name),
+		VerifierConfDepth:       ctx.GlobalUint64(flags.VerifierL1Confs.Name),
+		SequencerConfDepth:      ctx.GlobalUint64(flags.SequencerL1Confs.Name),
+		SequencerEnabled:        ctx.GlobalBool(flags.SequencerEnabledFlag.Name),
+		SequencerFallbackMargin: ctx.GlobalUint64(flags.SequencerFallbackMargin.Name),
 	}, nil
 }
 
 func NewRollupConfig(ctx *cli.Context) (*rollup.Config, error) {
+	if network := ctx.GlobalString(flags.Network.Name); network != "" {
+		return rollup.LoadNetworkConfig(network)
+	}
 	rollupConfigPath := ctx.GlobalString(flags.RollupConfig.Name)
 	file, err := os.Open(rollupConfigPath)
 	if err != nil {
@@ -0,0 +1,191 @@
+package caching
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/ethdb/leveldb"
+)
+
+var (
+	// data entries are stored under dataPrefix ++ key
+	dataPrefix = []byte("d")
+	// index entries are stored under indexPrefix ++ uint64 big-endian sequence number,
+	// with uint64 big-endian entry size ++ key as value, to evict the oldest entries first.
+	indexPrefix = []byte("i")
+)
+
+type diskEntry struct {
+	seq  uint64
+	size uint64
+	key  []byte
+}
+
+// DiskCache is a persistent key-value cache, bounded by the total size of the cached values.
+// Entries are evicted in the order they were added, oldest first.
+// Contents are not trusted by the cache: users should verify values they get from it.
+type DiskCache struct {
+	m     Metrics
+	label string
+	db    ethdb.KeyValueStore
+
+	mu      sync.Mutex
+	maxSize uint64
+	size    uint64
+	nextSeq uint64
+	// entries in order of insertion
+	entries []diskEntry
+}
+
+// OpenDiskCache opens (or creates) a disk cache in the given directory. See NewDiskCache.
+func OpenDiskCache(m Metrics, label string, dir string, maxSize uint64) (*DiskCache, error) {
+	db, err := leveldb.New(dir, 16, 16, "", false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open disk cache db at %q: %w", dir, err)
+	}
+	c, err := NewDiskCache(m, label, db, maxSize)
+	if err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// NewDiskCache creates a disk cache with the given metrics on top of a key-value store,
+// labeling the cache adds/gets, and restoring the entries that are already in the store.
+// Metrics are optional: no metrics will be tracked if m == nil.
+func NewDiskCache(m Metrics, label string, db ethdb.KeyValueStore, maxSize uint64) (*DiskCache, error) {
+	c := &DiskCache{
+		m:       m,
+		label:   label,
+		db:      db,
+		maxSize: maxSize,
+	}
+	it := db.NewIterator(indexPrefix, nil)
+	defer it.Release()
+	for it.Next() {
+		k, v := it.Key(), it.Value()
+		if len(k) != len(indexPrefix)+8 || len(v) < 8 {
+			return nil, fmt.Errorf("invalid disk cache index entry %x: %x", k, v)
+		}
+		e := diskEntry{
+			seq:  binary.BigEndian.Uint64(k[len(indexPrefix):]),
+			size: binary.BigEndian.Uint64(v[:8]),
+			key:  append([]byte(nil), v[8:]...),
+		}
+		c.entries = append(c.entries, e)
+		c.size += e.size
+		c.nextSeq = e.seq + 1
+	}
+	if err := it.Error(); err != nil {
+		return nil, fmt.Errorf("failed to read disk cache index: %w", err)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.evict(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *DiskCache) Get(key []byte) (value []byte, ok bool) {
+	value, err := c.db.Get(dataKey(key))
+	ok = err == nil
+	if c.m != nil {
+		c.m.CacheGet(c.label, ok)
+	}
+	return value, ok
+}
+
+// Add stores the value under the given key, unless the key is already present.
+// Older entries are evicted as necessary to stay within the size bound.
+func (c *DiskCache) Add(key, value []byte) (evicted bool, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	k := dataKey(key)
+	if ok, err := c.db.Has(k); err != nil {
+		return false, err
+	} else if ok {
+		return false, nil
+	}
+	e := diskEntry{seq: c.nextSeq, size: uint64(len(value)), key: append([]byte(nil), key...)}
+	batch := c.db.NewBatch()
+	if err := batch.Put(k, value); err != nil {
+		return false, err
+	}
+	if err := batch.Put(indexKey(e.seq), indexValue(e)); err != nil {
+		return false, err
+	}
+	if err := batch.Write(); err != nil {
+		return false, fmt.Errorf("failed to write disk cache entry: %w", err)
+	}
+	c.nextSeq += 1
+	c.entries = append(c.entries, e)
+	c.size += e.size
+	prevLen := len(c.entries)
+	if err := c.evict(); err != nil {
+		return false, err
+	}
+	evicted = len(c.entries) < prevLen
+	if c.m != nil {
+		c.m.CacheAdd(c.label, len(c.entries), evicted)
+	}
+	return evicted, nil
+}
+
+// evict removes the oldest entries until the cache is within its size bound. c.mu must be held.
+func (c *DiskCache) evict() error {
+	if c.size <= c.maxSize {
+		return nil
+	}
+	batch := c.db.NewBatch()
+	i := 0
+	size := c.size
+	for ; i < len(c.entries) && size > c.maxSize; i++ {
+		e := c.entries[i]
+		if err := batch.Delete(dataKey(e.key)); err != nil {
+			return err
+		}
+		if err := batch.Delete(indexKey(e.seq)); err != nil {
+			return err
+		}
+		size -= e.size
+	}
+	if err := batch.Write(); err != nil {
+		return fmt.Errorf("failed to evict disk cache entries: %w", err)
+	}
+	c.entries = c.entries[i:]
+	c.size = size
+	return nil
+}
+
+// Size returns the total size of the cached values, in bytes.
+func (c *DiskCache) Size() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.size
+}
+
+func (c *DiskCache) Close() error {
+	return c.db.Close()
+}
+
+func dataKey(key []byte) []byte {
+	out := make([]byte, 0, len(dataPrefix)+len(key))
+	return append(append(out, dataPrefix...), key...)
+}
+
+func indexKey(seq uint64) []byte {
+	out := make([]byte, len(indexPrefix)+8)
+	copy(out, indexPrefix)
+	binary.BigEndian.PutUint64(out[len(indexPrefix):], seq)
+	return out
+}
+
+func indexValue(e diskEntry) []byte {
+	out := make([]byte, 8, 8+len(e.key))
+	binary.BigEndian.PutUint64(out, e.size)
+	return append(out, e.key...)
+}
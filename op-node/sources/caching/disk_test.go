@@ -0,0 +1,69 @@
+package caching
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
+)
+
+func TestDiskCacheEviction(t *testing.T) {
+	db := memorydb.New()
+	c, err := NewDiskCache(nil, "test", db, 10)
+	require.NoError(t, err)
+
+	evicted, err := c.Add([]byte("a"), []byte("1234"))
+	require.NoError(t, err)
+	require.False(t, evicted)
+	evicted, err = c.Add([]byte("b"), []byte("5678"))
+	require.NoError(t, err)
+	require.False(t, evicted)
+	require.Equal(t, uint64(8), c.Size())
+
+	// existing keys are not overwritten
+	evicted, err = c.Add([]byte("a"), []byte("abcd"))
+	require.NoError(t, err)
+	require.False(t, evicted)
+	v, ok := c.Get([]byte("a"))
+	require.True(t, ok)
+	require.Equal(t, []byte("1234"), v)
+
+	// the oldest entry is evicted to make room
+	evicted, err = c.Add([]byte("c"), []byte("90"))
+	require.NoError(t, err)
+	require.False(t, evicted)
+	evicted, err = c.Add([]byte("d"), []byte("x"))
+	require.NoError(t, err)
+	require.True(t, evicted)
+	_, ok = c.Get([]byte("a"))
+	require.False(t, ok)
+	v, ok = c.Get([]byte("b"))
+	require.True(t, ok)
+	require.Equal(t, []byte("5678"), v)
+	require.Equal(t, uint64(7), c.Size())
+}
+
+func TestDiskCacheRestore(t *testing.T) {
+	db := memorydb.New()
+	c, err := NewDiskCache(nil, "test", db, 10)
+	require.NoError(t, err)
+	for _, k := range []string{"a", "b", "c"} {
+		_, err := c.Add([]byte(k), []byte("123"))
+		require.NoError(t, err)
+	}
+
+	// entries are restored from the db, and evicted in the same order when the bound is lowered
+	c, err = NewDiskCache(nil, "test", db, 6)
+	require.NoError(t, err)
+	require.Equal(t, uint64(6), c.Size())
+	_, ok := c.Get([]byte("a"))
+	require.False(t, ok)
+	_, err = c.Add([]byte("d"), []byte("456"))
+	require.NoError(t, err)
+	_, ok = c.Get([]byte("b"))
+	require.False(t, ok)
+	v, ok := c.Get([]byte("c"))
+	require.True(t, ok)
+	require.Equal(t, []byte("123"), v)
+}
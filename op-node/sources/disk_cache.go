@@ -0,0 +1,118 @@
+package sources
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/ethereum-optimism/optimism/op-node/eth"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Keys of the disk cache are a prefix byte followed by the block hash.
+const (
+	diskBlockPrefix    byte = 'b'
+	diskReceiptsPrefix byte = 'r'
+)
+
+func diskKey(prefix byte, blockHash common.Hash) []byte {
+	return append([]byte{prefix}, blockHash[:]...)
+}
+
+// diskBlock retrieves a block from the disk cache, if the cache is enabled.
+// Cached blocks are never trusted: the block hash and transactions root are always verified.
+func (s *EthClient) diskBlock(hash common.Hash) (*HeaderInfo, types.Transactions, bool) {
+	if s.diskCache == nil {
+		return nil, nil, false
+	}
+	data, ok := s.diskCache.Get(diskKey(diskBlockPrefix, hash))
+	if !ok {
+		return nil, nil, false
+	}
+	var block rpcBlock
+	if err := json.Unmarshal(data, &block); err != nil {
+		s.log.Warn("failed to decode block from disk cache", "hash", hash, "err", err)
+		return nil, nil, false
+	}
+	info, txs, err := block.Info(false, s.mustBePostMerge)
+	if err != nil || info.Hash() != hash {
+		s.log.Warn("ignoring invalid block from disk cache", "hash", hash, "err", err)
+		return nil, nil, false
+	}
+	s.headersCache.Add(hash, info)
+	s.transactionsCache.Add(hash, txs)
+	return info, txs, true
+}
+
+// storeDiskBlock writes the block to the disk cache, if the cache is enabled.
+func (s *EthClient) storeDiskBlock(block *rpcBlock) {
+	if s.diskCache == nil {
+		return
+	}
+	data, err := json.Marshal(block)
+	if err != nil {
+		s.log.Warn("failed to encode block for disk cache", "hash", block.Hash, "err", err)
+		return
+	}
+	if _, err := s.diskCache.Add(diskKey(diskBlockPrefix, block.Hash), data); err != nil {
+		s.log.Warn("failed to write block to disk cache", "hash", block.Hash, "err", err)
+	}
+}
+
+// diskReceipts retrieves the receipts of a block from the disk cache, if the cache is enabled.
+// Cached receipts are never trusted: they are always verified against the receipts root of the block.
+func (s *EthClient) diskReceipts(info eth.BlockInfo, txHashes []common.Hash) (eth.ReceiptsFetcher, bool) {
+	if s.diskCache == nil {
+		return nil, false
+	}
+	data, ok := s.diskCache.Get(diskKey(diskReceiptsPrefix, info.Hash()))
+	if !ok {
+		return nil, false
+	}
+	var receipts []*types.Receipt
+	if err := json.Unmarshal(data, &receipts); err != nil {
+		s.log.Warn("failed to decode receipts from disk cache", "hash", info.Hash(), "err", err)
+		return nil, false
+	}
+	verified, err := makeReceiptsFn(info.ID(), info.ReceiptHash())(txHashes, receipts)
+	if err != nil {
+		s.log.Warn("ignoring invalid receipts from disk cache", "hash", info.Hash(), "err", err)
+		return nil, false
+	}
+	return eth.FetchedReceipts(verified), true
+}
+
+// persistReceipts wraps the receipts fetcher to write the receipts to the disk cache once they are all fetched,
+// if the cache is enabled.
+func (s *EthClient) persistReceipts(blockHash common.Hash, r eth.ReceiptsFetcher) eth.ReceiptsFetcher {
+	if s.diskCache == nil {
+		return r
+	}
+	return &diskReceiptsFetcher{ReceiptsFetcher: r, store: func(receipts types.Receipts) {
+		data, err := json.Marshal(receipts)
+		if err != nil {
+			s.log.Warn("failed to encode receipts for disk cache", "hash", blockHash, "err", err)
+			return
+		}
+		if _, err := s.diskCache.Add(diskKey(diskReceiptsPrefix, blockHash), data); err != nil {
+			s.log.Warn("failed to write receipts to disk cache", "hash", blockHash, "err", err)
+		}
+	}}
+}
+
+// diskReceiptsFetcher stores the result of the receipts fetcher, once it is available and verified.
+type diskReceiptsFetcher struct {
+	eth.ReceiptsFetcher
+	store func(receipts types.Receipts)
+	once  sync.Once
+}
+
+func (f *diskReceiptsFetcher) Result() (types.Receipts, error) {
+	receipts, err := f.ReceiptsFetcher.Result()
+	if err == nil {
+		f.once.Do(func() {
+			f.store(receipts)
+		})
+	}
+	return receipts, err
+}
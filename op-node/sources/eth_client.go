@@ -95,6 +95,9 @@ type EthClient struct {
 	// cache payloads by hash
 	// common.Hash -> *eth.ExecutionPayload
 	payloadsCache *caching.LRUCache
+
+	// optional persistent cache of blocks and receipts by block hash, nil if disabled
+	diskCache *caching.DiskCache
 }
 
 // NewEthClient wraps a RPC with bindings to fetch ethereum data,
@@ -155,6 +158,7 @@ func (s *EthClient) blockCall(ctx context.Context, method string, id interface{}
 	}
 	s.headersCache.Add(info.Hash(), info)
 	s.transactionsCache.Add(info.Hash(), txs)
+	s.storeDiskBlock(block)
 	return info, txs, nil
 }
 
@@ -179,6 +183,9 @@ func (s *EthClient) InfoByHash(ctx context.Context, hash common.Hash) (eth.Block
 	if header, ok := s.headersCache.Get(hash); ok {
 		return header.(*HeaderInfo), nil
 	}
+	if info, _, ok := s.diskBlock(hash); ok {
+		return info, nil
+	}
 	return s.headerCall(ctx, "eth_getBlockByHash", hash)
 }
 
@@ -203,6 +210,9 @@ func (s *EthClient) InfoAndTxsByHash(ctx context.Context, hash common.Hash) (eth
 			return header.(*HeaderInfo), txs.(types.Transactions), nil
 		}
 	}
+	if info, txs, ok := s.diskBlock(hash); ok {
+		return info, txs, nil
+	}
 	return s.blockCall(ctx, "eth_getBlockByHash", hash)
 }
 
@@ -243,7 +253,11 @@ func (s *EthClient) Fetch(ctx context.Context, blockHash common.Hash) (eth.Block
 	for i := 0; i < len(txs); i++ {
 		txHashes[i] = txs[i].Hash()
 	}
-	r := NewReceiptsFetcher(info.ID(), info.ReceiptHash(), txHashes, s.client.BatchCallContext, s.maxBatchSize)
+	r, ok := s.diskReceipts(info, txHashes)
+	if !ok {
+		r = s.persistReceipts(blockHash,
+			NewReceiptsFetcher(info.ID(), info.ReceiptHash(), txHashes, s.client.BatchCallContext, s.maxBatchSize))
+	}
 	s.receiptsCache.Add(blockHash, r)
 	return info, txs, r, nil
 }
@@ -306,4 +320,9 @@ func (s *EthClient) GetProof(ctx context.Context, address common.Address, blockT
 
 func (s *EthClient) Close() {
 	s.client.Close()
+	if s.diskCache != nil {
+		if err := s.diskCache.Close(); err != nil {
+			s.log.Error("failed to close disk cache", "err", err)
+		}
+	}
 }
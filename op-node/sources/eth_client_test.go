@@ -137,3 +137,57 @@ func TestEthClient_InfoByNumber(t *testing.T) {
 	require.Equal(t, info, expectedInfo)
 	m.Mock.AssertExpectations(t)
 }
+
+func TestL1Client_DiskCache(t *testing.T) {
+	hdr, _ := randHeader()
+	hdr.TxHash = types.EmptyRootHash
+	rhdr := rpcHeader{
+		ParentHash:  hdr.ParentHash,
+		UncleHash:   hdr.UncleHash,
+		Root:        hdr.Root,
+		TxHash:      hdr.TxHash,
+		ReceiptHash: hdr.ReceiptHash,
+		Difficulty:  *(*hexutil.Big)(hdr.Difficulty),
+		Number:      hexutil.Uint64(hdr.Number.Uint64()),
+		Time:        hexutil.Uint64(hdr.Time),
+		Extra:       hdr.Extra,
+		MixDigest:   hdr.MixDigest,
+		BaseFee:     (*hexutil.Big)(hdr.BaseFee),
+		Hash:        hdr.Hash(),
+	}
+	block := &rpcBlock{rpcHeader: rhdr, Transactions: types.Transactions{}}
+	expectedInfo, _, err := block.Info(false, false)
+	require.NoError(t, err)
+
+	cfg := L1ClientDefaultConfig(&rollup.Config{SeqWindowSize: 10}, false)
+	cfg.DiskCachePath = t.TempDir()
+	cfg.DiskCacheSize = 1024 * 1024
+	ctx := context.Background()
+
+	m := new(mockRPC)
+	m.On("CallContext", ctx, new(*rpcBlock),
+		"eth_getBlockByHash", []interface{}{block.Hash, true}).Run(func(args mock.Arguments) {
+		*args[1].(**rpcBlock) = block
+	}).Return([]error{nil})
+	m.On("Close").Return()
+	s, err := NewL1Client(m, nil, nil, cfg)
+	require.NoError(t, err)
+	info, txs, err := s.InfoAndTxsByHash(ctx, block.Hash)
+	require.NoError(t, err)
+	require.Equal(t, expectedInfo, info)
+	require.Empty(t, txs)
+	s.Close()
+	m.Mock.AssertExpectations(t)
+
+	// After a restart, without expecting any calls from the mock, the disk cache will return the block
+	m = new(mockRPC)
+	m.On("Close").Return()
+	s, err = NewL1Client(m, nil, nil, cfg)
+	require.NoError(t, err)
+	info, txs, err = s.InfoAndTxsByHash(ctx, block.Hash)
+	require.NoError(t, err)
+	require.Equal(t, expectedInfo, info)
+	require.Empty(t, txs)
+	s.Close()
+	m.Mock.AssertExpectations(t)
+}
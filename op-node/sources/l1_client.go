@@ -18,6 +18,12 @@ type L1ClientConfig struct {
 	EthClientConfig
 
 	L1BlockRefsCacheSize int
+
+	// DiskCachePath is the directory of the persistent cache of L1 blocks and receipts.
+	// The disk cache is disabled if empty.
+	DiskCachePath string
+	// DiskCacheSize is the maximum total size of the blocks and receipts in the disk cache, in bytes.
+	DiskCacheSize uint64
 }
 
 func L1ClientDefaultConfig(config *rollup.Config, trustRPC bool) *L1ClientConfig {
@@ -59,6 +65,12 @@ func NewL1Client(client client.RPC, log log.Logger, metrics caching.Metrics, con
 	if err != nil {
 		return nil, err
 	}
+	if config.DiskCachePath != "" {
+		ethClient.diskCache, err = caching.OpenDiskCache(metrics, "disk", config.DiskCachePath, config.DiskCacheSize)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	return &L1Client{
 		EthClient:        ethClient,
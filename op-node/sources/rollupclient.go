@@ -6,6 +6,8 @@ import (
 
 	"github.com/ethereum-optimism/optimism/op-node/eth"
 	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/rpc"
 )
@@ -36,8 +38,39 @@ func (r *RollupClient) RollupConfig(ctx context.Context) (*rollup.Config, error)
 	return output, err
 }
 
+func (r *RollupClient) WithdrawalProof(ctx context.Context, txHash common.Hash) (*eth.WithdrawalProof, error) {
+	var output *eth.WithdrawalProof
+	err := r.rpc.CallContext(ctx, &output, "optimism_withdrawalProof", txHash)
+	return output, err
+}
+
 func (r *RollupClient) Version(ctx context.Context) (string, error) {
 	var output string
 	err := r.rpc.CallContext(ctx, &output, "optimism_version")
 	return output, err
 }
+
+// SubscribeUnsafeHead subscribes to the unsafe L2 head of the rollup node. This requires a websocket connection.
+func (r *RollupClient) SubscribeUnsafeHead(ctx context.Context, ch chan<- eth.L2BlockRef) (ethereum.Subscription, error) {
+	return r.rpc.Subscribe(ctx, "optimism", ch, "unsafeHead")
+}
+
+// SubscribeSafeHead subscribes to the safe L2 head of the rollup node. This requires a websocket connection.
+func (r *RollupClient) SubscribeSafeHead(ctx context.Context, ch chan<- eth.L2BlockRef) (ethereum.Subscription, error) {
+	return r.rpc.Subscribe(ctx, "optimism", ch, "safeHead")
+}
+
+// SubscribeFinalizedHead subscribes to the finalized L2 head of the rollup node. This requires a websocket connection.
+func (r *RollupClient) SubscribeFinalizedHead(ctx context.Context, ch chan<- eth.L2BlockRef) (ethereum.Subscription, error) {
+	return r.rpc.Subscribe(ctx, "optimism", ch, "finalizedHead")
+}
+
+// SubscribeL1Head subscribes to the L1 head as seen by the rollup node. This requires a websocket connection.
+func (r *RollupClient) SubscribeL1Head(ctx context.Context, ch chan<- eth.L1BlockRef) (ethereum.Subscription, error) {
+	return r.rpc.Subscribe(ctx, "optimism", ch, "l1Head")
+}
+
+// SubscribeDerivationReset subscribes to derivation pipeline resets of the rollup node. This requires a websocket connection.
+func (r *RollupClient) SubscribeDerivationReset(ctx context.Context, ch chan<- eth.DerivationResetEvent) (ethereum.Subscription, error) {
+	return r.rpc.Subscribe(ctx, "optimism", ch, "derivationReset")
+}
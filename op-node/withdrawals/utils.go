@@ -120,6 +120,22 @@ loop:
 
 }
 
+// OutputBlockNumber returns the L2 block number of the earliest output checkpoint of the L2OutputOracle
+// that includes the given L2 block. Checkpoints are every submissionInterval blocks, from the startingBlockNumber.
+func OutputBlockNumber(startingBlockNumber, submissionInterval, l2BlockNumber uint64) (uint64, error) {
+	if submissionInterval == 0 {
+		return 0, errors.New("submission interval cannot be 0")
+	}
+	if l2BlockNumber < startingBlockNumber {
+		return 0, fmt.Errorf("L2 block %d is before the starting block %d of the output oracle", l2BlockNumber, startingBlockNumber)
+	}
+	offset := (l2BlockNumber - startingBlockNumber) % submissionInterval
+	if offset == 0 {
+		return l2BlockNumber, nil
+	}
+	return l2BlockNumber + (submissionInterval - offset), nil
+}
+
 type ProofClient interface {
 	TransactionReceipt(context.Context, common.Hash) (*types.Receipt, error)
 	GetProof(context.Context, common.Address, []string, *big.Int) (*gethclient.AccountResult, error)
@@ -0,0 +1,35 @@
+package withdrawals
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutputBlockNumber(t *testing.T) {
+	testCases := []struct {
+		name     string
+		start    uint64
+		interval uint64
+		block    uint64
+		expected uint64
+	}{
+		{"starting block", 10, 5, 10, 10},
+		{"after starting block", 10, 5, 11, 15},
+		{"on checkpoint", 10, 5, 20, 20},
+		{"before checkpoint", 10, 5, 19, 20},
+		{"interval of one", 0, 1, 7, 7},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := OutputBlockNumber(tc.start, tc.interval, tc.block)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, out)
+		})
+	}
+
+	_, err := OutputBlockNumber(10, 5, 9)
+	require.Error(t, err, "block before the starting block is not included in any output")
+	_, err = OutputBlockNumber(10, 0, 11)
+	require.Error(t, err, "zero interval is invalid")
+}
@@ -1,15 +1,18 @@
 package op_proposer
 
 import (
+	"errors"
 	"time"
 
 	"github.com/urfave/cli"
 
+	"github.com/ethereum-optimism/optimism/op-proposer/drivers/l2output"
 	"github.com/ethereum-optimism/optimism/op-proposer/flags"
 	oplog "github.com/ethereum-optimism/optimism/op-service/log"
 	opmetrics "github.com/ethereum-optimism/optimism/op-service/metrics"
 	oppprof "github.com/ethereum-optimism/optimism/op-service/pprof"
 	oprpc "github.com/ethereum-optimism/optimism/op-service/rpc"
+	"github.com/ethereum-optimism/optimism/op-service/txmgr"
 )
 
 type Config struct {
@@ -60,6 +63,21 @@ type Config struct {
 
 	/* Optional Params */
 
+	// OutputSource is the L2 head of the rollup node up to which outputs are
+	// proposed, either safe or finalized.
+	OutputSource string
+
+	// MaxPendingOutputs is the maximum number of output proposals that are
+	// submitted at consecutive nonces at once, when the proposer is behind.
+	MaxPendingOutputs uint64
+
+	// MaxL1Lag is the number of L1 blocks the rollup node may lag behind the
+	// L1 head before it is considered to be syncing. 0 disables the check.
+	MaxL1Lag uint64
+
+	// TxMgrConfig configures the journal and the fee limits of the tx manager.
+	TxMgrConfig txmgr.CLIConfig
+
 	LogConfig oplog.CLIConfig
 
 	MetricsConfig opmetrics.CLIConfig
@@ -80,6 +98,15 @@ func (c Config) Check() error {
 	if err := c.PprofConfig.Check(); err != nil {
 		return err
 	}
+	if err := c.TxMgrConfig.Check(); err != nil {
+		return err
+	}
+	if err := l2output.OutputSource(c.OutputSource).Check(); err != nil {
+		return err
+	}
+	if c.MaxPendingOutputs == 0 {
+		return errors.New("max pending outputs must be at least 1")
+	}
 	return nil
 }
 
@@ -98,6 +125,10 @@ func NewConfig(ctx *cli.Context) Config {
 		Mnemonic:                  ctx.GlobalString(flags.MnemonicFlag.Name),
 		L2OutputHDPath:            ctx.GlobalString(flags.L2OutputHDPathFlag.Name),
 		PrivateKey:                ctx.GlobalString(flags.PrivateKeyFlag.Name),
+		OutputSource:              ctx.GlobalString(flags.OutputSourceFlag.Name),
+		MaxPendingOutputs:         ctx.GlobalUint64(flags.MaxPendingOutputsFlag.Name),
+		MaxL1Lag:                  ctx.GlobalUint64(flags.MaxL1LagFlag.Name),
+		TxMgrConfig:               txmgr.ReadCLIConfig(ctx),
 		RPCConfig:                 oprpc.ReadCLIConfig(ctx),
 		LogConfig:                 oplog.ReadCLIConfig(ctx),
 		MetricsConfig:             opmetrics.ReadCLIConfig(ctx),
@@ -34,3 +34,9 @@ type L1Client interface {
 	// hash. Note that the receipt is not available for pending transactions.
 	TransactionReceipt(context.Context, common.Hash) (*types.Receipt, error)
 }
+
+// BlockRange is a range of L2 blocks, of which the end is *exclusive*.
+type BlockRange struct {
+	Start *big.Int
+	End   *big.Int
+}
@@ -3,18 +3,21 @@ package l2output
 import (
 	"context"
 	"crypto/ecdsa"
+	"errors"
 	"fmt"
 	"math/big"
 	"strings"
+	"time"
 
 	"github.com/ethereum-optimism/optimism/op-node/sources"
+	"github.com/ethereum-optimism/optimism/op-proposer/drivers"
+	"github.com/ethereum-optimism/optimism/op-service/txmgr"
 
 	"github.com/ethereum-optimism/optimism/op-bindings/bindings"
 	"github.com/ethereum-optimism/optimism/op-node/eth"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/log"
@@ -23,6 +26,38 @@ import (
 var bigOne = big.NewInt(1)
 var supportedL2OutputVersion = eth.Bytes32{}
 
+var (
+	// ErrNodeSyncing is returned when the rollup node is still syncing,
+	// and its outputs may not be proposed yet.
+	ErrNodeSyncing = errors.New("rollup node is syncing")
+
+	// ErrNonCanonicalL1 is returned when the L1 block the rollup node derived up to
+	// is no longer part of the canonical L1 chain.
+	ErrNonCanonicalL1 = errors.New("rollup node L1 view is not canonical")
+)
+
+// OutputSource is the L2 head of the rollup node up to which outputs are proposed.
+type OutputSource string
+
+const (
+	// OutputSourceSafe proposes outputs up to the safe head, derived from L1 data that may still reorg.
+	OutputSourceSafe OutputSource = "safe"
+	// OutputSourceFinalized proposes outputs up to the finalized head, derived from finalized L1 data.
+	OutputSourceFinalized OutputSource = "finalized"
+)
+
+// OutputSources are the supported output sources.
+var OutputSources = []OutputSource{OutputSourceSafe, OutputSourceFinalized}
+
+func (s OutputSource) Check() error {
+	for _, source := range OutputSources {
+		if s == source {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown output source %q, expected one of %v", s, OutputSources)
+}
+
 type Config struct {
 	Log          log.Logger
 	Name         string
@@ -30,16 +65,22 @@ type Config struct {
 	L2Client     *ethclient.Client
 	RollupClient *sources.RollupClient
 	L2OOAddr     common.Address
-	ChainID      *big.Int
 	PrivKey      *ecdsa.PrivateKey
+
+	// OutputSource is the L2 head up to which outputs are proposed.
+	OutputSource OutputSource
+
+	// MaxL1Lag is the number of L1 blocks the derivation of the rollup node may
+	// lag behind the L1 head before it is considered to be syncing. 0 disables the check.
+	MaxL1Lag uint64
 }
 
 type Driver struct {
-	cfg             Config
-	l2ooContract    *bindings.L2OutputOracle
-	rawL2ooContract *bind.BoundContract
-	walletAddr      common.Address
-	l               log.Logger
+	cfg          Config
+	l2ooContract *bindings.L2OutputOracle
+	l2ooABI      abi.ABI
+	walletAddr   common.Address
+	l            log.Logger
 }
 
 func NewDriver(cfg Config) (*Driver, error) {
@@ -57,19 +98,15 @@ func NewDriver(cfg Config) (*Driver, error) {
 		return nil, err
 	}
 
-	rawL2ooContract := bind.NewBoundContract(
-		cfg.L2OOAddr, parsed, cfg.L1Client, cfg.L1Client, cfg.L1Client,
-	)
-
 	walletAddr := crypto.PubkeyToAddress(cfg.PrivKey.PublicKey)
 	log.Info("Configured driver", "wallet", walletAddr, "l2-output-contract", cfg.L2OOAddr)
 
 	return &Driver{
-		cfg:             cfg,
-		l2ooContract:    l2ooContract,
-		rawL2ooContract: rawL2ooContract,
-		walletAddr:      walletAddr,
-		l:               cfg.Log,
+		cfg:          cfg,
+		l2ooContract: l2ooContract,
+		l2ooABI:      parsed,
+		walletAddr:   walletAddr,
+		l:            cfg.Log,
 	}, nil
 }
 
@@ -83,11 +120,12 @@ func (d *Driver) WalletAddr() common.Address {
 	return d.walletAddr
 }
 
-// GetBlockRange returns the start and end L2 block heights that need to be
-// processed. Note that the end value is *exclusive*, therefore if the returned
-// values are identical nothing needs to be processed.
-func (d *Driver) GetBlockRange(
-	ctx context.Context) (*big.Int, *big.Int, error) {
+// GetBlockRanges returns the consecutive L2 block ranges that need to be
+// processed, one range per output, up to at most max ranges. Note that the end
+// values are *exclusive*. No ranges are returned if the submission interval
+// has not elapsed.
+func (d *Driver) GetBlockRanges(
+	ctx context.Context, max uint64) ([]drivers.BlockRange, error) {
 
 	name := d.cfg.Name
 
@@ -97,62 +135,116 @@ func (d *Driver) GetBlockRange(
 	}
 
 	// Determine the last committed L2 Block Number
-	start, err := d.l2ooContract.LatestBlockNumber(callOpts)
+	latestBlockNumber, err := d.l2ooContract.LatestBlockNumber(callOpts)
 	if err != nil {
 		d.l.Error(name+" unable to get latest block number", "err", err)
-		return nil, nil, err
+		return nil, err
 	}
-	start.Add(start, bigOne)
 
 	// Next determine the L2 block that we need to commit
 	nextBlockNumber, err := d.l2ooContract.NextBlockNumber(callOpts)
 	if err != nil {
 		d.l.Error(name+" unable to get next block number", "err", err)
-		return nil, nil, err
+		return nil, err
 	}
-	status, err := d.cfg.RollupClient.SyncStatus(ctx)
+	interval, err := d.l2ooContract.SUBMISSIONINTERVAL(callOpts)
 	if err != nil {
-		d.l.Error(name+" unable to get sync status", "err", err)
-		return nil, nil, err
+		d.l.Error(name+" unable to get submission interval", "err", err)
+		return nil, err
 	}
-	latestHeader, err := d.cfg.L2Client.HeaderByNumber(ctx, new(big.Int).SetUint64(status.SafeL2.Number))
+	status, err := d.syncStatus(ctx)
 	if err != nil {
-		d.l.Error(name+" unable to retrieve latest header", "err", err)
-		return nil, nil, err
+		d.l.Error(name+" unable to use rollup node", "err", err)
+		return nil, err
+	}
+	var currentBlockNumber *big.Int
+	switch d.cfg.OutputSource {
+	case OutputSourceFinalized:
+		currentBlockNumber = new(big.Int).SetUint64(status.FinalizedL2.Number)
+	default:
+		currentBlockNumber = new(big.Int).SetUint64(status.SafeL2.Number)
 	}
-	currentBlockNumber := big.NewInt(latestHeader.Number.Int64())
 
 	// If we do not have the new L2 Block number
 	if currentBlockNumber.Cmp(nextBlockNumber) < 0 {
 		d.l.Info(name+" submission interval has not elapsed",
-			"currentBlockNumber", currentBlockNumber, "nextBlockNumber", nextBlockNumber)
-		return start, start, nil
+			"currentBlockNumber", currentBlockNumber, "nextBlockNumber", nextBlockNumber,
+			"source", d.cfg.OutputSource)
+		return nil, nil
 	}
 
+	// Otherwise the submission interval has elapsed, possibly multiple times
+	// if the proposer fell behind. Propose an output per elapsed interval to
+	// catch up, and add one to each checkpoint block since end is exclusive.
+	ranges := outputRanges(latestBlockNumber, nextBlockNumber, interval, currentBlockNumber, max)
+
 	d.l.Info(name+" submission interval has elapsed",
-		"currentBlockNumber", currentBlockNumber, "nextBlockNumber", nextBlockNumber)
+		"currentBlockNumber", currentBlockNumber, "nextBlockNumber", nextBlockNumber,
+		"source", d.cfg.OutputSource, "outputs", len(ranges))
+
+	return ranges, nil
+}
+
+// outputRanges splits the blocks after latest up to current into ranges of one
+// submission interval each, starting with the range that ends at next.
+func outputRanges(latest, next, interval, current *big.Int, max uint64) []drivers.BlockRange {
+	var ranges []drivers.BlockRange
+	start := new(big.Int).Add(latest, bigOne)
+	for checkpoint := new(big.Int).Set(next); checkpoint.Cmp(current) <= 0 && uint64(len(ranges)) < max; checkpoint.Add(checkpoint, interval) {
+		end := new(big.Int).Add(checkpoint, bigOne)
+		ranges = append(ranges, drivers.BlockRange{Start: start, End: end})
+		start = end
+	}
+	return ranges
+}
+
+// syncStatus returns the sync status of the rollup node, if it is in sync with
+// the canonical L1 chain. ErrNodeSyncing is returned if the node is still syncing,
+// and ErrNonCanonicalL1 if the L1 block it derived up to has been reorged out.
+func (d *Driver) syncStatus(ctx context.Context) (*eth.SyncStatus, error) {
+	status, err := d.cfg.RollupClient.SyncStatus(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get sync status: %w", err)
+	}
+	if status.HeadL1 == (eth.L1BlockRef{}) || status.CurrentL1 == (eth.L1BlockRef{}) {
+		return nil, fmt.Errorf("%w: no L1 info yet", ErrNodeSyncing)
+	}
 
-	// Otherwise the submission interval has elapsed. Transform the next
-	// expected timestamp into its L2 block number, and add one since end is
-	// exclusive.
-	end := new(big.Int).Add(nextBlockNumber, bigOne)
+	l1Head, err := d.cfg.L1Client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get L1 head: %w", err)
+	}
+	if d.cfg.MaxL1Lag > 0 && status.CurrentL1.Number+d.cfg.MaxL1Lag < l1Head.Number.Uint64() {
+		return nil, fmt.Errorf("%w: derived up to L1 block %d, L1 head is %d",
+			ErrNodeSyncing, status.CurrentL1.Number, l1Head.Number)
+	}
 
-	return start, end, nil
+	l1Block, err := d.cfg.L1Client.HeaderByNumber(ctx, new(big.Int).SetUint64(status.CurrentL1.Number))
+	if err != nil {
+		return nil, fmt.Errorf("unable to get L1 block %d: %w", status.CurrentL1.Number, err)
+	}
+	if l1Block.Hash() != status.CurrentL1.Hash {
+		return nil, fmt.Errorf("%w: rollup node derived up to %s, canonical L1 block is %s",
+			ErrNonCanonicalL1, status.CurrentL1, l1Block.Hash())
+	}
+	return status, nil
 }
 
 // CraftTx transforms the L2 blocks between start and end into a transaction
-// using the given nonce.
-//
-// NOTE: This method SHOULD NOT publish the resulting transaction.
+// candidate, which is priced and published by the tx manager. The L1 view of
+// the rollup node is checked to still be canonical before the output is crafted.
 func (d *Driver) CraftTx(
 	ctx context.Context,
-	start, end, nonce *big.Int,
-) (*types.Transaction, error) {
+	start, end *big.Int,
+) (txmgr.TxCandidate, error) {
 
 	name := d.cfg.Name
 
-	d.l.Info(name+" crafting checkpoint tx", "start", start, "end", end,
-		"nonce", nonce)
+	d.l.Info(name+" crafting checkpoint tx", "start", start, "end", end)
+
+	if _, err := d.syncStatus(ctx); err != nil {
+		return txmgr.TxCandidate{}, err
+	}
 
 	// Fetch the final block in the range, as this is the only L2 output we need
 	// to submit.
@@ -160,70 +252,62 @@ func (d *Driver) CraftTx(
 
 	l2OutputRoot, err := d.outputRootAtBlock(ctx, nextCheckpointBlock)
 	if err != nil {
-		return nil, err
+		return txmgr.TxCandidate{}, err
 	}
 
 	numElements := new(big.Int).Sub(start, end).Uint64()
 	d.l.Info(name+" checkpoint constructed", "start", start, "end", end,
-		"nonce", nonce, "blocks_committed", numElements, "checkpoint_block", nextCheckpointBlock)
+		"blocks_committed", numElements, "checkpoint_block", nextCheckpointBlock)
 
 	l1Header, err := d.cfg.L1Client.HeaderByNumber(ctx, nil)
 	if err != nil {
-		return nil, fmt.Errorf("error resolving checkpoint block: %w", err)
+		return txmgr.TxCandidate{}, fmt.Errorf("error resolving checkpoint block: %w", err)
 	}
 
 	l2Header, err := d.cfg.L2Client.HeaderByNumber(ctx, nextCheckpointBlock)
 	if err != nil {
-		return nil, fmt.Errorf("error resolving checkpoint block: %w", err)
+		return txmgr.TxCandidate{}, fmt.Errorf("error resolving checkpoint block: %w", err)
 	}
 
 	if l2Header.Number.Cmp(nextCheckpointBlock) != 0 {
-		return nil, fmt.Errorf("invalid blockNumber: next blockNumber is %v, blockNumber of block is %v", nextCheckpointBlock, l2Header.Number)
+		return txmgr.TxCandidate{}, fmt.Errorf("invalid blockNumber: next blockNumber is %v, blockNumber of block is %v", nextCheckpointBlock, l2Header.Number)
 	}
 
-	opts, err := bind.NewKeyedTransactorWithChainID(
-		d.cfg.PrivKey, d.cfg.ChainID,
+	data, err := d.l2ooABI.Pack(
+		"proposeL2Output",
+		[32]byte(l2OutputRoot), nextCheckpointBlock, [32]byte(l1Header.Hash()), l1Header.Number,
 	)
 	if err != nil {
-		return nil, err
+		return txmgr.TxCandidate{}, err
 	}
-	opts.Context = ctx
-	opts.Nonce = nonce
-	opts.NoSend = true
 
-	return d.l2ooContract.ProposeL2Output(opts, l2OutputRoot, nextCheckpointBlock, l1Header.Hash(), l1Header.Number)
-}
-
-// UpdateGasPrice signs an otherwise identical txn to the one provided but with
-// updated gas prices sampled from the existing network conditions.
-//
-// NOTE: Thie method SHOULD NOT publish the resulting transaction.
-func (d *Driver) UpdateGasPrice(
-	ctx context.Context,
-	tx *types.Transaction,
-) (*types.Transaction, error) {
-
-	opts, err := bind.NewKeyedTransactorWithChainID(
-		d.cfg.PrivKey, d.cfg.ChainID,
-	)
+	urgency, err := d.outputUrgency(ctx, l2Header.Time)
 	if err != nil {
-		return nil, err
+		return txmgr.TxCandidate{}, err
 	}
-	opts.Context = ctx
-	opts.Nonce = new(big.Int).SetUint64(tx.Nonce())
-	opts.NoSend = true
 
-	return d.rawL2ooContract.RawTransact(opts, tx.Data())
+	return txmgr.TxCandidate{
+		To:      &d.cfg.L2OOAddr,
+		TxData:  data,
+		Urgency: urgency,
+	}, nil
 }
 
-// SendTransaction injects a signed transaction into the pending pool for
-// execution.
-func (d *Driver) SendTransaction(
-	ctx context.Context,
-	tx *types.Transaction,
-) error {
-
-	return d.cfg.L1Client.SendTransaction(ctx, tx)
+// outputUrgency returns the inclusion window of the output of the L2 block at the given time:
+// it should be proposed before the next output is due, one submission interval later.
+func (d *Driver) outputUrgency(ctx context.Context, l2Time uint64) (txmgr.Urgency, error) {
+	callOpts := &bind.CallOpts{Context: ctx}
+	interval, err := d.l2ooContract.SUBMISSIONINTERVAL(callOpts)
+	if err != nil {
+		return txmgr.Urgency{}, fmt.Errorf("error fetching submission interval: %w", err)
+	}
+	blockTime, err := d.l2ooContract.L2BLOCKTIME(callOpts)
+	if err != nil {
+		return txmgr.Urgency{}, fmt.Errorf("error fetching L2 block time: %w", err)
+	}
+	start := time.Unix(int64(l2Time), 0)
+	window := time.Duration(interval.Uint64()*blockTime.Uint64()) * time.Second
+	return txmgr.Urgency{Start: start, Deadline: start.Add(window)}, nil
 }
 
 func (d *Driver) outputRootAtBlock(ctx context.Context, blockNum *big.Int) (eth.Bytes32, error) {
@@ -0,0 +1,166 @@
+package l2output
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum-optimism/optimism/op-node/eth"
+	"github.com/ethereum-optimism/optimism/op-node/sources"
+	"github.com/ethereum-optimism/optimism/op-proposer/drivers"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutputRanges(t *testing.T) {
+	ranges := func(rs ...[2]int64) []drivers.BlockRange {
+		var out []drivers.BlockRange
+		for _, r := range rs {
+			out = append(out, drivers.BlockRange{Start: big.NewInt(r[0]), End: big.NewInt(r[1])})
+		}
+		return out
+	}
+	tests := []struct {
+		name    string
+		latest  int64
+		next    int64
+		current int64
+		max     uint64
+		exp     []drivers.BlockRange
+	}{
+		{"interval not elapsed", 10, 20, 19, 5, nil},
+		{"one interval", 10, 20, 25, 5, ranges([2]int64{11, 21})},
+		{"behind by three intervals", 10, 20, 40, 5,
+			ranges([2]int64{11, 21}, [2]int64{21, 31}, [2]int64{31, 41})},
+		{"capped at max pending txs", 10, 20, 100, 2,
+			ranges([2]int64{11, 21}, [2]int64{21, 31})},
+		{"zero max", 10, 20, 100, 0, nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := outputRanges(big.NewInt(test.latest), big.NewInt(test.next),
+				big.NewInt(10), big.NewInt(test.current), test.max)
+			require.Equal(t, test.exp, got)
+		})
+	}
+}
+
+// fakeL1 serves the L1 headers of the eth namespace.
+type fakeL1 struct {
+	headers []*types.Header
+}
+
+func (f *fakeL1) GetBlockByNumber(ctx context.Context, number rpc.BlockNumber, full bool) (*types.Header, error) {
+	if number < 0 {
+		return f.headers[len(f.headers)-1], nil
+	}
+	return f.headers[number], nil
+}
+
+// fakeRollupNode serves the sync status of the optimism namespace.
+type fakeRollupNode struct {
+	status *eth.SyncStatus
+}
+
+func (f *fakeRollupNode) SyncStatus(ctx context.Context) (*eth.SyncStatus, error) {
+	return f.status, nil
+}
+
+// newTestDriver creates a driver backed by an L1 chain of n blocks, and a
+// rollup node with the given sync status.
+func newTestDriver(t *testing.T, n int, status *eth.SyncStatus) (*Driver, []*types.Header) {
+	l1 := &fakeL1{}
+	for i := 0; i < n; i++ {
+		l1.headers = append(l1.headers, &types.Header{
+			Number:     big.NewInt(int64(i)),
+			Difficulty: common.Big0,
+			BaseFee:    common.Big1,
+		})
+	}
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("eth", l1))
+	require.NoError(t, server.RegisterName("optimism", &fakeRollupNode{status: status}))
+	client := rpc.DialInProc(server)
+	t.Cleanup(func() {
+		client.Close()
+		server.Stop()
+	})
+
+	return &Driver{
+		cfg: Config{
+			Name:         "TEST",
+			L1Client:     ethclient.NewClient(client),
+			RollupClient: sources.NewRollupClient(client),
+			OutputSource: OutputSourceSafe,
+			MaxL1Lag:     5,
+		},
+		l: log.New(),
+	}, l1.headers
+}
+
+func l1Ref(h *types.Header) eth.L1BlockRef {
+	return eth.L1BlockRef{Hash: h.Hash(), Number: h.Number.Uint64()}
+}
+
+func TestSyncStatus(t *testing.T) {
+	t.Run("in sync", func(t *testing.T) {
+		status := &eth.SyncStatus{}
+		d, headers := newTestDriver(t, 10, status)
+		status.HeadL1, status.CurrentL1 = l1Ref(headers[9]), l1Ref(headers[8])
+
+		got, err := d.syncStatus(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, status.CurrentL1, got.CurrentL1)
+	})
+
+	t.Run("no L1 info", func(t *testing.T) {
+		d, _ := newTestDriver(t, 10, &eth.SyncStatus{})
+
+		_, err := d.syncStatus(context.Background())
+		require.ErrorIs(t, err, ErrNodeSyncing)
+	})
+
+	t.Run("derivation lags behind L1", func(t *testing.T) {
+		status := &eth.SyncStatus{}
+		d, headers := newTestDriver(t, 10, status)
+		status.HeadL1, status.CurrentL1 = l1Ref(headers[9]), l1Ref(headers[3])
+
+		_, err := d.syncStatus(context.Background())
+		require.ErrorIs(t, err, ErrNodeSyncing)
+	})
+
+	t.Run("non-canonical L1 view", func(t *testing.T) {
+		status := &eth.SyncStatus{}
+		d, headers := newTestDriver(t, 10, status)
+		status.HeadL1 = l1Ref(headers[9])
+		status.CurrentL1 = eth.L1BlockRef{Hash: common.Hash{0xde, 0xad}, Number: 8}
+
+		_, err := d.syncStatus(context.Background())
+		require.ErrorIs(t, err, ErrNonCanonicalL1)
+	})
+}
+
+func TestCraftTxRefusesUnsyncedNode(t *testing.T) {
+	t.Run("syncing", func(t *testing.T) {
+		status := &eth.SyncStatus{}
+		d, headers := newTestDriver(t, 10, status)
+		status.HeadL1, status.CurrentL1 = l1Ref(headers[9]), l1Ref(headers[2])
+
+		_, err := d.CraftTx(context.Background(), big.NewInt(1), big.NewInt(11))
+		require.ErrorIs(t, err, ErrNodeSyncing)
+	})
+
+	t.Run("non-canonical L1 view", func(t *testing.T) {
+		status := &eth.SyncStatus{}
+		d, headers := newTestDriver(t, 10, status)
+		status.HeadL1 = l1Ref(headers[9])
+		status.CurrentL1 = eth.L1BlockRef{Hash: common.Hash{0xde, 0xad}, Number: 9}
+
+		_, err := d.CraftTx(context.Background(), big.NewInt(1), big.NewInt(11))
+		require.ErrorIs(t, err, ErrNonCanonicalL1)
+	})
+}
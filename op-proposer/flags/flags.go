@@ -8,6 +8,7 @@ import (
 	opmetrics "github.com/ethereum-optimism/optimism/op-service/metrics"
 	oppprof "github.com/ethereum-optimism/optimism/op-service/pprof"
 	oprpc "github.com/ethereum-optimism/optimism/op-service/rpc"
+	"github.com/ethereum-optimism/optimism/op-service/txmgr"
 )
 
 const envVarPrefix = "OP_PROPOSER"
@@ -85,6 +86,27 @@ var (
 		Usage:  "The private key to use with the l2output wallet. Must not be used with mnemonic.",
 		EnvVar: opservice.PrefixEnvVar(envVarPrefix, "PRIVATE_KEY"),
 	}
+	OutputSourceFlag = cli.StringFlag{
+		Name: "output-source",
+		Usage: "L2 head of the rollup node up to which outputs are proposed: " +
+			"'safe' or 'finalized'",
+		Value:  "safe",
+		EnvVar: opservice.PrefixEnvVar(envVarPrefix, "OUTPUT_SOURCE"),
+	}
+	MaxPendingOutputsFlag = cli.Uint64Flag{
+		Name: "max-pending-outputs",
+		Usage: "Maximum number of output proposals submitted at consecutive " +
+			"nonces at once, to catch up when the proposer is behind",
+		Value:  4,
+		EnvVar: opservice.PrefixEnvVar(envVarPrefix, "MAX_PENDING_OUTPUTS"),
+	}
+	MaxL1LagFlag = cli.Uint64Flag{
+		Name: "max-l1-lag",
+		Usage: "Number of L1 blocks the rollup node may lag behind the L1 head " +
+			"before it is considered to be syncing, and no outputs are proposed. 0 to disable",
+		Value:  20,
+		EnvVar: opservice.PrefixEnvVar(envVarPrefix, "MAX_L1_LAG"),
+	}
 )
 
 var requiredFlags = []cli.Flag{
@@ -102,6 +124,9 @@ var optionalFlags = []cli.Flag{
 	MnemonicFlag,
 	L2OutputHDPathFlag,
 	PrivateKeyFlag,
+	OutputSourceFlag,
+	MaxPendingOutputsFlag,
+	MaxL1LagFlag,
 }
 
 func init() {
@@ -110,6 +135,7 @@ func init() {
 	optionalFlags = append(optionalFlags, oplog.CLIFlags(envVarPrefix)...)
 	optionalFlags = append(optionalFlags, opmetrics.CLIFlags(envVarPrefix)...)
 	optionalFlags = append(optionalFlags, oppprof.CLIFlags(envVarPrefix)...)
+	optionalFlags = append(optionalFlags, txmgr.CLIFlags(envVarPrefix)...)
 
 	Flags = append(requiredFlags, optionalFlags...)
 }
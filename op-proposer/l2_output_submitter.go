@@ -17,11 +17,11 @@ import (
 
 	"github.com/ethereum-optimism/optimism/op-node/sources"
 	"github.com/ethereum-optimism/optimism/op-proposer/drivers/l2output"
-	"github.com/ethereum-optimism/optimism/op-proposer/txmgr"
 	oplog "github.com/ethereum-optimism/optimism/op-service/log"
 	opmetrics "github.com/ethereum-optimism/optimism/op-service/metrics"
 	oppprof "github.com/ethereum-optimism/optimism/op-service/pprof"
 	oprpc "github.com/ethereum-optimism/optimism/op-service/rpc"
+	"github.com/ethereum-optimism/optimism/op-service/txmgr"
 	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -50,7 +50,10 @@ func Main(version string) func(ctx *cli.Context) error {
 		l := oplog.NewLogger(cfg.LogConfig)
 		l.Info("Initializing L2 Output Submitter")
 
-		l2OutputSubmitter, err := NewL2OutputSubmitter(cfg, version, l)
+		registry := opmetrics.NewRegistry()
+		pricingMetrics := txmgr.NewPromPricingMetrics(registry, "op_proposer")
+
+		l2OutputSubmitter, err := NewL2OutputSubmitter(cfg, version, l, pricingMetrics)
 		if err != nil {
 			l.Error("Unable to create L2 Output Submitter", "error", err)
 			return err
@@ -77,7 +80,6 @@ func Main(version string) func(ctx *cli.Context) error {
 			}()
 		}
 
-		registry := opmetrics.NewRegistry()
 		metricsCfg := cfg.MetricsConfig
 		if metricsCfg.Enabled {
 			l.Info("starting metrics server", "addr", metricsCfg.ListenAddr, "port", metricsCfg.ListenPort)
@@ -126,6 +128,7 @@ func NewL2OutputSubmitter(
 	cfg Config,
 	gitVersion string,
 	l log.Logger,
+	m txmgr.PricingMetrics,
 ) (*L2OutputSubmitter, error) {
 
 	ctx := context.Background()
@@ -194,6 +197,14 @@ func NewL2OutputSubmitter(
 		SafeAbortNonceTooLowCount: cfg.SafeAbortNonceTooLowCount,
 	}
 
+	txMgr := txmgr.NewManager(txmgr.ManagerConfig{
+		Config:      txManagerConfig,
+		ChainID:     chainID,
+		From:        crypto.PubkeyToAddress(l2OutputPrivKey.PublicKey),
+		Signer:      txmgr.PrivateKeySignerFn(l2OutputPrivKey, chainID),
+		JournalPath: cfg.TxMgrConfig.JournalPath,
+	}, l1Client, txmgr.NewFeePricer(l, cfg.TxMgrConfig.Pricing, l1Client, m))
+
 	l2OutputDriver, err := l2output.NewDriver(l2output.Config{
 		Log:          l,
 		Name:         "L2Output Submitter",
@@ -201,20 +212,21 @@ func NewL2OutputSubmitter(
 		L2Client:     l2Client,
 		RollupClient: rollupClient,
 		L2OOAddr:     l2ooAddress,
-		ChainID:      chainID,
 		PrivKey:      l2OutputPrivKey,
+		OutputSource: l2output.OutputSource(cfg.OutputSource),
+		MaxL1Lag:     cfg.MaxL1Lag,
 	})
 	if err != nil {
 		return nil, err
 	}
 
 	l2OutputService := NewService(ServiceConfig{
-		Log:             l,
-		Context:         ctx,
-		Driver:          l2OutputDriver,
-		PollInterval:    cfg.PollInterval,
-		L1Client:        l1Client,
-		TxManagerConfig: txManagerConfig,
+		Log:           l,
+		Context:       ctx,
+		Driver:        l2OutputDriver,
+		PollInterval:  cfg.PollInterval,
+		TxManager:     txMgr,
+		MaxPendingTxs: cfg.MaxPendingOutputs,
 	})
 
 	return &L2OutputSubmitter{
@@ -2,14 +2,14 @@ package op_proposer
 
 import (
 	"context"
+	"errors"
 	"math/big"
 	"sync"
 	"time"
 
-	"github.com/ethereum-optimism/optimism/op-proposer/txmgr"
+	"github.com/ethereum-optimism/optimism/op-proposer/drivers"
+	"github.com/ethereum-optimism/optimism/op-service/txmgr"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/log"
 )
 
@@ -22,46 +22,35 @@ type Driver interface {
 	// WalletAddr is the wallet address used to pay for transaction fees.
 	WalletAddr() common.Address
 
-	// GetBlockRange returns the start and end L2 block heights that need to be
-	// processed. Note that the end value is *exclusive*, therefore if the
-	// returned values are identical nothing needs to be processed.
-	GetBlockRange(ctx context.Context) (*big.Int, *big.Int, error)
+	// GetBlockRanges returns the consecutive L2 block ranges that need to be
+	// processed, one range per transaction, up to at most max ranges. Note
+	// that the end values are *exclusive*. If no ranges are returned nothing
+	// needs to be processed.
+	GetBlockRanges(ctx context.Context, max uint64) ([]drivers.BlockRange, error)
 
 	// CraftTx transforms the L2 blocks between start and end into a transaction
-	// using the given nonce.
-	//
-	// NOTE: This method SHOULD NOT publish the resulting transaction.
+	// candidate, which is priced and published by the tx manager.
 	CraftTx(
 		ctx context.Context,
-		start, end, nonce *big.Int,
-	) (*types.Transaction, error)
-
-	// UpdateGasPrice signs an otherwise identical txn to the one provided but
-	// with updated gas prices sampled from the existing network conditions.
-	//
-	// NOTE: Thie method SHOULD NOT publish the resulting transaction.
-	UpdateGasPrice(
-		ctx context.Context,
-		tx *types.Transaction,
-	) (*types.Transaction, error)
-
-	// SendTransaction injects a signed transaction into the pending pool for
-	// execution.
-	SendTransaction(ctx context.Context, tx *types.Transaction) error
+		start, end *big.Int,
+	) (txmgr.TxCandidate, error)
 }
 
 type ServiceConfig struct {
-	Log             log.Logger
-	Context         context.Context
-	Driver          Driver
-	PollInterval    time.Duration
-	L1Client        *ethclient.Client
-	TxManagerConfig txmgr.Config
+	Log          log.Logger
+	Context      context.Context
+	Driver       Driver
+	PollInterval time.Duration
+	TxManager    *txmgr.Manager
+
+	// MaxPendingTxs is the maximum number of transactions queued at
+	// consecutive nonces in a single iteration, to catch up when behind.
+	MaxPendingTxs uint64
 }
 
 type Service struct {
 	cfg   ServiceConfig
-	txMgr txmgr.TxManager
+	txMgr *txmgr.Manager
 	l     log.Logger
 
 	ctx    context.Context
@@ -70,15 +59,11 @@ type Service struct {
 }
 
 func NewService(cfg ServiceConfig) *Service {
-	txMgr := txmgr.NewSimpleTxManager(
-		cfg.Driver.Name(), cfg.TxManagerConfig, cfg.L1Client,
-	)
-
 	ctx, cancel := context.WithCancel(cfg.Context)
 
 	return &Service{
 		cfg:    cfg,
-		txMgr:  txMgr,
+		txMgr:  cfg.TxManager,
 		l:      cfg.Log,
 		ctx:    ctx,
 		cancel: cancel,
@@ -86,6 +71,9 @@ func NewService(cfg ServiceConfig) *Service {
 }
 
 func (s *Service) Start() error {
+	if err := s.txMgr.Start(s.ctx); err != nil {
+		return err
+	}
 	s.wg.Add(1)
 	go s.eventLoop()
 	return nil
@@ -94,6 +82,7 @@ func (s *Service) Start() error {
 func (s *Service) Stop() error {
 	s.cancel()
 	s.wg.Wait()
+	s.txMgr.Close()
 	return nil
 }
 
@@ -107,68 +96,75 @@ func (s *Service) eventLoop() {
 	for {
 		select {
 		case <-ticker.C:
-			// Determine the range of L2 blocks that the submitter has not
+			// Determine the ranges of L2 blocks that the submitter has not
 			// processed, and needs to take action on.
-			s.l.Info(name + " fetching current block range")
-			start, end, err := s.cfg.Driver.GetBlockRange(s.ctx)
+			s.l.Info(name + " fetching current block ranges")
+			ranges, err := s.cfg.Driver.GetBlockRanges(s.ctx, s.maxPendingTxs())
 			if err != nil {
-				s.l.Error(name+" unable to get block range", "err", err)
+				s.l.Error(name+" unable to get block ranges", "err", err)
 				continue
 			}
 
 			// No new updates.
-			if start.Cmp(end) == 0 {
-				s.l.Info(name+" no updates", "start", start, "end", end)
+			if len(ranges) == 0 {
+				s.l.Info(name + " no updates")
 				continue
 			}
-			s.l.Info(name+" block range", "start", start, "end", end)
 
-			// Query for the submitter's current nonce.
-			nonce64, err := s.cfg.L1Client.NonceAt(
-				s.ctx, s.cfg.Driver.WalletAddr(), nil,
-			)
-			if err != nil {
-				s.l.Error(name+" unable to get current nonce",
-					"err", err)
-				continue
+			// Queue a transaction per range, so that the tx manager publishes
+			// them at consecutive nonces without waiting for each to confirm.
+			var queued []*txmgr.QueuedTx
+			for _, r := range ranges {
+				s.l.Info(name+" block range", "start", r.Start, "end", r.End)
+
+				candidate, err := s.cfg.Driver.CraftTx(s.ctx, r.Start, r.End)
+				if err != nil {
+					s.l.Error(name+" unable to craft tx",
+						"err", err)
+					break
+				}
+
+				q, err := s.txMgr.Queue(s.ctx, candidate)
+				if err != nil {
+					s.l.Error(name+" unable to queue tx", "err", err)
+					break
+				}
+				queued = append(queued, q)
 			}
-			nonce := new(big.Int).SetUint64(nonce64)
 
-			tx, err := s.cfg.Driver.CraftTx(
-				s.ctx, start, end, nonce,
-			)
-			if err != nil {
-				s.l.Error(name+" unable to craft tx",
-					"err", err)
-				continue
+			// Wait until one of our submitted transactions confirms at each
+			// nonce. The tx manager replaces the transactions at a bumped gas
+			// price if they are not mined in time. A transaction that cannot be
+			// replaced within the fee limits is still pending, and is waited on
+			// until it confirms, to not propose its range again.
+			for _, q := range queued {
+				receipt, err := q.Wait(s.ctx)
+				for errors.Is(err, txmgr.ErrReplacementLimited) || errors.Is(err, txmgr.ErrBudgetExhausted) {
+					s.l.Warn(name+" tx replacement limited, waiting for it to confirm", "nonce", q.Nonce(), "err", err)
+					receipt, err = q.Wait(s.ctx)
+				}
+				if err != nil {
+					s.l.Error(name+" unable to publish tx", "nonce", q.Nonce(), "err", err)
+					continue
+				}
+
+				// The transaction was successfully submitted.
+				s.l.Info(name+" tx successfully published",
+					"tx_hash", receipt.TxHash, "nonce", q.Nonce())
 			}
 
-			// Construct the a closure that will update the txn with the current
-			// gas prices.
-			updateGasPrice := func(ctx context.Context) (*types.Transaction, error) {
-				s.l.Info(name+" updating batch tx gas price", "start", start,
-					"end", end, "nonce", nonce)
-
-				return s.cfg.Driver.UpdateGasPrice(ctx, tx)
-			}
-
-			// Wait until one of our submitted transactions confirms. If no
-			// receipt is received it's likely our gas price was too low.
-			receipt, err := s.txMgr.Send(
-				s.ctx, updateGasPrice, s.cfg.Driver.SendTransaction,
-			)
-			if err != nil {
-				s.l.Error(name+" unable to publish tx", "err", err)
-				continue
-			}
-
-			// The transaction was successfully submitted.
-			s.l.Info(name+" tx successfully published",
-				"tx_hash", receipt.TxHash)
-
 		case <-s.ctx.Done():
 			s.l.Info(name + " service shutting down")
 			return
 		}
 	}
 }
+
+// maxPendingTxs returns the configured number of transactions to queue at
+// once, at least one.
+func (s *Service) maxPendingTxs() uint64 {
+	if s.cfg.MaxPendingTxs == 0 {
+		return 1
+	}
+	return s.cfg.MaxPendingTxs
+}
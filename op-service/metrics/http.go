@@ -16,17 +16,35 @@ var httpLabels = []string{
 	"response_code",
 }
 
+var rpcLabels = []string{
+	"rpc_method",
+}
+
+var rpcErrorLabels = []string{
+	"rpc_method",
+	"error_code",
+}
+
 type HTTPParams struct {
 	Method     string
 	StatusCode int
 }
 
+// RPCParams identifies a JSON-RPC call served over HTTP.
+type RPCParams struct {
+	Method    string
+	ErrorCode int
+}
+
 type HTTPRecorder interface {
 	RecordHTTPRequestDuration(params *HTTPParams, dur time.Duration)
 	RecordHTTPResponseSize(params *HTTPParams, size int)
 	RecordInflightRequest(params *HTTPParams, quantity int)
 	RecordHTTPRequest(params *HTTPParams)
 	RecordHTTPResponse(params *HTTPParams)
+	RecordRPCRequest(params *RPCParams)
+	RecordRPCRequestDuration(params *RPCParams, dur time.Duration)
+	RecordRPCError(params *RPCParams)
 }
 
 type noopHTTPRecorder struct{}
@@ -43,12 +61,21 @@ func (n *noopHTTPRecorder) RecordHTTPRequest(*HTTPParams) {}
 
 func (n *noopHTTPRecorder) RecordHTTPResponse(*HTTPParams) {}
 
+func (n *noopHTTPRecorder) RecordRPCRequest(*RPCParams) {}
+
+func (n *noopHTTPRecorder) RecordRPCRequestDuration(*RPCParams, time.Duration) {}
+
+func (n *noopHTTPRecorder) RecordRPCError(*RPCParams) {}
+
 type PromHTTPRecorder struct {
 	HTTPRequestDuration  *prometheus.HistogramVec
 	HTTPResponseSize     *prometheus.HistogramVec
 	HTTPInflightRequests *prometheus.GaugeVec
 	HTTPRequests         *prometheus.CounterVec
 	HTTPResponses        *prometheus.CounterVec
+	RPCRequestDuration   *prometheus.HistogramVec
+	RPCRequests          *prometheus.CounterVec
+	RPCErrors            *prometheus.CounterVec
 }
 
 func NewPromHTTPRecorder(r *prometheus.Registry, ns string) HTTPRecorder {
@@ -80,6 +107,22 @@ func NewPromHTTPRecorder(r *prometheus.Registry, ns string) HTTPRecorder {
 			Name:      "http_responses_count_total",
 			Help:      "Tracks total HTTP responses",
 		}, httpLabels),
+		RPCRequestDuration: promauto.With(r).NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: ns,
+			Name:      "rpc_request_duration_seconds",
+			Help:      "Tracks JSON-RPC request durations by method, in seconds",
+			Buckets:   prometheus.DefBuckets,
+		}, rpcLabels),
+		RPCRequests: promauto.With(r).NewCounterVec(prometheus.CounterOpts{
+			Namespace: ns,
+			Name:      "rpc_requests_count_total",
+			Help:      "Tracks total JSON-RPC requests by method",
+		}, rpcLabels),
+		RPCErrors: promauto.With(r).NewCounterVec(prometheus.CounterOpts{
+			Namespace: ns,
+			Name:      "rpc_errors_count_total",
+			Help:      "Tracks total JSON-RPC error responses by method and error code",
+		}, rpcErrorLabels),
 	}
 }
 
@@ -104,6 +147,18 @@ func (p *PromHTTPRecorder) RecordHTTPResponse(params *HTTPParams) {
 	p.HTTPResponses.WithLabelValues(params.Method, strconv.Itoa(params.StatusCode)).Inc()
 }
 
+func (p *PromHTTPRecorder) RecordRPCRequest(params *RPCParams) {
+	p.RPCRequests.WithLabelValues(params.Method).Inc()
+}
+
+func (p *PromHTTPRecorder) RecordRPCRequestDuration(params *RPCParams, dur time.Duration) {
+	p.RPCRequestDuration.WithLabelValues(params.Method).Observe(dur.Seconds())
+}
+
+func (p *PromHTTPRecorder) RecordRPCError(params *RPCParams) {
+	p.RPCErrors.WithLabelValues(params.Method, strconv.Itoa(params.ErrorCode)).Inc()
+}
+
 func NewHTTPRecordingMiddleware(rec HTTPRecorder, next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ww := httputil.NewWrappedResponseWriter(w)
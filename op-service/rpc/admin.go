@@ -0,0 +1,451 @@
+package rpc
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/op-service/httputil"
+	opmetrics "github.com/ethereum-optimism/optimism/op-service/metrics"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// AdminRole names a set of admin RPC methods a caller is allowed to call.
+type AdminRole string
+
+// AdminPolicy maps each role to the methods it is allowed to call. Methods are
+// either full JSON-RPC method names, e.g. admin_resetDerivationPipeline,
+// namespace wildcards, e.g. admin_*, or the * wildcard allowing every method.
+type AdminPolicy map[AdminRole][]string
+
+// ParseAdminPolicy parses rules of the form role:method into a policy.
+func ParseAdminPolicy(rules []string) (AdminPolicy, error) {
+	policy := make(AdminPolicy)
+	for _, rule := range rules {
+		role, method, ok := strings.Cut(strings.TrimSpace(rule), ":")
+		if !ok || role == "" || !isValidMethodPattern(method) {
+			return nil, fmt.Errorf("invalid admin RPC rule %q, expected role:method", rule)
+		}
+		policy[AdminRole(role)] = append(policy[AdminRole(role)], method)
+	}
+	return policy, nil
+}
+
+func isValidMethodPattern(pattern string) bool {
+	if pattern == "*" {
+		return true
+	}
+	if strings.HasSuffix(pattern, "_*") {
+		namespace := strings.TrimSuffix(pattern, "_*")
+		return methodLabel(namespace) == namespace
+	}
+	return methodLabel(pattern) == pattern
+}
+
+// Allowed returns whether the role is allowed to call the method.
+func (p AdminPolicy) Allowed(role AdminRole, method string) bool {
+	for _, pattern := range p[role] {
+		switch {
+		case pattern == "*":
+			return true
+		case strings.HasSuffix(pattern, "_*"):
+			if strings.HasPrefix(method, pattern[:len(pattern)-1]) {
+				return true
+			}
+		case pattern == method:
+			return true
+		}
+	}
+	return false
+}
+
+// AdminIdentity is the authenticated caller of an admin RPC request.
+type AdminIdentity struct {
+	// Subject identifies the caller, e.g. the sub claim of its JWT or the
+	// common name of its client certificate.
+	Subject string
+	Role    AdminRole
+	// Scheme is the authentication scheme the caller used: jwt or mtls.
+	Scheme string
+}
+
+// AdminAuthenticator authenticates the callers of admin RPC requests.
+type AdminAuthenticator interface {
+	Authenticate(r *http.Request) (*AdminIdentity, error)
+}
+
+// DefaultJWTMaxAge is the default max difference between the iat claim of
+// admin JWTs and the server time.
+const DefaultJWTMaxAge = time.Minute
+
+// AdminClaims are the claims of the JWTs of admin callers. The sub, role and
+// iat claims are required.
+type AdminClaims struct {
+	Role AdminRole `json:"role"`
+	jwt.RegisteredClaims
+}
+
+// JWTAdminAuthenticator authenticates callers by the HS256 JWT they send as a
+// bearer token. Each role has its own secret, and a token is only valid if it
+// is signed with the secret of the role it claims, so that the holder of the
+// secret of a role can't issue tokens for another role. Like the engine API,
+// tokens must have been issued recently, which limits the use of leaked
+// tokens.
+type JWTAdminAuthenticator struct {
+	secrets map[AdminRole][]byte
+	maxAge  time.Duration
+	now     func() time.Time
+}
+
+func NewJWTAdminAuthenticator(secrets map[AdminRole][]byte, maxAge time.Duration) *JWTAdminAuthenticator {
+	return &JWTAdminAuthenticator{
+		secrets: secrets,
+		maxAge:  maxAge,
+		now:     time.Now,
+	}
+}
+
+func (a *JWTAdminAuthenticator) Authenticate(r *http.Request) (*AdminIdentity, error) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return nil, errors.New("missing bearer token")
+	}
+
+	// The claims are decoded before the key is looked up, so the role claim
+	// selects the secret the signature is verified with.
+	var claims AdminClaims
+	_, err := jwt.ParseWithClaims(token, &claims, func(token *jwt.Token) (interface{}, error) {
+		role := token.Claims.(*AdminClaims).Role
+		secret, ok := a.secrets[role]
+		if !ok {
+			return nil, fmt.Errorf("no secret for role %q", role)
+		}
+		return secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithoutClaimsValidation())
+	if err != nil {
+		return nil, fmt.Errorf("invalid token: %w", err)
+	}
+
+	now := a.now()
+	if claims.IssuedAt == nil {
+		return nil, errors.New("missing iat claim")
+	}
+	if age := now.Sub(claims.IssuedAt.Time); age > a.maxAge || age < -a.maxAge {
+		return nil, fmt.Errorf("stale token issued at %v", claims.IssuedAt.Time)
+	}
+	if claims.ExpiresAt != nil && !now.Before(claims.ExpiresAt.Time) {
+		return nil, errors.New("expired token")
+	}
+	if claims.Subject == "" || claims.Role == "" {
+		return nil, errors.New("missing sub or role claim")
+	}
+	return &AdminIdentity{
+		Subject: claims.Subject,
+		Role:    claims.Role,
+		Scheme:  "jwt",
+	}, nil
+}
+
+// MTLSAdminAuthenticator authenticates callers by their client certificate,
+// which must have been verified against the client CAs of the server TLS
+// config. The subject of the caller is the common name of the certificate and
+// its role is the first organizational unit.
+type MTLSAdminAuthenticator struct{}
+
+func (MTLSAdminAuthenticator) Authenticate(r *http.Request) (*AdminIdentity, error) {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return nil, errors.New("missing verified client certificate")
+	}
+	cert := r.TLS.VerifiedChains[0][0]
+	if cert.Subject.CommonName == "" || len(cert.Subject.OrganizationalUnit) == 0 {
+		return nil, errors.New("client certificate without common name or organizational unit")
+	}
+	return &AdminIdentity{
+		Subject: cert.Subject.CommonName,
+		Role:    AdminRole(cert.Subject.OrganizationalUnit[0]),
+		Scheme:  "mtls",
+	}, nil
+}
+
+// AdminServer serves admin RPC APIs on a dedicated listener. Every request
+// must be authenticated, and every call of a request must be allowed for the
+// role of the caller, or the whole request is rejected. Admin calls are
+// written to the audit log, whether they are allowed or not.
+type AdminServer struct {
+	endpoint     string
+	apis         []rpc.API
+	policy       AdminPolicy
+	auth         AdminAuthenticator
+	tlsConfig    *tls.Config
+	httpRecorder opmetrics.HTTPRecorder
+	httpServer   *http.Server
+	listenAddr   net.Addr
+	log          log.Logger
+	audit        log.Logger
+}
+
+type AdminServerOption func(s *AdminServer)
+
+func WithAdminAPIs(apis []rpc.API) AdminServerOption {
+	return func(s *AdminServer) {
+		s.apis = apis
+	}
+}
+
+// WithAdminTLSConfig serves the admin APIs over TLS. It is required by
+// MTLSAdminAuthenticator, with the client CAs to verify callers against.
+func WithAdminTLSConfig(cfg *tls.Config) AdminServerOption {
+	return func(s *AdminServer) {
+		s.tlsConfig = cfg
+	}
+}
+
+func WithAdminHTTPRecorder(recorder opmetrics.HTTPRecorder) AdminServerOption {
+	return func(s *AdminServer) {
+		s.httpRecorder = recorder
+	}
+}
+
+func WithAdminLogger(lgr log.Logger) AdminServerOption {
+	return func(s *AdminServer) {
+		s.log = lgr
+	}
+}
+
+// WithAdminAuditLogger sets the logger of the audit log, which defaults to the
+// server logger.
+func WithAdminAuditLogger(lgr log.Logger) AdminServerOption {
+	return func(s *AdminServer) {
+		s.audit = lgr
+	}
+}
+
+func NewAdminServer(host string, port int, policy AdminPolicy, auth AdminAuthenticator, opts ...AdminServerOption) *AdminServer {
+	s := &AdminServer{
+		endpoint:     net.JoinHostPort(host, strconv.Itoa(port)),
+		policy:       policy,
+		auth:         auth,
+		httpRecorder: opmetrics.NoopHTTPRecorder,
+		log:          log.Root(),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.audit == nil {
+		s.audit = s.log.New("audit", "admin-rpc")
+	}
+	return s
+}
+
+func (s *AdminServer) Endpoint() string {
+	return s.endpoint
+}
+
+// Addr returns the address the server listens on, once started.
+func (s *AdminServer) Addr() net.Addr {
+	return s.listenAddr
+}
+
+func (s *AdminServer) AddAPI(api rpc.API) {
+	s.apis = append(s.apis, api)
+}
+
+func (s *AdminServer) Start() error {
+	srv := rpc.NewServer()
+	if err := node.RegisterApis(s.apis, nil, srv); err != nil {
+		return fmt.Errorf("error registering admin APIs: %w", err)
+	}
+
+	// Admin APIs are not meant to be called from browsers, so no CORS hosts
+	// are allowed.
+	nodeHdlr := node.NewHTTPHandlerStack(srv, nil, wildcardHosts, nil)
+	hdlr := opmetrics.NewHTTPRecordingMiddleware(s.httpRecorder, s.authorize(nodeHdlr))
+
+	listener, err := net.Listen("tcp", s.endpoint)
+	if err != nil {
+		return fmt.Errorf("admin http server failed: %w", err)
+	}
+	if s.tlsConfig != nil {
+		listener = tls.NewListener(listener, s.tlsConfig)
+	}
+	s.listenAddr = listener.Addr()
+
+	s.httpServer = &http.Server{
+		Handler:   NewAccessLogMiddleware(s.log, s.httpRecorder, hdlr),
+		TLSConfig: s.tlsConfig,
+	}
+	go func() {
+		if err := s.httpServer.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.log.Error("admin http server failed", "err", err)
+		}
+	}()
+	return nil
+}
+
+func (s *AdminServer) Stop() error {
+	if s.httpServer == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_ = s.httpServer.Shutdown(ctx)
+	return nil
+}
+
+// authorize only passes requests whose calls are all allowed for the role of
+// the authenticated caller to next.
+func (s *AdminServer) authorize(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auditCtx := []interface{}{
+			"request_id", httputil.RequestIDFromContext(r.Context()),
+			"remote_addr", r.RemoteAddr,
+		}
+
+		id, err := s.auth.Authenticate(r)
+		if err != nil {
+			s.audit.Warn("rejected unauthenticated admin RPC request", append(auditCtx, "err", err)...)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		auditCtx = append(auditCtx, "subject", id.Subject, "role", id.Role, "scheme", id.Scheme)
+
+		// Requests whose calls can't be read, e.g. because the body is too
+		// large, can't be authorized.
+		calls, _ := readCalls(r)
+		if len(calls) == 0 {
+			s.audit.Warn("rejected admin RPC request without calls", auditCtx...)
+			http.Error(w, "no JSON-RPC calls", http.StatusBadRequest)
+			return
+		}
+		methods := make([]string, len(calls))
+		var denied []string
+		for i, call := range calls {
+			methods[i] = call.Method
+			if !s.policy.Allowed(id.Role, call.Method) {
+				denied = append(denied, call.Method)
+			}
+		}
+		auditCtx = append(auditCtx, "methods", methods)
+		if len(denied) > 0 {
+			s.audit.Warn("denied admin RPC call", append(auditCtx, "denied", denied)...)
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+
+		ww := httputil.NewWrappedResponseWriter(w)
+		next.ServeHTTP(ww, r)
+		s.audit.Info("served admin RPC call", append(auditCtx, "status", ww.StatusCode)...)
+	})
+}
+
+// ParseAdminJWTSecrets parses entries of the form role=path into the path of
+// the JWT secret of each role.
+func ParseAdminJWTSecrets(entries []string) (map[AdminRole]string, error) {
+	paths := make(map[AdminRole]string)
+	for _, entry := range entries {
+		role, path, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok || role == "" || path == "" {
+			return nil, fmt.Errorf("invalid admin JWT secret %q, expected role=path", entry)
+		}
+		if _, ok := paths[AdminRole(role)]; ok {
+			return nil, fmt.Errorf("duplicate admin JWT secret for role %q", role)
+		}
+		paths[AdminRole(role)] = path
+	}
+	return paths, nil
+}
+
+// NewAdminServerFromCLIConfig creates an admin server from its CLI config,
+// loading the JWT secrets, TLS certificates and audit log it refers to.
+func NewAdminServerFromCLIConfig(cfg AdminCLIConfig, opts ...AdminServerOption) (*AdminServer, error) {
+	policy, err := ParseAdminPolicy(cfg.Allow)
+	if err != nil {
+		return nil, err
+	}
+
+	var auth AdminAuthenticator
+	if len(cfg.JWTSecrets) > 0 {
+		paths, err := ParseAdminJWTSecrets(cfg.JWTSecrets)
+		if err != nil {
+			return nil, err
+		}
+		secrets := make(map[AdminRole][]byte, len(paths))
+		for role, path := range paths {
+			if secrets[role], err = readJWTSecret(path); err != nil {
+				return nil, err
+			}
+		}
+		auth = NewJWTAdminAuthenticator(secrets, cfg.JWTMaxAge)
+	} else {
+		auth = MTLSAdminAuthenticator{}
+	}
+
+	if cfg.TLSCertPath != "" {
+		tlsConfig, err := newAdminTLSConfig(cfg.TLSCertPath, cfg.TLSKeyPath, cfg.TLSClientCAPath)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, WithAdminTLSConfig(tlsConfig))
+	}
+
+	if cfg.AuditLogPath != "" {
+		handler, err := log.FileHandler(cfg.AuditLogPath, log.JSONFormat())
+		if err != nil {
+			return nil, fmt.Errorf("cannot open admin audit log: %w", err)
+		}
+		audit := log.New()
+		audit.SetHandler(log.SyncHandler(handler))
+		opts = append(opts, WithAdminAuditLogger(audit))
+	}
+
+	return NewAdminServer(cfg.ListenAddr, cfg.ListenPort, policy, auth, opts...), nil
+}
+
+func readJWTSecret(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read admin JWT secret: %w", err)
+	}
+	secret := common.FromHex(strings.TrimSpace(string(data)))
+	if len(secret) != 32 {
+		return nil, fmt.Errorf("invalid admin JWT secret in path %s, not 32 hex-formatted bytes", path)
+	}
+	return secret, nil
+}
+
+func newAdminTLSConfig(certPath, keyPath, clientCAPath string) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(certPath, keyPath)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load admin TLS certificate: %w", err)
+	}
+	cfg := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+	if clientCAPath != "" {
+		pem, err := os.ReadFile(clientCAPath)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read admin TLS client CA: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates in admin TLS client CA %s", clientCAPath)
+		}
+		cfg.ClientCAs = pool
+		cfg.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return cfg, nil
+}
@@ -0,0 +1,395 @@
+package rpc
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/require"
+)
+
+type testAdminAPI struct {
+	resets int
+}
+
+func (a *testAdminAPI) Reset() int {
+	a.resets++
+	return a.resets
+}
+
+func (a *testAdminAPI) Status() string {
+	return "ok"
+}
+
+func TestParseAdminPolicy(t *testing.T) {
+	policy, err := ParseAdminPolicy([]string{
+		"operator:admin_*",
+		"monitor:admin_status",
+		"root:*",
+	})
+	require.NoError(t, err)
+
+	require.True(t, policy.Allowed("operator", "admin_reset"))
+	require.False(t, policy.Allowed("operator", "adminx_reset"))
+	require.True(t, policy.Allowed("monitor", "admin_status"))
+	require.False(t, policy.Allowed("monitor", "admin_reset"))
+	require.True(t, policy.Allowed("root", "debug_anything"))
+	require.False(t, policy.Allowed("unknown", "admin_status"))
+
+	for _, rule := range []string{"operator", ":admin_*", "operator:", "operator:admin_**", "operator:admin reset"} {
+		_, err := ParseAdminPolicy([]string{rule})
+		require.Error(t, err, rule)
+	}
+}
+
+func newAdminToken(t *testing.T, secret []byte, claims AdminClaims) string {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(secret)
+	require.NoError(t, err)
+	return token
+}
+
+func TestJWTAdminAuthenticator(t *testing.T) {
+	secret := bytes.Repeat([]byte{1}, 32)
+	monitorSecret := bytes.Repeat([]byte{3}, 32)
+	now := time.Unix(1_000_000, 0)
+	auth := NewJWTAdminAuthenticator(map[AdminRole][]byte{
+		"operator": secret,
+		"monitor":  monitorSecret,
+	}, time.Minute)
+	auth.now = func() time.Time { return now }
+
+	authenticate := func(token string) (*AdminIdentity, error) {
+		req, err := http.NewRequest(http.MethodPost, "/", nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+token)
+		return auth.Authenticate(req)
+	}
+	claims := func(iat time.Time) AdminClaims {
+		return AdminClaims{
+			Role: "operator",
+			RegisteredClaims: jwt.RegisteredClaims{
+				Subject:  "alice",
+				IssuedAt: jwt.NewNumericDate(iat),
+			},
+		}
+	}
+
+	id, err := authenticate(newAdminToken(t, secret, claims(now.Add(-30*time.Second))))
+	require.NoError(t, err)
+	require.Equal(t, &AdminIdentity{Subject: "alice", Role: "operator", Scheme: "jwt"}, id)
+
+	_, err = authenticate(newAdminToken(t, secret, claims(now.Add(-2*time.Minute))))
+	require.ErrorContains(t, err, "stale token")
+
+	_, err = authenticate(newAdminToken(t, bytes.Repeat([]byte{2}, 32), claims(now)))
+	require.ErrorContains(t, err, "invalid token")
+
+	expired := claims(now)
+	expired.ExpiresAt = jwt.NewNumericDate(now)
+	_, err = authenticate(newAdminToken(t, secret, expired))
+	require.ErrorContains(t, err, "expired token")
+
+	noRole := claims(now)
+	noRole.Role = ""
+	_, err = authenticate(newAdminToken(t, secret, noRole))
+	require.ErrorContains(t, err, "no secret for role")
+
+	noSubject := claims(now)
+	noSubject.Subject = ""
+	_, err = authenticate(newAdminToken(t, secret, noSubject))
+	require.ErrorContains(t, err, "missing sub or role")
+
+	// The holder of the monitor secret can't issue operator tokens.
+	_, err = authenticate(newAdminToken(t, monitorSecret, claims(now)))
+	require.ErrorContains(t, err, "invalid token")
+
+	monitor := claims(now)
+	monitor.Role = "monitor"
+	id, err = authenticate(newAdminToken(t, monitorSecret, monitor))
+	require.NoError(t, err)
+	require.Equal(t, AdminRole("monitor"), id.Role)
+
+	unsigned, err := jwt.NewWithClaims(jwt.SigningMethodNone, claims(now)).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	require.NoError(t, err)
+	_, err = authenticate(unsigned)
+	require.ErrorContains(t, err, "invalid token")
+
+	req, err := http.NewRequest(http.MethodPost, "/", nil)
+	require.NoError(t, err)
+	_, err = auth.Authenticate(req)
+	require.ErrorContains(t, err, "missing bearer token")
+}
+
+// auditRecords collects the audit log records of an admin server.
+type auditRecords struct {
+	records []*log.Record
+}
+
+func (a *auditRecords) Log(r *log.Record) error {
+	a.records = append(a.records, r)
+	return nil
+}
+
+func (a *auditRecords) last(t *testing.T) map[string]interface{} {
+	require.NotEmpty(t, a.records)
+	r := a.records[len(a.records)-1]
+	ctx := map[string]interface{}{"msg": r.Msg}
+	for i := 0; i+1 < len(r.Ctx); i += 2 {
+		ctx[r.Ctx[i].(string)] = r.Ctx[i+1]
+	}
+	return ctx
+}
+
+func startAdminServer(t *testing.T, auth AdminAuthenticator, opts ...AdminServerOption) (*AdminServer, *testAdminAPI, *auditRecords) {
+	policy, err := ParseAdminPolicy([]string{"operator:admin_*", "monitor:admin_status"})
+	require.NoError(t, err)
+
+	api := new(testAdminAPI)
+	audit := new(auditRecords)
+	auditLog := log.New()
+	auditLog.SetHandler(audit)
+	opts = append(opts,
+		WithAdminAPIs([]rpc.API{{Namespace: "admin", Service: api}}),
+		WithAdminAuditLogger(auditLog),
+	)
+	server := NewAdminServer("127.0.0.1", 0, policy, auth, opts...)
+	require.NoError(t, server.Start())
+	t.Cleanup(func() {
+		_ = server.Stop()
+	})
+	return server, api, audit
+}
+
+func postAdmin(t *testing.T, client *http.Client, url, token, body string) (int, string) {
+	req, err := http.NewRequest(http.MethodPost, url, strings.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	res, err := client.Do(req)
+	require.NoError(t, err)
+	defer res.Body.Close()
+	resBody, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+	return res.StatusCode, string(resBody)
+}
+
+func TestAdminServerJWT(t *testing.T) {
+	secrets := map[AdminRole][]byte{
+		"operator": bytes.Repeat([]byte{1}, 32),
+		"monitor":  bytes.Repeat([]byte{2}, 32),
+	}
+	server, api, audit := startAdminServer(t, NewJWTAdminAuthenticator(secrets, time.Minute))
+	url := fmt.Sprintf("http://%s", server.Addr())
+	signedToken := func(secret []byte, subject string, role AdminRole) string {
+		return newAdminToken(t, secret, AdminClaims{
+			Role: role,
+			RegisteredClaims: jwt.RegisteredClaims{
+				Subject:  subject,
+				IssuedAt: jwt.NewNumericDate(time.Now()),
+			},
+		})
+	}
+	token := func(subject string, role AdminRole) string {
+		return signedToken(secrets[role], subject, role)
+	}
+	reset := `{"jsonrpc":"2.0","id":1,"method":"admin_reset"}`
+
+	t.Run("rejects unauthenticated requests", func(t *testing.T) {
+		status, _ := postAdmin(t, http.DefaultClient, url, "", reset)
+		require.Equal(t, http.StatusUnauthorized, status)
+		require.Equal(t, 0, api.resets)
+		require.Equal(t, "rejected unauthenticated admin RPC request", audit.last(t)["msg"])
+	})
+
+	t.Run("serves allowed calls", func(t *testing.T) {
+		status, body := postAdmin(t, http.DefaultClient, url, token("alice", "operator"), reset)
+		require.Equal(t, http.StatusOK, status)
+		require.Contains(t, body, `"result":1`)
+
+		record := audit.last(t)
+		require.Equal(t, "served admin RPC call", record["msg"])
+		require.Equal(t, "alice", record["subject"])
+		require.Equal(t, AdminRole("operator"), record["role"])
+		require.Equal(t, []string{"admin_reset"}, record["methods"])
+	})
+
+	t.Run("denies batches with a disallowed call", func(t *testing.T) {
+		status, _ := postAdmin(t, http.DefaultClient, url, token("bob", "monitor"),
+			`[{"jsonrpc":"2.0","id":1,"method":"admin_status"},`+reset+`]`)
+		require.Equal(t, http.StatusForbidden, status)
+		require.Equal(t, 1, api.resets)
+
+		record := audit.last(t)
+		require.Equal(t, "denied admin RPC call", record["msg"])
+		require.Equal(t, "bob", record["subject"])
+		require.Equal(t, []string{"admin_reset"}, record["denied"])
+	})
+
+	t.Run("rejects role escalation with a lower-privilege secret", func(t *testing.T) {
+		status, _ := postAdmin(t, http.DefaultClient, url,
+			signedToken(secrets["monitor"], "mallory", "operator"), reset)
+		require.Equal(t, http.StatusUnauthorized, status)
+		require.Equal(t, 1, api.resets)
+		require.Equal(t, "rejected unauthenticated admin RPC request", audit.last(t)["msg"])
+	})
+
+	t.Run("rejects requests without calls", func(t *testing.T) {
+		status, _ := postAdmin(t, http.DefaultClient, url, token("alice", "operator"), `not json`)
+		require.Equal(t, http.StatusBadRequest, status)
+	})
+
+	t.Run("works with authenticated RPC clients", func(t *testing.T) {
+		client, err := rpc.DialHTTPWithClient(url, http.DefaultClient)
+		require.NoError(t, err)
+		client.SetHeader("Authorization", "Bearer "+token("carol", "monitor"))
+		var res string
+		require.NoError(t, client.Call(&res, "admin_status"))
+		require.Equal(t, "ok", res)
+	})
+}
+
+type testCert struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func newTestCert(t *testing.T, subject pkix.Name, parent *testCert, isCA bool) *testCert {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               subject,
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  isCA,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	signer, signerKey := tmpl, key
+	if parent != nil {
+		signer, signerKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, signer, &key.PublicKey, signerKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return &testCert{cert: cert, key: key}
+}
+
+func (c *testCert) tlsCertificate() tls.Certificate {
+	return tls.Certificate{
+		Certificate: [][]byte{c.cert.Raw},
+		PrivateKey:  c.key,
+	}
+}
+
+func TestAdminServerMTLS(t *testing.T) {
+	ca := newTestCert(t, pkix.Name{CommonName: "test ca"}, nil, true)
+	serverCert := newTestCert(t, pkix.Name{CommonName: "admin"}, ca, false)
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.cert)
+
+	server, api, audit := startAdminServer(t, MTLSAdminAuthenticator{}, WithAdminTLSConfig(&tls.Config{
+		Certificates: []tls.Certificate{serverCert.tlsCertificate()},
+		ClientCAs:    pool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+	}))
+	url := fmt.Sprintf("https://%s", server.Addr())
+	client := func(cert *testCert) *http.Client {
+		cfg := &tls.Config{RootCAs: pool}
+		if cert != nil {
+			cfg.Certificates = []tls.Certificate{cert.tlsCertificate()}
+		}
+		return &http.Client{Transport: &http.Transport{TLSClientConfig: cfg}}
+	}
+	reset := `{"jsonrpc":"2.0","id":1,"method":"admin_reset"}`
+
+	t.Run("rejects clients without certificates", func(t *testing.T) {
+		_, err := client(nil).Post(url, "application/json", strings.NewReader(reset))
+		require.Error(t, err)
+		require.Equal(t, 0, api.resets)
+	})
+
+	t.Run("serves calls allowed for the certificate role", func(t *testing.T) {
+		cert := newTestCert(t, pkix.Name{CommonName: "alice", OrganizationalUnit: []string{"operator"}}, ca, false)
+		status, body := postAdmin(t, client(cert), url, "", reset)
+		require.Equal(t, http.StatusOK, status)
+		var res struct {
+			Result int `json:"result"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(body), &res))
+		require.Equal(t, 1, res.Result)
+
+		record := audit.last(t)
+		require.Equal(t, "alice", record["subject"])
+		require.Equal(t, "mtls", record["scheme"])
+	})
+
+	t.Run("denies calls not allowed for the certificate role", func(t *testing.T) {
+		cert := newTestCert(t, pkix.Name{CommonName: "bob", OrganizationalUnit: []string{"monitor"}}, ca, false)
+		status, _ := postAdmin(t, client(cert), url, "", reset)
+		require.Equal(t, http.StatusForbidden, status)
+		require.Equal(t, 1, api.resets)
+	})
+}
+
+func TestAdminCLIConfigCheck(t *testing.T) {
+	valid := AdminCLIConfig{
+		Enabled:    true,
+		ListenPort: 8547,
+		JWTSecrets: []string{"operator=operator.txt", "monitor=monitor.txt"},
+		JWTMaxAge:  time.Minute,
+		Allow:      []string{"operator:admin_*"},
+	}
+	require.NoError(t, valid.Check())
+	require.NoError(t, AdminCLIConfig{}.Check())
+
+	cfg := valid
+	cfg.TLSClientCAPath = "ca.pem"
+	require.ErrorContains(t, cfg.Check(), "exactly one")
+
+	cfg = valid
+	cfg.JWTSecrets = nil
+	cfg.TLSClientCAPath = "ca.pem"
+	require.ErrorContains(t, cfg.Check(), "requires a TLS certificate")
+
+	cfg = valid
+	cfg.TLSCertPath = "cert.pem"
+	require.ErrorContains(t, cfg.Check(), "both a certificate and a key")
+
+	cfg = valid
+	cfg.JWTSecrets = []string{"jwt.txt"}
+	require.ErrorContains(t, cfg.Check(), "expected role=path")
+
+	cfg = valid
+	cfg.JWTSecrets = []string{"operator=a.txt", "operator=b.txt"}
+	require.ErrorContains(t, cfg.Check(), "duplicate admin JWT secret")
+
+	cfg = valid
+	cfg.Allow = nil
+	require.ErrorContains(t, cfg.Check(), "at least one allowed method")
+
+	cfg = valid
+	cfg.Allow = []string{"operator"}
+	require.ErrorContains(t, cfg.Check(), "invalid admin RPC policy")
+}
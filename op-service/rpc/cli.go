@@ -2,7 +2,9 @@ package rpc
 
 import (
 	"errors"
+	"fmt"
 	"math"
+	"time"
 
 	opservice "github.com/ethereum-optimism/optimism/op-service"
 	"github.com/urfave/cli"
@@ -11,6 +13,17 @@ import (
 const (
 	ListenAddrFlagName = "rpc.addr"
 	PortFlagName       = "rpc.port"
+
+	AdminEnabledFlagName     = "admin.rpc.enabled"
+	AdminListenAddrFlagName  = "admin.rpc.addr"
+	AdminPortFlagName        = "admin.rpc.port"
+	AdminJWTSecretFlagName   = "admin.rpc.jwt-secret"
+	AdminJWTMaxAgeFlagName   = "admin.rpc.jwt-max-age"
+	AdminTLSCertFlagName     = "admin.rpc.tls-cert"
+	AdminTLSKeyFlagName      = "admin.rpc.tls-key"
+	AdminTLSClientCAFlagName = "admin.rpc.tls-client-ca"
+	AdminAllowFlagName       = "admin.rpc.allow"
+	AdminAuditLogFlagName    = "admin.rpc.audit-log"
 )
 
 func CLIFlags(envPrefix string) []cli.Flag {
@@ -49,3 +62,124 @@ func ReadCLIConfig(ctx *cli.Context) CLIConfig {
 		ListenPort: ctx.GlobalInt(PortFlagName),
 	}
 }
+
+func AdminCLIFlags(envPrefix string) []cli.Flag {
+	return []cli.Flag{
+		cli.BoolFlag{
+			Name:   AdminEnabledFlagName,
+			Usage:  "Enable the authenticated admin rpc server",
+			EnvVar: opservice.PrefixEnvVar(envPrefix, "ADMIN_RPC_ENABLED"),
+		},
+		cli.StringFlag{
+			Name:   AdminListenAddrFlagName,
+			Usage:  "Admin rpc listening address",
+			Value:  "127.0.0.1",
+			EnvVar: opservice.PrefixEnvVar(envPrefix, "ADMIN_RPC_ADDR"),
+		},
+		cli.IntFlag{
+			Name:   AdminPortFlagName,
+			Usage:  "Admin rpc listening port",
+			Value:  8547,
+			EnvVar: opservice.PrefixEnvVar(envPrefix, "ADMIN_RPC_PORT"),
+		},
+		cli.StringSliceFlag{
+			Name:   AdminJWTSecretFlagName,
+			Usage:  "JWT secret authenticating the admin callers of a role, as role=path. Keys are 32 bytes, hex encoded in a file. Tokens must carry sub, role and iat claims, and be signed with the secret of their role.",
+			EnvVar: opservice.PrefixEnvVar(envPrefix, "ADMIN_RPC_JWT_SECRET"),
+		},
+		cli.DurationFlag{
+			Name:   AdminJWTMaxAgeFlagName,
+			Usage:  "Max difference between the iat claim of admin JWTs and the server time",
+			Value:  DefaultJWTMaxAge,
+			EnvVar: opservice.PrefixEnvVar(envPrefix, "ADMIN_RPC_JWT_MAX_AGE"),
+		},
+		cli.StringFlag{
+			Name:   AdminTLSCertFlagName,
+			Usage:  "Path to the TLS certificate of the admin rpc server",
+			EnvVar: opservice.PrefixEnvVar(envPrefix, "ADMIN_RPC_TLS_CERT"),
+		},
+		cli.StringFlag{
+			Name:   AdminTLSKeyFlagName,
+			Usage:  "Path to the TLS key of the admin rpc server",
+			EnvVar: opservice.PrefixEnvVar(envPrefix, "ADMIN_RPC_TLS_KEY"),
+		},
+		cli.StringFlag{
+			Name:   AdminTLSClientCAFlagName,
+			Usage:  "Path to the CA certificates authenticating admin callers with mTLS. The common name of client certificates is the caller, and their first organizational unit its role.",
+			EnvVar: opservice.PrefixEnvVar(envPrefix, "ADMIN_RPC_TLS_CLIENT_CA"),
+		},
+		cli.StringSliceFlag{
+			Name:   AdminAllowFlagName,
+			Usage:  "Admin rpc methods allowed for a role, as role:method. Methods can be namespace wildcards like admin_*, or * for all methods.",
+			EnvVar: opservice.PrefixEnvVar(envPrefix, "ADMIN_RPC_ALLOW"),
+		},
+		cli.StringFlag{
+			Name:   AdminAuditLogFlagName,
+			Usage:  "Path to the JSON audit log of admin rpc calls. Admin calls are written to the service log if empty.",
+			EnvVar: opservice.PrefixEnvVar(envPrefix, "ADMIN_RPC_AUDIT_LOG"),
+		},
+	}
+}
+
+type AdminCLIConfig struct {
+	Enabled         bool
+	ListenAddr      string
+	ListenPort      int
+	JWTSecrets      []string
+	JWTMaxAge       time.Duration
+	TLSCertPath     string
+	TLSKeyPath      string
+	TLSClientCAPath string
+	Allow           []string
+	AuditLogPath    string
+}
+
+func (c AdminCLIConfig) Check() error {
+	if !c.Enabled {
+		return nil
+	}
+
+	if c.ListenPort < 0 || c.ListenPort > math.MaxUint16 {
+		return errors.New("invalid admin RPC port")
+	}
+	if (len(c.JWTSecrets) == 0) == (c.TLSClientCAPath == "") {
+		return errors.New("admin RPC requires exactly one of a JWT secret or a TLS client CA")
+	}
+	if (c.TLSCertPath == "") != (c.TLSKeyPath == "") {
+		return errors.New("admin RPC TLS requires both a certificate and a key")
+	}
+	if c.TLSClientCAPath != "" && c.TLSCertPath == "" {
+		return errors.New("admin RPC mTLS requires a TLS certificate and key")
+	}
+	if len(c.JWTSecrets) > 0 {
+		if c.JWTMaxAge <= 0 {
+			return errors.New("admin RPC JWT max age must be positive")
+		}
+		if _, err := ParseAdminJWTSecrets(c.JWTSecrets); err != nil {
+			return err
+		}
+	}
+	if len(c.Allow) == 0 {
+		return errors.New("admin RPC requires at least one allowed method")
+	}
+	if _, err := ParseAdminPolicy(c.Allow); err != nil {
+		return fmt.Errorf("invalid admin RPC policy: %w", err)
+	}
+
+	return nil
+}
+
+func ReadAdminCLIConfig(ctx *cli.Context) AdminCLIConfig {
+	return AdminCLIConfig{
+		Enabled:         ctx.GlobalBool(AdminEnabledFlagName),
+		ListenAddr:      ctx.GlobalString(AdminListenAddrFlagName),
+		ListenPort:      ctx.GlobalInt(AdminPortFlagName),
+		JWTSecrets:      ctx.GlobalStringSlice(AdminJWTSecretFlagName),
+		JWTMaxAge:       ctx.GlobalDuration(AdminJWTMaxAgeFlagName),
+		TLSCertPath:     ctx.GlobalString(AdminTLSCertFlagName),
+		TLSKeyPath:      ctx.GlobalString(AdminTLSKeyFlagName),
+		TLSClientCAPath: ctx.GlobalString(AdminTLSClientCAFlagName),
+		Allow:           ctx.GlobalStringSlice(AdminAllowFlagName),
+		AuditLogPath:    ctx.GlobalString(AdminAuditLogFlagName),
+	}
+}
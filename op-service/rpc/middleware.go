@@ -0,0 +1,279 @@
+package rpc
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/op-service/httputil"
+	opmetrics "github.com/ethereum-optimism/optimism/op-service/metrics"
+
+	"github.com/ethereum/go-ethereum/log"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// maxInspectedBodySize bounds the size of request and response bodies
+	// inspected for JSON-RPC calls and errors. It matches the max request
+	// size of the go-ethereum RPC server. Larger bodies are passed through
+	// without being inspected.
+	maxInspectedBodySize = 5 * 1024 * 1024
+
+	// maxCapturedResponseSize bounds the size of response bodies copied to
+	// find JSON-RPC errors in them. Error responses are small, so only the
+	// errors of batches whose response exceeds it go unrecorded.
+	maxCapturedResponseSize = 64 * 1024
+
+	// maxMethodLen bounds the length of method names used as metric labels
+	// and span names.
+	maxMethodLen = 64
+
+	// UnknownMethod replaces the name of methods that don't exist or are
+	// invalid in metrics and spans, so that clients can't create arbitrary
+	// label values.
+	UnknownMethod = "unknown"
+
+	// errCodeMethodNotFound is the JSON-RPC error code of unknown methods.
+	errCodeMethodNotFound = -32601
+)
+
+var tracer = otel.Tracer("github.com/ethereum-optimism/optimism/op-service/rpc")
+
+type rpcCall struct {
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+}
+
+type rpcResponse struct {
+	ID    json.RawMessage `json:"id"`
+	Error *struct {
+		Code int `json:"code"`
+	} `json:"error"`
+}
+
+// NewAccessLogMiddleware logs every request along with the JSON-RPC methods
+// it calls, and records the latency and errors of each method with rec.
+// Requests are logged at info level if they failed, and at debug level
+// otherwise.
+//
+// The request ID sent by the client in the X-Request-Id header, or a new one,
+// is set on the response and added to the request context, where handlers
+// can read it with httputil.RequestIDFromContext.
+//
+// A server span is started for each request with the global OpenTelemetry
+// tracer provider, continuing the trace of the client if the global
+// propagator extracts one. Spans are only exported if the binary registers a
+// tracer provider.
+//
+// All calls of a batch are recorded with the latency of the whole batch.
+func NewAccessLogMiddleware(lgr log.Logger, rec opmetrics.HTTPRecorder, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reqID := httputil.RequestID(r)
+		w.Header().Set(httputil.RequestIDHeader, reqID)
+
+		ctx := httputil.WithRequestID(r.Context(), reqID)
+		ctx = otel.GetTextMapPropagator().Extract(ctx, propagation.HeaderCarrier(r.Header))
+		ctx, span := tracer.Start(ctx, "HTTP "+r.Method, trace.WithSpanKind(trace.SpanKindServer))
+		defer span.End()
+
+		calls, batch := readCalls(r)
+
+		ww := newCapturingResponseWriter(w)
+		start := time.Now()
+		next.ServeHTTP(ww, r.WithContext(ctx))
+		dur := time.Since(start)
+
+		errorCodes := ww.rpcErrorCodes()
+		methods := make([]string, 0, len(calls))
+		var failed int
+		for _, call := range calls {
+			params := &opmetrics.RPCParams{
+				Method: methodLabel(call.Method),
+			}
+			code, isErr := errorCodes[string(bytes.TrimSpace(call.ID))]
+			if isErr {
+				params.ErrorCode = code
+				if code == errCodeMethodNotFound {
+					params.Method = UnknownMethod
+				}
+			}
+			rec.RecordRPCRequest(params)
+			rec.RecordRPCRequestDuration(params, dur)
+			if isErr {
+				rec.RecordRPCError(params)
+				failed++
+			}
+			methods = append(methods, params.Method)
+		}
+
+		switch {
+		case batch:
+			span.SetName("jsonrpc batch")
+			span.SetAttributes(attribute.StringSlice("rpc.methods", methods))
+		case len(methods) == 1:
+			span.SetName("jsonrpc " + methods[0])
+			span.SetAttributes(attribute.String("rpc.method", methods[0]))
+		}
+		if len(calls) > 0 {
+			span.SetAttributes(attribute.String("rpc.system", "jsonrpc"))
+		}
+		span.SetAttributes(
+			attribute.String("request_id", reqID),
+			attribute.String("http.method", r.Method),
+			attribute.Int("http.status_code", ww.StatusCode),
+		)
+		if ww.StatusCode >= 500 || failed > 0 {
+			span.SetStatus(codes.Error, http.StatusText(ww.StatusCode))
+		}
+
+		logCtx := []interface{}{
+			"request_id", reqID,
+			"method", r.Method,
+			"path", r.URL.EscapedPath(),
+			"status", ww.StatusCode,
+			"response_len", ww.ResponseLen,
+			"duration", dur,
+			"remote_addr", r.RemoteAddr,
+			"user_agent", r.UserAgent(),
+		}
+		if len(calls) > 0 {
+			logCtx = append(logCtx, "rpc_methods", methods, "rpc_errors", failed)
+		}
+		if failed > 0 || ww.StatusCode >= 400 {
+			lgr.Info("served HTTP request", logCtx...)
+		} else {
+			lgr.Debug("served HTTP request", logCtx...)
+		}
+	})
+}
+
+// readCalls returns the JSON-RPC calls in the body of r, and whether they
+// were sent as a batch. The body is restored so that it can be read again.
+func readCalls(r *http.Request) ([]rpcCall, bool) {
+	if r.Method != http.MethodPost || r.Body == nil {
+		return nil, false
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxInspectedBodySize+1))
+	r.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(body), r.Body), r.Body}
+	if err != nil || len(body) > maxInspectedBodySize {
+		return nil, false
+	}
+
+	body = bytes.TrimSpace(body)
+	if len(body) > 0 && body[0] == '[' {
+		var calls []rpcCall
+		if err := json.Unmarshal(body, &calls); err != nil {
+			return nil, false
+		}
+		return calls, true
+	}
+	var call rpcCall
+	if err := json.Unmarshal(body, &call); err != nil || call.Method == "" {
+		return nil, false
+	}
+	return []rpcCall{call}, false
+}
+
+// methodLabel returns the method name if it is short and only made of
+// letters, digits and underscores, like the methods of go-ethereum RPC
+// services, and UnknownMethod otherwise.
+func methodLabel(method string) string {
+	if method == "" || len(method) > maxMethodLen {
+		return UnknownMethod
+	}
+	for _, c := range method {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_') {
+			return UnknownMethod
+		}
+	}
+	return method
+}
+
+// capturingResponseWriter keeps a copy of the response body, up to
+// maxCapturedResponseSize, to find JSON-RPC errors in it.
+type capturingResponseWriter struct {
+	*httputil.WrappedResponseWriter
+	body      bytes.Buffer
+	truncated bool
+	written   bool
+}
+
+func newCapturingResponseWriter(w http.ResponseWriter) *capturingResponseWriter {
+	return &capturingResponseWriter{
+		WrappedResponseWriter: httputil.NewWrappedResponseWriter(w),
+	}
+}
+
+func (w *capturingResponseWriter) Write(b []byte) (int, error) {
+	// Responses known to be too large are not copied at all.
+	if !w.written {
+		w.written = true
+		length, err := strconv.Atoi(w.Header().Get("Content-Length"))
+		if err == nil && length > maxCapturedResponseSize {
+			w.truncated = true
+		}
+	}
+	if !w.truncated {
+		if w.body.Len()+len(b) > maxCapturedResponseSize {
+			w.truncated = true
+			w.body = bytes.Buffer{}
+		} else {
+			w.body.Write(b)
+		}
+	}
+	return w.WrappedResponseWriter.Write(b)
+}
+
+// rpcErrorCodes returns the error codes of the JSON-RPC error responses, by
+// the ID of the call they answer.
+func (w *capturingResponseWriter) rpcErrorCodes() map[string]int {
+	if w.truncated || w.body.Len() == 0 {
+		return nil
+	}
+
+	body := w.body.Bytes()
+	if w.Header().Get("Content-Encoding") == "gzip" {
+		zr, err := gzip.NewReader(bytes.NewReader(body))
+		if err != nil {
+			return nil
+		}
+		body, err = io.ReadAll(io.LimitReader(zr, maxCapturedResponseSize))
+		if err != nil {
+			return nil
+		}
+	}
+
+	body = bytes.TrimSpace(body)
+	var responses []rpcResponse
+	if len(body) > 0 && body[0] == '[' {
+		if err := json.Unmarshal(body, &responses); err != nil {
+			return nil
+		}
+	} else {
+		var res rpcResponse
+		if err := json.Unmarshal(body, &res); err != nil {
+			return nil
+		}
+		responses = append(responses, res)
+	}
+
+	codes := make(map[string]int)
+	for _, res := range responses {
+		if res.Error != nil {
+			codes[string(bytes.TrimSpace(res.ID))] = res.Error.Code
+		}
+	}
+	return codes
+}
@@ -0,0 +1,167 @@
+package rpc
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/op-service/httputil"
+	opmetrics "github.com/ethereum-optimism/optimism/op-service/metrics"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+type recordedRPC struct {
+	requests []opmetrics.RPCParams
+	errors   []opmetrics.RPCParams
+}
+
+type testRPCRecorder struct {
+	opmetrics.HTTPRecorder
+	mu       sync.Mutex
+	recorded recordedRPC
+}
+
+func (r *testRPCRecorder) RecordRPCRequest(params *opmetrics.RPCParams) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.recorded.requests = append(r.recorded.requests, *params)
+}
+
+func (r *testRPCRecorder) RecordRPCRequestDuration(*opmetrics.RPCParams, time.Duration) {}
+
+func (r *testRPCRecorder) RecordRPCError(params *opmetrics.RPCParams) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.recorded.errors = append(r.recorded.errors, *params)
+}
+
+func newTestAccessLogHandler(t *testing.T, rec opmetrics.HTTPRecorder) http.Handler {
+	srv := rpc.NewServer()
+	require.NoError(t, srv.RegisterName("test", new(testAPI)))
+	hdlr := node.NewHTTPHandlerStack(srv, wildcardHosts, wildcardHosts, nil)
+	return NewAccessLogMiddleware(log.New(), rec, hdlr)
+}
+
+func postRPC(t *testing.T, hdlr http.Handler, body string, header http.Header) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	res := httptest.NewRecorder()
+	hdlr.ServeHTTP(res, req)
+	return res
+}
+
+func TestAccessLogMiddleware(t *testing.T) {
+	t.Run("records single calls", func(t *testing.T) {
+		rec := &testRPCRecorder{HTTPRecorder: opmetrics.NoopHTTPRecorder}
+		res := postRPC(t, newTestAccessLogHandler(t, rec),
+			`{"jsonrpc":"2.0","id":1,"method":"test_frobnicate","params":[2]}`, nil)
+		require.Equal(t, http.StatusOK, res.Code)
+		require.Contains(t, res.Body.String(), `"result":4`)
+		require.Equal(t, []opmetrics.RPCParams{{Method: "test_frobnicate"}}, rec.recorded.requests)
+		require.Empty(t, rec.recorded.errors)
+	})
+
+	t.Run("records each call of a batch", func(t *testing.T) {
+		rec := &testRPCRecorder{HTTPRecorder: opmetrics.NoopHTTPRecorder}
+		res := postRPC(t, newTestAccessLogHandler(t, rec), `[
+			{"jsonrpc":"2.0","id":1,"method":"test_frobnicate","params":[2]},
+			{"jsonrpc":"2.0","id":"two","method":"test_frobnicate","params":["x"]},
+			{"jsonrpc":"2.0","id":3,"method":"test_doesNotExist"}
+		]`, nil)
+		require.Equal(t, http.StatusOK, res.Code)
+		require.Equal(t, []opmetrics.RPCParams{
+			{Method: "test_frobnicate"},
+			{Method: "test_frobnicate", ErrorCode: -32602},
+			{Method: UnknownMethod, ErrorCode: errCodeMethodNotFound},
+		}, rec.recorded.requests)
+		require.Equal(t, rec.recorded.requests[1:], rec.recorded.errors)
+	})
+
+	t.Run("inspects gzipped responses", func(t *testing.T) {
+		rec := &testRPCRecorder{HTTPRecorder: opmetrics.NoopHTTPRecorder}
+		res := postRPC(t, newTestAccessLogHandler(t, rec),
+			`{"jsonrpc":"2.0","id":1,"method":"test_frobnicate","params":["x"]}`,
+			http.Header{"Accept-Encoding": {"gzip"}})
+		require.Equal(t, "gzip", res.Header().Get("Content-Encoding"))
+		require.Equal(t, []opmetrics.RPCParams{{Method: "test_frobnicate", ErrorCode: -32602}}, rec.recorded.errors)
+	})
+
+	t.Run("ignores invalid method names", func(t *testing.T) {
+		rec := &testRPCRecorder{HTTPRecorder: opmetrics.NoopHTTPRecorder}
+		postRPC(t, newTestAccessLogHandler(t, rec),
+			`{"jsonrpc":"2.0","id":1,"method":"test frobnicate\n"}`, nil)
+		require.Equal(t, []opmetrics.RPCParams{{Method: UnknownMethod, ErrorCode: errCodeMethodNotFound}}, rec.recorded.requests)
+	})
+
+	t.Run("does not inspect large responses", func(t *testing.T) {
+		rec := &testRPCRecorder{HTTPRecorder: opmetrics.NoopHTTPRecorder}
+		padding := strings.Repeat(" ", maxCapturedResponseSize)
+		hdlr := NewAccessLogMiddleware(log.New(), rec, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"error":{"code":-32000,"message":"boom"}}` + padding))
+		}))
+		postRPC(t, hdlr, `{"jsonrpc":"2.0","id":1,"method":"test_frobnicate","params":[2]}`, nil)
+		require.Equal(t, []opmetrics.RPCParams{{Method: "test_frobnicate"}}, rec.recorded.requests)
+		require.Empty(t, rec.recorded.errors)
+	})
+
+	t.Run("does not record requests without calls", func(t *testing.T) {
+		rec := &testRPCRecorder{HTTPRecorder: opmetrics.NoopHTTPRecorder}
+		res := postRPC(t, newTestAccessLogHandler(t, rec), `not json`, nil)
+		require.Equal(t, http.StatusOK, res.Code)
+		require.Empty(t, rec.recorded.requests)
+	})
+}
+
+func TestAccessLogMiddlewareRequestID(t *testing.T) {
+	var ctxID string
+	hdlr := NewAccessLogMiddleware(log.New(), opmetrics.NoopHTTPRecorder, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctxID = httputil.RequestIDFromContext(r.Context())
+	}))
+
+	t.Run("propagates the client request ID", func(t *testing.T) {
+		res := postRPC(t, hdlr, `{}`, http.Header{httputil.RequestIDHeader: {"abc-123"}})
+		require.Equal(t, "abc-123", res.Header().Get(httputil.RequestIDHeader))
+		require.Equal(t, "abc-123", ctxID)
+	})
+
+	t.Run("generates a request ID", func(t *testing.T) {
+		res := postRPC(t, hdlr, `{}`, nil)
+		id := res.Header().Get(httputil.RequestIDHeader)
+		require.Len(t, id, 32)
+		require.Equal(t, id, ctxID)
+	})
+
+	t.Run("replaces invalid request IDs", func(t *testing.T) {
+		res := postRPC(t, hdlr, `{}`, http.Header{httputil.RequestIDHeader: {"evil id"}})
+		id := res.Header().Get(httputil.RequestIDHeader)
+		require.NotEqual(t, "evil id", id)
+		require.Equal(t, id, ctxID)
+	})
+}
+
+func TestAccessLogMiddlewareLogLevel(t *testing.T) {
+	var levels []log.Lvl
+	lgr := log.New()
+	lgr.SetHandler(log.FuncHandler(func(r *log.Record) error {
+		levels = append(levels, r.Lvl)
+		return nil
+	}))
+	srv := rpc.NewServer()
+	require.NoError(t, srv.RegisterName("test", new(testAPI)))
+	hdlr := NewAccessLogMiddleware(lgr, opmetrics.NoopHTTPRecorder,
+		node.NewHTTPHandlerStack(srv, wildcardHosts, wildcardHosts, nil))
+
+	postRPC(t, hdlr, `{"jsonrpc":"2.0","id":1,"method":"test_frobnicate","params":[2]}`, nil)
+	postRPC(t, hdlr, `{"jsonrpc":"2.0","id":1,"method":"test_frobnicate","params":["x"]}`, nil)
+	require.Equal(t, []log.Lvl{log.LvlDebug, log.LvlInfo}, levels)
+}
@@ -9,7 +9,6 @@ import (
 	"strconv"
 	"time"
 
-	oplog "github.com/ethereum-optimism/optimism/op-service/log"
 	opmetrics "github.com/ethereum-optimism/optimism/op-service/metrics"
 
 	"github.com/ethereum/go-ethereum/log"
@@ -136,8 +135,8 @@ func (b *Server) Start() error {
 	mux := http.NewServeMux()
 	mux.Handle(b.rpcPath, nodeHdlr)
 	mux.Handle(b.healthzPath, b.healthzHandler)
-	metricsMW := oplog.NewLoggingMiddleware(b.log, opmetrics.NewHTTPRecordingMiddleware(b.httpRecorder, mux))
-	b.httpServer.Handler = metricsMW
+	metricsMW := opmetrics.NewHTTPRecordingMiddleware(b.httpRecorder, mux)
+	b.httpServer.Handler = NewAccessLogMiddleware(b.log, b.httpRecorder, metricsMW)
 	errCh := make(chan error, 1)
 	go func() {
 		if err := b.httpServer.ListenAndServe(); err != nil {
@@ -0,0 +1,79 @@
+package txmgr
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/urfave/cli"
+
+	opservice "github.com/ethereum-optimism/optimism/op-service"
+)
+
+const (
+	JournalPathFlagName  = "txmgr.journal"
+	MaxGasFeeCapFlagName = "pricing.max-fee-cap"
+	DailyBudgetFlagName  = "pricing.daily-budget"
+	MaxUrgencyFlagName   = "pricing.max-urgency"
+	PriceBumpFlagName    = "pricing.price-bump"
+)
+
+func CLIFlags(envPrefix string) []cli.Flag {
+	return []cli.Flag{
+		cli.StringFlag{
+			Name:   JournalPathFlagName,
+			Usage:  "File to keep the pending transactions and the fees spent in the daily budget in, to recover them after a restart. Disabled if empty",
+			EnvVar: opservice.PrefixEnvVar(envPrefix, "TXMGR_JOURNAL"),
+		},
+		cli.Uint64Flag{
+			Name:   MaxGasFeeCapFlagName,
+			Usage:  "Maximum gas fee cap of transactions, in gwei. 0 for no limit",
+			EnvVar: opservice.PrefixEnvVar(envPrefix, "PRICING_MAX_FEE_CAP"),
+		},
+		cli.Uint64Flag{
+			Name:   DailyBudgetFlagName,
+			Usage:  "Maximum fees to spend in any 24 hour period, in gwei. 0 for no limit",
+			EnvVar: opservice.PrefixEnvVar(envPrefix, "PRICING_DAILY_BUDGET"),
+		},
+		cli.Float64Flag{
+			Name:   MaxUrgencyFlagName,
+			Usage:  "Multiplier of the suggested gas tip when the deadline of a transaction is reached",
+			Value:  2,
+			EnvVar: opservice.PrefixEnvVar(envPrefix, "PRICING_MAX_URGENCY"),
+		},
+		cli.Uint64Flag{
+			Name:   PriceBumpFlagName,
+			Usage:  "Percentage by which replacement transactions raise the gas price, at least 10",
+			Value:  MinPriceBump,
+			EnvVar: opservice.PrefixEnvVar(envPrefix, "PRICING_PRICE_BUMP"),
+		},
+	}
+}
+
+type CLIConfig struct {
+	JournalPath string
+	Pricing     PricingConfig
+}
+
+func (c CLIConfig) Check() error {
+	return c.Pricing.Check()
+}
+
+func ReadCLIConfig(ctx *cli.Context) CLIConfig {
+	return CLIConfig{
+		JournalPath: ctx.GlobalString(JournalPathFlagName),
+		Pricing: PricingConfig{
+			MaxGasFeeCap: gweiToWei(ctx.GlobalUint64(MaxGasFeeCapFlagName)),
+			DailyBudget:  gweiToWei(ctx.GlobalUint64(DailyBudgetFlagName)),
+			MaxUrgency:   ctx.GlobalFloat64(MaxUrgencyFlagName),
+			PriceBump:    ctx.GlobalUint64(PriceBumpFlagName),
+		},
+	}
+}
+
+// gweiToWei converts a gwei amount to wei, with 0 as no value.
+func gweiToWei(gwei uint64) *big.Int {
+	if gwei == 0 {
+		return nil
+	}
+	return new(big.Int).Mul(new(big.Int).SetUint64(gwei), big.NewInt(params.GWei))
+}
@@ -0,0 +1,156 @@
+package txmgr
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// journal is the content of the journal file: the pending transactions of the Manager,
+// and the fees spent in the budget window of its PricingStrategy.
+type journal struct {
+	Pending []journalEntry `json:"pending"`
+	Spends  []journalSpend `json:"spends,omitempty"`
+}
+
+// journalSpend is a FeeSpend, as kept in the journal.
+type journalSpend struct {
+	Time time.Time    `json:"time"`
+	Fees *hexutil.Big `json:"fees"`
+}
+
+func newJournalSpends(spends []FeeSpend) []journalSpend {
+	out := make([]journalSpend, 0, len(spends))
+	for _, s := range spends {
+		out = append(out, journalSpend{Time: s.Time, Fees: (*hexutil.Big)(s.Fees)})
+	}
+	return out
+}
+
+func (j *journal) feeSpends() []FeeSpend {
+	out := make([]FeeSpend, 0, len(j.Spends))
+	for _, s := range j.Spends {
+		if s.Fees == nil {
+			continue
+		}
+		out = append(out, FeeSpend{Time: s.Time, Fees: (*big.Int)(s.Fees)})
+	}
+	return out
+}
+
+// journalTx is a signed transaction of a journaled transaction.
+type journalTx struct {
+	Tx     hexutil.Bytes `json:"tx"`
+	Cancel bool          `json:"cancel,omitempty"`
+}
+
+// journalEntry is a pending transaction of the Manager, as kept in the journal.
+type journalEntry struct {
+	Nonce    uint64          `json:"nonce"`
+	To       *common.Address `json:"to,omitempty"`
+	TxData   hexutil.Bytes   `json:"txData,omitempty"`
+	Value    *hexutil.Big    `json:"value,omitempty"`
+	GasLimit uint64          `json:"gasLimit"`
+	Start    time.Time       `json:"urgencyStart"`
+	Deadline time.Time       `json:"urgencyDeadline"`
+	Canceled bool            `json:"canceled,omitempty"`
+	Txs      []journalTx     `json:"txs"`
+}
+
+func newJournalEntry(q *QueuedTx) (journalEntry, error) {
+	e := journalEntry{
+		Nonce:    q.nonce,
+		To:       q.candidate.To,
+		TxData:   q.candidate.TxData,
+		Value:    (*hexutil.Big)(q.candidate.Value),
+		GasLimit: q.candidate.GasLimit,
+		Start:    q.candidate.Urgency.Start,
+		Deadline: q.candidate.Urgency.Deadline,
+		Canceled: q.canceled,
+	}
+	for _, s := range q.sent {
+		data, err := s.tx.MarshalBinary()
+		if err != nil {
+			return journalEntry{}, err
+		}
+		e.Txs = append(e.Txs, journalTx{Tx: data, Cancel: s.cancel})
+	}
+	return e, nil
+}
+
+// queuedTx restores the queued transaction of the entry. A requested cancellation that
+// was not sent yet is requested again.
+func (e *journalEntry) queuedTx() (*QueuedTx, error) {
+	q := newQueuedTx(e.Nonce, TxCandidate{
+		To:       e.To,
+		TxData:   e.TxData,
+		Value:    (*big.Int)(e.Value),
+		GasLimit: e.GasLimit,
+		Urgency:  Urgency{Start: e.Start, Deadline: e.Deadline},
+	})
+	q.canceled = e.Canceled
+	for _, jtx := range e.Txs {
+		var tx types.Transaction
+		if err := tx.UnmarshalBinary(jtx.Tx); err != nil {
+			return nil, err
+		}
+		if tx.Nonce() != e.Nonce {
+			return nil, fmt.Errorf("tx %s has nonce %d", tx.Hash(), tx.Nonce())
+		}
+		q.sent = append(q.sent, sentTx{tx: &tx, cancel: jtx.Cancel})
+	}
+	if q.canceled && len(q.sent) > 0 && !q.sent[len(q.sent)-1].cancel {
+		q.cancelReq <- struct{}{}
+	}
+	return q, nil
+}
+
+// readJournal reads the journal from the file at path. A missing journal is empty.
+func readJournal(path string) (journal, error) {
+	if path == "" {
+		return journal{}, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return journal{}, nil
+	} else if err != nil {
+		return journal{}, fmt.Errorf("failed to read tx journal: %w", err)
+	}
+	var j journal
+	if err := json.Unmarshal(data, &j); err != nil {
+		return journal{}, fmt.Errorf("failed to decode tx journal: %w", err)
+	}
+	return j, nil
+}
+
+// writeJournal replaces the journal at path, with the pending entries ordered by nonce.
+// The journal is written to a temporary file first, to not corrupt it when interrupted.
+func writeJournal(path string, j journal) error {
+	sort.Slice(j.Pending, func(a, b int) bool { return j.Pending[a].Nonce < j.Pending[b].Nonce })
+	data, err := json.MarshalIndent(j, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
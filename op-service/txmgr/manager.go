@@ -0,0 +1,571 @@
+package txmgr
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+var (
+	// ErrCanceled is returned when a queued transaction was replaced by its cancellation.
+	ErrCanceled = errors.New("transaction canceled")
+
+	// ErrNonceUsed is returned when the nonce of a queued transaction was used by a transaction
+	// that is unknown to the Manager.
+	ErrNonceUsed = errors.New("nonce used by unknown transaction")
+
+	// ErrNotStarted is returned when a transaction is queued before the Manager is started.
+	ErrNotStarted = errors.New("transaction manager not started")
+)
+
+// ETHBackend is the L1 client used by the Manager to craft, publish and track transactions.
+type ETHBackend interface {
+	ReceiptSource
+	GasPriceSource
+
+	// NonceAt returns the nonce of the account at the given block, or the latest block if nil.
+	NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error)
+
+	// PendingNonceAt returns the nonce of the account including the pending transactions.
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+
+	// EstimateGas estimates the gas limit of the given call.
+	EstimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64, error)
+
+	// SendTransaction publishes the signed transaction.
+	SendTransaction(ctx context.Context, tx *types.Transaction) error
+}
+
+// SignerFn signs a transaction of the from account.
+type SignerFn func(ctx context.Context, from common.Address, tx *types.Transaction) (*types.Transaction, error)
+
+// PrivateKeySignerFn returns a SignerFn that signs transactions of the given chain with key.
+func PrivateKeySignerFn(key *ecdsa.PrivateKey, chainID *big.Int) SignerFn {
+	signer := types.LatestSignerForChainID(chainID)
+	return func(_ context.Context, _ common.Address, tx *types.Transaction) (*types.Transaction, error) {
+		return types.SignTx(tx, signer, key)
+	}
+}
+
+// ManagerConfig houses the parameters of a Manager.
+type ManagerConfig struct {
+	Config
+
+	// ChainID is the chain the transactions are sent to.
+	ChainID *big.Int
+
+	// From is the account that sends the transactions, and of which the Manager manages the nonce.
+	From common.Address
+
+	// Signer signs the transactions of the From account.
+	Signer SignerFn
+
+	// JournalPath is the file in which the pending transactions and the fees spent in the budget window
+	// are kept, to recover them after a restart. Nothing is recovered if empty.
+	JournalPath string
+
+	// LegacyTxs signs legacy transactions, for chains without dynamic fee transactions.
+	// Their gas price is the fee cap of the pricing strategy.
+	LegacyTxs bool
+}
+
+// TxCandidate is a transaction to be sent by the Manager, which assigns its nonce and gas price.
+type TxCandidate struct {
+	// To is the recipient of the transaction, or nil for a contract creation.
+	To *common.Address
+	// TxData is the calldata of the transaction.
+	TxData []byte
+	// Value is the amount of wei sent with the transaction, if any.
+	Value *big.Int
+	// GasLimit of the transaction, estimated if 0.
+	GasLimit uint64
+	// Urgency is the inclusion window of the transaction, for its pricing.
+	Urgency Urgency
+}
+
+// sentTx is a signed transaction of a queued transaction, which may be a cancellation.
+type sentTx struct {
+	tx     *types.Transaction
+	cancel bool
+}
+
+// QueuedTx is a transaction queued in the Manager at a fixed nonce.
+// It is replaced at a higher gas price until one of its signed transactions confirms.
+type QueuedTx struct {
+	nonce     uint64
+	candidate TxCandidate
+
+	// sent and canceled are guarded by the mutex of the Manager
+	sent     []sentTx
+	canceled bool
+
+	cancelReq chan struct{}
+	// limited holds the latest error of a replacement that was limited by the pricing strategy,
+	// until it is returned by Wait, or a later replacement succeeds.
+	limited chan error
+	done    chan struct{}
+	receipt *types.Receipt
+	err     error
+}
+
+func newQueuedTx(nonce uint64, candidate TxCandidate) *QueuedTx {
+	return &QueuedTx{
+		nonce:     nonce,
+		candidate: candidate,
+		cancelReq: make(chan struct{}, 1),
+		limited:   make(chan error, 1),
+		done:      make(chan struct{}),
+	}
+}
+
+// Nonce returns the nonce of the queued transaction.
+func (q *QueuedTx) Nonce() uint64 {
+	return q.nonce
+}
+
+// Wait blocks until a transaction at the nonce confirms, and returns its receipt.
+// ErrReverted is returned with the receipt if it reverted,
+// and ErrCanceled with the receipt of the cancellation if the transaction was canceled.
+// If the transaction cannot be replaced at a bumped gas price, as the replacement exceeds the limits
+// of the pricing strategy, Wait returns ErrReplacementLimited or ErrBudgetExhausted without a receipt.
+// The transaction is still pending then: its replacement is retried, and the caller can keep waiting,
+// or cancel the transaction.
+func (q *QueuedTx) Wait(ctx context.Context) (*types.Receipt, error) {
+	select {
+	case <-q.done:
+		return q.receipt, q.err
+	case err := <-q.limited:
+		return nil, err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// setLimited notifies the waiting caller of a replacement that is limited by the pricing strategy,
+// or clears the notification if err is nil.
+func (q *QueuedTx) setLimited(err error) {
+	select {
+	case <-q.limited:
+	default:
+	}
+	if err != nil {
+		q.limited <- err
+	}
+}
+
+// Manager sends transactions of a single account. It assigns the nonces of queued transactions,
+// replaces transactions that are not mined in time at a bumped gas price, cancels transactions on request,
+// and recovers the pending transactions from its journal after a restart.
+type Manager struct {
+	cfg     ManagerConfig
+	backend ETHBackend
+	pricer  PricingStrategy
+	l       log.Logger
+
+	mu      sync.Mutex
+	started bool
+	nonce   uint64
+	pending map[uint64]*QueuedTx
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewManager creates a Manager, which sends transactions after it is started.
+func NewManager(cfg ManagerConfig, backend ETHBackend, pricer PricingStrategy) *Manager {
+	if cfg.NumConfirmations == 0 {
+		panic("txmgr: NumConfirmations cannot be zero")
+	}
+	if cfg.Log == nil {
+		cfg.Log = log.New()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Manager{
+		cfg:     cfg,
+		backend: backend,
+		pricer:  pricer,
+		l:       cfg.Log,
+		pending: make(map[uint64]*QueuedTx),
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+}
+
+// Start loads the nonce of the account and resumes the pending transactions of the journal.
+// Pending transactions of the account that are not in the journal, e.g. of a previous instance
+// without journal, are canceled so they do not block the transactions of this instance.
+func (m *Manager) Start(ctx context.Context) error {
+	confirmed, err := m.backend.NonceAt(ctx, m.cfg.From, nil)
+	if err != nil {
+		return fmt.Errorf("failed to fetch nonce: %w", err)
+	}
+	pendingNonce, err := m.backend.PendingNonceAt(ctx, m.cfg.From)
+	if err != nil {
+		return fmt.Errorf("failed to fetch pending nonce: %w", err)
+	}
+	j, err := readJournal(m.cfg.JournalPath)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.started {
+		return errors.New("transaction manager already started")
+	}
+	m.pricer.RestoreSpends(j.feeSpends())
+	m.nonce = confirmed
+	for _, e := range j.Pending {
+		if e.Nonce < confirmed {
+			m.l.Info(m.cfg.Name+" journaled transaction confirmed while stopped", "nonce", e.Nonce)
+			continue
+		}
+		q, err := e.queuedTx()
+		if err != nil {
+			return fmt.Errorf("invalid journal entry at nonce %d: %w", e.Nonce, err)
+		}
+		m.pending[q.nonce] = q
+		if q.nonce >= m.nonce {
+			m.nonce = q.nonce + 1
+		}
+		m.l.Info(m.cfg.Name+" resuming journaled transaction", "nonce", q.nonce, "sent", len(q.sent))
+	}
+	if pendingNonce > m.nonce {
+		m.nonce = pendingNonce
+	}
+	for nonce := confirmed; nonce < m.nonce; nonce++ {
+		if _, ok := m.pending[nonce]; ok {
+			continue
+		}
+		m.l.Warn(m.cfg.Name+" canceling unknown pending transaction", "nonce", nonce)
+		q := newQueuedTx(nonce, TxCandidate{})
+		q.canceled = true
+		m.pending[nonce] = q
+	}
+	m.started = true
+	m.writeJournal()
+	for _, q := range m.pending {
+		m.wg.Add(1)
+		go m.track(q)
+	}
+	return nil
+}
+
+// Close stops the tracking of the pending transactions. They are resumed from the journal on the next start.
+func (m *Manager) Close() {
+	m.cancel()
+	m.wg.Wait()
+}
+
+// Queue prices and signs the candidate at the next nonce of the account, and publishes it.
+// The returned QueuedTx is replaced at a bumped gas price until it confirms.
+func (m *Manager) Queue(ctx context.Context, candidate TxCandidate) (*QueuedTx, error) {
+	if candidate.GasLimit == 0 {
+		gas, err := m.backend.EstimateGas(ctx, ethereum.CallMsg{
+			From:  m.cfg.From,
+			To:    candidate.To,
+			Value: candidate.Value,
+			Data:  candidate.TxData,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to estimate gas: %w", err)
+		}
+		candidate.GasLimit = gas
+	}
+	price, err := m.pricer.Price(ctx, candidate.GasLimit, candidate.Urgency)
+	if err != nil {
+		return nil, fmt.Errorf("failed to price tx: %w", err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.started {
+		return nil, ErrNotStarted
+	}
+	q := newQueuedTx(m.nonce, candidate)
+	tx, err := m.sign(ctx, q, price, false)
+	if err != nil {
+		return nil, err
+	}
+	m.nonce++
+	q.sent = append(q.sent, sentTx{tx: tx})
+	m.pending[q.nonce] = q
+	m.writeJournal()
+	m.wg.Add(1)
+	go m.track(q)
+	return q, nil
+}
+
+// Send queues the candidate, and waits for it to confirm. See QueuedTx.Wait for the errors of a transaction
+// that is still pending when Send returns; use Queue to keep track of such a transaction.
+func (m *Manager) Send(ctx context.Context, candidate TxCandidate) (*types.Receipt, error) {
+	q, err := m.Queue(ctx, candidate)
+	if err != nil {
+		return nil, err
+	}
+	return q.Wait(ctx)
+}
+
+// Cancel replaces the pending transaction at the given nonce with a self-transfer of zero value.
+// The transaction may still confirm if it is mined before its cancellation.
+func (m *Manager) Cancel(nonce uint64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	q, ok := m.pending[nonce]
+	if !ok {
+		return fmt.Errorf("no pending transaction at nonce %d", nonce)
+	}
+	if !q.canceled {
+		q.canceled = true
+		m.writeJournal()
+		q.cancelReq <- struct{}{}
+	}
+	return nil
+}
+
+// Pending returns the nonces of the pending transactions.
+func (m *Manager) Pending() []uint64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	nonces := make([]uint64, 0, len(m.pending))
+	for nonce := range m.pending {
+		nonces = append(nonces, nonce)
+	}
+	return nonces
+}
+
+// sign signs the queued transaction at the given price, or its cancellation.
+func (m *Manager) sign(ctx context.Context, q *QueuedTx, price TxPrice, cancel bool) (*types.Transaction, error) {
+	to, value, data, gas := q.candidate.To, q.candidate.Value, q.candidate.TxData, q.candidate.GasLimit
+	if cancel {
+		from := m.cfg.From
+		to, value, data, gas = &from, nil, nil, params.TxGas
+	}
+	var rawTx types.TxData
+	if m.cfg.LegacyTxs {
+		rawTx = &types.LegacyTx{
+			Nonce:    q.nonce,
+			GasPrice: price.GasFeeCap,
+			Gas:      gas,
+			To:       to,
+			Value:    value,
+			Data:     data,
+		}
+	} else {
+		rawTx = &types.DynamicFeeTx{
+			ChainID:   m.cfg.ChainID,
+			Nonce:     q.nonce,
+			GasTipCap: price.GasTipCap,
+			GasFeeCap: price.GasFeeCap,
+			Gas:       gas,
+			To:        to,
+			Value:     value,
+			Data:      data,
+		}
+	}
+	tx, err := m.cfg.Signer(ctx, m.cfg.From, types.NewTx(rawTx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign tx: %w", err)
+	}
+	return tx, nil
+}
+
+// track publishes the queued transaction, and replaces it until it confirms.
+func (m *Manager) track(q *QueuedTx) {
+	defer m.wg.Done()
+	name := m.cfg.Name
+
+	sendState := NewSendState(m.cfg.SafeAbortNonceTooLowCount)
+	m.mu.Lock()
+	var last *sentTx
+	if len(q.sent) > 0 {
+		last = &q.sent[len(q.sent)-1]
+	}
+	m.mu.Unlock()
+	if last != nil {
+		m.publish(q, last.tx, sendState)
+	} else {
+		// recovered cancellations of unknown transactions have nothing to replace yet
+		m.replace(q, sendState)
+	}
+
+	resubmit := time.NewTicker(m.cfg.ResubmissionTimeout)
+	defer resubmit.Stop()
+	receipts := time.NewTicker(m.cfg.ReceiptQueryInterval)
+	defer receipts.Stop()
+
+	for {
+		select {
+		case <-m.ctx.Done():
+			return
+		case <-q.cancelReq:
+			m.l.Info(name+" canceling transaction", "nonce", q.nonce)
+			m.replace(q, sendState)
+		case <-resubmit.C:
+			// Avoid replacing a transaction that is waiting for confirmation.
+			if sendState.IsWaitingForConfirmation() {
+				continue
+			}
+			m.replace(q, sendState)
+		case <-receipts.C:
+			receipt, cancel := m.confirmedReceipt(q, sendState)
+			if receipt != nil {
+				var err error
+				if cancel {
+					err = ErrCanceled
+				} else if receipt.Status == types.ReceiptStatusFailed {
+					err = ErrReverted
+				}
+				m.finish(q, receipt, err)
+				return
+			}
+			if sendState.ShouldAbortImmediately() {
+				m.l.Warn(name+" nonce used by unknown transaction", "nonce", q.nonce)
+				m.finish(q, nil, ErrNonceUsed)
+				return
+			}
+		}
+	}
+}
+
+// replace signs and publishes a replacement of the queued transaction at a bumped gas price,
+// or its cancellation if requested.
+func (m *Manager) replace(q *QueuedTx, sendState *SendState) {
+	name := m.cfg.Name
+	m.mu.Lock()
+	cancel := q.canceled
+	var last *types.Transaction
+	if len(q.sent) > 0 {
+		last = q.sent[len(q.sent)-1].tx
+	}
+	m.mu.Unlock()
+
+	var (
+		price TxPrice
+		err   error
+	)
+	if last == nil {
+		price, err = m.pricer.Price(m.ctx, params.TxGas, q.candidate.Urgency)
+	} else {
+		price, err = m.pricer.Bump(m.ctx, last, q.candidate.Urgency)
+	}
+	if errors.Is(err, ErrReplacementLimited) || errors.Is(err, ErrBudgetExhausted) {
+		m.l.Warn(name+" replacement tx limited by pricing", "nonce", q.nonce, "canceled", cancel, "err", err)
+		q.setLimited(err)
+		return
+	} else if err != nil {
+		m.l.Warn(name+" unable to price replacement tx", "nonce", q.nonce, "err", err)
+		return
+	}
+	q.setLimited(nil)
+	tx, err := m.sign(m.ctx, q, price, cancel)
+	if err != nil {
+		m.l.Error(name+" unable to sign replacement tx", "nonce", q.nonce, "err", err)
+		return
+	}
+
+	m.mu.Lock()
+	q.sent = append(q.sent, sentTx{tx: tx, cancel: cancel})
+	m.writeJournal()
+	m.mu.Unlock()
+	m.publish(q, tx, sendState)
+}
+
+// publish sends the signed transaction of the queued transaction.
+func (m *Manager) publish(q *QueuedTx, tx *types.Transaction, sendState *SendState) {
+	name := m.cfg.Name
+	m.l.Info(name+" publishing transaction", "txHash", tx.Hash(), "nonce", tx.Nonce(),
+		"gasTipCap", tx.GasTipCap(), "gasFeeCap", tx.GasFeeCap())
+	err := m.backend.SendTransaction(m.ctx, tx)
+	sendState.ProcessSendError(err)
+	if err != nil {
+		m.l.Warn(name+" unable to publish transaction", "txHash", tx.Hash(), "nonce", tx.Nonce(), "err", err)
+	}
+}
+
+// confirmedReceipt returns the receipt of any of the signed transactions of the queued transaction,
+// once it has enough confirmations, and whether it is the receipt of a cancellation.
+func (m *Manager) confirmedReceipt(q *QueuedTx, sendState *SendState) (*types.Receipt, bool) {
+	m.mu.Lock()
+	sent := append([]sentTx(nil), q.sent...)
+	m.mu.Unlock()
+
+	for i := len(sent) - 1; i >= 0; i-- {
+		txHash := sent[i].tx.Hash()
+		receipt, err := m.backend.TransactionReceipt(m.ctx, txHash)
+		if errors.Is(err, ethereum.NotFound) || (err == nil && receipt == nil) {
+			sendState.TxNotMined(txHash)
+			continue
+		} else if err != nil {
+			m.l.Trace(m.cfg.Name+" receipt retrieval failed", "hash", txHash, "err", err)
+			continue
+		}
+		sendState.TxMined(txHash)
+
+		tipHeight, err := m.backend.BlockNumber(m.ctx)
+		if err != nil {
+			m.l.Error(m.cfg.Name+" unable to fetch block number", "err", err)
+			return nil, false
+		}
+		// See WaitMined for the confirmation condition.
+		if receipt.BlockNumber.Uint64()+m.cfg.NumConfirmations <= tipHeight+1 {
+			return receipt, sent[i].cancel
+		}
+		return nil, false
+	}
+	return nil, false
+}
+
+// finish resolves the queued transaction, and removes it from the pending transactions.
+// The fees of a confirmed transaction are accounted for before the journal is updated, so they are journaled
+// together with the removal of the transaction.
+func (m *Manager) finish(q *QueuedTx, receipt *types.Receipt, err error) {
+	if receipt != nil {
+		m.l.Info(m.cfg.Name+" transaction confirmed", "txHash", receipt.TxHash, "nonce", q.nonce,
+			"reverted", receipt.Status == types.ReceiptStatusFailed, "canceled", errors.Is(err, ErrCanceled))
+		if err := m.pricer.RecordReceipt(m.ctx, receipt); err != nil {
+			m.l.Warn(m.cfg.Name+" unable to account tx fees", "err", err)
+		}
+	}
+
+	m.mu.Lock()
+	delete(m.pending, q.nonce)
+	m.writeJournal()
+	m.mu.Unlock()
+
+	q.receipt, q.err = receipt, err
+	close(q.done)
+}
+
+// writeJournal writes the pending transactions and the spent fees to the journal, if any. The mutex must be held.
+func (m *Manager) writeJournal() {
+	if m.cfg.JournalPath == "" {
+		return
+	}
+	j := journal{
+		Pending: make([]journalEntry, 0, len(m.pending)),
+		Spends:  newJournalSpends(m.pricer.Spends()),
+	}
+	for _, q := range m.pending {
+		e, err := newJournalEntry(q)
+		if err != nil {
+			m.l.Error(m.cfg.Name+" unable to journal transaction", "nonce", q.nonce, "err", err)
+			continue
+		}
+		j.Pending = append(j.Pending, e)
+	}
+	if err := writeJournal(m.cfg.JournalPath, j); err != nil {
+		m.l.Error(m.cfg.Name+" unable to write journal", "err", err)
+	}
+}
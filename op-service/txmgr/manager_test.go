@@ -0,0 +1,700 @@
+package txmgr_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-service/txmgr"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// mockChain is a txmgr.ETHBackend with a transaction pool of a single account, that mines
+// the pooled transaction at the next nonce if it is accepted by the mine filter.
+type mockChain struct {
+	mu sync.Mutex
+
+	height uint64
+	nonce  uint64
+	pool   map[uint64]*types.Transaction
+	mined  map[common.Hash]*types.Receipt
+	txs    map[common.Hash]*types.Transaction
+	// sent are the published transactions, in order
+	sent []*types.Transaction
+
+	// accept decides whether a pooled transaction is mined
+	accept func(tx *types.Transaction) bool
+	// sendErr fails the publication of a transaction if it returns an error
+	sendErr func(tx *types.Transaction) error
+}
+
+func newMockChain(nonce uint64) *mockChain {
+	return &mockChain{
+		nonce:   nonce,
+		pool:    make(map[uint64]*types.Transaction),
+		mined:   make(map[common.Hash]*types.Receipt),
+		txs:     make(map[common.Hash]*types.Transaction),
+		accept:  func(*types.Transaction) bool { return true },
+		sendErr: func(*types.Transaction) error { return nil },
+	}
+}
+
+func (c *mockChain) setAccept(accept func(tx *types.Transaction) bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.accept = accept
+}
+
+// mine mines a block with the pooled transactions that are accepted, in nonce order.
+func (c *mockChain) mine() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.height++
+	for {
+		tx, ok := c.pool[c.nonce]
+		if !ok || !c.accept(tx) {
+			return
+		}
+		delete(c.pool, c.nonce)
+		c.nonce++
+		c.mined[tx.Hash()] = &types.Receipt{
+			TxHash:      tx.Hash(),
+			Status:      types.ReceiptStatusSuccessful,
+			GasUsed:     tx.Gas(),
+			BlockNumber: new(big.Int).SetUint64(c.height),
+		}
+	}
+}
+
+func (c *mockChain) setSendErr(sendErr func(tx *types.Transaction) error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sendErr = sendErr
+}
+
+// mineTx mines a block with the given published transaction, e.g. a transaction that
+// was replaced in the pool, but was still mined by another node.
+func (c *mockChain) mineTx(tx *types.Transaction) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.height++
+	delete(c.pool, tx.Nonce())
+	c.nonce = tx.Nonce() + 1
+	c.mined[tx.Hash()] = &types.Receipt{
+		TxHash:      tx.Hash(),
+		Status:      types.ReceiptStatusSuccessful,
+		GasUsed:     tx.Gas(),
+		BlockNumber: new(big.Int).SetUint64(c.height),
+	}
+}
+
+// sentTxs returns the published transactions, in order.
+func (c *mockChain) sentTxs() []*types.Transaction {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]*types.Transaction(nil), c.sent...)
+}
+
+// autoMine mines a block every interval, until the test ends.
+func (c *mockChain) autoMine(t *testing.T, interval time.Duration) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				c.mine()
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+func (c *mockChain) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.sendErr(tx); err != nil {
+		return err
+	}
+	if tx.Nonce() < c.nonce {
+		return core.ErrNonceTooLow
+	}
+	if prev, ok := c.pool[tx.Nonce()]; ok && prev.Hash() != tx.Hash() {
+		minTip := new(big.Int).Div(new(big.Int).Mul(prev.GasTipCap(), big.NewInt(110)), big.NewInt(100))
+		minFeeCap := new(big.Int).Div(new(big.Int).Mul(prev.GasFeeCap(), big.NewInt(110)), big.NewInt(100))
+		if tx.GasTipCap().Cmp(minTip) < 0 || tx.GasFeeCap().Cmp(minFeeCap) < 0 {
+			return core.ErrReplaceUnderpriced
+		}
+	}
+	c.pool[tx.Nonce()] = tx
+	c.txs[tx.Hash()] = tx
+	c.sent = append(c.sent, tx)
+	return nil
+}
+
+func (c *mockChain) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if r, ok := c.mined[txHash]; ok {
+		return r, nil
+	}
+	return nil, ethereum.NotFound
+}
+
+func (c *mockChain) TransactionByHash(ctx context.Context, txHash common.Hash) (*types.Transaction, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if tx, ok := c.txs[txHash]; ok {
+		_, mined := c.mined[txHash]
+		return tx, !mined, nil
+	}
+	return nil, false, ethereum.NotFound
+}
+
+func (c *mockChain) BlockNumber(ctx context.Context) (uint64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.height, nil
+}
+
+func (c *mockChain) NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.nonce, nil
+}
+
+func (c *mockChain) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	nonce := c.nonce
+	for {
+		if _, ok := c.pool[nonce]; !ok {
+			return nonce, nil
+		}
+		nonce++
+	}
+}
+
+func (c *mockChain) EstimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64, error) {
+	return 21000 + 16*uint64(len(msg.Data)), nil
+}
+
+func (c *mockChain) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	return big.NewInt(10), nil
+}
+
+func (c *mockChain) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	return &types.Header{BaseFee: big.NewInt(100)}, nil
+}
+
+func newTestManager(t *testing.T, chain *mockChain, journalPath string) (*txmgr.Manager, common.Address) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	return newTestManagerWithKey(t, chain, journalPath, key), crypto.PubkeyToAddress(key.PublicKey)
+}
+
+func newTestManagerWithKey(t *testing.T, chain *mockChain, journalPath string, key *ecdsa.PrivateKey) *txmgr.Manager {
+	pricer := txmgr.NewFeePricer(log.New(), txmgr.PricingConfig{}, chain, nil)
+	return newTestManagerWithPricer(t, chain, journalPath, key, pricer)
+}
+
+func newTestManagerWithPricer(t *testing.T, chain *mockChain, journalPath string, key *ecdsa.PrivateKey, pricer txmgr.PricingStrategy) *txmgr.Manager {
+	return txmgr.NewManager(testManagerConfig(key, journalPath), chain, pricer)
+}
+
+func testManagerConfig(key *ecdsa.PrivateKey, journalPath string) txmgr.ManagerConfig {
+	chainID := big.NewInt(900)
+	return txmgr.ManagerConfig{
+		Config: txmgr.Config{
+			Log:                       log.New(),
+			Name:                      "TEST",
+			ResubmissionTimeout:       50 * time.Millisecond,
+			ReceiptQueryInterval:      5 * time.Millisecond,
+			NumConfirmations:          1,
+			SafeAbortNonceTooLowCount: 3,
+		},
+		ChainID:     chainID,
+		From:        crypto.PubkeyToAddress(key.PublicKey),
+		Signer:      txmgr.PrivateKeySignerFn(key, chainID),
+		JournalPath: journalPath,
+	}
+}
+
+func TestManagerQueuesNonces(t *testing.T) {
+	chain := newMockChain(5)
+	chain.autoMine(t, 10*time.Millisecond)
+	m, _ := newTestManager(t, chain, "")
+	defer m.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err := m.Queue(ctx, txmgr.TxCandidate{To: &common.Address{0x42}})
+	require.ErrorIs(t, err, txmgr.ErrNotStarted)
+	require.NoError(t, m.Start(ctx))
+
+	var queued []*txmgr.QueuedTx
+	for i := 0; i < 3; i++ {
+		q, err := m.Queue(ctx, txmgr.TxCandidate{To: &common.Address{0x42}, TxData: []byte{byte(i)}})
+		require.NoError(t, err)
+		require.Equal(t, uint64(5+i), q.Nonce())
+		queued = append(queued, q)
+	}
+	for _, q := range queued {
+		receipt, err := q.Wait(ctx)
+		require.NoError(t, err)
+		require.NotNil(t, receipt)
+	}
+	require.Empty(t, m.Pending())
+}
+
+func TestManagerReplacesUnderpriced(t *testing.T) {
+	chain := newMockChain(0)
+	// only mine the tx once the fee cap has been bumped twice
+	chain.setAccept(func(tx *types.Transaction) bool {
+		return tx.GasFeeCap().Cmp(big.NewInt(210*121/100)) >= 0
+	})
+	chain.autoMine(t, 10*time.Millisecond)
+	m, _ := newTestManager(t, chain, "")
+	defer m.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, m.Start(ctx))
+	receipt, err := m.Send(ctx, txmgr.TxCandidate{To: &common.Address{0x42}, GasLimit: 50000})
+	require.NoError(t, err)
+	tx, _, err := chain.TransactionByHash(ctx, receipt.TxHash)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(255), tx.GasFeeCap(), "bumped twice by 10%")
+	require.Equal(t, uint64(50000), tx.Gas())
+}
+
+func TestManagerLegacyTxs(t *testing.T) {
+	chain := newMockChain(0)
+	chain.autoMine(t, 10*time.Millisecond)
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	chainID := big.NewInt(900)
+	m := txmgr.NewManager(txmgr.ManagerConfig{
+		Config: txmgr.Config{
+			Log:                       log.New(),
+			Name:                      "TEST",
+			ResubmissionTimeout:       50 * time.Millisecond,
+			ReceiptQueryInterval:      5 * time.Millisecond,
+			NumConfirmations:          1,
+			SafeAbortNonceTooLowCount: 3,
+		},
+		ChainID:   chainID,
+		From:      crypto.PubkeyToAddress(key.PublicKey),
+		Signer:    txmgr.PrivateKeySignerFn(key, chainID),
+		LegacyTxs: true,
+	}, chain, txmgr.NewFeePricer(log.New(), txmgr.PricingConfig{}, chain, nil))
+	defer m.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, m.Start(ctx))
+	receipt, err := m.Send(ctx, txmgr.TxCandidate{To: &common.Address{0x42}, Value: big.NewInt(7)})
+	require.NoError(t, err)
+	tx, _, err := chain.TransactionByHash(ctx, receipt.TxHash)
+	require.NoError(t, err)
+	require.Equal(t, uint8(types.LegacyTxType), tx.Type())
+	require.Equal(t, big.NewInt(210), tx.GasPrice(), "priced at the fee cap")
+	require.Equal(t, big.NewInt(7), tx.Value())
+	require.Equal(t, chainID, tx.ChainId(), "replay protected")
+}
+
+func TestManagerCancel(t *testing.T) {
+	chain := newMockChain(0)
+	m, from := newTestManager(t, chain, "")
+	defer m.Close()
+	// only mine self-transfers
+	chain.setAccept(func(tx *types.Transaction) bool {
+		return *tx.To() == from
+	})
+	chain.autoMine(t, 10*time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, m.Start(ctx))
+	q, err := m.Queue(ctx, txmgr.TxCandidate{To: &common.Address{0x42}, TxData: []byte{1, 2, 3}})
+	require.NoError(t, err)
+	require.NoError(t, m.Cancel(q.Nonce()))
+	receipt, err := q.Wait(ctx)
+	require.ErrorIs(t, err, txmgr.ErrCanceled)
+	tx, _, err := chain.TransactionByHash(ctx, receipt.TxHash)
+	require.NoError(t, err)
+	require.Equal(t, from, *tx.To())
+	require.Empty(t, tx.Data())
+
+	require.Error(t, m.Cancel(q.Nonce()), "no longer pending")
+}
+
+func TestManagerReplacementLimited(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	chain := newMockChain(0)
+	chain.setAccept(func(tx *types.Transaction) bool { return false })
+	chain.autoMine(t, 10*time.Millisecond)
+	// the initial fee cap of 210 fits, the minimum bump to 231 does not
+	pricer := txmgr.NewFeePricer(log.New(), txmgr.PricingConfig{MaxGasFeeCap: big.NewInt(220)}, chain, nil)
+	m := newTestManagerWithPricer(t, chain, "", key, pricer)
+	defer m.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, m.Start(ctx))
+	q, err := m.Queue(ctx, txmgr.TxCandidate{To: &common.Address{0x42}})
+	require.NoError(t, err)
+	receipt, err := q.Wait(ctx)
+	require.ErrorIs(t, err, txmgr.ErrReplacementLimited)
+	require.Nil(t, receipt)
+	require.Equal(t, []uint64{q.Nonce()}, m.Pending(), "the transaction is still pending")
+
+	// the caller keeps waiting, and the transaction confirms at its original price
+	chain.setAccept(func(tx *types.Transaction) bool { return true })
+	for {
+		receipt, err = q.Wait(ctx)
+		if !errors.Is(err, txmgr.ErrReplacementLimited) {
+			break
+		}
+	}
+	require.NoError(t, err)
+	require.NotNil(t, receipt)
+}
+
+func TestManagerRecoversJournal(t *testing.T) {
+	journal := filepath.Join(t.TempDir(), "journal.json")
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	chain := newMockChain(0)
+	chain.setAccept(func(tx *types.Transaction) bool { return false })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	m := newTestManagerWithKey(t, chain, journal, key)
+	require.NoError(t, m.Start(ctx))
+	q, err := m.Queue(ctx, txmgr.TxCandidate{To: &common.Address{0x42}, TxData: []byte{1}})
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), q.Nonce())
+	m.Close()
+
+	// a transaction of the account that the manager does not know of
+	chainID := big.NewInt(900)
+	unknown, err := types.SignTx(types.NewTx(&types.DynamicFeeTx{
+		ChainID:   chainID,
+		Nonce:     1,
+		To:        &common.Address{0x43},
+		Gas:       21000,
+		GasTipCap: big.NewInt(10),
+		GasFeeCap: big.NewInt(210),
+	}), types.LatestSignerForChainID(chainID), key)
+	require.NoError(t, err)
+	require.NoError(t, chain.SendTransaction(ctx, unknown))
+
+	// the restarted manager resumes the journaled tx, and cancels the unknown one
+	chain.setAccept(func(tx *types.Transaction) bool { return *tx.To() != common.Address{0x43} })
+	chain.autoMine(t, 10*time.Millisecond)
+	m = newTestManagerWithKey(t, chain, journal, key)
+	defer m.Close()
+	require.NoError(t, m.Start(ctx))
+	require.ElementsMatch(t, []uint64{0, 1}, m.Pending())
+	q, err = m.Queue(ctx, txmgr.TxCandidate{To: &common.Address{0x44}})
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), q.Nonce())
+	_, err = q.Wait(ctx)
+	require.NoError(t, err)
+	require.Eventually(t, func() bool { return len(m.Pending()) == 0 }, 5*time.Second, 10*time.Millisecond)
+
+	chain.mu.Lock()
+	defer chain.mu.Unlock()
+	var toJournaled, canceled int
+	for hash := range chain.mined {
+		switch *chain.txs[hash].To() {
+		case common.Address{0x42}:
+			toJournaled++
+		case crypto.PubkeyToAddress(key.PublicKey):
+			canceled++
+		}
+	}
+	require.Equal(t, 1, toJournaled)
+	require.Equal(t, 1, canceled)
+}
+
+func TestManagerRecoversSpends(t *testing.T) {
+	journal := filepath.Join(t.TempDir(), "journal.json")
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	chain := newMockChain(0)
+	chain.autoMine(t, 10*time.Millisecond)
+	budget := txmgr.PricingConfig{DailyBudget: big.NewInt(1_000_000_000)}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	pricer := txmgr.NewFeePricer(log.New(), budget, chain, nil)
+	m := newTestManagerWithPricer(t, chain, journal, key, pricer)
+	require.NoError(t, m.Start(ctx))
+	_, err = m.Send(ctx, txmgr.TxCandidate{To: &common.Address{0x42}})
+	require.NoError(t, err)
+	spends := pricer.Spends()
+	require.Len(t, spends, 1)
+	m.Close()
+
+	// the restarted manager restores the fees spent in the budget window
+	restarted := txmgr.NewFeePricer(log.New(), budget, chain, nil)
+	m = newTestManagerWithPricer(t, chain, journal, key, restarted)
+	defer m.Close()
+	require.NoError(t, m.Start(ctx))
+	restored := restarted.Spends()
+	require.Len(t, restored, 1)
+	require.Equal(t, spends[0].Fees, restored[0].Fees)
+	require.True(t, spends[0].Time.Equal(restored[0].Time))
+}
+
+// errRPCFailure is a sentinel error used to fail publications and receipt queries.
+var errRPCFailure = errors.New("rpc failure")
+
+// TestManagerConfirmsReplacedTx asserts that the receipt of a transaction is returned if it is mined after it
+// was replaced at a bumped gas price.
+func TestManagerConfirmsReplacedTx(t *testing.T) {
+	chain := newMockChain(0)
+	chain.setAccept(func(tx *types.Transaction) bool { return false })
+	chain.autoMine(t, 10*time.Millisecond)
+	m, _ := newTestManager(t, chain, "")
+	defer m.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, m.Start(ctx))
+	q, err := m.Queue(ctx, txmgr.TxCandidate{To: &common.Address{0x42}})
+	require.NoError(t, err)
+	require.Eventually(t, func() bool { return len(chain.sentTxs()) >= 3 }, 5*time.Second, 5*time.Millisecond)
+
+	// the first transaction is mined, although it was replaced twice
+	first := chain.sentTxs()[0]
+	chain.mineTx(first)
+	receipt, err := q.Wait(ctx)
+	require.NoError(t, err)
+	require.Equal(t, first.Hash(), receipt.TxHash)
+	require.Equal(t, big.NewInt(210), first.GasFeeCap())
+}
+
+// TestManagerRetriesFailedPublications asserts that a transaction confirms once one of its publications succeeds,
+// if the previous ones failed.
+func TestManagerRetriesFailedPublications(t *testing.T) {
+	chain := newMockChain(0)
+	// fail the publications until the fee cap has been bumped twice
+	chain.setSendErr(func(tx *types.Transaction) error {
+		if tx.GasFeeCap().Cmp(big.NewInt(255)) < 0 {
+			return errRPCFailure
+		}
+		return nil
+	})
+	chain.autoMine(t, 10*time.Millisecond)
+	m, _ := newTestManager(t, chain, "")
+	defer m.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, m.Start(ctx))
+	receipt, err := m.Send(ctx, txmgr.TxCandidate{To: &common.Address{0x42}})
+	require.NoError(t, err)
+	tx, _, err := chain.TransactionByHash(ctx, receipt.TxHash)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(255), tx.GasFeeCap())
+	require.Len(t, chain.sentTxs(), 1, "only the last publication succeeded")
+}
+
+// TestManagerWaitsForConfirmationsAfterNonceTooLow asserts that replacements rejected as nonce too low, because an
+// earlier transaction of the nonce was mined, don't abort the transaction while it waits for confirmations.
+func TestManagerWaitsForConfirmationsAfterNonceTooLow(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	chain := newMockChain(0)
+	chain.setAccept(func(tx *types.Transaction) bool { return false })
+	cfg := testManagerConfig(key, "")
+	cfg.NumConfirmations = 2
+	// replacements are published before the receipt of the mined transaction is queried
+	cfg.ResubmissionTimeout = 10 * time.Millisecond
+	cfg.ReceiptQueryInterval = 200 * time.Millisecond
+	m := txmgr.NewManager(cfg, chain, txmgr.NewFeePricer(log.New(), txmgr.PricingConfig{}, chain, nil))
+	defer m.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, m.Start(ctx))
+	q, err := m.Queue(ctx, txmgr.TxCandidate{To: &common.Address{0x42}})
+	require.NoError(t, err)
+	require.Eventually(t, func() bool { return len(chain.sentTxs()) == 1 }, 5*time.Second, time.Millisecond)
+	first := chain.sentTxs()[0]
+	var nonceTooLow int
+	chain.setSendErr(func(tx *types.Transaction) error {
+		if tx.Nonce() < chain.nonce {
+			nonceTooLow++
+		}
+		return nil
+	})
+	chain.mineTx(first)
+
+	waitCtx, waitCancel := context.WithTimeout(ctx, 300*time.Millisecond)
+	defer waitCancel()
+	_, err = q.Wait(waitCtx)
+	require.ErrorIs(t, err, context.DeadlineExceeded, "waiting for the second confirmation")
+
+	chain.mine()
+	receipt, err := q.Wait(ctx)
+	require.NoError(t, err)
+	require.Equal(t, first.Hash(), receipt.TxHash)
+	chain.mu.Lock()
+	defer chain.mu.Unlock()
+	require.GreaterOrEqual(t, nonceTooLow, int(cfg.SafeAbortNonceTooLowCount))
+}
+
+// TestManagerAbortsNonceUsedByUnknownTx asserts that a transaction is aborted if its nonce is used by a
+// transaction the manager does not know of.
+func TestManagerAbortsNonceUsedByUnknownTx(t *testing.T) {
+	chain := newMockChain(0)
+	chain.setAccept(func(tx *types.Transaction) bool { return false })
+	m, _ := newTestManager(t, chain, "")
+	defer m.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, m.Start(ctx))
+	q, err := m.Queue(ctx, txmgr.TxCandidate{To: &common.Address{0x42}})
+	require.NoError(t, err)
+	require.Eventually(t, func() bool { return len(chain.sentTxs()) == 1 }, 5*time.Second, time.Millisecond)
+
+	// another transaction of the account is mined at the nonce
+	chain.mu.Lock()
+	delete(chain.pool, q.Nonce())
+	chain.nonce++
+	chain.mu.Unlock()
+
+	receipt, err := q.Wait(ctx)
+	require.ErrorIs(t, err, txmgr.ErrNonceUsed)
+	require.Nil(t, receipt)
+	require.Empty(t, m.Pending())
+}
+
+// TestManagerSendCanBeCanceled asserts that Send returns once its context is canceled, if the transaction
+// never confirms. The transaction is still pending then.
+func TestManagerSendCanBeCanceled(t *testing.T) {
+	chain := newMockChain(0)
+	chain.setAccept(func(tx *types.Transaction) bool { return false })
+	chain.autoMine(t, 10*time.Millisecond)
+	m, _ := newTestManager(t, chain, "")
+	defer m.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, m.Start(ctx))
+	sendCtx, sendCancel := context.WithTimeout(ctx, 200*time.Millisecond)
+	defer sendCancel()
+	receipt, err := m.Send(sendCtx, txmgr.TxCandidate{To: &common.Address{0x42}})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Nil(t, receipt)
+	require.Equal(t, []uint64{0}, m.Pending())
+}
+
+func TestManagerPanicOnZeroConfs(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	chain := newMockChain(0)
+	cfg := testManagerConfig(key, "")
+	cfg.NumConfirmations = 0
+	require.Panics(t, func() {
+		txmgr.NewManager(cfg, chain, txmgr.NewFeePricer(log.New(), txmgr.PricingConfig{}, chain, nil))
+	})
+}
+
+func TestWaitMinedReturnsReceiptOnFirstSuccess(t *testing.T) {
+	chain := newMockChain(0)
+	tx := types.NewTx(&types.LegacyTx{})
+	chain.mineTx(tx)
+
+	receipt, err := txmgr.WaitMined(context.Background(), chain, tx, 5*time.Millisecond, 1)
+	require.NoError(t, err)
+	require.Equal(t, tx.Hash(), receipt.TxHash)
+}
+
+func TestWaitMinedCanBeCanceled(t *testing.T) {
+	chain := newMockChain(0)
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	receipt, err := txmgr.WaitMined(ctx, chain, types.NewTx(&types.LegacyTx{}), 5*time.Millisecond, 1)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Nil(t, receipt)
+}
+
+func TestWaitMinedMultipleConfs(t *testing.T) {
+	chain := newMockChain(0)
+	tx := types.NewTx(&types.LegacyTx{})
+	chain.mineTx(tx)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	receipt, err := txmgr.WaitMined(ctx, chain, tx, 5*time.Millisecond, 2)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Nil(t, receipt)
+
+	// the transaction confirms with the next block
+	chain.mine()
+	receipt, err = txmgr.WaitMined(context.Background(), chain, tx, 5*time.Millisecond, 2)
+	require.NoError(t, err)
+	require.Equal(t, tx.Hash(), receipt.TxHash)
+}
+
+// failingBackend is a txmgr.ReceiptSource that fails the first query of the block number and of a receipt.
+type failingBackend struct {
+	returnSuccessBlockNumber bool
+	returnSuccessReceipt     bool
+}
+
+func (b *failingBackend) BlockNumber(ctx context.Context) (uint64, error) {
+	if !b.returnSuccessBlockNumber {
+		b.returnSuccessBlockNumber = true
+		return 0, errRPCFailure
+	}
+	return 1, nil
+}
+
+func (b *failingBackend) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	if !b.returnSuccessReceipt {
+		b.returnSuccessReceipt = true
+		return nil, errRPCFailure
+	}
+	return &types.Receipt{
+		TxHash:      txHash,
+		BlockNumber: big.NewInt(1),
+	}, nil
+}
+
+func TestWaitMinedReturnsReceiptAfterFailure(t *testing.T) {
+	var backend failingBackend
+	tx := types.NewTx(&types.LegacyTx{})
+
+	receipt, err := txmgr.WaitMined(context.Background(), &backend, tx, 5*time.Millisecond, 1)
+	require.NoError(t, err)
+	require.Equal(t, tx.Hash(), receipt.TxHash)
+}
@@ -0,0 +1,349 @@
+package txmgr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+var (
+	// ErrBudgetExhausted is returned when the daily budget does not leave room for any fees.
+	ErrBudgetExhausted = errors.New("daily fee budget exhausted")
+
+	// ErrReplacementLimited is returned when a transaction cannot be replaced,
+	// as the minimum price bump exceeds the max fee cap or the remaining budget.
+	ErrReplacementLimited = errors.New("replacement price exceeds fee limits")
+)
+
+// MinPriceBump is the minimum percentage by which the gas tip and fee cap of a replacement
+// transaction must be raised for the L1 transaction pool to accept it.
+const MinPriceBump = 10
+
+// budgetWindow is the period over which the spending budget applies.
+const budgetWindow = 24 * time.Hour
+
+// GasPriceSource is the subset of an L1 client used to sample the current fee market,
+// and to look up the fees of confirmed transactions.
+type GasPriceSource interface {
+	// SuggestGasTipCap returns the currently suggested gas tip cap.
+	SuggestGasTipCap(ctx context.Context) (*big.Int, error)
+
+	// HeaderByNumber returns the header of the given block, or the latest block if number is nil.
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+
+	// TransactionByHash returns the transaction with the given hash.
+	TransactionByHash(ctx context.Context, hash common.Hash) (tx *types.Transaction, isPending bool, err error)
+}
+
+// TxPrice is the gas price of a dynamic fee transaction.
+type TxPrice struct {
+	GasTipCap *big.Int
+	GasFeeCap *big.Int
+}
+
+// Urgency describes the window in which a transaction should be included.
+// The zero value is never urgent.
+type Urgency struct {
+	// Start is the time at which the transaction could first be included.
+	Start time.Time
+	// Deadline is the time by which the transaction must be included.
+	Deadline time.Time
+}
+
+// Level returns the fraction of the inclusion window that has elapsed at now, between 0 and 1.
+func (u Urgency) Level(now time.Time) float64 {
+	if u.Deadline.IsZero() || !u.Deadline.After(u.Start) {
+		return 0
+	}
+	level := float64(now.Sub(u.Start)) / float64(u.Deadline.Sub(u.Start))
+	if level < 0 {
+		return 0
+	}
+	if level > 1 {
+		return 1
+	}
+	return level
+}
+
+// PricingStrategy decides the gas price of newly crafted transactions, and of their replacements.
+type PricingStrategy interface {
+	// Price returns the gas price of a new transaction that uses up to gas,
+	// and should be included with the given urgency.
+	Price(ctx context.Context, gas uint64, urgency Urgency) (TxPrice, error)
+
+	// Bump returns the gas price of a replacement of tx, which is raised enough
+	// for the transaction pool to accept the replacement.
+	Bump(ctx context.Context, tx *types.Transaction, urgency Urgency) (TxPrice, error)
+
+	// RecordReceipt accounts for the fees paid by a confirmed transaction.
+	RecordReceipt(ctx context.Context, receipt *types.Receipt) error
+
+	// Spends returns the fees accounted for in the current budget window, to persist them across restarts.
+	Spends() []FeeSpend
+
+	// RestoreSpends accounts for the fees that were spent before a restart.
+	RestoreSpends(spends []FeeSpend)
+}
+
+// PricingConfig configures the limits of a FeePricer.
+type PricingConfig struct {
+	// MaxGasFeeCap is the maximum fee cap per gas of any transaction, or nil for no limit.
+	MaxGasFeeCap *big.Int
+
+	// DailyBudget is the maximum amount of wei to spend on fees in any 24 hour period,
+	// or nil for no limit.
+	DailyBudget *big.Int
+
+	// MaxUrgency is the factor the suggested gas tip is multiplied by at the deadline of a transaction.
+	// The factor rises linearly from 1 at the start of the inclusion window.
+	MaxUrgency float64
+
+	// PriceBump is the percentage by which a replacement raises the gas tip and fee cap,
+	// at least MinPriceBump.
+	PriceBump uint64
+}
+
+func (c PricingConfig) Check() error {
+	if c.MaxUrgency != 0 && c.MaxUrgency < 1 {
+		return fmt.Errorf("max urgency multiplier must be at least 1, got %f", c.MaxUrgency)
+	}
+	if c.PriceBump != 0 && c.PriceBump < MinPriceBump {
+		return fmt.Errorf("price bump must be at least %d%%, got %d%%", MinPriceBump, c.PriceBump)
+	}
+	if c.MaxGasFeeCap != nil && c.MaxGasFeeCap.Sign() <= 0 {
+		return errors.New("max gas fee cap must be positive")
+	}
+	if c.DailyBudget != nil && c.DailyBudget.Sign() <= 0 {
+		return errors.New("daily budget must be positive")
+	}
+	return nil
+}
+
+// FeeSpend is the fees paid by a confirmed transaction, at the time it was confirmed.
+type FeeSpend struct {
+	Time time.Time
+	Fees *big.Int
+}
+
+// FeePricer is the default PricingStrategy. It multiplies the suggested gas tip by the urgency of
+// the transaction, and sets the fee cap to the tip plus twice the base fee (see CalcGasFeeCap),
+// limited by the max fee cap and the remaining daily budget.
+type FeePricer struct {
+	cfg     PricingConfig
+	source  GasPriceSource
+	metrics PricingMetrics
+	log     log.Logger
+
+	mu     sync.Mutex
+	spends []FeeSpend
+
+	// now is the clock used for urgency and budget accounting, replaced in tests.
+	now func() time.Time
+}
+
+var _ PricingStrategy = (*FeePricer)(nil)
+
+// NewFeePricer creates a FeePricer that samples the fee market from source.
+// Unset values of cfg default to no urgency and a MinPriceBump bump.
+func NewFeePricer(log log.Logger, cfg PricingConfig, source GasPriceSource, metrics PricingMetrics) *FeePricer {
+	if cfg.MaxUrgency < 1 {
+		cfg.MaxUrgency = 1
+	}
+	if cfg.PriceBump < MinPriceBump {
+		cfg.PriceBump = MinPriceBump
+	}
+	if metrics == nil {
+		metrics = NoopPricingMetrics
+	}
+	return &FeePricer{
+		cfg:     cfg,
+		source:  source,
+		metrics: metrics,
+		log:     log,
+		now:     time.Now,
+	}
+}
+
+// marketPrice samples the fee market, and raises the suggested tip by the urgency of the transaction.
+func (p *FeePricer) marketPrice(ctx context.Context, urgency Urgency) (TxPrice, error) {
+	gasTipCap, err := p.source.SuggestGasTipCap(ctx)
+	if err != nil {
+		return TxPrice{}, fmt.Errorf("failed to fetch gas tip cap: %w", err)
+	}
+	head, err := p.source.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return TxPrice{}, fmt.Errorf("failed to fetch L1 head: %w", err)
+	}
+	level := urgency.Level(p.now())
+	if multiplier := 1 + level*(p.cfg.MaxUrgency-1); multiplier > 1 {
+		// multiply in per-mille to stay in integer arithmetic
+		gasTipCap = new(big.Int).Mul(gasTipCap, big.NewInt(int64(multiplier*1000)))
+		gasTipCap.Div(gasTipCap, big.NewInt(1000))
+	}
+	p.metrics.RecordUrgency(level)
+	return TxPrice{
+		GasTipCap: gasTipCap,
+		GasFeeCap: CalcGasFeeCap(head.BaseFee, gasTipCap),
+	}, nil
+}
+
+// prune removes the spending before the budget window. The mutex must be held.
+func (p *FeePricer) prune() {
+	cutoff := p.now().Add(-budgetWindow)
+	for len(p.spends) > 0 && !p.spends[0].Time.After(cutoff) {
+		p.spends = p.spends[1:]
+	}
+}
+
+// spent returns the fees spent in the budget window, and prunes older spending.
+func (p *FeePricer) spent() *big.Int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.prune()
+	total := new(big.Int)
+	for _, s := range p.spends {
+		total.Add(total, s.Fees)
+	}
+	return total
+}
+
+// Spends returns the fees spent in the budget window.
+func (p *FeePricer) Spends() []FeeSpend {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.prune()
+	return append([]FeeSpend(nil), p.spends...)
+}
+
+// RestoreSpends adds the given spending to the budget window, in order of time.
+func (p *FeePricer) RestoreSpends(spends []FeeSpend) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.spends = append(p.spends, spends...)
+	sort.SliceStable(p.spends, func(i, j int) bool { return p.spends[i].Time.Before(p.spends[j].Time) })
+	p.prune()
+}
+
+// feeCapLimit returns the highest fee cap a transaction using up to gas may have, and the reason of the limit,
+// or nil if there is no limit.
+func (p *FeePricer) feeCapLimit(gas uint64) (limit *big.Int, reason string, err error) {
+	if p.cfg.MaxGasFeeCap != nil {
+		limit, reason = p.cfg.MaxGasFeeCap, "max_fee_cap"
+	}
+	if p.cfg.DailyBudget != nil && gas > 0 {
+		remaining := new(big.Int).Sub(p.cfg.DailyBudget, p.spent())
+		budgetLimit := remaining.Div(remaining, new(big.Int).SetUint64(gas))
+		if budgetLimit.Sign() <= 0 {
+			p.metrics.RecordPriceLimited("budget")
+			return nil, "", ErrBudgetExhausted
+		}
+		if limit == nil || budgetLimit.Cmp(limit) < 0 {
+			limit, reason = budgetLimit, "budget"
+		}
+	}
+	return limit, reason, nil
+}
+
+// Price returns the market price of a new transaction, limited by the max fee cap and the remaining budget.
+// A limited price may be below the current base fee, to wait for fees to drop.
+func (p *FeePricer) Price(ctx context.Context, gas uint64, urgency Urgency) (TxPrice, error) {
+	price, err := p.marketPrice(ctx, urgency)
+	if err != nil {
+		return TxPrice{}, err
+	}
+	limit, reason, err := p.feeCapLimit(gas)
+	if err != nil {
+		return TxPrice{}, err
+	}
+	if limit != nil && price.GasFeeCap.Cmp(limit) > 0 {
+		p.log.Warn("limiting gas fee cap", "reason", reason, "fee_cap", price.GasFeeCap, "limit", limit)
+		p.metrics.RecordPriceLimited(reason)
+		price.GasFeeCap = limit
+		if price.GasTipCap.Cmp(limit) > 0 {
+			price.GasTipCap = limit
+		}
+	}
+	p.metrics.RecordGasPrice(price)
+	return price, nil
+}
+
+// Bump returns the market price for a replacement of tx, raised to at least PriceBump percent
+// over the price of tx, as required by the L1 transaction pool to accept the replacement.
+// ErrReplacementLimited is returned if the minimum bump exceeds the max fee cap or remaining budget.
+func (p *FeePricer) Bump(ctx context.Context, tx *types.Transaction, urgency Urgency) (TxPrice, error) {
+	price, err := p.marketPrice(ctx, urgency)
+	if err != nil {
+		return TxPrice{}, err
+	}
+	minTipCap := p.bumped(tx.GasTipCap())
+	minFeeCap := p.bumped(tx.GasFeeCap())
+	if price.GasTipCap.Cmp(minTipCap) < 0 {
+		price.GasTipCap = minTipCap
+	}
+	if price.GasFeeCap.Cmp(minFeeCap) < 0 {
+		price.GasFeeCap = minFeeCap
+	}
+	if price.GasFeeCap.Cmp(price.GasTipCap) < 0 {
+		price.GasFeeCap = price.GasTipCap
+	}
+
+	limit, reason, err := p.feeCapLimit(tx.Gas())
+	if err != nil {
+		return TxPrice{}, err
+	}
+	if limit != nil && price.GasFeeCap.Cmp(limit) > 0 {
+		p.metrics.RecordPriceLimited(reason)
+		if minFeeCap.Cmp(limit) > 0 || minTipCap.Cmp(limit) > 0 {
+			return TxPrice{}, fmt.Errorf("%w: %s of %v is below minimum fee cap %v", ErrReplacementLimited, reason, limit, minFeeCap)
+		}
+		price.GasFeeCap = limit
+		if price.GasTipCap.Cmp(limit) > 0 {
+			price.GasTipCap = limit
+		}
+	}
+	p.log.Info("bumping gas price", "nonce", tx.Nonce(),
+		"old_tip_cap", tx.GasTipCap(), "old_fee_cap", tx.GasFeeCap(),
+		"tip_cap", price.GasTipCap, "fee_cap", price.GasFeeCap)
+	p.metrics.RecordGasBump(price)
+	p.metrics.RecordGasPrice(price)
+	return price, nil
+}
+
+// bumped returns v raised by the configured price bump percentage, rounded up.
+func (p *FeePricer) bumped(v *big.Int) *big.Int {
+	out := new(big.Int).Mul(v, new(big.Int).SetUint64(100+p.cfg.PriceBump))
+	out.Add(out, big.NewInt(99))
+	return out.Div(out, big.NewInt(100))
+}
+
+// RecordReceipt accounts for the fees of a confirmed transaction in the daily budget.
+// The effective gas price is not part of the receipt, and is computed from the
+// confirmed transaction and the base fee of its block.
+func (p *FeePricer) RecordReceipt(ctx context.Context, receipt *types.Receipt) error {
+	tx, _, err := p.source.TransactionByHash(ctx, receipt.TxHash)
+	if err != nil {
+		return fmt.Errorf("failed to fetch confirmed tx %s: %w", receipt.TxHash, err)
+	}
+	header, err := p.source.HeaderByNumber(ctx, receipt.BlockNumber)
+	if err != nil {
+		return fmt.Errorf("failed to fetch block %v of confirmed tx: %w", receipt.BlockNumber, err)
+	}
+	gasPrice := new(big.Int).Add(header.BaseFee, tx.GasTipCap())
+	if gasPrice.Cmp(tx.GasFeeCap()) > 0 {
+		gasPrice = tx.GasFeeCap()
+	}
+	fees := new(big.Int).Mul(gasPrice, new(big.Int).SetUint64(receipt.GasUsed))
+	p.mu.Lock()
+	p.spends = append(p.spends, FeeSpend{Time: p.now(), Fees: fees})
+	p.mu.Unlock()
+	p.metrics.RecordFeesSpent(fees)
+	return nil
+}
@@ -0,0 +1,110 @@
+package txmgr
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const pricingSubsystem = "txmgr"
+
+// PricingMetrics records the gas prices chosen by a FeePricer.
+type PricingMetrics interface {
+	RecordGasPrice(price TxPrice)
+	RecordGasBump(price TxPrice)
+	RecordUrgency(level float64)
+	RecordPriceLimited(reason string)
+	RecordFeesSpent(fees *big.Int)
+}
+
+type noopPricingMetrics struct{}
+
+var NoopPricingMetrics PricingMetrics = new(noopPricingMetrics)
+
+func (n *noopPricingMetrics) RecordGasPrice(TxPrice) {}
+
+func (n *noopPricingMetrics) RecordGasBump(TxPrice) {}
+
+func (n *noopPricingMetrics) RecordUrgency(float64) {}
+
+func (n *noopPricingMetrics) RecordPriceLimited(string) {}
+
+func (n *noopPricingMetrics) RecordFeesSpent(*big.Int) {}
+
+type PromPricingMetrics struct {
+	GasTipCap     prometheus.Gauge
+	GasFeeCap     prometheus.Gauge
+	GasBumps      prometheus.Counter
+	Urgency       prometheus.Gauge
+	PriceLimited  *prometheus.CounterVec
+	FeesSpentGwei prometheus.Counter
+}
+
+func NewPromPricingMetrics(r *prometheus.Registry, ns string) PricingMetrics {
+	return &PromPricingMetrics{
+		GasTipCap: promauto.With(r).NewGauge(prometheus.GaugeOpts{
+			Namespace: ns,
+			Subsystem: pricingSubsystem,
+			Name:      "gas_tip_cap_gwei",
+			Help:      "Gas tip cap of the last priced transaction, in gwei",
+		}),
+		GasFeeCap: promauto.With(r).NewGauge(prometheus.GaugeOpts{
+			Namespace: ns,
+			Subsystem: pricingSubsystem,
+			Name:      "gas_fee_cap_gwei",
+			Help:      "Gas fee cap of the last priced transaction, in gwei",
+		}),
+		GasBumps: promauto.With(r).NewCounter(prometheus.CounterOpts{
+			Namespace: ns,
+			Subsystem: pricingSubsystem,
+			Name:      "gas_bumps_total",
+			Help:      "Count of gas price bumps of replacement transactions",
+		}),
+		Urgency: promauto.With(r).NewGauge(prometheus.GaugeOpts{
+			Namespace: ns,
+			Subsystem: pricingSubsystem,
+			Name:      "urgency",
+			Help:      "Fraction of the inclusion window of the last priced transaction that had elapsed",
+		}),
+		PriceLimited: promauto.With(r).NewCounterVec(prometheus.CounterOpts{
+			Namespace: ns,
+			Subsystem: pricingSubsystem,
+			Name:      "price_limited_total",
+			Help:      "Count of gas prices lowered to stay within the fee limits, by limit",
+		}, []string{"reason"}),
+		FeesSpentGwei: promauto.With(r).NewCounter(prometheus.CounterOpts{
+			Namespace: ns,
+			Subsystem: pricingSubsystem,
+			Name:      "fees_spent_gwei_total",
+			Help:      "Total fees paid by confirmed transactions, in gwei",
+		}),
+	}
+}
+
+func toGwei(v *big.Int) float64 {
+	f, _ := new(big.Float).Quo(new(big.Float).SetInt(v), big.NewFloat(params.GWei)).Float64()
+	return f
+}
+
+func (p *PromPricingMetrics) RecordGasPrice(price TxPrice) {
+	p.GasTipCap.Set(toGwei(price.GasTipCap))
+	p.GasFeeCap.Set(toGwei(price.GasFeeCap))
+}
+
+func (p *PromPricingMetrics) RecordGasBump(price TxPrice) {
+	p.GasBumps.Inc()
+}
+
+func (p *PromPricingMetrics) RecordUrgency(level float64) {
+	p.Urgency.Set(level)
+}
+
+func (p *PromPricingMetrics) RecordPriceLimited(reason string) {
+	p.PriceLimited.WithLabelValues(reason).Inc()
+}
+
+func (p *PromPricingMetrics) RecordFeesSpent(fees *big.Int) {
+	p.FeesSpentGwei.Add(toGwei(fees))
+}
@@ -0,0 +1,183 @@
+package txmgr
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// staticGasPrices is a GasPriceSource with a fixed gas tip and base fee.
+type staticGasPrices struct {
+	tip, baseFee int64
+	txs          map[common.Hash]*types.Transaction
+}
+
+func (s *staticGasPrices) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	return big.NewInt(s.tip), nil
+}
+
+func (s *staticGasPrices) TransactionByHash(ctx context.Context, hash common.Hash) (*types.Transaction, bool, error) {
+	tx, ok := s.txs[hash]
+	if !ok {
+		return nil, false, ethereum.NotFound
+	}
+	return tx, false, nil
+}
+
+// confirm returns the receipt of a transaction confirmed at the given gas prices.
+func (s *staticGasPrices) confirm(gasTipCap, gasFeeCap int64, gasUsed uint64) *types.Receipt {
+	if s.txs == nil {
+		s.txs = make(map[common.Hash]*types.Transaction)
+	}
+	tx := types.NewTx(&types.DynamicFeeTx{Nonce: uint64(len(s.txs)), GasTipCap: big.NewInt(gasTipCap), GasFeeCap: big.NewInt(gasFeeCap), Gas: gasUsed})
+	s.txs[tx.Hash()] = tx
+	return &types.Receipt{TxHash: tx.Hash(), GasUsed: gasUsed, BlockNumber: big.NewInt(1)}
+}
+
+func (s *staticGasPrices) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	return &types.Header{BaseFee: big.NewInt(s.baseFee)}, nil
+}
+
+// bumpMetrics counts the recorded bumps, other metrics are not recorded.
+type bumpMetrics struct {
+	noopPricingMetrics
+	bumps   int
+	limited map[string]int
+}
+
+func (m *bumpMetrics) RecordGasBump(TxPrice) {
+	m.bumps++
+}
+
+func (m *bumpMetrics) RecordPriceLimited(reason string) {
+	m.limited[reason]++
+}
+
+func newTestPricer(cfg PricingConfig, source GasPriceSource) (*FeePricer, *bumpMetrics, *time.Time) {
+	m := &bumpMetrics{limited: make(map[string]int)}
+	p := NewFeePricer(log.New(), cfg, source, m)
+	now := time.Unix(1_000_000, 0)
+	p.now = func() time.Time { return now }
+	return p, m, &now
+}
+
+func priceTx(price TxPrice, gas uint64) *types.Transaction {
+	return types.NewTx(&types.DynamicFeeTx{GasTipCap: price.GasTipCap, GasFeeCap: price.GasFeeCap, Gas: gas})
+}
+
+func TestUrgencyLevel(t *testing.T) {
+	start := time.Unix(1000, 0)
+	u := Urgency{Start: start, Deadline: start.Add(100 * time.Second)}
+	require.Equal(t, 0.0, u.Level(start.Add(-time.Second)))
+	require.Equal(t, 0.0, u.Level(start))
+	require.Equal(t, 0.25, u.Level(start.Add(25*time.Second)))
+	require.Equal(t, 1.0, u.Level(start.Add(200*time.Second)))
+	require.Equal(t, 0.0, Urgency{}.Level(start), "no deadline")
+}
+
+func TestFeePricerPrice(t *testing.T) {
+	source := &staticGasPrices{tip: 100, baseFee: 1000}
+	p, m, now := newTestPricer(PricingConfig{MaxUrgency: 3}, source)
+
+	price, err := p.Price(context.Background(), 21000, Urgency{})
+	require.NoError(t, err)
+	require.Equal(t, TxPrice{GasTipCap: big.NewInt(100), GasFeeCap: big.NewInt(2100)}, price)
+
+	// the tip rises with the urgency, up to MaxUrgency times at the deadline
+	urgency := Urgency{Start: *now, Deadline: now.Add(time.Minute)}
+	price, err = p.Price(context.Background(), 21000, urgency)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(100), price.GasTipCap)
+	*now = now.Add(30 * time.Second)
+	price, err = p.Price(context.Background(), 21000, urgency)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(200), price.GasTipCap)
+	*now = now.Add(time.Hour)
+	price, err = p.Price(context.Background(), 21000, urgency)
+	require.NoError(t, err)
+	require.Equal(t, TxPrice{GasTipCap: big.NewInt(300), GasFeeCap: big.NewInt(2300)}, price)
+	require.Zero(t, m.bumps)
+}
+
+func TestFeePricerMaxFeeCap(t *testing.T) {
+	source := &staticGasPrices{tip: 100, baseFee: 1000}
+	p, m, _ := newTestPricer(PricingConfig{MaxGasFeeCap: big.NewInt(1500)}, source)
+
+	price, err := p.Price(context.Background(), 21000, Urgency{})
+	require.NoError(t, err)
+	require.Equal(t, TxPrice{GasTipCap: big.NewInt(100), GasFeeCap: big.NewInt(1500)}, price)
+	require.Equal(t, 1, m.limited["max_fee_cap"])
+
+	// a bump is limited by the max fee cap, as long as the minimum bump fits
+	tx := priceTx(TxPrice{GasTipCap: big.NewInt(100), GasFeeCap: big.NewInt(1300)}, 21000)
+	price, err = p.Bump(context.Background(), tx, Urgency{})
+	require.NoError(t, err)
+	require.Equal(t, TxPrice{GasTipCap: big.NewInt(110), GasFeeCap: big.NewInt(1500)}, price)
+
+	tx = priceTx(price, 21000)
+	_, err = p.Bump(context.Background(), tx, Urgency{})
+	require.ErrorIs(t, err, ErrReplacementLimited)
+	require.Equal(t, 1, m.bumps)
+}
+
+func TestFeePricerBump(t *testing.T) {
+	source := &staticGasPrices{tip: 100, baseFee: 1000}
+	p, m, _ := newTestPricer(PricingConfig{}, source)
+
+	// the market price did not change, the replacement is raised by the minimum bump, rounded up
+	tx := priceTx(TxPrice{GasTipCap: big.NewInt(105), GasFeeCap: big.NewInt(2105)}, 21000)
+	price, err := p.Bump(context.Background(), tx, Urgency{})
+	require.NoError(t, err)
+	require.Equal(t, TxPrice{GasTipCap: big.NewInt(116), GasFeeCap: big.NewInt(2316)}, price)
+
+	// the market price rose more than the minimum bump
+	source.baseFee = 5000
+	price, err = p.Bump(context.Background(), tx, Urgency{})
+	require.NoError(t, err)
+	require.Equal(t, TxPrice{GasTipCap: big.NewInt(116), GasFeeCap: big.NewInt(10100)}, price)
+	require.Equal(t, 2, m.bumps)
+
+	// a larger configured bump
+	p, _, _ = newTestPricer(PricingConfig{PriceBump: 50}, source)
+	price, err = p.Bump(context.Background(), tx, Urgency{})
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(158), price.GasTipCap)
+}
+
+func TestFeePricerDailyBudget(t *testing.T) {
+	source := &staticGasPrices{tip: 100, baseFee: 1000}
+	p, m, now := newTestPricer(PricingConfig{DailyBudget: big.NewInt(100_000_000)}, source)
+
+	price, err := p.Price(context.Background(), 21000, Urgency{})
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(2100), price.GasFeeCap)
+
+	// spend most of the budget, at an effective gas price limited by the fee cap,
+	// and the fee cap is limited to the remaining budget
+	require.NoError(t, p.RecordReceipt(context.Background(), source.confirm(1000, 1900, 50000)))
+	price, err = p.Price(context.Background(), 21000, Urgency{})
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(5_000_000/21000), price.GasFeeCap)
+	require.Equal(t, 1, m.limited["budget"])
+
+	// the effective gas price is the base fee plus the tip
+	source.baseFee = 0
+	require.NoError(t, p.RecordReceipt(context.Background(), source.confirm(1, 1000, 5_000_000)))
+	source.baseFee = 1000
+	_, err = p.Price(context.Background(), 21000, Urgency{})
+	require.ErrorIs(t, err, ErrBudgetExhausted)
+
+	// the budget is available again a day later
+	*now = now.Add(24 * time.Hour)
+	price, err = p.Price(context.Background(), 21000, Urgency{})
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(2100), price.GasFeeCap)
+}
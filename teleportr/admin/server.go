@@ -0,0 +1,269 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/teleportr/db"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/gorilla/mux"
+)
+
+const (
+	ContentTypeHeader = "Content-Type"
+	ContentTypeJSON   = "application/json"
+)
+
+// ReorgStore provides access to the reorgs recorded by the disburser.
+type ReorgStore interface {
+	UnacknowledgedReorgs() ([]db.Reorg, error)
+	AcknowledgeReorg(id uint64, acknowledgedAt time.Time) error
+}
+
+// RejectedDepositStore provides access to the token deposits rejected by the
+// disburser.
+type RejectedDepositStore interface {
+	UnrefundedRejectedDeposits() ([]db.RejectedDeposit, error)
+	MarkRejectedDepositRefunded(token common.Address, id uint64, refundTxnHash common.Hash) error
+}
+
+// Server exposes operator endpoints of the disburser. It is meant to be
+// reachable by operators only, and should not be exposed publicly.
+type Server struct {
+	ctx              context.Context
+	reorgs           ReorgStore
+	rejectedDeposits RejectedDepositStore
+
+	httpServer *http.Server
+}
+
+func NewServer(
+	ctx context.Context,
+	reorgs ReorgStore,
+	rejectedDeposits RejectedDepositStore,
+) *Server {
+
+	return &Server{
+		ctx:              ctx,
+		reorgs:           reorgs,
+		rejectedDeposits: rejectedDeposits,
+	}
+}
+
+// Handler returns the routes served by the admin server.
+func (s *Server) Handler() http.Handler {
+	handler := mux.NewRouter()
+	handler.HandleFunc("/reorgs", s.HandleReorgs).Methods("GET")
+	handler.HandleFunc(
+		"/reorgs/{id:[0-9]+}/acknowledge",
+		s.HandleAcknowledgeReorg,
+	).Methods("POST")
+	handler.HandleFunc(
+		"/rejected-deposits", s.HandleRejectedDeposits,
+	).Methods("GET")
+	handler.HandleFunc(
+		"/rejected-deposits/{token:0x[0-9a-fA-F]{40}}/{id:[0-9]+}/refund",
+		s.HandleRefundRejectedDeposit,
+	).Methods("POST")
+	return handler
+}
+
+func (s *Server) ListenAndServe(host string, port uint64) error {
+	addr := fmt.Sprintf("%s:%d", host, port)
+	s.httpServer = &http.Server{
+		Handler: s.Handler(),
+		Addr:    addr,
+		BaseContext: func(_ net.Listener) context.Context {
+			return s.ctx
+		},
+	}
+	log.Info("Starting admin HTTP server", "addr", addr)
+	return s.httpServer.ListenAndServe()
+}
+
+func (s *Server) Shutdown(ctx context.Context) error {
+	if s.httpServer == nil {
+		return nil
+	}
+	return s.httpServer.Shutdown(ctx)
+}
+
+type RPCReorg struct {
+	ID                 string `json:"id"`
+	BlockNumber        string `json:"block_number"`
+	OldBlockHash       string `json:"old_block_hash"`
+	NewBlockHash       string `json:"new_block_hash"`
+	RolledBackDeposits string `json:"rolled_back_deposits"`
+	DetectedAt         string `json:"detected_at_unix"`
+}
+
+type ReorgsResponse struct {
+	Reorgs []RPCReorg `json:"reorgs"`
+}
+
+// HandleReorgs lists the reorgs awaiting acknowledgement. Disbursements are
+// paused while the list is non-empty.
+func (s *Server) HandleReorgs(w http.ResponseWriter, r *http.Request) {
+	reorgs, err := s.reorgs.UnacknowledgedReorgs()
+	if err != nil {
+		log.Error("Unable to load unacknowledged reorgs", "err", err)
+		writeError(w, http.StatusInternalServerError)
+		return
+	}
+
+	resp := ReorgsResponse{
+		Reorgs: make([]RPCReorg, 0, len(reorgs)),
+	}
+	for _, reorg := range reorgs {
+		resp.Reorgs = append(resp.Reorgs, RPCReorg{
+			ID:                 strconv.FormatUint(reorg.ID, 10),
+			BlockNumber:        strconv.FormatUint(reorg.BlockNumber, 10),
+			OldBlockHash:       reorg.OldBlockHash.String(),
+			NewBlockHash:       reorg.NewBlockHash.String(),
+			RolledBackDeposits: strconv.FormatUint(reorg.RolledBackDeposits, 10),
+			DetectedAt:         strconv.FormatInt(reorg.DetectedAt.Unix(), 10),
+		})
+	}
+
+	writeJSON(w, resp)
+}
+
+// HandleAcknowledgeReorg marks a reorg as acknowledged, resuming disbursements
+// once no other reorgs are pending.
+func (s *Server) HandleAcknowledgeReorg(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest)
+		return
+	}
+
+	err = s.reorgs.AcknowledgeReorg(id, time.Now())
+	if errors.Is(err, db.ErrUnknownReorg) {
+		writeError(w, http.StatusNotFound)
+		return
+	} else if err != nil {
+		log.Error("Unable to acknowledge reorg", "id", id, "err", err)
+		writeError(w, http.StatusInternalServerError)
+		return
+	}
+
+	log.Warn("Reorg acknowledged by operator", "id", id,
+		"remote_addr", r.RemoteAddr)
+
+	writeJSON(w, struct{}{})
+}
+
+type RPCRejectedDeposit struct {
+	Token          string `json:"token"`
+	ID             string `json:"id"`
+	Address        string `json:"address"`
+	Amount         string `json:"amount"`
+	TxnHash        string `json:"txn_hash"`
+	BlockNumber    string `json:"block_number"`
+	BlockTimestamp string `json:"block_timestamp_unix"`
+	Reason         string `json:"reason"`
+}
+
+type RejectedDepositsResponse struct {
+	RejectedDeposits []RPCRejectedDeposit `json:"rejected_deposits"`
+}
+
+// HandleRejectedDeposits lists the token deposits that were rejected for
+// falling outside of the deposit limits of the token, and still need to be
+// refunded.
+func (s *Server) HandleRejectedDeposits(w http.ResponseWriter, r *http.Request) {
+	deposits, err := s.rejectedDeposits.UnrefundedRejectedDeposits()
+	if err != nil {
+		log.Error("Unable to load rejected deposits", "err", err)
+		writeError(w, http.StatusInternalServerError)
+		return
+	}
+
+	resp := RejectedDepositsResponse{
+		RejectedDeposits: make([]RPCRejectedDeposit, 0, len(deposits)),
+	}
+	for _, deposit := range deposits {
+		resp.RejectedDeposits = append(resp.RejectedDeposits, RPCRejectedDeposit{
+			Token:          deposit.Token.String(),
+			ID:             strconv.FormatUint(deposit.ID, 10),
+			Address:        deposit.Address.String(),
+			Amount:         deposit.Amount.String(),
+			TxnHash:        deposit.TxnHash.String(),
+			BlockNumber:    strconv.FormatUint(deposit.BlockNumber, 10),
+			BlockTimestamp: strconv.FormatInt(deposit.BlockTimestamp.Unix(), 10),
+			Reason:         deposit.Reason,
+		})
+	}
+
+	writeJSON(w, resp)
+}
+
+type RefundRequest struct {
+	RefundTxnHash string `json:"refund_txn_hash"`
+}
+
+// HandleRefundRejectedDeposit records the refund of a rejected deposit, which
+// the operator sent back to the depositor on L1.
+func (s *Server) HandleRefundRejectedDeposit(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	token := common.HexToAddress(vars["token"])
+	id, err := strconv.ParseUint(vars["id"], 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest)
+		return
+	}
+
+	var req RefundRequest
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil || !isHexHash(req.RefundTxnHash) {
+		writeError(w, http.StatusBadRequest)
+		return
+	}
+	refundTxnHash := common.HexToHash(req.RefundTxnHash)
+
+	err = s.rejectedDeposits.MarkRejectedDepositRefunded(token, id, refundTxnHash)
+	if errors.Is(err, db.ErrUnknownDeposit) {
+		writeError(w, http.StatusNotFound)
+		return
+	} else if err != nil {
+		log.Error("Unable to mark rejected deposit refunded", "token", token,
+			"id", id, "err", err)
+		writeError(w, http.StatusInternalServerError)
+		return
+	}
+
+	log.Warn("Rejected deposit refunded by operator", "token", token,
+		"id", id, "refund_txn_hash", refundTxnHash,
+		"remote_addr", r.RemoteAddr)
+
+	writeJSON(w, struct{}{})
+}
+
+// isHexHash returns true if s is a 0x-prefixed 32 byte hex string.
+func isHexHash(s string) bool {
+	b, err := hexutil.Decode(s)
+	return err == nil && len(b) == common.HashLength
+}
+
+func writeJSON(w http.ResponseWriter, resp interface{}) {
+	jsonResp, err := json.Marshal(resp)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set(ContentTypeHeader, ContentTypeJSON)
+	_, _ = w.Write(jsonResp)
+}
+
+func writeError(w http.ResponseWriter, statusCode int) {
+	http.Error(w, http.StatusText(statusCode), statusCode)
+}
@@ -0,0 +1,184 @@
+package admin_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/teleportr/admin"
+	"github.com/ethereum-optimism/optimism/teleportr/db"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+type mockReorgStore struct {
+	reorgs []db.Reorg
+	err    error
+}
+
+func (m *mockReorgStore) UnacknowledgedReorgs() ([]db.Reorg, error) {
+	return m.reorgs, m.err
+}
+
+func (m *mockReorgStore) AcknowledgeReorg(id uint64, _ time.Time) error {
+	if m.err != nil {
+		return m.err
+	}
+	for i, reorg := range m.reorgs {
+		if reorg.ID == id {
+			m.reorgs = append(m.reorgs[:i], m.reorgs[i+1:]...)
+			return nil
+		}
+	}
+	return db.ErrUnknownReorg
+}
+
+type mockRejectedDepositStore struct {
+	deposits []db.RejectedDeposit
+}
+
+func (m *mockRejectedDepositStore) UnrefundedRejectedDeposits() ([]db.RejectedDeposit, error) {
+	return m.deposits, nil
+}
+
+func (m *mockRejectedDepositStore) MarkRejectedDepositRefunded(
+	token common.Address, id uint64, refundTxnHash common.Hash) error {
+
+	for i, deposit := range m.deposits {
+		if deposit.Token == token && deposit.ID == id {
+			m.deposits = append(m.deposits[:i], m.deposits[i+1:]...)
+			return nil
+		}
+	}
+	return db.ErrUnknownDeposit
+}
+
+func serve(t *testing.T, s *admin.Server, method, path string) *httptest.ResponseRecorder {
+	return serveBody(t, s, method, path, "")
+}
+
+func serveBody(t *testing.T, s *admin.Server, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, req)
+	return rec
+}
+
+func listReorgs(t *testing.T, s *admin.Server) []admin.RPCReorg {
+	rec := serve(t, s, "GET", "/reorgs")
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var resp admin.ReorgsResponse
+	require.Nil(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	return resp.Reorgs
+}
+
+// TestAcknowledgeReorg asserts that reorgs are listed until acknowledged.
+func TestAcknowledgeReorg(t *testing.T) {
+	store := &mockReorgStore{
+		reorgs: []db.Reorg{{
+			ID:                 7,
+			BlockNumber:        100,
+			OldBlockHash:       common.HexToHash("0x01"),
+			NewBlockHash:       common.HexToHash("0x02"),
+			RolledBackDeposits: 3,
+			DetectedAt:         time.Unix(1000, 0),
+		}},
+	}
+	s := admin.NewServer(context.Background(), store, nil)
+
+	require.Equal(t, []admin.RPCReorg{{
+		ID:                 "7",
+		BlockNumber:        "100",
+		OldBlockHash:       common.HexToHash("0x01").String(),
+		NewBlockHash:       common.HexToHash("0x02").String(),
+		RolledBackDeposits: "3",
+		DetectedAt:         "1000",
+	}}, listReorgs(t, s))
+
+	rec := serve(t, s, "POST", "/reorgs/8/acknowledge")
+	require.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = serve(t, s, "GET", "/reorgs/7/acknowledge")
+	require.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+
+	rec = serve(t, s, "POST", "/reorgs/7/acknowledge")
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	require.Equal(t, []admin.RPCReorg{}, listReorgs(t, s))
+}
+
+// TestReorgsStoreError asserts that database failures are surfaced as
+// internal errors.
+func TestReorgsStoreError(t *testing.T) {
+	s := admin.NewServer(context.Background(), &mockReorgStore{err: errors.New("boom")}, nil)
+
+	rec := serve(t, s, "GET", "/reorgs")
+	require.Equal(t, http.StatusInternalServerError, rec.Code)
+
+	rec = serve(t, s, "POST", "/reorgs/1/acknowledge")
+	require.Equal(t, http.StatusInternalServerError, rec.Code)
+}
+
+// TestRefundRejectedDeposit asserts that rejected deposits are listed until
+// their refund is recorded.
+func TestRefundRejectedDeposit(t *testing.T) {
+	token := common.HexToAddress("0x7070")
+	store := &mockRejectedDepositStore{
+		deposits: []db.RejectedDeposit{{
+			Deposit: db.Deposit{
+				ID:      42,
+				Token:   token,
+				Address: common.HexToAddress("0xaa01"),
+				Amount:  big.NewInt(1000),
+				ConfirmationInfo: db.ConfirmationInfo{
+					TxnHash:        common.HexToHash("0x01"),
+					BlockNumber:    100,
+					BlockTimestamp: time.Unix(1000, 0),
+				},
+			},
+			Reason: "amount outside of deposit limits [1, 10]",
+			Status: db.RejectedDepositStatusRejected,
+		}},
+	}
+	s := admin.NewServer(context.Background(), &mockReorgStore{}, store)
+
+	listRejected := func() []admin.RPCRejectedDeposit {
+		rec := serve(t, s, "GET", "/rejected-deposits")
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var resp admin.RejectedDepositsResponse
+		require.Nil(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		return resp.RejectedDeposits
+	}
+	require.Equal(t, []admin.RPCRejectedDeposit{{
+		Token:          token.String(),
+		ID:             "42",
+		Address:        common.HexToAddress("0xaa01").String(),
+		Amount:         "1000",
+		TxnHash:        common.HexToHash("0x01").String(),
+		BlockNumber:    "100",
+		BlockTimestamp: "1000",
+		Reason:         "amount outside of deposit limits [1, 10]",
+	}}, listRejected())
+
+	refund := `{"refund_txn_hash": "` + common.HexToHash("0xff").String() + `"}`
+	path := "/rejected-deposits/" + token.String() + "/42/refund"
+
+	rec := serveBody(t, s, "POST", path, `{"refund_txn_hash": "0x1234"}`)
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = serveBody(t, s, "POST", "/rejected-deposits/"+token.String()+"/43/refund", refund)
+	require.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = serveBody(t, s, "POST", path, refund)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	require.Equal(t, []admin.RPCRejectedDeposit{}, listRejected())
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/ethereum-optimism/optimism/teleportr/bindings/deposit"
 	"github.com/ethereum-optimism/optimism/teleportr/bindings/disburse"
+	"github.com/ethereum-optimism/optimism/teleportr/tokens"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethclient"
@@ -25,6 +26,14 @@ type ChainData struct {
 	NextDepositID          uint64
 	MaxDepositAmount       *big.Int
 	MinDepositAmount       *big.Int
+	Tokens                 []TokenChainData
+}
+
+// TokenChainData holds the on-chain balances of a configured ERC20 token.
+type TokenChainData struct {
+	Token            tokens.Token
+	DisburserBalance *big.Int
+	DepositBalance   *big.Int
 }
 
 type chainDataReaderImpl struct {
@@ -34,6 +43,7 @@ type chainDataReaderImpl struct {
 	depositContractAddr common.Address
 	disburserContract   *disburse.TeleportrDisburser
 	disburserWalletAddr common.Address
+	tokens              []tokens.Token
 }
 
 func NewChainDataReader(
@@ -41,6 +51,7 @@ func NewChainDataReader(
 	depositContractAddr, disburserWalletAddr common.Address,
 	depositContract *deposit.TeleportrDeposit,
 	disburserContract *disburse.TeleportrDisburser,
+	tokenList []tokens.Token,
 ) ChainDataReader {
 	return &chainDataReaderImpl{
 		l1Client:            l1Client,
@@ -49,6 +60,7 @@ func NewChainDataReader(
 		depositContractAddr: depositContractAddr,
 		disburserContract:   disburserContract,
 		disburserWalletAddr: disburserWalletAddr,
+		tokens:              tokenList,
 	}
 }
 
@@ -136,6 +148,29 @@ func (c *chainDataReaderImpl) Get(ctx context.Context) (*ChainData, error) {
 		return nil, err
 	}
 
+	tokenData := make([]TokenChainData, 0, len(c.tokens))
+	for _, token := range c.tokens {
+		disburserTokenBal, err := tokens.BalanceOf(
+			ctx, c.l2Client, token.L2Address, c.disburserWalletAddr,
+		)
+		if err != nil {
+			rpcErrorsTotal.WithLabelValues("disburser_token_balance").Inc()
+			return nil, err
+		}
+		depositTokenBal, err := tokens.BalanceOf(
+			ctx, c.l1Client, token.L1Address, token.DepositAddress,
+		)
+		if err != nil {
+			rpcErrorsTotal.WithLabelValues("deposit_token_balance").Inc()
+			return nil, err
+		}
+		tokenData = append(tokenData, TokenChainData{
+			Token:            token,
+			DisburserBalance: disburserTokenBal,
+			DepositBalance:   depositTokenBal,
+		})
+	}
+
 	return &ChainData{
 		MaxBalance:             maxBalance,
 		DisburserBalance:       disburserBal,
@@ -144,6 +179,7 @@ func (c *chainDataReaderImpl) Get(ctx context.Context) (*ChainData, error) {
 		NextDepositID:          nextDepositID,
 		MaxDepositAmount:       maxDepositAmount,
 		MinDepositAmount:       minDepositAmount,
+		Tokens:                 tokenData,
 	}, nil
 }
 
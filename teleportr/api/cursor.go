@@ -0,0 +1,51 @@
+package api
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/teleportr/db"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ErrInvalidCursor signals that a history cursor could not be decoded.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// EncodeCursor returns the opaque representation of a history cursor handed
+// out to clients.
+func EncodeCursor(cursor db.TeleportCursor) string {
+	raw := fmt.Sprintf("%d:%s:%d",
+		cursor.BlockTimestamp.Unix(), cursor.Token.Hex(), cursor.ID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeCursor parses a history cursor returned by EncodeCursor.
+func DecodeCursor(encoded string) (db.TeleportCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return db.TeleportCursor{}, ErrInvalidCursor
+	}
+
+	parts := strings.Split(string(raw), ":")
+	if len(parts) != 3 || !common.IsHexAddress(parts[1]) {
+		return db.TeleportCursor{}, ErrInvalidCursor
+	}
+	timestamp, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return db.TeleportCursor{}, ErrInvalidCursor
+	}
+	id, err := strconv.ParseUint(parts[2], 10, 64)
+	if err != nil {
+		return db.TeleportCursor{}, ErrInvalidCursor
+	}
+
+	return db.TeleportCursor{
+		BlockTimestamp: time.Unix(timestamp, 0),
+		Token:          common.HexToAddress(parts[1]),
+		ID:             id,
+	}, nil
+}
@@ -0,0 +1,40 @@
+package api
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/teleportr/db"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	cursor := db.TeleportCursor{
+		BlockTimestamp: time.Unix(1660000000, 0),
+		Token:          common.HexToAddress("0xa1"),
+		ID:             42,
+	}
+
+	decoded, err := DecodeCursor(EncodeCursor(cursor))
+	require.NoError(t, err)
+	require.Equal(t, cursor, decoded)
+}
+
+func TestDecodeInvalidCursor(t *testing.T) {
+	encode := func(raw string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(raw))
+	}
+
+	for _, cursor := range []string{
+		"!!!",
+		encode("1660000000"),
+		encode("x:0x00000000000000000000000000000000000000a1:1"),
+		encode("1660000000:0xa1:1"),
+		encode("1660000000:0x00000000000000000000000000000000000000a1:-1"),
+	} {
+		_, err := DecodeCursor(cursor)
+		require.Equal(t, ErrInvalidCursor, err, cursor)
+	}
+}
@@ -40,4 +40,9 @@ var (
 	}, []string{
 		"method",
 	})
+	activeStreams = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: TeleportrAPINamespace,
+		Name:      "active_streams",
+		Help:      "Number of open server-sent event streams.",
+	})
 )
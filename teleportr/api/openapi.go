@@ -0,0 +1,294 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+const openAPIVersion = "3.0.3"
+
+// apiParam describes a path or query parameter of an API operation.
+type apiParam struct {
+	name        string
+	in          string
+	description string
+	pattern     string
+	required    bool
+}
+
+// apiOperation describes a GET operation served by the API. The response is
+// a value of the type returned on success, used to generate its schema.
+type apiOperation struct {
+	path        string
+	summary     string
+	params      []apiParam
+	contentType string
+	response    interface{}
+}
+
+var (
+	addrParam = apiParam{
+		name:        "addr",
+		in:          "path",
+		description: "Address of the depositor",
+		pattern:     "^0x[0-9a-fA-F]{40}$",
+		required:    true,
+	}
+	txHashParam = apiParam{
+		name:        "txhash",
+		in:          "path",
+		description: "Hash of the L1 deposit transaction",
+		pattern:     "^0x[0-9a-fA-F]{64}$",
+		required:    true,
+	}
+	amountParam = apiParam{
+		name:        "amount",
+		in:          "path",
+		description: "Deposit amount in wei, or in the token's base units",
+		pattern:     "^[0-9]{1,80}$",
+		required:    true,
+	}
+	tokenParam = apiParam{
+		name:        "token",
+		in:          "path",
+		description: "L1 address of the deposited ERC20 token",
+		pattern:     "^0x[0-9a-fA-F]{40}$",
+		required:    true,
+	}
+)
+
+// apiOperations lists every route of Server.Router.
+var apiOperations = []apiOperation{
+	{
+		path:        "/healthz",
+		summary:     "Liveness check",
+		contentType: "text/plain",
+	},
+	{
+		path:        "/openapi.json",
+		summary:     "This OpenAPI document",
+		contentType: ContentTypeJSON,
+	},
+	{
+		path:        "/status",
+		summary:     "Limits, balances and availability of ETH and each token",
+		contentType: ContentTypeJSON,
+		response:    StatusResponse{},
+	},
+	{
+		path:        "/estimate/{addr}/{amount}",
+		summary:     "Estimate the L1 fees of an ETH deposit",
+		params:      []apiParam{addrParam, amountParam},
+		contentType: ContentTypeJSON,
+		response:    EstimateResponse{},
+	},
+	{
+		path:        "/estimate/{token}/{addr}/{amount}",
+		summary:     "Estimate the L1 fees of an ERC20 token deposit",
+		params:      []apiParam{tokenParam, addrParam, amountParam},
+		contentType: ContentTypeJSON,
+		response:    EstimateResponse{},
+	},
+	{
+		path:        "/track/{txhash}",
+		summary:     "Track the teleport of a deposit",
+		params:      []apiParam{txHashParam},
+		contentType: ContentTypeJSON,
+		response:    TrackResponse{},
+	},
+	{
+		path:    "/history/{addr}",
+		summary: "List the teleports of an address, from newest to oldest",
+		params: []apiParam{
+			addrParam,
+			{
+				name:        "cursor",
+				in:          "query",
+				description: "The next_cursor of the previous page",
+			},
+			{
+				name:        "limit",
+				in:          "query",
+				description: "Maximum number of teleports in the page, at most 100",
+				pattern:     "^[0-9]+$",
+			},
+		},
+		contentType: ContentTypeJSON,
+		response:    HistoryResponse{},
+	},
+	{
+		path: "/stream/track/{txhash}",
+		summary: "Stream server-sent `" + EventTrack + "` events whenever " +
+			"the teleport of a deposit changes",
+		params:      []apiParam{txHashParam},
+		contentType: ContentTypeEventStream,
+		response:    TrackResponse{},
+	},
+	{
+		path: "/stream/history/{addr}",
+		summary: "Stream server-sent `" + EventTeleport + "` events whenever " +
+			"a recent teleport of an address changes",
+		params:      []apiParam{addrParam},
+		contentType: ContentTypeEventStream,
+		response:    RPCTeleport{},
+	},
+}
+
+// OpenAPISpec returns the OpenAPI document describing the API. Schemas are
+// generated from the response types, so the document can't drift from the
+// JSON actually served.
+func OpenAPISpec() map[string]interface{} {
+	schemas := make(map[string]interface{})
+	paths := make(map[string]interface{})
+	for _, op := range apiOperations {
+		content := map[string]interface{}{}
+		if op.response != nil {
+			content["schema"] = schemaRef(reflect.TypeOf(op.response), schemas)
+		}
+
+		operation := map[string]interface{}{
+			"summary": op.summary,
+			"responses": map[string]interface{}{
+				"200": map[string]interface{}{
+					"description": "OK",
+					"content": map[string]interface{}{
+						op.contentType: content,
+					},
+				},
+				"default": map[string]interface{}{
+					"description": "Error, with the reason as plain text",
+				},
+			},
+		}
+
+		if len(op.params) > 0 {
+			params := make([]interface{}, 0, len(op.params))
+			for _, param := range op.params {
+				schema := map[string]interface{}{"type": "string"}
+				if param.pattern != "" {
+					schema["pattern"] = param.pattern
+				}
+				params = append(params, map[string]interface{}{
+					"name":        param.name,
+					"in":          param.in,
+					"description": param.description,
+					"required":    param.required,
+					"schema":      schema,
+				})
+			}
+			operation["parameters"] = params
+		}
+
+		paths[op.path] = map[string]interface{}{"get": operation}
+	}
+
+	return map[string]interface{}{
+		"openapi": openAPIVersion,
+		"info": map[string]interface{}{
+			"title":   "Teleportr API",
+			"version": "1.0.0",
+		},
+		"paths": paths,
+		"components": map[string]interface{}{
+			"schemas": schemas,
+		},
+	}
+}
+
+// schemaRef returns the schema of t, adding the schemas of any named structs
+// to schemas and referencing them.
+func schemaRef(t reflect.Type, schemas map[string]interface{}) map[string]interface{} {
+	switch t.Kind() {
+	case reflect.Ptr:
+		return map[string]interface{}{
+			"allOf":    []interface{}{schemaRef(t.Elem(), schemas)},
+			"nullable": true,
+		}
+
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{
+			"type":  "array",
+			"items": schemaRef(t.Elem(), schemas),
+		}
+
+	case reflect.Struct:
+		if _, ok := schemas[t.Name()]; !ok {
+			// Reserve the name before recursing, in case of cycles.
+			schemas[t.Name()] = nil
+			schemas[t.Name()] = structSchema(t, schemas)
+		}
+		return map[string]interface{}{
+			"$ref": "#/components/schemas/" + t.Name(),
+		}
+
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer", "format": "int64"}
+
+	default:
+		return map[string]interface{}{"type": "string"}
+	}
+}
+
+func structSchema(t reflect.Type, schemas map[string]interface{}) map[string]interface{} {
+	properties := make(map[string]interface{})
+	var required []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" || !field.IsExported() {
+			continue
+		}
+
+		name := field.Name
+		omitEmpty := false
+		if tag != "" {
+			parts := strings.Split(tag, ",")
+			if parts[0] != "" {
+				name = parts[0]
+			}
+			for _, opt := range parts[1:] {
+				omitEmpty = omitEmpty || opt == "omitempty"
+			}
+		}
+
+		properties[name] = schemaRef(field.Type, schemas)
+		if !omitEmpty {
+			required = append(required, name)
+		}
+	}
+
+	schema := map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+var (
+	openAPISpecOnce sync.Once
+	openAPISpecJSON []byte
+)
+
+// HandleOpenAPI serves the OpenAPI document of the API.
+func HandleOpenAPI(w http.ResponseWriter, r *http.Request) {
+	openAPISpecOnce.Do(func() {
+		var err error
+		openAPISpecJSON, err = json.Marshal(OpenAPISpec())
+		if err != nil {
+			panic(err)
+		}
+	})
+
+	w.Header().Set(ContentTypeHeader, ContentTypeJSON)
+	_, _ = w.Write(openAPISpecJSON)
+}
@@ -0,0 +1,73 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"regexp"
+	"sort"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+)
+
+var routeVarPattern = regexp.MustCompile(`\{([a-z]+):[^/]*\}`)
+
+// TestOpenAPIDocumentsAllRoutes asserts that every route served by the API is
+// described in the OpenAPI document, and vice versa.
+func TestOpenAPIDocumentsAllRoutes(t *testing.T) {
+	s := &Server{}
+
+	var routes []string
+	err := s.Router().Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		tmpl, err := route.GetPathTemplate()
+		if err != nil {
+			return err
+		}
+		routes = append(routes, routeVarPattern.ReplaceAllString(tmpl, "{$1}"))
+		return nil
+	})
+	require.NoError(t, err)
+
+	var documented []string
+	for path := range OpenAPISpec()["paths"].(map[string]interface{}) {
+		documented = append(documented, path)
+	}
+
+	sort.Strings(routes)
+	sort.Strings(documented)
+	require.Equal(t, routes, documented)
+}
+
+// TestOpenAPISchemas asserts that schemas follow the JSON encoding of the
+// response types.
+func TestOpenAPISchemas(t *testing.T) {
+	rec := httptest.NewRecorder()
+	HandleOpenAPI(rec, httptest.NewRequest("GET", "/openapi.json", nil))
+	require.Equal(t, ContentTypeJSON, rec.Header().Get(ContentTypeHeader))
+
+	var spec struct {
+		Components struct {
+			Schemas map[string]struct {
+				Properties map[string]json.RawMessage `json:"properties"`
+				Required   []string                   `json:"required"`
+			} `json:"schemas"`
+		} `json:"components"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &spec))
+
+	teleport, ok := spec.Components.Schemas["RPCTeleport"]
+	require.True(t, ok)
+	require.Contains(t, teleport.Properties, "token")
+	require.Contains(t, teleport.Properties, "amount_wei")
+	require.JSONEq(t,
+		`{"allOf": [{"$ref": "#/components/schemas/RPCDisbursement"}], "nullable": true}`,
+		string(teleport.Properties["disbursement"]),
+	)
+	require.Contains(t, spec.Components.Schemas, "RPCDisbursement")
+	require.Contains(t, spec.Components.Schemas, "TokenStatusResponse")
+
+	history, ok := spec.Components.Schemas["HistoryResponse"]
+	require.True(t, ok)
+	require.Equal(t, []string{"teleports"}, history.Required)
+}
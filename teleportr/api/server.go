@@ -19,11 +19,12 @@ import (
 	"github.com/ethereum-optimism/optimism/bss-core/dial"
 	"github.com/ethereum-optimism/optimism/bss-core/drivers"
 	"github.com/ethereum-optimism/optimism/bss-core/metrics"
-	"github.com/ethereum-optimism/optimism/bss-core/txmgr"
+	"github.com/ethereum-optimism/optimism/op-service/txmgr"
 	"github.com/ethereum-optimism/optimism/teleportr/bindings/deposit"
 	"github.com/ethereum-optimism/optimism/teleportr/bindings/disburse"
 	"github.com/ethereum-optimism/optimism/teleportr/db"
 	"github.com/ethereum-optimism/optimism/teleportr/flags"
+	"github.com/ethereum-optimism/optimism/teleportr/tokens"
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethclient"
@@ -68,6 +69,11 @@ func Main(gitVersion string) func(*cli.Context) error {
 			return err
 		}
 
+		tokenList, err := tokens.Load(cfg.TokensConfig)
+		if err != nil {
+			return err
+		}
+
 		l1Client, err := dial.L1EthClientWithTimeout(
 			ctx, cfg.L1EthRpc, cfg.DisableHTTP2,
 		)
@@ -105,6 +111,7 @@ func Main(gitVersion string) func(*cli.Context) error {
 			disburserWalletAddr,
 			depositContract,
 			disburserContract,
+			tokenList,
 		)
 
 		// TODO(conner): make read-only
@@ -133,6 +140,7 @@ func Main(gitVersion string) func(*cli.Context) error {
 			NewCachingChainDataReader(cdr, time.Minute),
 			depositAddr,
 			cfg.NumConfirmations,
+			tokenList,
 		)
 
 		var wg sync.WaitGroup
@@ -152,11 +160,13 @@ func Main(gitVersion string) func(*cli.Context) error {
 
 		select {
 		case <-interruptChannel:
-			_ = server.httpServer.Shutdown(ctx)
-			time.AfterFunc(defaultTimeout, func() {
-				cancel()
+			shutdownCtx, shutdownCancel := context.WithTimeout(ctx, defaultTimeout)
+			if err := server.httpServer.Shutdown(shutdownCtx); err != nil {
+				log.Warn("HTTP server did not shut down in time", "err", err)
 				_ = server.httpServer.Close()
-			})
+			}
+			shutdownCancel()
+			cancel()
 			wg.Wait()
 		case <-ctx.Done():
 		}
@@ -184,6 +194,7 @@ type Config struct {
 	MetricsHostname        string
 	MetricsPort            uint64
 	DisableHTTP2           bool
+	TokensConfig           string
 }
 
 func NewConfig(ctx *cli.Context) (Config, error) {
@@ -206,6 +217,7 @@ func NewConfig(ctx *cli.Context) (Config, error) {
 		MetricsHostname:        ctx.GlobalString(flags.MetricsHostnameFlag.Name),
 		MetricsPort:            ctx.GlobalUint64(flags.MetricsPortFlag.Name),
 		DisableHTTP2:           ctx.GlobalBool(flags.HTTP2DisableFlag.Name),
+		TokensConfig:           ctx.GlobalString(flags.TokensConfigFlag.Name),
 	}, nil
 }
 
@@ -224,6 +236,13 @@ type Server struct {
 	chainDataReader  ChainDataReader
 	depositAddr      common.Address
 	numConfirmations uint64
+	tokens           []tokens.Token
+
+	// streamsCtx is canceled when the HTTP server shuts down, which closes
+	// the open streams since Shutdown waits for all requests to finish.
+	streamsCtx  context.Context
+	stopStreams context.CancelFunc
+	streams     *streamPoller
 
 	httpServer *http.Server
 }
@@ -236,12 +255,13 @@ func NewServer(
 	chainDataReader ChainDataReader,
 	depositAddr common.Address,
 	numConfirmations uint64,
+	tokenList []tokens.Token,
 ) *Server {
 	if numConfirmations == 0 {
 		panic("NumConfirmations cannot be zero")
 	}
 
-	return &Server{
+	s := &Server{
 		ctx:              ctx,
 		l1Client:         l1Client,
 		l2Client:         l2Client,
@@ -249,12 +269,21 @@ func NewServer(
 		chainDataReader:  chainDataReader,
 		depositAddr:      depositAddr,
 		numConfirmations: numConfirmations,
+		tokens:           tokenList,
 	}
+	s.streamsCtx, s.stopStreams = context.WithCancel(ctx)
+	s.streams = newStreamPoller(
+		s.streamsCtx, StreamPollInterval, MaxStreams, s.pollStreamTopics,
+	)
+	return s
 }
 
-func (s *Server) ListenAndServe(host string, port uint16) error {
+// Router returns the routes served by the API. Every route must be described
+// in the OpenAPI document returned by OpenAPISpec.
+func (s *Server) Router() *mux.Router {
 	handler := mux.NewRouter()
 	handler.HandleFunc("/healthz", HandleHealthz).Methods("GET")
+	handler.HandleFunc("/openapi.json", HandleOpenAPI).Methods("GET")
 	handler.HandleFunc(
 		"/status",
 		instrumentedErrorHandler(s.HandleStatus),
@@ -263,6 +292,10 @@ func (s *Server) ListenAndServe(host string, port uint16) error {
 		"/estimate/{addr:0x[0-9a-fA-F]{40}}/{amount:[0-9]{1,80}}",
 		instrumentedErrorHandler(s.HandleEstimate),
 	).Methods("GET")
+	handler.HandleFunc(
+		"/estimate/{token:0x[0-9a-fA-F]{40}}/{addr:0x[0-9a-fA-F]{40}}/{amount:[0-9]{1,80}}",
+		instrumentedErrorHandler(s.HandleEstimate),
+	).Methods("GET")
 	handler.HandleFunc(
 		"/track/{txhash:0x[0-9a-fA-F]{64}}",
 		instrumentedErrorHandler(s.HandleTrack),
@@ -271,19 +304,39 @@ func (s *Server) ListenAndServe(host string, port uint16) error {
 		"/history/{addr:0x[0-9a-fA-F]{40}}",
 		instrumentedErrorHandler(s.HandleHistory),
 	).Methods("GET")
+	handler.HandleFunc(
+		"/stream/track/{txhash:0x[0-9a-fA-F]{64}}",
+		instrumentedStreamHandler(s.HandleTrackStream),
+	).Methods("GET")
+	handler.HandleFunc(
+		"/stream/history/{addr:0x[0-9a-fA-F]{40}}",
+		instrumentedStreamHandler(s.HandleHistoryStream),
+	).Methods("GET")
+	return handler
+}
+
+func (s *Server) ListenAndServe(host string, port uint16) error {
+	addr := fmt.Sprintf("%s:%d", host, port)
+	s.httpServer = s.newHTTPServer(addr)
+	log.Info("Starting HTTP server", "addr", addr)
+	return s.httpServer.ListenAndServe()
+}
+
+// newHTTPServer creates the HTTP server of the API, which closes the open
+// streams when it shuts down.
+func (s *Server) newHTTPServer(addr string) *http.Server {
 	c := cors.New(cors.Options{
 		AllowedOrigins: []string{"*"},
 	})
-	addr := fmt.Sprintf("%s:%d", host, port)
-	s.httpServer = &http.Server{
-		Handler: c.Handler(handler),
+	httpServer := &http.Server{
+		Handler: c.Handler(s.Router()),
 		Addr:    addr,
 		BaseContext: func(_ net.Listener) context.Context {
 			return s.ctx
 		},
 	}
-	log.Info("Starting HTTP server", "addr", addr)
-	return s.httpServer.ListenAndServe()
+	httpServer.RegisterOnShutdown(s.stopStreams)
+	return httpServer
 }
 
 func HandleHealthz(w http.ResponseWriter, r *http.Request) {
@@ -298,6 +351,45 @@ type StatusResponse struct {
 	MaxDepositAmountWei       string `json:"max_deposit_amount_wei"`
 	DisbursementLag           uint64 `json:"disbursement_lag"`
 	IsAvailable               bool   `json:"is_available"`
+
+	Tokens []TokenStatusResponse `json:"tokens"`
+}
+
+// TokenStatusResponse reports the limits and balances of an ERC20 token.
+// Amounts are denominated in the token's base units.
+type TokenStatusResponse struct {
+	Symbol                 string `json:"symbol"`
+	L1Address              string `json:"l1_address"`
+	L2Address              string `json:"l2_address"`
+	DepositAddress         string `json:"deposit_address"`
+	DisburserWalletBalance string `json:"disburser_wallet_balance"`
+	DepositAddressBalance  string `json:"deposit_address_balance"`
+	MaximumBalance         string `json:"maximum_balance"`
+	MinDepositAmount       string `json:"min_deposit_amount"`
+	MaxDepositAmount       string `json:"max_deposit_amount"`
+	IsAvailable            bool   `json:"is_available"`
+}
+
+func makeTokenStatusResponse(data TokenChainData) TokenStatusResponse {
+	token := data.Token
+	balanceAfterMaxDeposit := new(big.Int).Add(
+		data.DepositBalance, token.MaxDepositAmount,
+	)
+	isAvailable := token.MaxBalance.Cmp(balanceAfterMaxDeposit) >= 0 &&
+		data.DisburserBalance.Cmp(token.MaxDepositAmount) >= 0
+
+	return TokenStatusResponse{
+		Symbol:                 token.Symbol,
+		L1Address:              token.L1Address.String(),
+		L2Address:              token.L2Address.String(),
+		DepositAddress:         token.DepositAddress.String(),
+		DisburserWalletBalance: data.DisburserBalance.String(),
+		DepositAddressBalance:  data.DepositBalance.String(),
+		MaximumBalance:         token.MaxBalance.String(),
+		MinDepositAmount:       token.MinDepositAmount.String(),
+		MaxDepositAmount:       token.MaxDepositAmount.String(),
+		IsAvailable:            isAvailable,
+	}
 }
 
 func (s *Server) HandleStatus(
@@ -326,6 +418,10 @@ func (s *Server) HandleStatus(
 		MaxDepositAmountWei:       chainData.MaxDepositAmount.String(),
 		DisbursementLag:           disbursementLag,
 		IsAvailable:               isAvailable,
+		Tokens:                    make([]TokenStatusResponse, 0, len(chainData.Tokens)),
+	}
+	for _, tokenData := range chainData.Tokens {
+		resp.Tokens = append(resp.Tokens, makeTokenStatusResponse(tokenData))
 	}
 
 	jsonResp, err := json.Marshal(resp)
@@ -382,6 +478,36 @@ func (s *Server) HandleEstimate(
 		}
 	}
 
+	// Deposits of ETH are sent to the deposit contract, while deposits of a
+	// token are ERC20 transfers to the token's deposit address.
+	callMsg := ethereum.CallMsg{
+		From:  address,
+		To:    &s.depositAddr,
+		Value: amount,
+	}
+	if tokenStr, ok := vars["token"]; ok {
+		token, ok := tokens.Find(s.tokens, common.HexToAddress(tokenStr))
+		if !ok {
+			return StatusError{
+				Err:  errors.New("unknown token"),
+				Code: http.StatusNotFound,
+			}
+		}
+		if !token.InLimits(amount) {
+			return StatusError{
+				Err:  errors.New("amount outside of deposit limits"),
+				Code: http.StatusBadRequest,
+			}
+		}
+		data, err := tokens.TransferData(token.DepositAddress, amount)
+		if err != nil {
+			return err
+		}
+		callMsg.To = &token.L1Address
+		callMsg.Value = nil
+		callMsg.Data = data
+	}
+
 	gasTipCap, err := s.l1Client.SuggestGasTipCap(ctx)
 	if err != nil {
 		rpcErrorsTotal.WithLabelValues("suggest_gas_tip_cap").Inc()
@@ -405,15 +531,10 @@ func (s *Server) HandleEstimate(
 
 	gasFeeCap := txmgr.CalcGasFeeCap(header.BaseFee, gasTipCap)
 
-	gasUsed, err := s.l1Client.EstimateGas(ctx, ethereum.CallMsg{
-		From:      address,
-		To:        &s.depositAddr,
-		Gas:       0,
-		GasFeeCap: gasFeeCap,
-		GasTipCap: gasTipCap,
-		Value:     amount,
-		Data:      nil,
-	})
+	callMsg.GasFeeCap = gasFeeCap
+	callMsg.GasTipCap = gasTipCap
+
+	gasUsed, err := s.l1Client.EstimateGas(ctx, callMsg)
 	if err != nil {
 		rpcErrorsTotal.WithLabelValues("estimate_gas").Inc()
 		return err
@@ -436,8 +557,12 @@ func (s *Server) HandleEstimate(
 	return err
 }
 
+// RPCTeleport is the API representation of a teleport. Token is the L1 address
+// of the deposited ERC20 token, or the zero address for ETH. For token deposits
+// AmountWei holds the amount in the token's base units.
 type RPCTeleport struct {
 	ID             string           `json:"id"`
+	Token          string           `json:"token"`
 	Address        string           `json:"address"`
 	AmountWei      string           `json:"amount_wei"`
 	TxHash         string           `json:"tx_hash"`
@@ -449,6 +574,7 @@ type RPCTeleport struct {
 func makeRPCTeleport(teleport *db.Teleport) RPCTeleport {
 	rpcTeleport := RPCTeleport{
 		ID:             strconv.FormatUint(teleport.ID, 10),
+		Token:          teleport.Token.String(),
 		Address:        teleport.Address.String(),
 		AmountWei:      teleport.Amount.String(),
 		TxHash:         teleport.Deposit.TxnHash.String(),
@@ -496,22 +622,58 @@ func (s *Server) HandleTrack(
 	}
 	txHash := common.HexToHash(txHashStr)
 
+	resp, err := s.loadTrackResponse(ctx, txHash)
+	if err != nil {
+		return err
+	}
+
+	if resp == nil {
+		return StatusError{
+			Code: http.StatusNotFound,
+		}
+	}
+
+	jsonResp, err := json.Marshal(resp)
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set(ContentTypeHeader, ContentTypeJSON)
+	_, err = w.Write(jsonResp)
+	return err
+}
+
+// loadTrackResponse returns the state of the teleport with the given deposit
+// hash, or nil if the deposit is unknown.
+func (s *Server) loadTrackResponse(
+	ctx context.Context,
+	txHash common.Hash,
+) (*TrackResponse, error) {
+
 	blockNumber, err := s.l1Client.BlockNumber(ctx)
 	if err != nil {
 		rpcErrorsTotal.WithLabelValues("block_number").Inc()
-		return err
+		return nil, err
 	}
 
+	return s.loadTrackResponseAt(txHash, blockNumber)
+}
+
+// loadTrackResponseAt returns the state of the teleport with the given deposit
+// hash as of the given L1 block number, or nil if the deposit is unknown.
+func (s *Server) loadTrackResponseAt(
+	txHash common.Hash,
+	blockNumber uint64,
+) (*TrackResponse, error) {
+
 	teleport, err := s.database.LoadTeleportByDepositHash(txHash)
 	if err != nil {
 		databaseErrorsTotal.WithLabelValues("load_teleport_by_deposit_hash").Inc()
-		return err
+		return nil, err
 	}
 
 	if teleport == nil {
-		return StatusError{
-			Code: http.StatusNotFound,
-		}
+		return nil, nil
 	}
 
 	var confsRemaining uint64
@@ -520,25 +682,20 @@ func (s *Server) HandleTrack(
 			(blockNumber + 1)
 	}
 
-	resp := TrackResponse{
+	return &TrackResponse{
 		CurrentBlockNumber:     strconv.FormatUint(blockNumber, 10),
 		ConfirmationsRequired:  strconv.FormatUint(s.numConfirmations, 10),
 		ConfirmationsRemaining: strconv.FormatUint(confsRemaining, 10),
 		Teleport:               makeRPCTeleport(teleport),
-	}
-
-	jsonResp, err := json.Marshal(resp)
-	if err != nil {
-		return err
-	}
-
-	w.Header().Set(ContentTypeHeader, ContentTypeJSON)
-	_, err = w.Write(jsonResp)
-	return err
+	}, nil
 }
 
+// HistoryResponse holds a page of teleports of an address, from newest to
+// oldest. NextCursor is set if older teleports may exist, and is passed as the
+// cursor query parameter to load the next page.
 type HistoryResponse struct {
-	Teleports []RPCTeleport `json:"teleports"`
+	Teleports  []RPCTeleport `json:"teleports"`
+	NextCursor string        `json:"next_cursor,omitempty"`
 }
 
 func (s *Server) HandleHistory(
@@ -557,7 +714,32 @@ func (s *Server) HandleHistory(
 	}
 	addr := common.HexToAddress(addrStr)
 
-	teleports, err := s.database.LoadTeleportsByAddress(addr)
+	query := r.URL.Query()
+	var cursor *db.TeleportCursor
+	if cursorStr := query.Get("cursor"); cursorStr != "" {
+		decoded, err := DecodeCursor(cursorStr)
+		if err != nil {
+			return StatusError{
+				Err:  err,
+				Code: http.StatusBadRequest,
+			}
+		}
+		cursor = &decoded
+	}
+	limit := uint64(db.MaxTeleportsPageSize)
+	if limitStr := query.Get("limit"); limitStr != "" {
+		parsed, err := strconv.ParseUint(limitStr, 10, 64)
+		if err != nil || parsed == 0 || parsed > db.MaxTeleportsPageSize {
+			return StatusError{
+				Err: fmt.Errorf("limit must be between 1 and %d",
+					db.MaxTeleportsPageSize),
+				Code: http.StatusBadRequest,
+			}
+		}
+		limit = parsed
+	}
+
+	teleports, err := s.database.LoadTeleportsByAddress(addr, cursor, limit)
 	if err != nil {
 		databaseErrorsTotal.WithLabelValues("load_teleports_by_address").Inc()
 		return err
@@ -571,6 +753,9 @@ func (s *Server) HandleHistory(
 	resp := HistoryResponse{
 		Teleports: rpcTeleports,
 	}
+	if uint64(len(teleports)) == limit {
+		resp.NextCursor = EncodeCursor(teleports[len(teleports)-1].Cursor())
+	}
 
 	jsonResp, err := json.Marshal(resp)
 	if err != nil {
@@ -0,0 +1,516 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/teleportr/db"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+const (
+	ContentTypeEventStream = "text/event-stream"
+
+	// StreamPollInterval is the interval at which streams check for changes
+	// to the teleports they follow.
+	StreamPollInterval = 5 * time.Second
+
+	// MaxStreams is the maximum number of concurrently open streams. Streams
+	// opened beyond the limit are refused with 503 Service Unavailable.
+	MaxStreams = 1000
+
+	// EventTrack is the name of events sent by track streams.
+	EventTrack = "track"
+
+	// EventTeleport is the name of events sent by history streams.
+	EventTeleport = "teleport"
+)
+
+// ErrTooManyStreams is returned when subscribing to the stream poller while
+// MaxStreams streams are open.
+var ErrTooManyStreams = errors.New("too many open streams")
+
+// streamEvent is a server-sent event. The event is only sent if its state
+// differs from the state last sent for the same key.
+type streamEvent struct {
+	key   string
+	name  string
+	state interface{}
+	data  interface{}
+}
+
+// streamTopic identifies what a stream follows: a deposit hash for track
+// streams, or an address for history streams. Streams following the same topic
+// share the same polls.
+type streamTopic struct {
+	name string
+	key  string
+}
+
+// streamUpdate is the result of polling a topic.
+type streamUpdate struct {
+	events []streamEvent
+	err    error
+}
+
+// streamSubscription receives the updates of a topic. Only the latest update
+// is kept, such that slow clients don't hold up the poller.
+type streamSubscription struct {
+	topic   streamTopic
+	updates chan streamUpdate
+}
+
+// streamTopicState holds the subscriptions of a topic, and its latest update
+// which is sent to new subscriptions right away.
+type streamTopicState struct {
+	subs   map[*streamSubscription]struct{}
+	latest *streamUpdate
+}
+
+// streamPoller polls the topics followed by all open streams from a single
+// goroutine, and fans the updates out to the subscribed streams. The goroutine
+// only runs while streams are open.
+type streamPoller struct {
+	ctx        context.Context
+	interval   time.Duration
+	maxStreams int
+	poll       func(context.Context, []streamTopic) map[streamTopic]streamUpdate
+
+	mu      sync.Mutex
+	topics  map[streamTopic]*streamTopicState
+	numSubs int
+	running bool
+
+	// wake is signaled when a topic without updates is subscribed to, so
+	// that it is polled without waiting for the next interval.
+	wake chan struct{}
+}
+
+func newStreamPoller(
+	ctx context.Context,
+	interval time.Duration,
+	maxStreams int,
+	poll func(context.Context, []streamTopic) map[streamTopic]streamUpdate,
+) *streamPoller {
+
+	return &streamPoller{
+		ctx:        ctx,
+		interval:   interval,
+		maxStreams: maxStreams,
+		poll:       poll,
+		topics:     make(map[streamTopic]*streamTopicState),
+		wake:       make(chan struct{}, 1),
+	}
+}
+
+// subscribe registers a stream following the given topic, and starts the
+// polling goroutine if it is not running. ErrTooManyStreams is returned if
+// maxStreams streams are open.
+func (p *streamPoller) subscribe(topic streamTopic) (*streamSubscription, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.numSubs >= p.maxStreams {
+		return nil, ErrTooManyStreams
+	}
+
+	sub := &streamSubscription{
+		topic:   topic,
+		updates: make(chan streamUpdate, 1),
+	}
+	state, ok := p.topics[topic]
+	if !ok {
+		state = &streamTopicState{
+			subs: make(map[*streamSubscription]struct{}),
+		}
+		p.topics[topic] = state
+	}
+	state.subs[sub] = struct{}{}
+	p.numSubs++
+
+	if state.latest != nil {
+		sub.updates <- *state.latest
+	} else {
+		select {
+		case p.wake <- struct{}{}:
+		default:
+		}
+	}
+
+	if !p.running {
+		p.running = true
+		go p.loop()
+	}
+
+	return sub, nil
+}
+
+// unsubscribe removes the subscription, and forgets its topic once no stream
+// follows it anymore.
+func (p *streamPoller) unsubscribe(sub *streamSubscription) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	state, ok := p.topics[sub.topic]
+	if !ok {
+		return
+	}
+	if _, ok := state.subs[sub]; !ok {
+		return
+	}
+	delete(state.subs, sub)
+	p.numSubs--
+	if len(state.subs) == 0 {
+		delete(p.topics, sub.topic)
+	}
+}
+
+// loop polls all topics at every interval, and topics without updates as soon
+// as they are subscribed to. It returns once no stream is open.
+func (p *streamPoller) loop() {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.ctx.Done():
+			p.mu.Lock()
+			p.running = false
+			p.mu.Unlock()
+			return
+		case <-p.wake:
+			p.pollTopics(false)
+		case <-ticker.C:
+			p.pollTopics(true)
+		}
+
+		p.mu.Lock()
+		if p.numSubs == 0 {
+			p.running = false
+			p.mu.Unlock()
+			return
+		}
+		p.mu.Unlock()
+	}
+}
+
+// pollTopics polls the subscribed topics, or only those without updates if all
+// is false, and sends the updates to their subscriptions.
+func (p *streamPoller) pollTopics(all bool) {
+	p.mu.Lock()
+	var topics []streamTopic
+	for topic, state := range p.topics {
+		if all || state.latest == nil {
+			topics = append(topics, topic)
+		}
+	}
+	p.mu.Unlock()
+
+	if len(topics) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(p.ctx, defaultTimeout)
+	updates := p.poll(ctx, topics)
+	cancel()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for topic, update := range updates {
+		state, ok := p.topics[topic]
+		if !ok {
+			continue
+		}
+		update := update
+		state.latest = &update
+		for sub := range state.subs {
+			// Replace any update the stream has yet to receive.
+			select {
+			case <-sub.updates:
+			default:
+			}
+			sub.updates <- update
+		}
+	}
+}
+
+// pollStreamTopics loads the events of each topic. The L1 block number is
+// fetched once for all track topics.
+func (s *Server) pollStreamTopics(
+	ctx context.Context,
+	topics []streamTopic,
+) map[streamTopic]streamUpdate {
+
+	updates := make(map[streamTopic]streamUpdate, len(topics))
+
+	var blockNumber *uint64
+	var blockNumberErr error
+	for _, topic := range topics {
+		switch topic.name {
+		case EventTrack:
+			if blockNumber == nil && blockNumberErr == nil {
+				number, err := s.l1Client.BlockNumber(ctx)
+				if err != nil {
+					rpcErrorsTotal.WithLabelValues("block_number").Inc()
+					blockNumberErr = err
+				} else {
+					blockNumber = &number
+				}
+			}
+			if blockNumberErr != nil {
+				updates[topic] = streamUpdate{err: blockNumberErr}
+				continue
+			}
+			events, err := s.pollTrackEvents(topic.key, *blockNumber)
+			updates[topic] = streamUpdate{events: events, err: err}
+
+		case EventTeleport:
+			events, err := s.pollHistoryEvents(topic.key)
+			updates[topic] = streamUpdate{events: events, err: err}
+		}
+	}
+
+	return updates
+}
+
+// pollTrackEvents returns the track event of the teleport with the given
+// deposit hash, if the deposit is known.
+func (s *Server) pollTrackEvents(
+	txHashStr string,
+	blockNumber uint64,
+) ([]streamEvent, error) {
+
+	resp, err := s.loadTrackResponseAt(common.HexToHash(txHashStr), blockNumber)
+	if err != nil || resp == nil {
+		return nil, err
+	}
+
+	// The current block number changes every block, so only the remaining
+	// confirmations and the teleport itself make up the state.
+	return []streamEvent{{
+		key:  txHashStr,
+		name: EventTrack,
+		state: struct {
+			ConfirmationsRemaining string
+			Teleport               RPCTeleport
+		}{resp.ConfirmationsRemaining, resp.Teleport},
+		data: resp,
+	}}, nil
+}
+
+// pollHistoryEvents returns a teleport event for each of the most recent
+// teleports of the given address.
+func (s *Server) pollHistoryEvents(addrStr string) ([]streamEvent, error) {
+	teleports, err := s.database.LoadTeleportsByAddress(
+		common.HexToAddress(addrStr), nil, db.MaxTeleportsPageSize,
+	)
+	if err != nil {
+		databaseErrorsTotal.WithLabelValues("load_teleports_by_address").Inc()
+		return nil, err
+	}
+
+	// Send the oldest teleports first, so that clients can append events to
+	// their history in order.
+	events := make([]streamEvent, 0, len(teleports))
+	for i := len(teleports) - 1; i >= 0; i-- {
+		teleport := makeRPCTeleport(&teleports[i])
+		events = append(events, streamEvent{
+			key:   teleport.Token + ":" + teleport.ID,
+			name:  EventTeleport,
+			state: teleport,
+			data:  teleport,
+		})
+	}
+	return events, nil
+}
+
+// HandleTrackStream streams the state of the teleport with the given deposit
+// hash as server-sent events. An event is sent once the deposit is known, and
+// again whenever its confirmations or disbursement change.
+func (s *Server) HandleTrackStream(
+	ctx context.Context,
+	w http.ResponseWriter,
+	r *http.Request,
+) error {
+
+	txHash := common.HexToHash(mux.Vars(r)["txhash"])
+
+	return s.stream(ctx, w, streamTopic{
+		name: EventTrack,
+		key:  txHash.String(),
+	})
+}
+
+// HandleHistoryStream streams the most recent teleports of an address as
+// server-sent events. An event is sent for each teleport when it is first seen,
+// and again whenever it changes, e.g. once it is disbursed.
+func (s *Server) HandleHistoryStream(
+	ctx context.Context,
+	w http.ResponseWriter,
+	r *http.Request,
+) error {
+
+	addr := common.HexToAddress(mux.Vars(r)["addr"])
+
+	return s.stream(ctx, w, streamTopic{
+		name: EventTeleport,
+		key:  addr.String(),
+	})
+}
+
+// stream subscribes to the updates of the topic until the client disconnects
+// or the server shuts down, and writes any events whose state changed since they were last sent. A
+// comment is written when nothing changed to keep idle connections open.
+func (s *Server) stream(
+	ctx context.Context,
+	w http.ResponseWriter,
+	topic streamTopic,
+) error {
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return StatusError{
+			Code: http.StatusNotImplemented,
+		}
+	}
+
+	sub, err := s.streams.subscribe(topic)
+	if err != nil {
+		return StatusError{
+			Err:  err,
+			Code: http.StatusServiceUnavailable,
+		}
+	}
+	defer s.streams.unsubscribe(sub)
+
+	// Close the stream when the server shuts down.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-s.streamsCtx.Done():
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	w.Header().Set(ContentTypeHeader, ContentTypeEventStream)
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	return writeStreamUpdates(ctx, w, flusher, sub.updates)
+}
+
+// writeStreamUpdates writes the events of the updates until the client
+// disconnects or an update fails.
+func writeStreamUpdates(
+	ctx context.Context,
+	w http.ResponseWriter,
+	flusher http.Flusher,
+	updates <-chan streamUpdate,
+) error {
+
+	lastStates := make(map[string][]byte)
+	for {
+		var update streamUpdate
+		select {
+		case <-ctx.Done():
+			return nil
+		case update = <-updates:
+		}
+		if update.err != nil {
+			return update.err
+		}
+
+		var sent int
+		for _, event := range update.events {
+			state, err := json.Marshal(event.state)
+			if err != nil {
+				return err
+			}
+			if bytes.Equal(lastStates[event.key], state) {
+				continue
+			}
+
+			data, err := json.Marshal(event.data)
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.name, data)
+			if err != nil {
+				return err
+			}
+			lastStates[event.key] = state
+			sent++
+		}
+		if sent == 0 {
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return err
+			}
+		}
+		flusher.Flush()
+	}
+}
+
+// instrumentedStreamHandler is the equivalent of instrumentedErrorHandler for
+// long-lived streams, which are not bound by the request timeout. Errors after
+// the stream started can only be logged, as the status was already sent.
+func instrumentedStreamHandler(
+	h func(context.Context, http.ResponseWriter, *http.Request) error,
+) func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		rpcRequestsTotal.Inc()
+		activeStreams.Inc()
+		defer activeStreams.Dec()
+
+		ctx := context.WithValue(r.Context(), ContextKeyReqID, uuid.NewString())
+		reqID := GetReqID(ctx)
+
+		log.Info("HTTP stream opened",
+			"req_id", reqID,
+			"path", r.URL.Path,
+			"user_agent", r.UserAgent())
+
+		start := time.Now()
+		err := h(ctx, w, r)
+		elapsed := time.Since(start)
+
+		statusCode := http.StatusOK
+		switch e := err.(type) {
+		case nil:
+			log.Info("HTTP stream closed",
+				"req_id", reqID,
+				"elapsed", elapsed)
+
+		case Error:
+			statusCode = e.Status()
+			log.Warn("HTTP stream error",
+				"req_id", reqID,
+				"elapsed", elapsed,
+				"status", statusCode,
+				"err", e.Error())
+			http.Error(w, e.Error(), statusCode)
+
+		default:
+			log.Warn("HTTP stream internal error",
+				"req_id", reqID,
+				"elapsed", elapsed,
+				"err", err)
+		}
+
+		httpResponseCodesTotal.WithLabelValues(strconv.Itoa(statusCode)).Inc()
+	}
+}
@@ -0,0 +1,143 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+// TestStreamSendsChangedEvents asserts that events are only sent when their
+// state changes, and that keep-alives are sent otherwise.
+func TestStreamSendsChangedEvents(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	updates := make(chan streamUpdate)
+	go func() {
+		updates <- streamUpdate{events: []streamEvent{
+			{key: "a", name: "teleport", state: 1, data: "first"},
+		}}
+		updates <- streamUpdate{events: []streamEvent{
+			{key: "a", name: "teleport", state: 1, data: "ignored"},
+		}}
+		updates <- streamUpdate{events: []streamEvent{
+			{key: "a", name: "teleport", state: 2, data: "second"},
+			{key: "b", name: "teleport", state: 1, data: "third"},
+		}}
+		cancel()
+	}()
+
+	rec := httptest.NewRecorder()
+	err := writeStreamUpdates(ctx, rec, rec, updates)
+	require.NoError(t, err)
+	require.Equal(t,
+		"event: teleport\ndata: \"first\"\n\n"+
+			": keep-alive\n\n"+
+			"event: teleport\ndata: \"second\"\n\n"+
+			"event: teleport\ndata: \"third\"\n\n",
+		rec.Body.String(),
+	)
+}
+
+// TestStreamPollerSharesPolls asserts that streams following the same topic
+// share polls, that new topics are polled right away, and that the number of
+// streams is capped.
+func TestStreamPollerSharesPolls(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	polled := make(chan []streamTopic, 10)
+	poll := func(ctx context.Context, topics []streamTopic) map[streamTopic]streamUpdate {
+		polled <- topics
+		updates := make(map[streamTopic]streamUpdate)
+		for _, topic := range topics {
+			updates[topic] = streamUpdate{events: []streamEvent{
+				{key: topic.key, name: topic.name, state: 1, data: topic.key},
+			}}
+		}
+		return updates
+	}
+	p := newStreamPoller(ctx, time.Hour, 2, poll)
+
+	topicA := streamTopic{name: EventTrack, key: "a"}
+	topicB := streamTopic{name: EventTeleport, key: "b"}
+
+	subA1, err := p.subscribe(topicA)
+	require.NoError(t, err)
+	require.Equal(t, []streamTopic{topicA}, <-polled)
+	require.Equal(t, "a", (<-subA1.updates).events[0].data)
+
+	// A second stream of the same topic receives the latest update without
+	// polling again.
+	subA2, err := p.subscribe(topicA)
+	require.NoError(t, err)
+	require.Equal(t, "a", (<-subA2.updates).events[0].data)
+	require.Empty(t, polled)
+
+	_, err = p.subscribe(topicB)
+	require.Equal(t, ErrTooManyStreams, err)
+
+	p.unsubscribe(subA1)
+	subB, err := p.subscribe(topicB)
+	require.NoError(t, err)
+	require.Equal(t, []streamTopic{topicB}, <-polled)
+	require.Equal(t, "b", (<-subB.updates).events[0].data)
+}
+
+// TestServerShutdownClosesStreams asserts that shutting down the HTTP server
+// closes the open streams instead of waiting for clients to disconnect.
+func TestServerShutdownClosesStreams(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s := &Server{ctx: ctx}
+	s.streamsCtx, s.stopStreams = context.WithCancel(ctx)
+	poll := func(ctx context.Context, topics []streamTopic) map[streamTopic]streamUpdate {
+		updates := make(map[streamTopic]streamUpdate)
+		for _, topic := range topics {
+			updates[topic] = streamUpdate{events: []streamEvent{
+				{key: topic.key, name: topic.name, state: 1, data: topic.key},
+			}}
+		}
+		return updates
+	}
+	s.streams = newStreamPoller(s.streamsCtx, time.Hour, MaxStreams, poll)
+
+	httpServer := s.newHTTPServer("")
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	served := make(chan error, 1)
+	go func() {
+		served <- httpServer.Serve(listener)
+	}()
+
+	addr := common.Address{0x01}
+	resp, err := http.Get(
+		fmt.Sprintf("http://%s/stream/history/%s", listener.Addr(), addr),
+	)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	body := bufio.NewReader(resp.Body)
+	line, err := body.ReadString('\n')
+	require.NoError(t, err)
+	require.Equal(t, "event: teleport\n", line)
+
+	shutdownCtx, shutdownCancel := context.WithTimeout(ctx, 5*time.Second)
+	defer shutdownCancel()
+	require.NoError(t, httpServer.Shutdown(shutdownCtx))
+	require.ErrorIs(t, <-served, http.ErrServerClosed)
+
+	// The stream ends instead of being cut off.
+	_, err = io.ReadAll(body)
+	require.NoError(t, err)
+}
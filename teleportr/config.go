@@ -111,6 +111,20 @@ type Config struct {
 
 	// DisableHTTP2 disables HTTP2 support.
 	DisableHTTP2 bool
+
+	// TokensConfig is the path to the JSON file listing the ERC20 tokens that
+	// can be teleported in addition to ETH.
+	TokensConfig string
+
+	// AdminServerEnable if true, will run the admin server used by operators
+	// to acknowledge L1 reorgs that paused disbursements. Defaults to true.
+	AdminServerEnable bool
+
+	// AdminHostname is the hostname at which the admin server is running.
+	AdminHostname string
+
+	// AdminPort is the port at which the admin server is running.
+	AdminPort uint64
 }
 
 func NewConfig(ctx *cli.Context) (Config, error) {
@@ -145,5 +159,9 @@ func NewConfig(ctx *cli.Context) (Config, error) {
 		MetricsHostname:     ctx.GlobalString(flags.MetricsHostnameFlag.Name),
 		MetricsPort:         ctx.GlobalUint64(flags.MetricsPortFlag.Name),
 		DisableHTTP2:        ctx.GlobalBool(flags.HTTP2DisableFlag.Name),
+		TokensConfig:        ctx.GlobalString(flags.TokensConfigFlag.Name),
+		AdminServerEnable:   ctx.GlobalBoolT(flags.AdminServerEnableFlag.Name),
+		AdminHostname:       ctx.GlobalString(flags.AdminHostnameFlag.Name),
+		AdminPort:           ctx.GlobalUint64(flags.AdminPortFlag.Name),
 	}, nil
 }
@@ -19,6 +19,10 @@ var (
 
 	// ErrUnknownDeposit signals that the target deposit could not be found.
 	ErrUnknownDeposit = errors.New("unknown deposit")
+
+	// ErrUnknownReorg signals that the target reorg could not be found, or has
+	// already been acknowledged.
+	ErrUnknownReorg = errors.New("unknown reorg")
 )
 
 // ConfirmationInfo holds metadata about a tx on either the L1 or L2 chain.
@@ -29,15 +33,82 @@ type ConfirmationInfo struct {
 }
 
 // Deposit represents an event emitted from the TeleportrDeposit contract on L1,
-// along with additional info about the tx that generated the event.
+// or an ERC20 transfer to a token deposit address, along with additional info
+// about the tx that generated the event. Token is the L1 address of the ERC20
+// token, or the zero address for ETH deposits.
 type Deposit struct {
 	ID      uint64
+	Token   common.Address
 	Address common.Address
 	Amount  *big.Int
 
+	// BlockHash is the hash of the L1 block containing the deposit, used to
+	// detect deposits that were removed by a reorg.
+	BlockHash common.Hash
+
 	ConfirmationInfo
 }
 
+// BlockID identifies an L1 block by number and hash.
+type BlockID struct {
+	Number uint64
+	Hash   common.Hash
+}
+
+// Reorg records an L1 reorg that removed blocks containing confirmed
+// deposits. Disbursements are paused while a reorg is unacknowledged.
+type Reorg struct {
+	ID uint64
+
+	// BlockNumber is the number of the earliest removed block holding
+	// deposits.
+	BlockNumber uint64
+
+	// OldBlockHash is the hash of the removed block.
+	OldBlockHash common.Hash
+
+	// NewBlockHash is the hash of the canonical block at the same height, or
+	// the zero hash if the chain is now shorter.
+	NewBlockHash common.Hash
+
+	// RolledBackDeposits is the number of undisbursed deposits that were
+	// removed from the database to be re-ingested.
+	RolledBackDeposits uint64
+
+	// DetectedAt is the time the reorg was detected.
+	DetectedAt time.Time
+}
+
+// RejectedDepositStatus is the status of a rejected deposit.
+type RejectedDepositStatus string
+
+const (
+	// RejectedDepositStatusRejected marks a rejected deposit that has not
+	// been refunded yet.
+	RejectedDepositStatusRejected RejectedDepositStatus = "rejected"
+
+	// RejectedDepositStatusRefunded marks a rejected deposit that was
+	// refunded by an operator.
+	RejectedDepositStatusRefunded RejectedDepositStatus = "refunded"
+)
+
+// RejectedDeposit is a token deposit that is not disbursed, as it falls
+// outside of the deposit limits of the token. It is kept until an operator
+// refunds it.
+type RejectedDeposit struct {
+	Deposit
+
+	// Reason describes why the deposit was rejected.
+	Reason string
+
+	// Status is the refund status of the deposit.
+	Status RejectedDepositStatus
+
+	// RefundTxnHash is the hash of the refund transaction, or the zero hash if
+	// the deposit was not refunded yet.
+	RefundTxnHash common.Hash
+}
+
 type Disbursement struct {
 	Success bool
 
@@ -97,6 +168,85 @@ CREATE TABLE IF NOT EXISTS pending_txs (
 );
 `
 
+const addDepositsTokenColumn = `
+ALTER TABLE deposits
+ADD COLUMN IF NOT EXISTS token VARCHAR NOT NULL
+DEFAULT '0x0000000000000000000000000000000000000000'
+`
+
+const addDisbursementsTokenColumn = `
+ALTER TABLE disbursements
+ADD COLUMN IF NOT EXISTS token VARCHAR NOT NULL
+DEFAULT '0x0000000000000000000000000000000000000000'
+`
+
+const addPendingTxsTokenColumn = `
+ALTER TABLE pending_txs
+ADD COLUMN IF NOT EXISTS token VARCHAR NOT NULL
+DEFAULT '0x0000000000000000000000000000000000000000'
+`
+
+// keyDepositsByToken replaces the deposit id primary keys with (token, id),
+// since deposit ids are only unique for a given token.
+const keyDepositsByToken = `
+DO $$
+BEGIN
+	IF NOT EXISTS (
+		SELECT 1 FROM pg_constraint WHERE conname = 'deposits_token_id_pkey'
+	) THEN
+		ALTER TABLE disbursements DROP CONSTRAINT IF EXISTS disbursements_id_fkey;
+		ALTER TABLE disbursements DROP CONSTRAINT IF EXISTS disbursements_pkey;
+		ALTER TABLE deposits DROP CONSTRAINT IF EXISTS deposits_pkey;
+		ALTER TABLE deposits
+			ADD CONSTRAINT deposits_token_id_pkey PRIMARY KEY (token, id);
+		ALTER TABLE disbursements
+			ADD CONSTRAINT disbursements_token_id_pkey PRIMARY KEY (token, id);
+		ALTER TABLE disbursements
+			ADD CONSTRAINT disbursements_token_id_fkey FOREIGN KEY (token, id)
+			REFERENCES deposits (token, id);
+	END IF;
+END $$;
+`
+
+const addDepositsBlockHashColumn = `
+ALTER TABLE deposits
+ADD COLUMN IF NOT EXISTS block_hash VARCHAR NOT NULL DEFAULT ''
+`
+
+const addLastProcessedBlockHashColumn = `
+ALTER TABLE last_processed_block
+ADD COLUMN IF NOT EXISTS hash VARCHAR NOT NULL DEFAULT ''
+`
+
+const reorgsTable = `
+CREATE TABLE IF NOT EXISTS reorgs (
+	id SERIAL8 PRIMARY KEY,
+	block_number INT8 NOT NULL,
+	old_block_hash VARCHAR NOT NULL,
+	new_block_hash VARCHAR NOT NULL,
+	rolled_back_deposits INT8 NOT NULL,
+	detected_at TIMESTAMPTZ NOT NULL,
+	acknowledged_at TIMESTAMPTZ
+);
+`
+
+const rejectedDepositsTable = `
+CREATE TABLE IF NOT EXISTS rejected_deposits (
+	token VARCHAR NOT NULL,
+	id INT8 NOT NULL,
+	txn_hash VARCHAR NOT NULL,
+	block_number INT8 NOT NULL,
+	block_hash VARCHAR NOT NULL,
+	block_timestamp TIMESTAMPTZ NOT NULL,
+	address VARCHAR NOT NULL,
+	amount VARCHAR NOT NULL,
+	reason VARCHAR NOT NULL,
+	status VARCHAR NOT NULL,
+	refund_txn_hash VARCHAR NOT NULL DEFAULT '',
+	PRIMARY KEY (token, id)
+);
+`
+
 var migrations = []string{
 	createDepositsTable,
 	createDepositTxnHashIndex,
@@ -104,6 +254,14 @@ var migrations = []string{
 	createDisbursementsTable,
 	lastProcessedBlockTable,
 	pendingTxTable,
+	addDepositsTokenColumn,
+	addDisbursementsTokenColumn,
+	addPendingTxsTokenColumn,
+	keyDepositsByToken,
+	addDepositsBlockHashColumn,
+	addLastProcessedBlockHashColumn,
+	reorgsTable,
+	rejectedDepositsTable,
 }
 
 // Config houses the data required to connect to a Postgres backend.
@@ -188,24 +346,26 @@ func (d *Database) Close() error {
 }
 
 const upsertLastProcessedBlock = `
-INSERT INTO last_processed_block (value)
-VALUES ($1)
+INSERT INTO last_processed_block (value, hash)
+VALUES ($1, $2)
 ON CONFLICT (id) DO UPDATE
-SET value = $1
+SET (value, hash) = ($1, $2)
 `
 
 const upsertDepositStatement = `
-INSERT INTO deposits (id, txn_hash, block_number, block_timestamp, address, amount)
-VALUES ($1, $2, $3, $4, $5, $6)
-ON CONFLICT (id) DO UPDATE 
-SET (txn_hash, block_number, block_timestamp, address, amount) = ($2, $3, $4, $5, $6)
+INSERT INTO deposits (id, txn_hash, block_number, block_timestamp, address, amount, token, block_hash)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+ON CONFLICT (token, id) DO UPDATE
+SET (txn_hash, block_number, block_timestamp, address, amount, block_hash) = ($2, $3, $4, $5, $6, $8)
 `
 
 // UpsertDeposits inserts a list of deposits into the database, or updats an
-// existing deposit in place if the same ID is found.
+// existing deposit in place if the same token and ID is found. The hash of the
+// last processed block is recorded so that later reorgs can be detected.
 func (d *Database) UpsertDeposits(
 	deposits []Deposit,
 	lastProcessedBlock uint64,
+	lastProcessedBlockHash common.Hash,
 ) error {
 
 	// Sanity check deposits.
@@ -232,13 +392,19 @@ func (d *Database) UpsertDeposits(
 			deposit.BlockTimestamp,
 			deposit.Address.String(),
 			deposit.Amount.String(),
+			deposit.Token.String(),
+			deposit.BlockHash.String(),
 		)
 		if err != nil {
 			return err
 		}
 	}
 
-	_, err = tx.Exec(upsertLastProcessedBlock, lastProcessedBlock)
+	_, err = tx.Exec(
+		upsertLastProcessedBlock,
+		lastProcessedBlock,
+		lastProcessedBlockHash.String(),
+	)
 	if err != nil {
 		return err
 	}
@@ -264,18 +430,384 @@ func (d *Database) LastProcessedBlock() (*uint64, error) {
 	return &lastProcessedBlock, nil
 }
 
+const lastProcessedBlockHashQuery = `
+SELECT hash FROM last_processed_block
+`
+
+// LastProcessedBlockHash returns the hash of the last processed block, or the
+// zero hash if no block was processed or the hash was not recorded.
+func (d *Database) LastProcessedBlockHash() (common.Hash, error) {
+	row := d.conn.QueryRow(lastProcessedBlockHashQuery)
+
+	var hashStr string
+	err := row.Scan(&hashStr)
+	if err == sql.ErrNoRows || (err == nil && hashStr == "") {
+		return common.Hash{}, nil
+	} else if err != nil {
+		return common.Hash{}, err
+	}
+
+	return common.HexToHash(hashStr), nil
+}
+
+const depositBlocksQuery = `
+SELECT DISTINCT block_number, block_hash
+FROM deposits
+WHERE block_number >= $1 AND block_hash != ''
+ORDER BY block_number ASC
+`
+
+// DepositBlocks returns the blocks at or above fromBlock that contain deposits
+// with a recorded block hash, in ascending order.
+func (d *Database) DepositBlocks(fromBlock uint64) ([]BlockID, error) {
+	rows, err := d.conn.Query(depositBlocksQuery, fromBlock)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var blocks []BlockID
+	for rows.Next() {
+		var block BlockID
+		var hashStr string
+		err = rows.Scan(&block.Number, &hashStr)
+		if err != nil {
+			return nil, err
+		}
+		block.Hash = common.HexToHash(hashStr)
+
+		blocks = append(blocks, block)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return blocks, nil
+}
+
+const deleteUndisbursedDepositsStatement = `
+DELETE FROM deposits AS dep
+WHERE dep.block_number >= $1 AND NOT EXISTS (
+	SELECT 1 FROM disbursements AS dis
+	WHERE dis.token = dep.token AND dis.id = dep.id
+)
+`
+
+const deleteUnrefundedRejectedDepositsStatement = `
+DELETE FROM rejected_deposits
+WHERE block_number >= $1 AND status = 'rejected'
+`
+
+// RollbackDeposits removes all undisbursed deposits and unrefunded rejected
+// deposits at or above fromBlock, and rewinds the last processed block to the
+// given block, such that the removed range is ingested again. The number of
+// removed undisbursed deposits is returned.
+func (d *Database) RollbackDeposits(
+	fromBlock uint64,
+	lastProcessedBlock BlockID,
+) (uint64, error) {
+
+	tx, err := d.conn.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	result, err := tx.Exec(deleteUndisbursedDepositsStatement, fromBlock)
+	if err != nil {
+		return 0, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	_, err = tx.Exec(deleteUnrefundedRejectedDepositsStatement, fromBlock)
+	if err != nil {
+		return 0, err
+	}
+
+	_, err = tx.Exec(
+		upsertLastProcessedBlock,
+		lastProcessedBlock.Number,
+		lastProcessedBlock.Hash.String(),
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return uint64(rowsAffected), tx.Commit()
+}
+
+const upsertRejectedDepositStatement = `
+INSERT INTO rejected_deposits (token, id, txn_hash, block_number, block_hash, block_timestamp, address, amount, reason, status)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, 'rejected')
+ON CONFLICT (token, id) DO UPDATE
+SET (txn_hash, block_number, block_hash, block_timestamp, address, amount, reason) = ($3, $4, $5, $6, $7, $8, $9)
+WHERE rejected_deposits.status = 'rejected'
+`
+
+// UpsertRejectedDeposits records a list of rejected deposits, or updates an
+// unrefunded rejected deposit in place if the same token and ID is found.
+func (d *Database) UpsertRejectedDeposits(deposits []RejectedDeposit) error {
+	// Sanity check deposits.
+	for _, deposit := range deposits {
+		if deposit.BlockTimestamp.IsZero() {
+			return ErrZeroTimestamp
+		}
+	}
+
+	tx, err := d.conn.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	for _, deposit := range deposits {
+		_, err = tx.Exec(
+			upsertRejectedDepositStatement,
+			deposit.Token.String(),
+			deposit.ID,
+			deposit.TxnHash.String(),
+			deposit.BlockNumber,
+			deposit.BlockHash.String(),
+			deposit.BlockTimestamp,
+			deposit.Address.String(),
+			deposit.Amount.String(),
+			deposit.Reason,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+const unrefundedRejectedDepositsQuery = `
+SELECT token, id, txn_hash, block_number, block_hash, block_timestamp, address, amount, reason, status, refund_txn_hash
+FROM rejected_deposits
+WHERE status = 'rejected'
+ORDER BY block_number ASC, token ASC, id ASC
+`
+
+// UnrefundedRejectedDeposits returns all rejected deposits that have not been
+// refunded by an operator.
+func (d *Database) UnrefundedRejectedDeposits() ([]RejectedDeposit, error) {
+	rows, err := d.conn.Query(unrefundedRejectedDepositsQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deposits []RejectedDeposit
+	for rows.Next() {
+		var deposit RejectedDeposit
+		var tokenStr string
+		var txnHashStr string
+		var blockHashStr string
+		var addressStr string
+		var amountStr string
+		var refundTxnHashStr string
+		err = rows.Scan(
+			&tokenStr,
+			&deposit.ID,
+			&txnHashStr,
+			&deposit.BlockNumber,
+			&blockHashStr,
+			&deposit.BlockTimestamp,
+			&addressStr,
+			&amountStr,
+			&deposit.Reason,
+			&deposit.Status,
+			&refundTxnHashStr,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		amount, ok := new(big.Int).SetString(amountStr, 10)
+		if !ok {
+			return nil, fmt.Errorf("unable to parse amount %v", amountStr)
+		}
+		deposit.Token = common.HexToAddress(tokenStr)
+		deposit.TxnHash = common.HexToHash(txnHashStr)
+		deposit.BlockHash = common.HexToHash(blockHashStr)
+		deposit.BlockTimestamp = deposit.BlockTimestamp.Local()
+		deposit.Address = common.HexToAddress(addressStr)
+		deposit.Amount = amount
+		if refundTxnHashStr != "" {
+			deposit.RefundTxnHash = common.HexToHash(refundTxnHashStr)
+		}
+
+		deposits = append(deposits, deposit)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return deposits, nil
+}
+
+const markRejectedDepositRefundedStatement = `
+UPDATE rejected_deposits
+SET (status, refund_txn_hash) = ('refunded', $3)
+WHERE token = $1 AND id = $2 AND status = 'rejected'
+`
+
+// MarkRejectedDepositRefunded records the refund of the rejected deposit of the
+// given token and ID. ErrUnknownDeposit is returned if there is no such
+// rejected deposit, or it was already refunded.
+func (d *Database) MarkRejectedDepositRefunded(
+	token common.Address,
+	id uint64,
+	refundTxnHash common.Hash,
+) error {
+
+	result, err := d.conn.Exec(
+		markRejectedDepositRefundedStatement,
+		token.String(),
+		id,
+		refundTxnHash.String(),
+	)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected != 1 {
+		return ErrUnknownDeposit
+	}
+	return nil
+}
+
+const insertReorgStatement = `
+INSERT INTO reorgs (block_number, old_block_hash, new_block_hash, rolled_back_deposits, detected_at)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id
+`
+
+// InsertReorg records an unacknowledged reorg and returns its ID.
+func (d *Database) InsertReorg(reorg Reorg) (uint64, error) {
+	if reorg.DetectedAt.IsZero() {
+		return 0, ErrZeroTimestamp
+	}
+
+	row := d.conn.QueryRow(
+		insertReorgStatement,
+		reorg.BlockNumber,
+		reorg.OldBlockHash.String(),
+		reorg.NewBlockHash.String(),
+		reorg.RolledBackDeposits,
+		reorg.DetectedAt,
+	)
+
+	var id uint64
+	if err := row.Scan(&id); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+const unacknowledgedReorgsQuery = `
+SELECT id, block_number, old_block_hash, new_block_hash, rolled_back_deposits, detected_at
+FROM reorgs
+WHERE acknowledged_at IS NULL
+ORDER BY id ASC
+`
+
+// UnacknowledgedReorgs returns all reorgs that have not been acknowledged by
+// an operator.
+func (d *Database) UnacknowledgedReorgs() ([]Reorg, error) {
+	rows, err := d.conn.Query(unacknowledgedReorgsQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reorgs []Reorg
+	for rows.Next() {
+		var reorg Reorg
+		var oldBlockHashStr string
+		var newBlockHashStr string
+		err = rows.Scan(
+			&reorg.ID,
+			&reorg.BlockNumber,
+			&oldBlockHashStr,
+			&newBlockHashStr,
+			&reorg.RolledBackDeposits,
+			&reorg.DetectedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		reorg.OldBlockHash = common.HexToHash(oldBlockHashStr)
+		reorg.NewBlockHash = common.HexToHash(newBlockHashStr)
+		reorg.DetectedAt = reorg.DetectedAt.Local()
+
+		reorgs = append(reorgs, reorg)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return reorgs, nil
+}
+
+const acknowledgeReorgStatement = `
+UPDATE reorgs
+SET acknowledged_at = $2
+WHERE id = $1 AND acknowledged_at IS NULL
+`
+
+// AcknowledgeReorg marks the reorg with the given ID as acknowledged.
+func (d *Database) AcknowledgeReorg(id uint64, acknowledgedAt time.Time) error {
+	if acknowledgedAt.IsZero() {
+		return ErrZeroTimestamp
+	}
+
+	result, err := d.conn.Exec(acknowledgeReorgStatement, id, acknowledgedAt)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected != 1 {
+		return ErrUnknownReorg
+	}
+	return nil
+}
+
 const confirmedDepositsQuery = `
-SELECT dep.*
+SELECT
+dep.id, dep.token, dep.txn_hash, dep.block_number, dep.block_hash,
+dep.block_timestamp, dep.address, dep.amount
 FROM deposits AS dep
-LEFT JOIN disbursements AS dis ON dep.id = dis.id
-WHERE dis.id IS NULL AND dep.block_number + $1 <= $2 + 1
+LEFT JOIN disbursements AS dis ON dep.token = dis.token AND dep.id = dis.id
+WHERE dis.id IS NULL AND dep.block_number + $1 <= $2 + 1 AND dep.token = $3
 ORDER BY dep.id ASC
 `
 
-// ConfirmedDeposits returns the set of all deposits that have sufficient
-// confirmation, but do not have a recorded disbursement.
-func (d *Database) ConfirmedDeposits(blockNumber, confirmations uint64) ([]Deposit, error) {
-	rows, err := d.conn.Query(confirmedDepositsQuery, confirmations, blockNumber)
+// ConfirmedDeposits returns the set of all deposits of the given token that
+// have sufficient confirmation, but do not have a recorded disbursement.
+func (d *Database) ConfirmedDeposits(
+	token common.Address,
+	blockNumber, confirmations uint64,
+) ([]Deposit, error) {
+
+	rows, err := d.conn.Query(
+		confirmedDepositsQuery, confirmations, blockNumber, token.String(),
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -284,13 +816,17 @@ func (d *Database) ConfirmedDeposits(blockNumber, confirmations uint64) ([]Depos
 	var deposits []Deposit
 	for rows.Next() {
 		var deposit Deposit
+		var tokenStr string
 		var txnHashStr string
+		var blockHashStr string
 		var addressStr string
 		var amountStr string
 		err = rows.Scan(
 			&deposit.ID,
+			&tokenStr,
 			&txnHashStr,
 			&deposit.BlockNumber,
+			&blockHashStr,
 			&deposit.BlockTimestamp,
 			&addressStr,
 			&amountStr,
@@ -302,7 +838,11 @@ func (d *Database) ConfirmedDeposits(blockNumber, confirmations uint64) ([]Depos
 		if !ok {
 			return nil, fmt.Errorf("unable to parse amount %v", amount)
 		}
+		deposit.Token = common.HexToAddress(tokenStr)
 		deposit.TxnHash = common.HexToHash(txnHashStr)
+		if blockHashStr != "" {
+			deposit.BlockHash = common.HexToHash(blockHashStr)
+		}
 		deposit.BlockTimestamp = deposit.BlockTimestamp.Local()
 		deposit.Amount = amount
 		deposit.Address = common.HexToAddress(addressStr)
@@ -318,14 +858,16 @@ func (d *Database) ConfirmedDeposits(blockNumber, confirmations uint64) ([]Depos
 
 const latestDisbursementIDQuery = `
 SELECT id FROM disbursements
+WHERE token = $1
 ORDER BY id DESC
 LIMIT 1
 `
 
-// LatestDisbursementID returns the latest deposit id known to the database that
-// has a recorded disbursement.
+// LatestDisbursementID returns the latest ETH deposit id known to the database
+// that has a recorded disbursement. Only ETH deposit ids are assigned
+// sequentially by the TeleportrDeposit contract.
 func (d *Database) LatestDisbursementID() (*uint64, error) {
-	row := d.conn.QueryRow(latestDisbursementIDQuery)
+	row := d.conn.QueryRow(latestDisbursementIDQuery, common.Address{}.String())
 
 	var latestDisbursementID uint64
 	err := row.Scan(&latestDisbursementID)
@@ -339,15 +881,16 @@ func (d *Database) LatestDisbursementID() (*uint64, error) {
 }
 
 const markDisbursedStatement = `
-INSERT INTO disbursements (id, txn_hash, block_number, block_timestamp, success)
-VALUES ($1, $2, $3, $4, $5)
-ON CONFLICT (id) DO UPDATE
+INSERT INTO disbursements (id, txn_hash, block_number, block_timestamp, success, token)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (token, id) DO UPDATE
 SET (txn_hash, block_number, block_timestamp, success) = ($2, $3, $4, $5)
 `
 
 // UpsertDisbursement inserts a disbursement, or updates an existing record
-// in-place if the ID already exists.
+// in-place if the token and ID already exists.
 func (d *Database) UpsertDisbursement(
+	token common.Address,
 	id uint64,
 	txnHash common.Hash,
 	blockNumber uint64,
@@ -365,6 +908,7 @@ func (d *Database) UpsertDisbursement(
 		blockNumber,
 		blockTimestamp,
 		success,
+		token.String(),
 	)
 	if err != nil {
 		if strings.Contains(err.Error(), "violates foreign key constraint") {
@@ -385,12 +929,12 @@ func (d *Database) UpsertDisbursement(
 
 const loadTeleportByDepositHashQuery = `
 SELECT
-dep.id, dep.address, dep.amount, dis.success,
+dep.id, dep.token, dep.address, dep.amount, dis.success,
 dep.txn_hash, dep.block_number, dep.block_timestamp,
 dis.txn_hash, dis.block_number, dis.block_timestamp
 FROM deposits AS dep
 LEFT JOIN disbursements AS dis
-ON dep.id = dis.id
+ON dep.token = dis.token AND dep.id = dis.id
 WHERE dep.txn_hash = $1
 LIMIT 1
 `
@@ -410,24 +954,74 @@ func (d *Database) LoadTeleportByDepositHash(
 	return &teleport, nil
 }
 
+// MaxTeleportsPageSize is the maximum number of teleports returned by a single
+// call to LoadTeleportsByAddress.
+const MaxTeleportsPageSize = 100
+
+// TeleportCursor identifies the position of a teleport in the history of an
+// address, which is ordered from newest to oldest.
+type TeleportCursor struct {
+	BlockTimestamp time.Time
+	Token          common.Address
+	ID             uint64
+}
+
+// Cursor returns the cursor pointing at the teleport.
+func (t Teleport) Cursor() TeleportCursor {
+	return TeleportCursor{
+		BlockTimestamp: t.Deposit.BlockTimestamp,
+		Token:          t.Token,
+		ID:             t.ID,
+	}
+}
+
 const loadTeleportsByAddressQuery = `
 SELECT
-dep.id, dep.address, dep.amount, dis.success,
+dep.id, dep.token, dep.address, dep.amount, dis.success,
 dep.txn_hash, dep.block_number, dep.block_timestamp,
 dis.txn_hash, dis.block_number, dis.block_timestamp
 FROM deposits AS dep
 LEFT JOIN disbursements AS dis
-ON dep.id = dis.id
-WHERE dep.address = $1
-ORDER BY dep.block_timestamp DESC, dep.id DESC
-LIMIT 100
+ON dep.token = dis.token AND dep.id = dis.id
+WHERE dep.address = $1 AND (
+	$2::TIMESTAMPTZ IS NULL OR
+	(dep.block_timestamp, dep.token, dep.id) < ($2, $3, $4)
+)
+ORDER BY dep.block_timestamp DESC, dep.token DESC, dep.id DESC
+LIMIT $5
 `
 
+// LoadTeleportsByAddress returns up to limit teleports of the address, from
+// newest to oldest. If a cursor is provided, only teleports older than the
+// cursor are returned, which allows paging through the full history. The limit
+// is capped at MaxTeleportsPageSize.
 func (d *Database) LoadTeleportsByAddress(
 	addr common.Address,
+	cursor *TeleportCursor,
+	limit uint64,
 ) ([]Teleport, error) {
 
-	rows, err := d.conn.Query(loadTeleportsByAddressQuery, addr.String())
+	if limit == 0 || limit > MaxTeleportsPageSize {
+		limit = MaxTeleportsPageSize
+	}
+
+	var cursorTimestamp *time.Time
+	var cursorToken string
+	var cursorID uint64
+	if cursor != nil {
+		cursorTimestamp = &cursor.BlockTimestamp
+		cursorToken = cursor.Token.String()
+		cursorID = cursor.ID
+	}
+
+	rows, err := d.conn.Query(
+		loadTeleportsByAddressQuery,
+		addr.String(),
+		cursorTimestamp,
+		cursorToken,
+		cursorID,
+		limit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -450,12 +1044,12 @@ func (d *Database) LoadTeleportsByAddress(
 
 const completedTeleportsQuery = `
 SELECT
-dep.id, dep.address, dep.amount, dis.success,
+dep.id, dep.token, dep.address, dep.amount, dis.success,
 dep.txn_hash, dep.block_number, dep.block_timestamp,
 dis.txn_hash, dis.block_number, dis.block_timestamp
 FROM deposits AS dep, disbursements AS dis
-WHERE dep.id = dis.id
-ORDER BY id DESC
+WHERE dep.token = dis.token AND dep.id = dis.id
+ORDER BY dep.token ASC, dep.id DESC
 `
 
 // CompletedTeleports returns the set of all deposits that have also been
@@ -488,6 +1082,7 @@ type Scanner interface {
 
 func scanTeleport(scanner Scanner) (Teleport, error) {
 	var teleport Teleport
+	var tokenStr string
 	var addressStr string
 	var amountStr string
 	var depTxnHashStr string
@@ -497,6 +1092,7 @@ func scanTeleport(scanner Scanner) (Teleport, error) {
 	var success *bool
 	err := scanner.Scan(
 		&teleport.ID,
+		&tokenStr,
 		&addressStr,
 		&amountStr,
 		&success,
@@ -515,6 +1111,7 @@ func scanTeleport(scanner Scanner) (Teleport, error) {
 	if !ok {
 		return Teleport{}, fmt.Errorf("unable to parse amount %v", amount)
 	}
+	teleport.Token = common.HexToAddress(tokenStr)
 	teleport.Address = common.HexToAddress(addressStr)
 	teleport.Amount = amount
 	teleport.Deposit.TxnHash = common.HexToHash(depTxnHashStr)
@@ -549,13 +1146,17 @@ type PendingTx struct {
 
 	// EndID is the deposit id fo the last disbursement, exclusive.
 	EndID uint64
+
+	// Token is the L1 address of the disbursed token, or the zero address
+	// for ETH disbursements.
+	Token common.Address
 }
 
 const upsertPendingTxStatement = `
-INSERT INTO pending_txs (txn_hash, start_id, end_id)
-VALUES ($1, $2, $3)
+INSERT INTO pending_txs (txn_hash, start_id, end_id, token)
+VALUES ($1, $2, $3, $4)
 ON CONFLICT (txn_hash) DO UPDATE
-SET (start_id, end_id) = ($2, $3)
+SET (start_id, end_id, token) = ($2, $3, $4)
 `
 
 // UpsertPendingTx inserts a disbursement, or updates the entry if the TxHash
@@ -566,12 +1167,13 @@ func (d *Database) UpsertPendingTx(pendingTx PendingTx) error {
 		pendingTx.TxHash.String(),
 		pendingTx.StartID,
 		pendingTx.EndID,
+		pendingTx.Token.String(),
 	)
 	return err
 }
 
 const listPendingTxsQuery = `
-SELECT txn_hash, start_id, end_id
+SELECT txn_hash, start_id, end_id, token
 FROM pending_txs
 ORDER BY start_id DESC, end_id DESC, txn_hash ASC
 `
@@ -588,15 +1190,18 @@ func (d *Database) ListPendingTxs() ([]PendingTx, error) {
 	for rows.Next() {
 		var pendingTx PendingTx
 		var txHashStr string
+		var tokenStr string
 		err = rows.Scan(
 			&txHashStr,
 			&pendingTx.StartID,
 			&pendingTx.EndID,
+			&tokenStr,
 		)
 		if err != nil {
 			return nil, err
 		}
 		pendingTx.TxHash = common.HexToHash(txHashStr)
+		pendingTx.Token = common.HexToAddress(tokenStr)
 
 		pendingTxs = append(pendingTxs, pendingTx)
 	}
@@ -609,17 +1214,18 @@ func (d *Database) ListPendingTxs() ([]PendingTx, error) {
 
 const deletePendingTxsStatement = `
 DELETE FROM pending_txs
-WHERE start_id = $1 AND end_id = $2
+WHERE start_id = $1 AND end_id = $2 AND token = $3
 `
 
-// DeletePendingTx removes any pending txs with matching start and end ids. This
-// allows the caller to remove any logically-conflicting pending txs from the
-// database after successfully processing the outcomes.
-func (d *Database) DeletePendingTx(startID, endID uint64) error {
+// DeletePendingTx removes any pending txs with matching token, start and end
+// ids. This allows the caller to remove any logically-conflicting pending txs
+// from the database after successfully processing the outcomes.
+func (d *Database) DeletePendingTx(token common.Address, startID, endID uint64) error {
 	_, err := d.conn.Exec(
 		deletePendingTxsStatement,
 		startID,
 		endID,
+		token.String(),
 	)
 	return err
 }
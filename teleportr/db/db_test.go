@@ -15,6 +15,8 @@ import (
 
 var (
 	testTimestamp = time.Unix(time.Now().Unix(), 0)
+
+	eth = common.Address{}
 )
 
 func newDatabase(t *testing.T) *db.Database {
@@ -63,10 +65,10 @@ func TestUpsertEmptyDeposits(t *testing.T) {
 	d := newDatabase(t)
 	defer d.Close()
 
-	err := d.UpsertDeposits(nil, 0)
+	err := d.UpsertDeposits(nil, 0, common.Hash{})
 	require.Nil(t, err)
 
-	err = d.UpsertDeposits([]db.Deposit{}, 0)
+	err = d.UpsertDeposits([]db.Deposit{}, 0, common.Hash{})
 	require.Nil(t, err)
 }
 
@@ -78,7 +80,7 @@ func TestUpsertDepositWithZeroTimestampFails(t *testing.T) {
 	d := newDatabase(t)
 	defer d.Close()
 
-	err := d.UpsertDeposits([]db.Deposit{{}}, 0)
+	err := d.UpsertDeposits([]db.Deposit{{}}, 0, common.Hash{})
 	require.Equal(t, db.ErrZeroTimestamp, err)
 }
 
@@ -101,10 +103,10 @@ func TestUpsertDeposits(t *testing.T) {
 		},
 	}
 
-	err := d.UpsertDeposits([]db.Deposit{deposit1}, 0)
+	err := d.UpsertDeposits([]db.Deposit{deposit1}, 0, common.Hash{})
 	require.Nil(t, err)
 
-	deposits, err := d.ConfirmedDeposits(1, 1)
+	deposits, err := d.ConfirmedDeposits(eth, 1, 1)
 	require.Nil(t, err)
 	require.Equal(t, deposits, []db.Deposit{deposit1})
 
@@ -119,10 +121,10 @@ func TestUpsertDeposits(t *testing.T) {
 		},
 	}
 
-	err = d.UpsertDeposits([]db.Deposit{deposit2}, 0)
+	err = d.UpsertDeposits([]db.Deposit{deposit2}, 0, common.Hash{})
 	require.Nil(t, err)
 
-	deposits, err = d.ConfirmedDeposits(2, 1)
+	deposits, err = d.ConfirmedDeposits(eth, 2, 1)
 	require.Nil(t, err)
 	require.Equal(t, deposits, []db.Deposit{deposit2})
 }
@@ -145,7 +147,7 @@ func TestUpsertDepositsRecordsLastProcessedBlock(t *testing.T) {
 	require.Nil(t, lastProcessedBlock)
 
 	// Insert nil deposits through block 1.
-	err = d.UpsertDeposits(nil, 1)
+	err = d.UpsertDeposits(nil, 1, common.Hash{})
 	require.Nil(t, err)
 
 	// Check that LastProcessedBlock returns 1.
@@ -154,7 +156,7 @@ func TestUpsertDepositsRecordsLastProcessedBlock(t *testing.T) {
 	require.Equal(t, uint64Ptr(1), lastProcessedBlock)
 
 	// Insert empty deposits through block 2.
-	err = d.UpsertDeposits([]db.Deposit{}, 2)
+	err = d.UpsertDeposits([]db.Deposit{}, 2, common.Hash{})
 	require.Nil(t, err)
 
 	// Check that LastProcessedBlock returns 2.
@@ -173,7 +175,7 @@ func TestUpsertDepositsRecordsLastProcessedBlock(t *testing.T) {
 			BlockTimestamp: testTimestamp,
 		},
 	}
-	err = d.UpsertDeposits([]db.Deposit{deposit}, 4)
+	err = d.UpsertDeposits([]db.Deposit{deposit}, 4, common.Hash{})
 	require.Nil(t, err)
 
 	// Check that LastProcessedBlock returns 2.
@@ -191,7 +193,7 @@ func TestConfirmedDeposits(t *testing.T) {
 	d := newDatabase(t)
 	defer d.Close()
 
-	deposits, err := d.ConfirmedDeposits(1e9, 1)
+	deposits, err := d.ConfirmedDeposits(eth, 1e9, 1)
 	require.Nil(t, err)
 	require.Equal(t, int(0), len(deposits))
 
@@ -228,29 +230,29 @@ func TestConfirmedDeposits(t *testing.T) {
 
 	err = d.UpsertDeposits([]db.Deposit{
 		deposit1, deposit2, deposit3,
-	}, 0)
+	}, 0, common.Hash{})
 	require.Nil(t, err)
 
 	// First deposit only has 1 conf, should not be found using 2 confs at block
 	// 1.
-	deposits, err = d.ConfirmedDeposits(1, 2)
+	deposits, err = d.ConfirmedDeposits(eth, 1, 2)
 	require.Nil(t, err)
 	require.Equal(t, int(0), len(deposits))
 
 	// First deposit should be returned when querying for 1 conf at block 1.
-	deposits, err = d.ConfirmedDeposits(1, 1)
+	deposits, err = d.ConfirmedDeposits(eth, 1, 1)
 	require.Nil(t, err)
 	require.Equal(t, []db.Deposit{deposit1}, deposits)
 
 	// All deposits should be returned when querying for 1 conf at block 2.
-	deposits, err = d.ConfirmedDeposits(2, 1)
+	deposits, err = d.ConfirmedDeposits(eth, 2, 1)
 	require.Nil(t, err)
 	require.Equal(t, []db.Deposit{deposit1, deposit2, deposit3}, deposits)
 
-	err = d.UpsertDisbursement(deposit1.ID, common.HexToHash("0xdd01"), 1, testTimestamp, true)
+	err = d.UpsertDisbursement(eth, deposit1.ID, common.HexToHash("0xdd01"), 1, testTimestamp, true)
 	require.Nil(t, err)
 
-	deposits, err = d.ConfirmedDeposits(2, 1)
+	deposits, err = d.ConfirmedDeposits(eth, 2, 1)
 	require.Nil(t, err)
 	require.Equal(t, []db.Deposit{deposit2, deposit3}, deposits)
 }
@@ -271,11 +273,11 @@ func TestUpsertDisbursement(t *testing.T) {
 	disBlockNumber := uint64(2)
 
 	// Calling UpsertDisbursement with the zero timestamp should fail.
-	err := d.UpsertDisbursement(0, common.HexToHash("0xdd00"), 0, time.Time{}, true)
+	err := d.UpsertDisbursement(eth, 0, common.HexToHash("0xdd00"), 0, time.Time{}, true)
 	require.Equal(t, db.ErrZeroTimestamp, err)
 
 	// Calling UpsertDisbursement with an unknown id should fail.
-	err = d.UpsertDisbursement(0, common.HexToHash("0xdd00"), 0, testTimestamp, true)
+	err = d.UpsertDisbursement(eth, 0, common.HexToHash("0xdd00"), 0, testTimestamp, true)
 	require.Equal(t, db.ErrUnknownDeposit, err)
 
 	// Now, insert a real deposit that we will disburse.
@@ -290,14 +292,14 @@ func TestUpsertDisbursement(t *testing.T) {
 				BlockTimestamp: testTimestamp,
 			},
 		},
-	}, 0)
+	}, 0, common.Hash{})
 	require.Nil(t, err)
 
 	// Mark the deposit as disbursed with some temporary info.
 	tempDisTxnHash := common.HexToHash("0xee00")
 	tempDisBlockNumber := uint64(1)
 	err = d.UpsertDisbursement(
-		1, tempDisTxnHash, tempDisBlockNumber, testTimestamp, false,
+		eth, 1, tempDisTxnHash, tempDisBlockNumber, testTimestamp, false,
 	)
 	require.Nil(t, err)
 
@@ -331,7 +333,7 @@ func TestUpsertDisbursement(t *testing.T) {
 	require.Equal(t, expTeleports, teleports)
 
 	// Overwrite the disbursement info with the final values.
-	err = d.UpsertDisbursement(1, disTxnHash, disBlockNumber, testTimestamp, true)
+	err = d.UpsertDisbursement(eth, 1, disTxnHash, disBlockNumber, testTimestamp, true)
 	require.Nil(t, err)
 
 	expTeleports = []db.Teleport{
@@ -461,35 +463,35 @@ func TestDeletePendingTx(t *testing.T) {
 	require.Equal(t, []db.PendingTx{pendingTx3, pendingTx1, pendingTx2}, pendingTxs)
 
 	// Delete with indexes that do not match any start/end, no effect.
-	err = d.DeletePendingTx(3, 4)
+	err = d.DeletePendingTx(eth, 3, 4)
 	require.Nil(t, err)
 	pendingTxs, err = d.ListPendingTxs()
 	require.Nil(t, err)
 	require.Equal(t, []db.PendingTx{pendingTx3, pendingTx1, pendingTx2}, pendingTxs)
 
 	// Delete with indexes that matches start but no end, no effect.
-	err = d.DeletePendingTx(1, 3)
+	err = d.DeletePendingTx(eth, 1, 3)
 	require.Nil(t, err)
 	pendingTxs, err = d.ListPendingTxs()
 	require.Nil(t, err)
 	require.Equal(t, []db.PendingTx{pendingTx3, pendingTx1, pendingTx2}, pendingTxs)
 
 	// Delete with indexes that matches end but no start, no effect.
-	err = d.DeletePendingTx(0, 2)
+	err = d.DeletePendingTx(eth, 0, 2)
 	require.Nil(t, err)
 	pendingTxs, err = d.ListPendingTxs()
 	require.Nil(t, err)
 	require.Equal(t, []db.PendingTx{pendingTx3, pendingTx1, pendingTx2}, pendingTxs)
 
 	// Delete with indexes that matches start and end, should remove both.
-	err = d.DeletePendingTx(0, 1)
+	err = d.DeletePendingTx(eth, 0, 1)
 	require.Nil(t, err)
 	pendingTxs, err = d.ListPendingTxs()
 	require.Nil(t, err)
 	require.Equal(t, []db.PendingTx{pendingTx3}, pendingTxs)
 
 	// Delete with indexes that matches start and end, no empty.
-	err = d.DeletePendingTx(1, 2)
+	err = d.DeletePendingTx(eth, 1, 2)
 	require.Nil(t, err)
 	pendingTxs, err = d.ListPendingTxs()
 	require.Nil(t, err)
@@ -524,7 +526,7 @@ func TestLoadTeleports(t *testing.T) {
 		},
 	}
 
-	err := d.UpsertDeposits([]db.Deposit{deposit1}, 0)
+	err := d.UpsertDeposits([]db.Deposit{deposit1}, 0, common.Hash{})
 	require.Nil(t, err)
 
 	// The same, undisbursed teleport should be retruned by hash and address.
@@ -538,13 +540,13 @@ func TestLoadTeleports(t *testing.T) {
 	require.NotNil(t, teleport)
 	require.Equal(t, expTeleport, *teleport)
 
-	teleports, err := d.LoadTeleportsByAddress(address)
+	teleports, err := d.LoadTeleportsByAddress(address, nil, 0)
 	require.Nil(t, err)
 	require.Equal(t, []db.Teleport{expTeleport}, teleports)
 
 	// Insert a disbursement for the above deposit.
 	err = d.UpsertDisbursement(
-		1, disTxnHash, disBlockNumber, testTimestamp, true,
+		eth, 1, disTxnHash, disBlockNumber, testTimestamp, true,
 	)
 	require.Nil(t, err)
 
@@ -566,7 +568,324 @@ func TestLoadTeleports(t *testing.T) {
 	require.NotNil(t, teleport)
 	require.Equal(t, expTeleport, *teleport)
 
-	teleports, err = d.LoadTeleportsByAddress(address)
+	teleports, err = d.LoadTeleportsByAddress(address, nil, 0)
 	require.Nil(t, err)
 	require.Equal(t, []db.Teleport{expTeleport}, teleports)
 }
+
+// TestTokenDeposits asserts that deposits are keyed by token and id, such that
+// ETH and ERC20 deposits with the same id are tracked independently.
+func TestTokenDeposits(t *testing.T) {
+	t.Parallel()
+
+	d := newDatabase(t)
+	defer d.Close()
+
+	token := common.HexToAddress("0x7777")
+	ethDeposit := db.Deposit{
+		ID:      1,
+		Token:   eth,
+		Address: common.HexToAddress("0xaa01"),
+		Amount:  big.NewInt(1),
+		ConfirmationInfo: db.ConfirmationInfo{
+			TxnHash:        common.HexToHash("0xff01"),
+			BlockNumber:    1,
+			BlockTimestamp: testTimestamp,
+		},
+	}
+	tokenDeposit := db.Deposit{
+		ID:      1,
+		Token:   token,
+		Address: common.HexToAddress("0xaa02"),
+		Amount:  big.NewInt(2),
+		ConfirmationInfo: db.ConfirmationInfo{
+			TxnHash:        common.HexToHash("0xff02"),
+			BlockNumber:    1,
+			BlockTimestamp: testTimestamp,
+		},
+	}
+
+	err := d.UpsertDeposits([]db.Deposit{ethDeposit, tokenDeposit}, 1, common.Hash{})
+	require.Nil(t, err)
+
+	deposits, err := d.ConfirmedDeposits(eth, 1, 1)
+	require.Nil(t, err)
+	require.Equal(t, []db.Deposit{ethDeposit}, deposits)
+
+	deposits, err = d.ConfirmedDeposits(token, 1, 1)
+	require.Nil(t, err)
+	require.Equal(t, []db.Deposit{tokenDeposit}, deposits)
+
+	// Disbursing the token deposit should leave the ETH deposit untouched.
+	err = d.UpsertDisbursement(
+		token, 1, common.HexToHash("0xdd01"), 1, testTimestamp, true,
+	)
+	require.Nil(t, err)
+
+	deposits, err = d.ConfirmedDeposits(token, 1, 1)
+	require.Nil(t, err)
+	require.Equal(t, 0, len(deposits))
+
+	deposits, err = d.ConfirmedDeposits(eth, 1, 1)
+	require.Nil(t, err)
+	require.Equal(t, []db.Deposit{ethDeposit}, deposits)
+
+	// Token disbursements do not count towards the latest ETH disbursement.
+	latestID, err := d.LatestDisbursementID()
+	require.Nil(t, err)
+	require.Nil(t, latestID)
+
+	// Pending txs are deleted by token as well.
+	pendingTx := db.PendingTx{
+		TxHash:  common.HexToHash("0x01"),
+		StartID: 1,
+		EndID:   2,
+		Token:   token,
+	}
+	err = d.UpsertPendingTx(pendingTx)
+	require.Nil(t, err)
+
+	err = d.DeletePendingTx(eth, 1, 2)
+	require.Nil(t, err)
+
+	pendingTxs, err := d.ListPendingTxs()
+	require.Nil(t, err)
+	require.Equal(t, []db.PendingTx{pendingTx}, pendingTxs)
+
+	err = d.DeletePendingTx(token, 1, 2)
+	require.Nil(t, err)
+
+	pendingTxs, err = d.ListPendingTxs()
+	require.Nil(t, err)
+	require.Equal(t, 0, len(pendingTxs))
+}
+
+// TestRollbackDeposits asserts that RollbackDeposits removes undisbursed
+// deposits at or above the given block, and rewinds the last processed block.
+func TestRollbackDeposits(t *testing.T) {
+	t.Parallel()
+
+	d := newDatabase(t)
+	defer d.Close()
+
+	newDeposit := func(id, blockNumber uint64) db.Deposit {
+		return db.Deposit{
+			ID:        id,
+			Address:   common.HexToAddress("0xaa01"),
+			Amount:    big.NewInt(1),
+			BlockHash: common.BigToHash(new(big.Int).SetUint64(blockNumber)),
+			ConfirmationInfo: db.ConfirmationInfo{
+				TxnHash:        common.BigToHash(new(big.Int).SetUint64(id)),
+				BlockNumber:    blockNumber,
+				BlockTimestamp: testTimestamp,
+			},
+		}
+	}
+	deposit0 := newDeposit(0, 1)
+	deposit1 := newDeposit(1, 2)
+	deposit2 := newDeposit(2, 3)
+
+	err := d.UpsertDeposits(
+		[]db.Deposit{deposit0, deposit1, deposit2}, 3, common.HexToHash("0x03"),
+	)
+	require.Nil(t, err)
+
+	hash, err := d.LastProcessedBlockHash()
+	require.Nil(t, err)
+	require.Equal(t, common.HexToHash("0x03"), hash)
+
+	blocks, err := d.DepositBlocks(2)
+	require.Nil(t, err)
+	require.Equal(t, []db.BlockID{
+		{Number: 2, Hash: deposit1.BlockHash},
+		{Number: 3, Hash: deposit2.BlockHash},
+	}, blocks)
+
+	// Disbursed deposits are kept when rolling back.
+	err = d.UpsertDisbursement(
+		eth, 1, common.HexToHash("0xdd01"), 1, testTimestamp, true,
+	)
+	require.Nil(t, err)
+
+	rolledBack, err := d.RollbackDeposits(
+		2, db.BlockID{Number: 1, Hash: common.HexToHash("0x01")},
+	)
+	require.Nil(t, err)
+	require.Equal(t, uint64(1), rolledBack)
+
+	deposits, err := d.ConfirmedDeposits(eth, 3, 1)
+	require.Nil(t, err)
+	require.Equal(t, []db.Deposit{deposit0}, deposits)
+
+	lastProcessedBlock, err := d.LastProcessedBlock()
+	require.Nil(t, err)
+	require.Equal(t, uint64(1), *lastProcessedBlock)
+
+	hash, err = d.LastProcessedBlockHash()
+	require.Nil(t, err)
+	require.Equal(t, common.HexToHash("0x01"), hash)
+}
+
+// TestRejectedDeposits asserts that rejected deposits are recorded until they
+// are refunded, and rolled back with the deposits of reorged blocks.
+func TestRejectedDeposits(t *testing.T) {
+	t.Parallel()
+
+	d := newDatabase(t)
+	defer d.Close()
+
+	token := common.HexToAddress("0x7070")
+	newRejectedDeposit := func(id, blockNumber uint64) db.RejectedDeposit {
+		return db.RejectedDeposit{
+			Deposit: db.Deposit{
+				ID:        id,
+				Token:     token,
+				Address:   common.HexToAddress("0xaa01"),
+				Amount:    big.NewInt(1000),
+				BlockHash: common.BigToHash(new(big.Int).SetUint64(blockNumber)),
+				ConfirmationInfo: db.ConfirmationInfo{
+					TxnHash:        common.BigToHash(new(big.Int).SetUint64(id)),
+					BlockNumber:    blockNumber,
+					BlockTimestamp: testTimestamp,
+				},
+			},
+			Reason: "amount outside of deposit limits [1, 10]",
+			Status: db.RejectedDepositStatusRejected,
+		}
+	}
+
+	err := d.UpsertRejectedDeposits([]db.RejectedDeposit{{}})
+	require.Equal(t, db.ErrZeroTimestamp, err)
+
+	rejected0 := newRejectedDeposit(0, 1)
+	rejected1 := newRejectedDeposit(1, 2)
+	rejected2 := newRejectedDeposit(2, 3)
+	err = d.UpsertRejectedDeposits(
+		[]db.RejectedDeposit{rejected0, rejected1, rejected2},
+	)
+	require.Nil(t, err)
+
+	// Recording the same deposits again is a no-op.
+	err = d.UpsertRejectedDeposits([]db.RejectedDeposit{rejected0})
+	require.Nil(t, err)
+
+	deposits, err := d.UnrefundedRejectedDeposits()
+	require.Nil(t, err)
+	require.Equal(t, []db.RejectedDeposit{rejected0, rejected1, rejected2}, deposits)
+
+	// Rejected deposits are not disbursed.
+	confirmed, err := d.ConfirmedDeposits(token, 3, 1)
+	require.Nil(t, err)
+	require.Equal(t, 0, len(confirmed))
+
+	err = d.MarkRejectedDepositRefunded(token, 3, common.HexToHash("0xff"))
+	require.Equal(t, db.ErrUnknownDeposit, err)
+
+	err = d.MarkRejectedDepositRefunded(token, 1, common.HexToHash("0xff"))
+	require.Nil(t, err)
+
+	// Refunding twice fails, as the deposit is no longer pending.
+	err = d.MarkRejectedDepositRefunded(token, 1, common.HexToHash("0xff"))
+	require.Equal(t, db.ErrUnknownDeposit, err)
+
+	deposits, err = d.UnrefundedRejectedDeposits()
+	require.Nil(t, err)
+	require.Equal(t, []db.RejectedDeposit{rejected0, rejected2}, deposits)
+
+	// Unrefunded rejected deposits of reorged blocks are rolled back, such
+	// that they are ingested again.
+	_, err = d.RollbackDeposits(
+		2, db.BlockID{Number: 1, Hash: common.HexToHash("0x01")},
+	)
+	require.Nil(t, err)
+
+	deposits, err = d.UnrefundedRejectedDeposits()
+	require.Nil(t, err)
+	require.Equal(t, []db.RejectedDeposit{rejected0}, deposits)
+}
+
+// TestReorgs asserts that reorgs are listed until they are acknowledged.
+func TestReorgs(t *testing.T) {
+	t.Parallel()
+
+	d := newDatabase(t)
+	defer d.Close()
+
+	reorgs, err := d.UnacknowledgedReorgs()
+	require.Nil(t, err)
+	require.Equal(t, 0, len(reorgs))
+
+	_, err = d.InsertReorg(db.Reorg{})
+	require.Equal(t, db.ErrZeroTimestamp, err)
+
+	reorg := db.Reorg{
+		BlockNumber:        10,
+		OldBlockHash:       common.HexToHash("0x0a"),
+		NewBlockHash:       common.HexToHash("0x0b"),
+		RolledBackDeposits: 2,
+		DetectedAt:         testTimestamp,
+	}
+	reorg.ID, err = d.InsertReorg(reorg)
+	require.Nil(t, err)
+
+	reorgs, err = d.UnacknowledgedReorgs()
+	require.Nil(t, err)
+	require.Equal(t, []db.Reorg{reorg}, reorgs)
+
+	err = d.AcknowledgeReorg(reorg.ID+1, testTimestamp)
+	require.Equal(t, db.ErrUnknownReorg, err)
+
+	err = d.AcknowledgeReorg(reorg.ID, testTimestamp)
+	require.Nil(t, err)
+
+	// Acknowledging twice fails, as the reorg is no longer pending.
+	err = d.AcknowledgeReorg(reorg.ID, testTimestamp)
+	require.Equal(t, db.ErrUnknownReorg, err)
+
+	reorgs, err = d.UnacknowledgedReorgs()
+	require.Nil(t, err)
+	require.Equal(t, 0, len(reorgs))
+}
+
+// TestLoadTeleportsByAddressPagination asserts that LoadTeleportsByAddress
+// pages through the history of an address from newest to oldest.
+func TestLoadTeleportsByAddressPagination(t *testing.T) {
+	t.Parallel()
+
+	d := newDatabase(t)
+	defer d.Close()
+
+	address := common.HexToAddress("0xaa01")
+	var deposits []db.Deposit
+	for i := uint64(0); i < 5; i++ {
+		deposits = append(deposits, db.Deposit{
+			ID:      i,
+			Address: address,
+			Amount:  big.NewInt(1),
+			ConfirmationInfo: db.ConfirmationInfo{
+				TxnHash:        common.BigToHash(new(big.Int).SetUint64(i)),
+				BlockNumber:    i,
+				BlockTimestamp: testTimestamp.Add(time.Duration(i/2) * time.Second),
+			},
+		})
+	}
+	err := d.UpsertDeposits(deposits, 4, common.Hash{})
+	require.Nil(t, err)
+
+	var ids []uint64
+	var cursor *db.TeleportCursor
+	for {
+		teleports, err := d.LoadTeleportsByAddress(address, cursor, 2)
+		require.Nil(t, err)
+		if len(teleports) == 0 {
+			break
+		}
+		require.LessOrEqual(t, len(teleports), 2)
+		for _, teleport := range teleports {
+			ids = append(ids, teleport.ID)
+		}
+		next := teleports[len(teleports)-1].Cursor()
+		cursor = &next
+	}
+	require.Equal(t, []uint64{4, 3, 2, 1, 0}, ids)
+}
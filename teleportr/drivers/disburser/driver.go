@@ -4,17 +4,17 @@ import (
 	"context"
 	"crypto/ecdsa"
 	"errors"
+	"fmt"
 	"math/big"
-	"strings"
 	"sync"
 	"time"
 
-	"github.com/cenkalti/backoff"
 	"github.com/ethereum-optimism/optimism/bss-core/metrics"
-	"github.com/ethereum-optimism/optimism/bss-core/txmgr"
+	"github.com/ethereum-optimism/optimism/op-service/txmgr"
 	"github.com/ethereum-optimism/optimism/teleportr/bindings/deposit"
 	"github.com/ethereum-optimism/optimism/teleportr/bindings/disburse"
 	"github.com/ethereum-optimism/optimism/teleportr/db"
+	"github.com/ethereum-optimism/optimism/teleportr/tokens"
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
@@ -37,11 +37,53 @@ var DisbursementFailedTopic = common.HexToHash(
 
 const MaxDisbursements = 15
 
+// ReorgSearchDepth is the number of blocks below the last processed block whose
+// deposits are checked against the canonical chain once a reorg is detected.
+const ReorgSearchDepth = 1000
+
+// Database is the storage of deposits, disbursements and reorgs used by the
+// driver. It is implemented by *db.Database.
+type Database interface {
+	LastProcessedBlock() (*uint64, error)
+	LastProcessedBlockHash() (common.Hash, error)
+	UpsertDeposits(
+		deposits []db.Deposit,
+		lastProcessedBlock uint64,
+		lastProcessedBlockHash common.Hash,
+	) error
+	UpsertRejectedDeposits(deposits []db.RejectedDeposit) error
+	ConfirmedDeposits(
+		token common.Address,
+		blockNumber, confirmations uint64,
+	) ([]db.Deposit, error)
+	DepositBlocks(fromBlock uint64) ([]db.BlockID, error)
+	RollbackDeposits(
+		fromBlock uint64,
+		lastProcessedBlock db.BlockID,
+	) (uint64, error)
+	LatestDisbursementID() (*uint64, error)
+	UpsertDisbursement(
+		token common.Address,
+		id uint64,
+		txnHash common.Hash,
+		blockNumber uint64,
+		blockTimestamp time.Time,
+		success bool,
+	) error
+	ListPendingTxs() ([]db.PendingTx, error)
+	UpsertPendingTx(pendingTx db.PendingTx) error
+	DeletePendingTx(token common.Address, startID, endID uint64) error
+	InsertReorg(reorg db.Reorg) (uint64, error)
+	UnacknowledgedReorgs() ([]db.Reorg, error)
+}
+
+var _ Database = (*db.Database)(nil)
+
 type Config struct {
 	Name                 string
 	L1Client             *ethclient.Client
 	L2Client             *ethclient.Client
-	Database             *db.Database
+	Database             Database
 	MaxTxSize            uint64
 	NumConfirmations     uint64
 	DeployBlockNumber    uint64
@@ -51,17 +93,28 @@ type Config struct {
 	ChainID              *big.Int
 	PrivKey              *ecdsa.PrivateKey
 	ChainMetricsEnable   bool
+	Tokens               []tokens.Token
+}
+
+// rejectedDeposit identifies a token deposit that was rejected for falling
+// outside of the token's deposit limits.
+type rejectedDeposit struct {
+	token common.Address
+	id    uint64
 }
 
 type Driver struct {
-	cfg                  Config
-	depositContract      *deposit.TeleportrDeposit
-	disburserContract    *disburse.TeleportrDisburser
-	rawDisburserContract *bind.BoundContract
-	walletAddr           common.Address
-	metrics              *Metrics
+	cfg               Config
+	depositContract   *deposit.TeleportrDeposit
+	disburserContract *disburse.TeleportrDisburser
+	disburserABI      *abi.ABI
+	signer            txmgr.SignerFn
+	walletAddr        common.Address
+	metrics           *Metrics
 
 	currentDepositIDs   []uint64
+	currentToken        *tokens.Token
+	rejectedDeposits    map[rejectedDeposit]uint64
 	chainMetricsEnabled bool
 	metricsMu           sync.Mutex
 }
@@ -88,28 +141,24 @@ func NewDriver(cfg Config, parentCtx context.Context) (*Driver, error) {
 		return nil, err
 	}
 
-	parsed, err := abi.JSON(strings.NewReader(
-		disburse.TeleportrDisburserMetaData.ABI,
-	))
+	disburserABI, err := disburse.TeleportrDisburserMetaData.GetAbi()
 	if err != nil {
 		return nil, err
 	}
 
-	rawDisburserContract := bind.NewBoundContract(
-		cfg.DisburserAddr, parsed, cfg.L2Client, cfg.L2Client, cfg.L2Client,
-	)
-
 	walletAddr := crypto.PubkeyToAddress(cfg.PrivKey.PublicKey)
 	metricsInst := NewMetrics(cfg.Name)
 
 	d := &Driver{
-		cfg:                  cfg,
-		depositContract:      depositContract,
-		disburserContract:    disburserContract,
-		rawDisburserContract: rawDisburserContract,
-		walletAddr:           walletAddr,
-		metrics:              metricsInst,
-		chainMetricsEnabled:  cfg.ChainMetricsEnable,
+		cfg:                 cfg,
+		depositContract:     depositContract,
+		disburserContract:   disburserContract,
+		disburserABI:        disburserABI,
+		signer:              txmgr.PrivateKeySignerFn(cfg.PrivKey, cfg.ChainID),
+		walletAddr:          walletAddr,
+		metrics:             metricsInst,
+		rejectedDeposits:    make(map[rejectedDeposit]uint64),
+		chainMetricsEnabled: cfg.ChainMetricsEnable,
 	}
 	if d.chainMetricsEnabled {
 		go d.collectChainMetricsBackground(parentCtx)
@@ -132,19 +181,6 @@ func (d *Driver) Metrics() metrics.Metrics {
 	return d.metrics
 }
 
-// ClearPendingTx a publishes a transaction at the next available nonce in order
-// to clear any transactions in the mempool left over from a prior running
-// instance. When publishing to L2 there is no mempool so the transaction can't
-// get stuck, thus the behavior is unimplemented.
-func (d *Driver) ClearPendingTx(
-	ctx context.Context,
-	txMgr txmgr.TxManager,
-	l1Client *ethclient.Client,
-) error {
-
-	return nil
-}
-
 // GetBatchBlockRange returns the start and end L2 block heights that need to be
 // processed. Note that the end value is *exclusive*, therefore if the returned
 // values are identical nothing needs to be processed.
@@ -154,8 +190,9 @@ func (d *Driver) GetBatchBlockRange(
 	// Update metrics on each iteration.
 	d.collectChainMetrics(ctx)
 
-	// Clear the current deposit IDs from any prior iteration.
+	// Clear the current deposit IDs and token from any prior iteration.
 	d.currentDepositIDs = nil
+	d.currentToken = nil
 
 	// Before proceeding, process the outcomes of any transactions we've
 	// published in the past. This handles both the restart case, as well as
@@ -188,11 +225,16 @@ func (d *Driver) GetBatchBlockRange(
 
 	// Now, proceed to ingest any new deposits by inspect L1 events from the
 	// deposit contract, using the last processed block in postgres as a lower
-	// bound.
+	// bound. Any deposits from blocks that were reorged out since the last
+	// iteration are rolled back beforehand.
 	blockNumber, err := d.cfg.L1Client.BlockNumber(ctx)
 	if err != nil {
 		return nil, nil, err
 	}
+	err = d.detectReorg(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
 	lastProcessedBlock, err := d.lastProcessedBlock()
 	if err != nil {
 		return nil, nil, err
@@ -203,6 +245,20 @@ func (d *Driver) GetBatchBlockRange(
 		return nil, nil, err
 	}
 
+	// Refuse to disburse anything while a deep reorg has not been
+	// acknowledged by an operator, as confirmed deposits may have vanished.
+	reorgs, err := d.unacknowledgedReorgs()
+	if err != nil {
+		return nil, nil, err
+	}
+	d.metrics.UnacknowledgedReorgs.Set(float64(len(reorgs)))
+	if len(reorgs) > 0 {
+		log.Warn("Disbursements paused by unacknowledged reorg",
+			"reorg_id", reorgs[0].ID,
+			"block_number", reorgs[0].BlockNumber)
+		return startID, startID, nil
+	}
+
 	// After successfully ingesting deposits, check to see if there are any
 	// now-confirmed deposits that we can attempt to disburse.
 	confirmedDeposits, err := d.loadConfirmedDepositsInRange(
@@ -212,8 +268,10 @@ func (d *Driver) GetBatchBlockRange(
 		return nil, nil, err
 	}
 
+	// ETH deposits take priority. Only once those are exhausted do we move
+	// on to any confirmed token deposits.
 	if len(confirmedDeposits) == 0 {
-		return startID, startID, nil
+		return d.nextTokenDepositRange(ctx, blockNumber, startID)
 	}
 
 	// Compute the end fo the range as the last confirmed deposit plus one.
@@ -223,15 +281,13 @@ func (d *Driver) GetBatchBlockRange(
 	return startID, endID, nil
 }
 
-// CraftBatchTx transforms the L2 blocks between start and end into a batch
-// transaction using the given nonce. A dummy gas price is used in the resulting
-// transaction to use for size estimation.
-//
-// NOTE: This method SHOULD NOT publish the resulting transaction.
+// CraftBatchTx transforms the confirmed deposits between start and end into a
+// disbursement transaction candidate, or a transfer of the current token. A nil
+// candidate is returned if the token deposit is no longer confirmed.
 func (d *Driver) CraftBatchTx(
 	ctx context.Context,
-	start, end, nonce *big.Int,
-) (*types.Transaction, error) {
+	start, end *big.Int,
+) (*txmgr.TxCandidate, error) {
 
 	name := d.cfg.Name
 
@@ -240,6 +296,10 @@ func (d *Driver) CraftBatchTx(
 		return nil, err
 	}
 
+	if d.currentToken != nil {
+		return d.craftTokenTx(ctx, blockNumber, start)
+	}
+
 	confirmedDeposits, err := d.loadConfirmedDepositsInRange(
 		blockNumber, start.Uint64(), end.Uint64(),
 	)
@@ -260,108 +320,112 @@ func (d *Driver) CraftBatchTx(
 		value = value.Add(value, deposit.Amount)
 	}
 
-	log.Info(name+" crafting batch tx", "start", start, "end", end,
-		"nonce", nonce)
+	log.Info(name+" crafting batch tx", "start", start, "end", end)
 
 	d.metrics.NumElementsPerBatch().Observe(float64(len(disbursements)))
 
 	log.Info(name+" batch constructed", "num_disbursements", len(disbursements))
 
-	gasPrice, err := d.cfg.L2Client.SuggestGasPrice(ctx)
+	data, err := d.disburserABI.Pack("disburse", start, disbursements)
 	if err != nil {
 		return nil, err
 	}
 
-	opts, err := bind.NewKeyedTransactorWithChainID(
-		d.cfg.PrivKey, d.cfg.ChainID,
-	)
+	d.currentDepositIDs = depositIDs
+
+	// The gas limit is estimated by the tx manager.
+	return &txmgr.TxCandidate{
+		To:     &d.cfg.DisburserAddr,
+		TxData: data,
+		Value:  value,
+	}, nil
+}
+
+// craftTokenTx crafts an ERC20 transfer from the disburser wallet for the
+// confirmed deposit of the current token with the given id.
+func (d *Driver) craftTokenTx(
+	ctx context.Context,
+	blockNumber uint64,
+	depositID *big.Int,
+) (*txmgr.TxCandidate, error) {
+
+	token := d.currentToken
+
+	confirmedDeposits, err := d.confirmedDeposits(token.L1Address, blockNumber)
 	if err != nil {
 		return nil, err
 	}
-	opts.Context = ctx
-	opts.Nonce = nonce
-	opts.GasPrice = gasPrice
-	opts.NoSend = true
-	opts.Value = value
 
-	tx, err := d.disburserContract.Disburse(opts, start, disbursements)
+	var deposit *db.Deposit
+	for i := range confirmedDeposits {
+		if confirmedDeposits[i].ID == depositID.Uint64() {
+			deposit = &confirmedDeposits[i]
+			break
+		}
+	}
+	if deposit == nil {
+		return nil, nil
+	}
+
+	log.Info(d.cfg.Name+" crafting token tx", "token", token.Symbol,
+		"deposit_id", deposit.ID, "amount", deposit.Amount)
+
+	data, err := tokens.TransferData(deposit.Address, deposit.Amount)
 	if err != nil {
 		return nil, err
 	}
 
-	d.currentDepositIDs = depositIDs
+	d.metrics.NumElementsPerBatch().Observe(1)
+	d.currentDepositIDs = []uint64{deposit.ID}
 
-	return tx, nil
+	return &txmgr.TxCandidate{
+		To:     &token.L2Address,
+		TxData: data,
+	}, nil
 }
 
-// UpdateGasPrice signs an otherwise identical txn to the one provided but with
-// updated gas prices sampled from the existing network conditions.
-//
-// NOTE: Thie method SHOULD NOT publish the resulting transaction.
-func (d *Driver) UpdateGasPrice(
+// SignTx signs a transaction of the disburser wallet, and records the hash of
+// a disbursement in Postgres before it is published, so that we can recover if
+// we crash after publishing. It is the signer of the tx manager, and is called
+// again for each replacement of the disbursement.
+func (d *Driver) SignTx(
 	ctx context.Context,
+	from common.Address,
 	tx *types.Transaction,
 ) (*types.Transaction, error) {
 
-	gasPrice, err := d.cfg.L2Client.SuggestGasPrice(ctx)
+	signedTx, err := d.signer(ctx, from, tx)
 	if err != nil {
 		return nil, err
 	}
 
-	opts, err := bind.NewKeyedTransactorWithChainID(
-		d.cfg.PrivKey, d.cfg.ChainID,
-	)
-	if err != nil {
-		return nil, err
+	// The tx manager cancels transactions with a self-transfer, which
+	// disburses nothing.
+	if *tx.To() == d.walletAddr {
+		return signedTx, nil
+	}
+	if len(d.currentDepositIDs) == 0 {
+		return nil, errors.New("no deposits to disburse")
 	}
-	opts.Context = ctx
-	opts.Nonce = new(big.Int).SetUint64(tx.Nonce())
-	opts.GasPrice = gasPrice
-	opts.Value = tx.Value()
-	opts.NoSend = true
-
-	return d.rawDisburserContract.RawTransact(opts, tx.Data())
-}
-
-// SendTransaction injects a signed transaction into the pending pool for
-// execution.
-func (d *Driver) SendTransaction(
-	ctx context.Context,
-	tx *types.Transaction,
-) error {
 
-	txHash := tx.Hash()
 	startID := d.currentDepositIDs[0]
 	endID := d.currentDepositIDs[len(d.currentDepositIDs)-1] + 1
+	token := tokens.ETH
+	if d.currentToken != nil {
+		token = d.currentToken.L1Address
+	}
 
-	// Record the pending transaction hash so that we can recover if we crash
-	// after publishing.
-	err := d.upsertPendingTx(db.PendingTx{
-		TxHash:  txHash,
+	err = d.upsertPendingTx(db.PendingTx{
+		TxHash:  signedTx.Hash(),
 		StartID: startID,
 		EndID:   endID,
+		Token:   token,
 	})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	// This requires special handling - if this request fails,
-	// then teleportr will halt. Use exponential backoff here to
-	// handle expected failures (e.g., 503s, 524s, etc.).
-	return backoff.Retry(func() error {
-		subCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
-		defer cancel()
-		err := d.cfg.L2Client.SendTransaction(subCtx, tx)
-		if err == nil {
-			return err
-		}
-		if !IsRetryableError(err) {
-			d.metrics.FailedTXSubmissions.WithLabelValues("permanent").Inc()
-			return backoff.Permanent(err)
-		}
-		d.metrics.FailedTXSubmissions.WithLabelValues("recoverable").Inc()
-		return err
-	}, DefaultBackoff)
+	return signedTx, nil
 }
 
 // processPendingTxs is a helper method which updates Postgres with the effects
@@ -392,8 +456,9 @@ func (d *Driver) processPendingTxs(ctx context.Context) error {
 			return err
 		}
 
-		// Also skip any reverted transactions.
-		if r.Status != 1 {
+		// Also skip any reverted disburser transactions. Reverted token
+		// transfers are recorded as failed disbursements.
+		if r.Status != 1 && pendingTx.Token == tokens.ETH {
 			continue
 		}
 
@@ -415,6 +480,14 @@ func (d *Driver) processPendingTxs(ctx context.Context) error {
 	}
 	blockTimestamp := time.Unix(int64(header.Time), 0)
 
+	if pendingTx.Token != tokens.ETH {
+		err = d.recordTokenDisbursement(pendingTx, receipt, blockTimestamp)
+		if err != nil {
+			return err
+		}
+		return d.clearPendingTxs(pendingTx)
+	}
+
 	var successfulDisbursements int
 	var failedDisbursements int
 	var failedUpserts uint64
@@ -437,6 +510,7 @@ func (d *Driver) processPendingTxs(ctx context.Context) error {
 		}
 
 		err = d.cfg.Database.UpsertDisbursement(
+			tokens.ETH,
 			depositID,
 			receipt.TxHash,
 			receipt.BlockNumber.Uint64(),
@@ -472,15 +546,70 @@ func (d *Driver) processPendingTxs(ctx context.Context) error {
 		return errors.New("failed to upsert all disbursements successfully")
 	}
 
-	// If we upserted all disbursements successfully, remove any pending txs
-	// with the same start/end id.
-	err = d.deletePendingTx(pendingTx.StartID, pendingTx.EndID)
+	return d.clearPendingTxs(pendingTx)
+}
+
+// recordTokenDisbursement marks the token deposit disbursed by the pending tx
+// as successful or failed depending on the receipt status.
+func (d *Driver) recordTokenDisbursement(
+	pendingTx db.PendingTx,
+	receipt *types.Receipt,
+	blockTimestamp time.Time,
+) error {
+
+	symbol := pendingTx.Token.String()
+	if token, ok := tokens.Find(d.cfg.Tokens, pendingTx.Token); ok {
+		symbol = token.Symbol
+	}
+	success := receipt.Status == types.ReceiptStatusSuccessful
+
+	err := d.cfg.Database.UpsertDisbursement(
+		pendingTx.Token,
+		pendingTx.StartID,
+		receipt.TxHash,
+		receipt.BlockNumber.Uint64(),
+		blockTimestamp,
+		success,
+	)
+	if err != nil {
+		d.metrics.FailedDatabaseMethods.With(DBMethodUpsertDisbursement).Inc()
+		log.Warn("Unable to mark token disbursement",
+			"token", symbol,
+			"depositId", pendingTx.StartID,
+			"txHash", receipt.TxHash,
+			"err", err)
+		return err
+	}
+
+	if success {
+		d.metrics.SuccessfulTokenDisbursements.WithLabelValues(symbol).Inc()
+	} else {
+		d.metrics.FailedTokenDisbursements.WithLabelValues(symbol).Inc()
+	}
+
+	log.Info("Token disbursement marked",
+		"token", symbol,
+		"depositId", pendingTx.StartID,
+		"success", success,
+		"txHash", receipt.TxHash,
+		"blockNumber", receipt.BlockNumber,
+		"blockTimestamp", blockTimestamp)
+
+	return nil
+}
+
+// clearPendingTxs removes any pending txs with the same token and start/end id
+// as the processed pending tx, after all of its disbursements were recorded.
+func (d *Driver) clearPendingTxs(pendingTx db.PendingTx) error {
+	err := d.deletePendingTx(
+		pendingTx.Token, pendingTx.StartID, pendingTx.EndID,
+	)
 	if err != nil {
 		return err
 	}
 
 	// Sanity check that this leaves our pending tx table empty.
-	pendingTxs, err = d.listPendingTxs()
+	pendingTxs, err := d.listPendingTxs()
 	if err != nil {
 		return err
 	}
@@ -521,6 +650,16 @@ func (d *Driver) ingestDeposits(
 			end = blockNumber
 		}
 
+		// Load the end of the range before filtering, such that a reorg
+		// that happens while filtering changes the recorded hash and is
+		// detected on the next iteration.
+		endHeader, err := d.cfg.L1Client.HeaderByNumber(
+			ctx, new(big.Int).SetUint64(end),
+		)
+		if err != nil {
+			return err
+		}
+
 		opts := &bind.FilterOpts{
 			Start:   start,
 			End:     &end,
@@ -544,9 +683,11 @@ func (d *Driver) ingestDeposits(
 			}
 
 			deposits = append(deposits, db.Deposit{
-				ID:      event.DepositId.Uint64(),
-				Address: event.Emitter,
-				Amount:  event.Amount,
+				ID:        event.DepositId.Uint64(),
+				Token:     tokens.ETH,
+				Address:   event.Emitter,
+				Amount:    event.Amount,
+				BlockHash: event.Raw.BlockHash,
 				ConfirmationInfo: db.ConfirmationInfo{
 					TxnHash:        event.Raw.TxHash,
 					BlockNumber:    event.Raw.BlockNumber,
@@ -559,7 +700,22 @@ func (d *Driver) ingestDeposits(
 			return err
 		}
 
-		err = d.upsertDeposits(deposits, end)
+		tokenDeposits, rejectedDeposits, err := d.filterTokenDeposits(
+			ctx, start, end,
+		)
+		if err != nil {
+			return err
+		}
+		deposits = append(deposits, tokenDeposits...)
+
+		// Record the rejected deposits before advancing the last processed
+		// block, so that they can be refunded by an operator.
+		err = d.upsertRejectedDeposits(rejectedDeposits)
+		if err != nil {
+			return err
+		}
+
+		err = d.upsertDeposits(deposits, end, endHeader.Hash())
 		if err != nil {
 			return err
 		}
@@ -568,7 +724,289 @@ func (d *Driver) ingestDeposits(
 	return nil
 }
 
-// loadConfirmedDeposits retrieves the list of confirmed deposits with IDs
+// detectReorg checks that the last processed block is still part of the
+// canonical L1 chain. If it is not, undisbursed deposits from the affected
+// blocks are rolled back so that they are ingested again from the new chain.
+// Reorgs that only replace unconfirmed blocks are expected, but a reorg that
+// removes a block with confirmed deposits is recorded so that disbursements are
+// paused until an operator acknowledges it.
+func (d *Driver) detectReorg(ctx context.Context) error {
+	lastProcessedBlock, err := d.lastProcessedBlock()
+	if err != nil {
+		return err
+	}
+	if lastProcessedBlock == nil {
+		return nil
+	}
+	lastProcessedBlockHash, err := d.lastProcessedBlockHash()
+	if err != nil {
+		return err
+	}
+
+	// Blocks processed before hashes were recorded can't be checked.
+	if lastProcessedBlockHash == (common.Hash{}) {
+		return nil
+	}
+
+	canonicalHash, err := d.canonicalBlockHash(ctx, *lastProcessedBlock)
+	if err != nil {
+		return err
+	}
+	if canonicalHash == lastProcessedBlockHash {
+		return nil
+	}
+
+	// Find the earliest block holding deposits that is no longer canonical.
+	var searchStart uint64
+	if *lastProcessedBlock > ReorgSearchDepth {
+		searchStart = *lastProcessedBlock - ReorgSearchDepth
+	}
+	depositBlocks, err := d.depositBlocks(searchStart)
+	if err != nil {
+		return err
+	}
+	var removedBlock *db.BlockID
+	var removedBlockNewHash common.Hash
+	for i := range depositBlocks {
+		hash, err := d.canonicalBlockHash(ctx, depositBlocks[i].Number)
+		if err != nil {
+			return err
+		}
+		if hash != depositBlocks[i].Hash {
+			removedBlock = &depositBlocks[i]
+			removedBlockNewHash = hash
+			break
+		}
+	}
+
+	params := RollbackBlockNumberParams{
+		LastProcessedBlockNumber: *lastProcessedBlock,
+		NumConfirmations:         d.cfg.NumConfirmations,
+		DeployBlockNumber:        d.cfg.DeployBlockNumber,
+	}
+	if removedBlock != nil {
+		params.RemovedDepositBlockNumber = &removedBlock.Number
+	}
+	rollbackFrom, deep := FindRollbackBlockNumber(params)
+
+	depth := ReorgDepthShallow
+	if deep {
+		depth = ReorgDepthDeep
+	}
+	d.metrics.Reorgs.WithLabelValues(depth).Inc()
+
+	// Rewind to the block before the rollback, which remains canonical.
+	var rewindTo db.BlockID
+	if rollbackFrom > 0 {
+		rewindTo.Number = rollbackFrom - 1
+		rewindTo.Hash, err = d.canonicalBlockHash(ctx, rewindTo.Number)
+		if err != nil {
+			return err
+		}
+	}
+
+	rolledBack, err := d.rollbackDeposits(rollbackFrom, rewindTo)
+	if err != nil {
+		return err
+	}
+
+	if !deep {
+		log.Info("Rolled back deposits after L1 reorg",
+			"last_processed_block", *lastProcessedBlock,
+			"rollback_from", rollbackFrom,
+			"rolled_back_deposits", rolledBack)
+		return nil
+	}
+
+	log.Error("Deep L1 reorg removed confirmed deposits, pausing disbursements",
+		"block_number", removedBlock.Number,
+		"old_block_hash", removedBlock.Hash,
+		"new_block_hash", removedBlockNewHash,
+		"rolled_back_deposits", rolledBack)
+
+	return d.insertReorg(db.Reorg{
+		BlockNumber:        removedBlock.Number,
+		OldBlockHash:       removedBlock.Hash,
+		NewBlockHash:       removedBlockNewHash,
+		RolledBackDeposits: rolledBack,
+		DetectedAt:         time.Now(),
+	})
+}
+
+// canonicalBlockHash returns the hash of the canonical L1 block at the given
+// height, or the zero hash if the chain is shorter.
+func (d *Driver) canonicalBlockHash(
+	ctx context.Context,
+	blockNumber uint64,
+) (common.Hash, error) {
+
+	header, err := d.cfg.L1Client.HeaderByNumber(
+		ctx, new(big.Int).SetUint64(blockNumber),
+	)
+	if err == ethereum.NotFound {
+		return common.Hash{}, nil
+	} else if err != nil {
+		return common.Hash{}, err
+	}
+	return header.Hash(), nil
+}
+
+// filterTokenDeposits returns the ERC20 transfers to the token deposit
+// addresses between the start and end blocks, inclusive. Transfers outside of
+// the token's deposit limits are returned as rejected deposits, which are
+// recorded for a refund but not disbursed.
+func (d *Driver) filterTokenDeposits(
+	ctx context.Context,
+	start, end uint64,
+) ([]db.Deposit, []db.RejectedDeposit, error) {
+
+	if len(d.cfg.Tokens) == 0 {
+		return nil, nil, nil
+	}
+
+	// Forget about rejected deposits that will not be scanned again.
+	for key, blockNumber := range d.rejectedDeposits {
+		if blockNumber < start {
+			delete(d.rejectedDeposits, key)
+		}
+	}
+
+	var tokenAddrs []common.Address
+	var depositAddrs []common.Hash
+	for _, token := range d.cfg.Tokens {
+		tokenAddrs = append(tokenAddrs, token.L1Address)
+		depositAddrs = append(depositAddrs, token.DepositAddress.Hash())
+	}
+
+	logs, err := d.cfg.L1Client.FilterLogs(ctx, ethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(start),
+		ToBlock:   new(big.Int).SetUint64(end),
+		Addresses: tokenAddrs,
+		Topics: [][]common.Hash{
+			{tokens.TransferTopic}, nil, depositAddrs,
+		},
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var deposits []db.Deposit
+	var rejected []db.RejectedDeposit
+	for _, event := range logs {
+		if len(event.Topics) != 3 {
+			continue
+		}
+		token, ok := tokens.Find(d.cfg.Tokens, event.Address)
+		if !ok || common.BytesToAddress(event.Topics[2][:]) != token.DepositAddress {
+			continue
+		}
+
+		amount, err := tokens.ParseTransfer(event.Data)
+		if err != nil {
+			return nil, nil, err
+		}
+		id := tokens.DepositID(event.BlockNumber, event.Index)
+		from := common.BytesToAddress(event.Topics[1][:])
+
+		header, err := d.cfg.L1Client.HeaderByNumber(
+			ctx, new(big.Int).SetUint64(event.BlockNumber),
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		deposit := db.Deposit{
+			ID:        id,
+			Token:     token.L1Address,
+			Address:   from,
+			Amount:    amount,
+			BlockHash: event.BlockHash,
+			ConfirmationInfo: db.ConfirmationInfo{
+				TxnHash:        event.TxHash,
+				BlockNumber:    event.BlockNumber,
+				BlockTimestamp: time.Unix(int64(header.Time), 0),
+			},
+		}
+
+		if !token.InLimits(amount) {
+			reason := fmt.Sprintf("amount outside of deposit limits [%v, %v]",
+				token.MinDepositAmount, token.MaxDepositAmount)
+			rejected = append(rejected, db.RejectedDeposit{
+				Deposit: deposit,
+				Reason:  reason,
+				Status:  db.RejectedDepositStatusRejected,
+			})
+
+			key := rejectedDeposit{token: token.L1Address, id: id}
+			if _, ok := d.rejectedDeposits[key]; !ok {
+				d.rejectedDeposits[key] = event.BlockNumber
+				d.metrics.RejectedDeposits.WithLabelValues(token.Symbol).Inc()
+				log.Warn("Rejecting token deposit outside of limits",
+					"token", token.Symbol,
+					"address", from,
+					"amount", amount,
+					"txHash", event.TxHash)
+			}
+			continue
+		}
+
+		deposits = append(deposits, deposit)
+	}
+
+	return deposits, rejected, nil
+}
+
+// nextTokenDepositRange returns the range holding the oldest confirmed deposit
+// of the first token that the disburser wallet holds enough of to disburse,
+// and selects that token for the subsequent CraftBatchTx. Token deposits are
+// disbursed one at a time, so the range holds a single deposit id. If there is
+// nothing to disburse, the empty range [emptyID, emptyID) is returned.
+func (d *Driver) nextTokenDepositRange(
+	ctx context.Context,
+	blockNumber uint64,
+	emptyID *big.Int,
+) (*big.Int, *big.Int, error) {
+
+	for i := range d.cfg.Tokens {
+		token := &d.cfg.Tokens[i]
+
+		confirmedDeposits, err := d.confirmedDeposits(
+			token.L1Address, blockNumber,
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(confirmedDeposits) == 0 {
+			continue
+		}
+		deposit := confirmedDeposits[0]
+
+		balance, err := tokens.BalanceOf(
+			ctx, d.cfg.L2Client, token.L2Address, d.walletAddr,
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+		if balance.Cmp(deposit.Amount) < 0 {
+			log.Warn("Insufficient token balance to disburse deposit",
+				"token", token.Symbol,
+				"deposit_id", deposit.ID,
+				"amount", deposit.Amount,
+				"balance", balance)
+			continue
+		}
+
+		d.currentToken = token
+		startID := new(big.Int).SetUint64(deposit.ID)
+		endID := new(big.Int).SetUint64(deposit.ID + 1)
+
+		return startID, endID, nil
+	}
+
+	return emptyID, emptyID, nil
+}
+
+// loadConfirmedDeposits retrieves the list of confirmed ETH deposits with IDs
 // in the range [startID, endID).
 func (d *Driver) loadConfirmedDepositsInRange(
 	blockNumber uint64,
@@ -576,7 +1014,7 @@ func (d *Driver) loadConfirmedDepositsInRange(
 	endID uint64,
 ) ([]db.Deposit, error) {
 
-	confirmedDeposits, err := d.confirmedDeposits(blockNumber)
+	confirmedDeposits, err := d.confirmedDeposits(tokens.ETH, blockNumber)
 	if err != nil {
 		return nil, err
 	}
@@ -682,8 +1120,13 @@ func (d *Driver) logDatabaseContractMismatch(
 	}
 }
 
-func (d *Driver) upsertDeposits(deposits []db.Deposit, end uint64) error {
-	err := d.cfg.Database.UpsertDeposits(deposits, end)
+func (d *Driver) upsertDeposits(
+	deposits []db.Deposit,
+	end uint64,
+	endHash common.Hash,
+) error {
+
+	err := d.cfg.Database.UpsertDeposits(deposits, end, endHash)
 	if err != nil {
 		d.metrics.FailedDatabaseMethods.With(DBMethodUpsertDeposits).Inc()
 		return err
@@ -691,9 +1134,13 @@ func (d *Driver) upsertDeposits(deposits []db.Deposit, end uint64) error {
 	return nil
 }
 
-func (d *Driver) confirmedDeposits(blockNumber uint64) ([]db.Deposit, error) {
+func (d *Driver) confirmedDeposits(
+	token common.Address,
+	blockNumber uint64,
+) ([]db.Deposit, error) {
+
 	confirmedDeposits, err := d.cfg.Database.ConfirmedDeposits(
-		blockNumber, d.cfg.NumConfirmations,
+		token, blockNumber, d.cfg.NumConfirmations,
 	)
 	if err != nil {
 		d.metrics.FailedDatabaseMethods.With(DBMethodConfirmedDeposits).Inc()
@@ -711,6 +1158,72 @@ func (d *Driver) lastProcessedBlock() (*uint64, error) {
 	return lastProcessedBlock, nil
 }
 
+func (d *Driver) lastProcessedBlockHash() (common.Hash, error) {
+	hash, err := d.cfg.Database.LastProcessedBlockHash()
+	if err != nil {
+		d.metrics.FailedDatabaseMethods.With(DBMethodLastProcessedBlockHash).Inc()
+		return common.Hash{}, err
+	}
+	return hash, nil
+}
+
+func (d *Driver) depositBlocks(fromBlock uint64) ([]db.BlockID, error) {
+	blocks, err := d.cfg.Database.DepositBlocks(fromBlock)
+	if err != nil {
+		d.metrics.FailedDatabaseMethods.With(DBMethodDepositBlocks).Inc()
+		return nil, err
+	}
+	return blocks, nil
+}
+
+func (d *Driver) upsertRejectedDeposits(
+	deposits []db.RejectedDeposit,
+) error {
+
+	if len(deposits) == 0 {
+		return nil
+	}
+	err := d.cfg.Database.UpsertRejectedDeposits(deposits)
+	if err != nil {
+		d.metrics.FailedDatabaseMethods.With(DBMethodUpsertRejectedDeposits).Inc()
+		return err
+	}
+	return nil
+}
+
+func (d *Driver) rollbackDeposits(
+	fromBlock uint64,
+	lastProcessedBlock db.BlockID,
+) (uint64, error) {
+
+	rolledBack, err := d.cfg.Database.RollbackDeposits(
+		fromBlock, lastProcessedBlock,
+	)
+	if err != nil {
+		d.metrics.FailedDatabaseMethods.With(DBMethodRollbackDeposits).Inc()
+		return 0, err
+	}
+	return rolledBack, nil
+}
+
+func (d *Driver) insertReorg(reorg db.Reorg) error {
+	_, err := d.cfg.Database.InsertReorg(reorg)
+	if err != nil {
+		d.metrics.FailedDatabaseMethods.With(DBMethodInsertReorg).Inc()
+		return err
+	}
+	return nil
+}
+
+func (d *Driver) unacknowledgedReorgs() ([]db.Reorg, error) {
+	reorgs, err := d.cfg.Database.UnacknowledgedReorgs()
+	if err != nil {
+		d.metrics.FailedDatabaseMethods.With(DBMethodUnacknowledgedReorgs).Inc()
+		return nil, err
+	}
+	return reorgs, nil
+}
+
 func (d *Driver) upsertPendingTx(pendingTx db.PendingTx) error {
 	err := d.cfg.Database.UpsertPendingTx(pendingTx)
 	if err != nil {
@@ -738,8 +1251,12 @@ func (d *Driver) latestDisbursementID() (*uint64, error) {
 	return lastDisbursementID, nil
 }
 
-func (d *Driver) deletePendingTx(startID, endID uint64) error {
-	err := d.cfg.Database.DeletePendingTx(startID, endID)
+func (d *Driver) deletePendingTx(
+	token common.Address,
+	startID, endID uint64,
+) error {
+
+	err := d.cfg.Database.DeletePendingTx(token, startID, endID)
 	if err != nil {
 		d.metrics.FailedDatabaseMethods.With(DBMethodDeletePendingTx).Inc()
 		return err
@@ -800,7 +1317,39 @@ func (d *Driver) collectChainMetrics(ctx context.Context) {
 		nextDisbursementID = big.NewInt(0)
 	}
 
+	disburserTokenBals := make(map[string]*big.Int)
+	depositTokenBals := make(map[string]*big.Int)
+	for _, token := range d.cfg.Tokens {
+		bal, err := tokens.BalanceOf(
+			subCtx, d.cfg.L2Client, token.L2Address, d.walletAddr,
+		)
+		if err != nil {
+			log.Error("Error getting disburser token balance",
+				"token", token.Symbol, "err", err)
+			bal = big.NewInt(0)
+		}
+		disburserTokenBals[token.Symbol] = bal
+
+		bal, err = tokens.BalanceOf(
+			subCtx, d.cfg.L1Client, token.L1Address, token.DepositAddress,
+		)
+		if err != nil {
+			log.Error("Error getting deposit token balance",
+				"token", token.Symbol, "err", err)
+			bal = big.NewInt(0)
+		}
+		depositTokenBals[token.Symbol] = bal
+	}
+
 	d.metricsMu.Lock()
+	for symbol, bal := range disburserTokenBals {
+		balFloat, _ := new(big.Float).SetInt(bal).Float64()
+		d.metrics.DisburserTokenBalance.WithLabelValues(symbol).Set(balFloat)
+	}
+	for symbol, bal := range depositTokenBals {
+		balFloat, _ := new(big.Float).SetInt(bal).Float64()
+		d.metrics.DepositTokenBalance.WithLabelValues(symbol).Set(balFloat)
+	}
 	d.metrics.DisburserBalance.Set(float64(disburserBal.Uint64()))
 	d.metrics.DepositContractBalance.Set(float64(depositBal.Uint64()))
 	d.metrics.ContractNextDepositID.Set(float64(nextDepositID.Uint64()))
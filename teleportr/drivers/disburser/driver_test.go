@@ -0,0 +1,261 @@
+package disburser
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/teleportr/db"
+	"github.com/ethereum-optimism/optimism/teleportr/tokens"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeL1 serves the headers and logs of an L1 chain in the eth namespace.
+type fakeL1 struct {
+	headers []*types.Header
+	logs    []types.Log
+}
+
+func (f *fakeL1) GetBlockByNumber(ctx context.Context, number rpc.BlockNumber, full bool) (*types.Header, error) {
+	if number < 0 {
+		return f.headers[len(f.headers)-1], nil
+	}
+	if int(number) >= len(f.headers) {
+		return nil, nil
+	}
+	return f.headers[number], nil
+}
+
+func (f *fakeL1) GetLogs(ctx context.Context, crit map[string]interface{}) ([]types.Log, error) {
+	return f.logs, nil
+}
+
+// newFakeL1Client serves the fake L1 chain over an in-process RPC connection.
+func newFakeL1Client(t *testing.T, l1 *fakeL1) *ethclient.Client {
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("eth", l1))
+	client := rpc.DialInProc(server)
+	t.Cleanup(func() {
+		client.Close()
+		server.Stop()
+	})
+	return ethclient.NewClient(client)
+}
+
+// newFakeL1Chain returns n headers with distinct hashes, whose block times are
+// their block numbers.
+func newFakeL1Chain(n int) []*types.Header {
+	var headers []*types.Header
+	for i := 0; i < n; i++ {
+		headers = append(headers, &types.Header{
+			Number:     big.NewInt(int64(i)),
+			Time:       uint64(i),
+			Difficulty: common.Big0,
+		})
+	}
+	return headers
+}
+
+func transferLog(token, from, to common.Address, amount int64, block *types.Header, index uint) types.Log {
+	return types.Log{
+		Address: token,
+		Topics: []common.Hash{
+			tokens.TransferTopic, from.Hash(), to.Hash(),
+		},
+		Data:        common.BigToHash(big.NewInt(amount)).Bytes(),
+		BlockNumber: block.Number.Uint64(),
+		BlockHash:   block.Hash(),
+		TxHash:      common.BigToHash(big.NewInt(int64(index) + 1)),
+		Index:       index,
+	}
+}
+
+// TestFilterTokenDepositsRejectsOutOfLimits asserts that token deposits
+// outside of the deposit limits are returned as rejected deposits with a
+// reason, so that they are recorded for a refund.
+func TestFilterTokenDepositsRejectsOutOfLimits(t *testing.T) {
+	token := tokens.Token{
+		Symbol:           "TKN",
+		L1Address:        common.HexToAddress("0x7070"),
+		L2Address:        common.HexToAddress("0x7171"),
+		DepositAddress:   common.HexToAddress("0xdd01"),
+		MinDepositAmount: big.NewInt(10),
+		MaxDepositAmount: big.NewInt(100),
+	}
+	alice := common.HexToAddress("0xaa01")
+	bob := common.HexToAddress("0xbb01")
+
+	headers := newFakeL1Chain(5)
+	l1 := &fakeL1{
+		headers: headers,
+		logs: []types.Log{
+			transferLog(token.L1Address, alice, token.DepositAddress, 50, headers[2], 0),
+			transferLog(token.L1Address, bob, token.DepositAddress, 500, headers[3], 1),
+		},
+	}
+	d := &Driver{
+		cfg: Config{
+			L1Client: newFakeL1Client(t, l1),
+			Tokens:   []tokens.Token{token},
+		},
+		metrics:          NewMetrics("test_filter_token_deposits"),
+		rejectedDeposits: make(map[rejectedDeposit]uint64),
+	}
+
+	deposits, rejected, err := d.filterTokenDeposits(context.Background(), 0, 4)
+	require.NoError(t, err)
+
+	require.Len(t, deposits, 1)
+	require.Equal(t, alice, deposits[0].Address)
+	require.Equal(t, big.NewInt(50), deposits[0].Amount)
+
+	require.Equal(t, []db.RejectedDeposit{{
+		Deposit: db.Deposit{
+			ID:        tokens.DepositID(3, 1),
+			Token:     token.L1Address,
+			Address:   bob,
+			Amount:    big.NewInt(500),
+			BlockHash: headers[3].Hash(),
+			ConfirmationInfo: db.ConfirmationInfo{
+				TxnHash:        common.BigToHash(big.NewInt(2)),
+				BlockNumber:    3,
+				BlockTimestamp: time.Unix(3, 0),
+			},
+		},
+		Reason: "amount outside of deposit limits [10, 100]",
+		Status: db.RejectedDepositStatusRejected,
+	}}, rejected)
+
+	// Rescanning the range returns the rejected deposit again, as recording it
+	// is idempotent.
+	_, rejected, err = d.filterTokenDeposits(context.Background(), 0, 4)
+	require.NoError(t, err)
+	require.Len(t, rejected, 1)
+}
+
+// fakeReorgDatabase records the rollbacks and reorgs of the driver. Methods
+// unrelated to reorgs are left unimplemented.
+type fakeReorgDatabase struct {
+	Database
+
+	lastProcessedBlock     uint64
+	lastProcessedBlockHash common.Hash
+	depositBlocks          []db.BlockID
+
+	rollbackFrom *uint64
+	rewindTo     db.BlockID
+	reorgs       []db.Reorg
+}
+
+func (f *fakeReorgDatabase) LastProcessedBlock() (*uint64, error) {
+	return &f.lastProcessedBlock, nil
+}
+
+func (f *fakeReorgDatabase) LastProcessedBlockHash() (common.Hash, error) {
+	return f.lastProcessedBlockHash, nil
+}
+
+func (f *fakeReorgDatabase) DepositBlocks(fromBlock uint64) ([]db.BlockID, error) {
+	var blocks []db.BlockID
+	for _, block := range f.depositBlocks {
+		if block.Number >= fromBlock {
+			blocks = append(blocks, block)
+		}
+	}
+	return blocks, nil
+}
+
+func (f *fakeReorgDatabase) RollbackDeposits(
+	fromBlock uint64,
+	lastProcessedBlock db.BlockID,
+) (uint64, error) {
+
+	f.rollbackFrom = &fromBlock
+	f.rewindTo = lastProcessedBlock
+	return 1, nil
+}
+
+func (f *fakeReorgDatabase) InsertReorg(reorg db.Reorg) (uint64, error) {
+	f.reorgs = append(f.reorgs, reorg)
+	return uint64(len(f.reorgs)), nil
+}
+
+// forkFakeL1Chain replaces the headers from the given height onwards with
+// headers of a competing fork.
+func forkFakeL1Chain(headers []*types.Header, from int) []*types.Header {
+	forked := append([]*types.Header{}, headers[:from]...)
+	for _, header := range headers[from:] {
+		header = types.CopyHeader(header)
+		header.Extra = []byte("fork")
+		forked = append(forked, header)
+	}
+	return forked
+}
+
+// TestDetectReorg asserts that reorgs of unconfirmed blocks roll back the
+// deposits of those blocks, while reorgs removing confirmed deposits are also
+// recorded to pause disbursements.
+func TestDetectReorg(t *testing.T) {
+	headers := newFakeL1Chain(20)
+	newTestDriver := func(t *testing.T, name string, l1Headers []*types.Header) (*Driver, *fakeReorgDatabase) {
+		database := &fakeReorgDatabase{
+			lastProcessedBlock:     15,
+			lastProcessedBlockHash: headers[15].Hash(),
+			depositBlocks: []db.BlockID{
+				{Number: 5, Hash: headers[5].Hash()},
+				{Number: 14, Hash: headers[14].Hash()},
+			},
+		}
+		return &Driver{
+			cfg: Config{
+				L1Client:         newFakeL1Client(t, &fakeL1{headers: l1Headers}),
+				Database:         database,
+				NumConfirmations: 3,
+			},
+			metrics: NewMetrics("test_detect_reorg_" + name),
+		}, database
+	}
+
+	t.Run("no_reorg", func(t *testing.T) {
+		d, database := newTestDriver(t, "no_reorg", headers)
+
+		require.NoError(t, d.detectReorg(context.Background()))
+		require.Nil(t, database.rollbackFrom)
+		require.Empty(t, database.reorgs)
+	})
+
+	t.Run("shallow", func(t *testing.T) {
+		// The fork only replaces blocks that were unconfirmed as of the last
+		// processed block.
+		d, database := newTestDriver(t, "shallow", forkFakeL1Chain(headers, 14))
+
+		require.NoError(t, d.detectReorg(context.Background()))
+		require.NotNil(t, database.rollbackFrom)
+		require.Equal(t, uint64(14), *database.rollbackFrom)
+		require.Equal(t, db.BlockID{Number: 13, Hash: headers[13].Hash()}, database.rewindTo)
+		require.Empty(t, database.reorgs)
+	})
+
+	t.Run("deep", func(t *testing.T) {
+		// The fork replaces the confirmed deposit at block 5.
+		forked := forkFakeL1Chain(headers, 5)
+		d, database := newTestDriver(t, "deep", forked)
+
+		require.NoError(t, d.detectReorg(context.Background()))
+		require.NotNil(t, database.rollbackFrom)
+		require.Equal(t, uint64(5), *database.rollbackFrom)
+		require.Equal(t, db.BlockID{Number: 4, Hash: headers[4].Hash()}, database.rewindTo)
+
+		require.Len(t, database.reorgs, 1)
+		reorg := database.reorgs[0]
+		require.Equal(t, uint64(5), reorg.BlockNumber)
+		require.Equal(t, headers[5].Hash(), reorg.OldBlockHash)
+		require.Equal(t, forked[5].Hash(), reorg.NewBlockHash)
+		require.Equal(t, uint64(1), reorg.RolledBackDeposits)
+	})
+}
@@ -0,0 +1,47 @@
+package disburser
+
+// RollbackBlockNumberParams holds the arguments passed to
+// FindRollbackBlockNumber.
+type RollbackBlockNumberParams struct {
+	// LastProcessedBlockNumber is the height of the last processed block,
+	// which is no longer part of the canonical chain.
+	LastProcessedBlockNumber uint64
+
+	// NumConfirmations is the number of confirmations required to consider a
+	// block final.
+	NumConfirmations uint64
+
+	// DeployBlockNumber is the deployment height of the Deposit contract.
+	DeployBlockNumber uint64
+
+	// RemovedDepositBlockNumber is the height of the earliest block with
+	// deposits that is no longer part of the canonical chain.
+	//
+	// NOTE: This will be nil if none of the blocks with deposits were removed.
+	RemovedDepositBlockNumber *uint64
+}
+
+// FindRollbackBlockNumber returns the height from which deposits are rolled
+// back after a reorg, and whether the reorg removed confirmed deposits. Blocks
+// that were unconfirmed as of the last processed block are always rolled back,
+// since they may have been replaced by the reorg.
+func FindRollbackBlockNumber(params RollbackBlockNumberParams) (uint64, bool) {
+	// The first unconfirmed block as of the last processed block, bounded by
+	// the deployment height.
+	rollbackBlockNumber := params.DeployBlockNumber
+	if params.LastProcessedBlockNumber+1 >=
+		params.NumConfirmations+params.DeployBlockNumber {
+
+		rollbackBlockNumber = params.LastProcessedBlockNumber + 2 -
+			params.NumConfirmations
+	}
+
+	// A removed deposit below the unconfirmed blocks was already confirmed,
+	// and possibly disbursed.
+	removed := params.RemovedDepositBlockNumber
+	if removed != nil && *removed < rollbackBlockNumber {
+		return *removed, true
+	}
+
+	return rollbackBlockNumber, false
+}
@@ -0,0 +1,100 @@
+package disburser_test
+
+import (
+	"testing"
+
+	"github.com/ethereum-optimism/optimism/teleportr/drivers/disburser"
+	"github.com/stretchr/testify/require"
+)
+
+type rollbackBlockNumberTestCase struct {
+	name                   string
+	params                 disburser.RollbackBlockNumberParams
+	expRollbackBlockNumber uint64
+	expDeep                bool
+}
+
+// TestFindRollbackBlockNumber exhaustively tests the behavior of
+// FindRollbackBlockNumber and its edge cases.
+func TestFindRollbackBlockNumber(t *testing.T) {
+	tests := []rollbackBlockNumberTestCase{
+		// Unconfirmed blocks are rolled back if no deposits were removed.
+		{
+			name: "no removed deposits",
+			params: disburser.RollbackBlockNumberParams{
+				LastProcessedBlockNumber: 100,
+				NumConfirmations:         5,
+				DeployBlockNumber:        42,
+			},
+			expRollbackBlockNumber: 97,
+		},
+		// Removed deposits in unconfirmed blocks are a shallow reorg.
+		{
+			name: "removed unconfirmed deposit",
+			params: disburser.RollbackBlockNumberParams{
+				LastProcessedBlockNumber:  100,
+				NumConfirmations:          5,
+				DeployBlockNumber:         42,
+				RemovedDepositBlockNumber: uint64Ptr(97),
+			},
+			expRollbackBlockNumber: 97,
+		},
+		// Removed deposits in confirmed blocks are a deep reorg, and extend
+		// the rollback.
+		{
+			name: "removed confirmed deposit",
+			params: disburser.RollbackBlockNumberParams{
+				LastProcessedBlockNumber:  100,
+				NumConfirmations:          5,
+				DeployBlockNumber:         42,
+				RemovedDepositBlockNumber: uint64Ptr(96),
+			},
+			expRollbackBlockNumber: 96,
+			expDeep:                true,
+		},
+		// With a single confirmation every processed block is confirmed.
+		{
+			name: "single confirmation",
+			params: disburser.RollbackBlockNumberParams{
+				LastProcessedBlockNumber:  100,
+				NumConfirmations:          1,
+				DeployBlockNumber:         42,
+				RemovedDepositBlockNumber: uint64Ptr(100),
+			},
+			expRollbackBlockNumber: 100,
+			expDeep:                true,
+		},
+		// The rollback never extends below the deploy block number.
+		{
+			name: "conf lookback before deploy number",
+			params: disburser.RollbackBlockNumberParams{
+				LastProcessedBlockNumber: 44,
+				NumConfirmations:         10,
+				DeployBlockNumber:        42,
+			},
+			expRollbackBlockNumber: 42,
+		},
+		// Removed deposits at the deploy number within the confirmation
+		// window are unconfirmed.
+		{
+			name: "removed deposit at deploy number",
+			params: disburser.RollbackBlockNumberParams{
+				LastProcessedBlockNumber:  44,
+				NumConfirmations:          10,
+				DeployBlockNumber:         42,
+				RemovedDepositBlockNumber: uint64Ptr(42),
+			},
+			expRollbackBlockNumber: 42,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rollbackBlockNumber, deep := disburser.FindRollbackBlockNumber(
+				test.params,
+			)
+			require.Equal(t, test.expRollbackBlockNumber, rollbackBlockNumber)
+			require.Equal(t, test.expDeep, deep)
+		})
+	}
+}
@@ -6,7 +6,11 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promauto"
 )
 
-const methodLabel = "method"
+const (
+	methodLabel = "method"
+	tokenLabel  = "token"
+	depthLabel  = "depth"
+)
 
 var (
 	// DBMethodUpsertDeposits is a label for UpsertDeposits db method.
@@ -32,6 +36,36 @@ var (
 
 	// DBMethodDeletePendingTx is a label for DeletePendingTx db method.
 	DBMethodDeletePendingTx = prometheus.Labels{methodLabel: "delete_pending_tx"}
+
+	// DBMethodLastProcessedBlockHash is a label for LastProcessedBlockHash db
+	// method.
+	DBMethodLastProcessedBlockHash = prometheus.Labels{methodLabel: "last_processed_block_hash"}
+
+	// DBMethodDepositBlocks is a label for DepositBlocks db method.
+	DBMethodDepositBlocks = prometheus.Labels{methodLabel: "deposit_blocks"}
+
+	// DBMethodUpsertRejectedDeposits is a label for UpsertRejectedDeposits db
+	// method.
+	DBMethodUpsertRejectedDeposits = prometheus.Labels{methodLabel: "upsert_rejected_deposits"}
+
+	// DBMethodRollbackDeposits is a label for RollbackDeposits db method.
+	DBMethodRollbackDeposits = prometheus.Labels{methodLabel: "rollback_deposits"}
+
+	// DBMethodInsertReorg is a label for InsertReorg db method.
+	DBMethodInsertReorg = prometheus.Labels{methodLabel: "insert_reorg"}
+
+	// DBMethodUnacknowledgedReorgs is a label for UnacknowledgedReorgs db
+	// method.
+	DBMethodUnacknowledgedReorgs = prometheus.Labels{methodLabel: "unacknowledged_reorgs"}
+)
+
+const (
+	// ReorgDepthShallow labels reorgs that only removed unconfirmed blocks.
+	ReorgDepthShallow = "shallow"
+
+	// ReorgDepthDeep labels reorgs that removed blocks with confirmed
+	// deposits.
+	ReorgDepthDeep = "deep"
 )
 
 // Metrics extends the BSS core metrics with additional metrics tracked by the
@@ -79,6 +113,34 @@ type Metrics struct {
 	// FailedTXSubmissions tracks failed requests to eth_sendRawTransaction
 	// during transaction submission.
 	FailedTXSubmissions *prometheus.CounterVec
+
+	// SuccessfulTokenDisbursements tracks the number of successful ERC20
+	// disbursements for each token.
+	SuccessfulTokenDisbursements *prometheus.CounterVec
+
+	// FailedTokenDisbursements tracks the number of reverted ERC20
+	// disbursements for each token.
+	FailedTokenDisbursements *prometheus.CounterVec
+
+	// RejectedDeposits tracks the number of ERC20 deposits that fall outside
+	// of the configured deposit limits for each token.
+	RejectedDeposits *prometheus.CounterVec
+
+	// DisburserTokenBalance tracks the disburser wallet's balance of each
+	// token on L2.
+	DisburserTokenBalance *prometheus.GaugeVec
+
+	// DepositTokenBalance tracks the deposit address' balance of each token
+	// on L1.
+	DepositTokenBalance *prometheus.GaugeVec
+
+	// Reorgs tracks the number of detected L1 reorgs by depth.
+	Reorgs *prometheus.CounterVec
+
+	// UnacknowledgedReorgs tracks the number of deep reorgs awaiting operator
+	// acknowledgement. Disbursements are paused while this is non-zero, so
+	// any value above zero should be treated as a critical alert.
+	UnacknowledgedReorgs prometheus.Gauge
 }
 
 // NewMetrics initializes a new, extended metrics object.
@@ -147,5 +209,41 @@ func NewMetrics(subsystem string) *Metrics {
 		}, []string{
 			"type",
 		}),
+		SuccessfulTokenDisbursements: promauto.NewCounterVec(prometheus.CounterOpts{
+			Name:      "successful_token_disbursements",
+			Help:      "Number of successful ERC20 disbursements per token",
+			Subsystem: base.SubsystemName(),
+		}, []string{tokenLabel}),
+		FailedTokenDisbursements: promauto.NewCounterVec(prometheus.CounterOpts{
+			Name:      "failed_token_disbursements",
+			Help:      "Number of reverted ERC20 disbursements per token",
+			Subsystem: base.SubsystemName(),
+		}, []string{tokenLabel}),
+		RejectedDeposits: promauto.NewCounterVec(prometheus.CounterOpts{
+			Name:      "rejected_deposits",
+			Help:      "Number of ERC20 deposits outside of the token's deposit limits",
+			Subsystem: base.SubsystemName(),
+		}, []string{tokenLabel}),
+		DisburserTokenBalance: promauto.NewGaugeVec(prometheus.GaugeOpts{
+			Name:      "disburser_token_balance",
+			Help:      "Balance in base units of Teleportr's disburser wallet per token",
+			Subsystem: base.SubsystemName(),
+		}, []string{tokenLabel}),
+		DepositTokenBalance: promauto.NewGaugeVec(prometheus.GaugeOpts{
+			Name:      "deposit_token_balance",
+			Help:      "Balance in base units of Teleportr's deposit address per token",
+			Subsystem: base.SubsystemName(),
+		}, []string{tokenLabel}),
+		Reorgs: promauto.NewCounterVec(prometheus.CounterOpts{
+			Name:      "reorgs",
+			Help:      "Number of detected L1 reorgs by depth",
+			Subsystem: base.SubsystemName(),
+		}, []string{depthLabel}),
+		UnacknowledgedReorgs: promauto.NewGauge(prometheus.GaugeOpts{
+			Name: "unacknowledged_reorgs",
+			Help: "Number of deep L1 reorgs awaiting operator " +
+				"acknowledgement, disbursements are paused while non-zero",
+			Subsystem: base.SubsystemName(),
+		}),
 	}
 }
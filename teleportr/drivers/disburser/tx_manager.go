@@ -0,0 +1,124 @@
+package disburser
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/cenkalti/backoff"
+	"github.com/ethereum-optimism/optimism/op-service/txmgr"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// NewTxManager creates the tx manager that publishes the disbursements of the
+// driver on L2. L2 only accepts legacy transactions, which are priced at the
+// suggested gas price of L2.
+func (d *Driver) NewTxManager(cfg txmgr.Config) *txmgr.Manager {
+	backend := &l2Backend{Client: d.cfg.L2Client, metrics: d.metrics}
+	return txmgr.NewManager(txmgr.ManagerConfig{
+		Config:    cfg,
+		ChainID:   d.cfg.ChainID,
+		From:      d.walletAddr,
+		Signer:    d.SignTx,
+		LegacyTxs: true,
+	}, backend, &gasPricer{source: d.cfg.L2Client})
+}
+
+// l2Backend is the L2 client of the tx manager, which retries failures to
+// publish a transaction.
+type l2Backend struct {
+	*ethclient.Client
+	metrics *Metrics
+}
+
+// SendTransaction injects a signed transaction into the pending pool for
+// execution.
+func (b *l2Backend) SendTransaction(
+	ctx context.Context,
+	tx *types.Transaction,
+) error {
+
+	// This requires special handling - if this request fails,
+	// then teleportr will halt. Use exponential backoff here to
+	// handle expected failures (e.g., 503s, 524s, etc.).
+	return backoff.Retry(func() error {
+		subCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+		err := b.Client.SendTransaction(subCtx, tx)
+		if err == nil {
+			return err
+		}
+		if !IsRetryableError(err) {
+			b.metrics.FailedTXSubmissions.WithLabelValues("permanent").Inc()
+			return backoff.Permanent(err)
+		}
+		b.metrics.FailedTXSubmissions.WithLabelValues("recoverable").Inc()
+		return err
+	}, DefaultBackoff)
+}
+
+// gasPriceSource suggests the gas price of legacy transactions.
+type gasPriceSource interface {
+	SuggestGasPrice(ctx context.Context) (*big.Int, error)
+}
+
+// gasPricer is the txmgr.PricingStrategy of legacy transactions on L2. It
+// uses the suggested gas price as both the tip and the fee cap, and does not
+// limit the fees spent.
+type gasPricer struct {
+	source gasPriceSource
+}
+
+var _ txmgr.PricingStrategy = (*gasPricer)(nil)
+
+// Price returns the suggested gas price.
+func (p *gasPricer) Price(
+	ctx context.Context,
+	gas uint64,
+	urgency txmgr.Urgency,
+) (txmgr.TxPrice, error) {
+
+	gasPrice, err := p.source.SuggestGasPrice(ctx)
+	if err != nil {
+		return txmgr.TxPrice{}, err
+	}
+	return txmgr.TxPrice{GasTipCap: gasPrice, GasFeeCap: gasPrice}, nil
+}
+
+// Bump returns the suggested gas price, raised to at least the minimum price
+// bump over the gas price of tx.
+func (p *gasPricer) Bump(
+	ctx context.Context,
+	tx *types.Transaction,
+	urgency txmgr.Urgency,
+) (txmgr.TxPrice, error) {
+
+	price, err := p.Price(ctx, tx.Gas(), urgency)
+	if err != nil {
+		return txmgr.TxPrice{}, err
+	}
+	minPrice := new(big.Int).Mul(tx.GasPrice(), big.NewInt(100+txmgr.MinPriceBump))
+	minPrice.Div(minPrice, big.NewInt(100))
+	if price.GasFeeCap.Cmp(minPrice) < 0 {
+		price = txmgr.TxPrice{GasTipCap: minPrice, GasFeeCap: minPrice}
+	}
+	return price, nil
+}
+
+// RecordReceipt is a no-op, as the fees on L2 are not limited.
+func (p *gasPricer) RecordReceipt(
+	ctx context.Context,
+	receipt *types.Receipt,
+) error {
+
+	return nil
+}
+
+// Spends returns no fees, as the fees on L2 are not limited.
+func (p *gasPricer) Spends() []txmgr.FeeSpend {
+	return nil
+}
+
+// RestoreSpends is a no-op, as the fees on L2 are not limited.
+func (p *gasPricer) RestoreSpends(spends []txmgr.FeeSpend) {}
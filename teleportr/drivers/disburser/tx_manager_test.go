@@ -0,0 +1,41 @@
+package disburser
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum-optimism/optimism/op-service/txmgr"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+type fixedGasPrice int64
+
+func (p fixedGasPrice) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	return big.NewInt(int64(p)), nil
+}
+
+func TestGasPricerBump(t *testing.T) {
+	tests := []struct {
+		name      string
+		suggested int64
+		old       int64
+		exp       int64
+	}{
+		{"suggested above min bump", 200, 100, 200},
+		{"suggested below min bump", 105, 100, 110},
+		{"suggested unchanged", 100, 100, 110},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pricer := &gasPricer{source: fixedGasPrice(test.suggested)}
+			tx := types.NewTx(&types.LegacyTx{GasPrice: big.NewInt(test.old)})
+
+			price, err := pricer.Bump(context.Background(), tx, txmgr.Urgency{})
+			require.NoError(t, err)
+			require.Equal(t, big.NewInt(test.exp), price.GasFeeCap)
+			require.Equal(t, big.NewInt(test.exp), price.GasTipCap)
+		})
+	}
+}
@@ -51,4 +51,5 @@ var APIFlags = []cli.Flag{
 	MetricsHostnameFlag,
 	MetricsPortFlag,
 	HTTP2DisableFlag,
+	TokensConfigFlag,
 }
@@ -186,6 +186,32 @@ var (
 		Usage:  "Whether or not to disable HTTP/2 support.",
 		EnvVar: prefixEnvVar("HTTP2_DISABLE"),
 	}
+	AdminServerEnableFlag = cli.BoolTFlag{
+		Name: "admin-server-enable",
+		Usage: "Whether or not to run the admin server used to acknowledge " +
+			"L1 reorgs that paused disbursements. Enabled by default, as " +
+			"disbursements remain paused after a deep reorg until it is " +
+			"acknowledged",
+		EnvVar: prefixEnvVar("ADMIN_SERVER_ENABLE"),
+	}
+	AdminHostnameFlag = cli.StringFlag{
+		Name:   "admin-hostname",
+		Usage:  "The hostname of the admin server",
+		Value:  "127.0.0.1",
+		EnvVar: prefixEnvVar("ADMIN_HOSTNAME"),
+	}
+	AdminPortFlag = cli.Uint64Flag{
+		Name:   "admin-port",
+		Usage:  "The port of the admin server",
+		Value:  7301,
+		EnvVar: prefixEnvVar("ADMIN_PORT"),
+	}
+	TokensConfigFlag = cli.StringFlag{
+		Name: "tokens-config",
+		Usage: "Path to a JSON file listing the ERC20 tokens that can be " +
+			"teleported, along with their deposit limits",
+		EnvVar: prefixEnvVar("TOKENS_CONFIG"),
+	}
 )
 
 var requiredFlags = []cli.Flag{
@@ -220,6 +246,10 @@ var optionalFlags = []cli.Flag{
 	MetricsHostnameFlag,
 	MetricsPortFlag,
 	HTTP2DisableFlag,
+	TokensConfigFlag,
+	AdminServerEnableFlag,
+	AdminHostnameFlag,
+	AdminPortFlag,
 }
 
 // Flags contains the list of configuration options available to the binary.
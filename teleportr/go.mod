@@ -4,8 +4,11 @@ go 1.18
 
 replace github.com/ethereum-optimism/optimism/bss-core v0.0.0 => ../bss-core
 
+replace github.com/ethereum-optimism/optimism/op-service v0.0.0 => ../op-service
+
 require (
 	github.com/ethereum-optimism/optimism/bss-core v0.0.0
+	github.com/ethereum-optimism/optimism/op-service v0.0.0
 	github.com/ethereum/go-ethereum v1.10.17
 	github.com/google/uuid v1.3.0
 	github.com/gorilla/mux v1.8.0
@@ -2,6 +2,7 @@ package teleportr
 
 import (
 	"context"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
@@ -10,9 +11,11 @@ import (
 	bsscore "github.com/ethereum-optimism/optimism/bss-core"
 	"github.com/ethereum-optimism/optimism/bss-core/dial"
 	"github.com/ethereum-optimism/optimism/bss-core/metrics"
-	"github.com/ethereum-optimism/optimism/bss-core/txmgr"
+	"github.com/ethereum-optimism/optimism/op-service/txmgr"
+	"github.com/ethereum-optimism/optimism/teleportr/admin"
 	"github.com/ethereum-optimism/optimism/teleportr/db"
 	"github.com/ethereum-optimism/optimism/teleportr/drivers/disburser"
+	"github.com/ethereum-optimism/optimism/teleportr/tokens"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/urfave/cli"
 )
@@ -56,6 +59,11 @@ func Main(gitVersion string) func(ctx *cli.Context) error {
 			return err
 		}
 
+		tokenList, err := tokens.Load(cfg.TokensConfig)
+		if err != nil {
+			return err
+		}
+
 		l1Client, err := dial.L1EthClientWithTimeout(ctx, cfg.L1EthRpc, cfg.DisableHTTP2)
 		if err != nil {
 			return err
@@ -85,12 +93,31 @@ func Main(gitVersion string) func(ctx *cli.Context) error {
 			go metrics.RunServer(cfg.MetricsHostname, cfg.MetricsPort)
 		}
 
+		if cfg.AdminServerEnable {
+			adminServer := admin.NewServer(ctx, database, database)
+			go func() {
+				err := adminServer.ListenAndServe(cfg.AdminHostname, cfg.AdminPort)
+				if err != nil && err != http.ErrServerClosed {
+					log.Error("Admin server failed", "err", err)
+				}
+			}()
+			defer func() {
+				_ = adminServer.Shutdown(context.Background())
+			}()
+		} else {
+			log.Warn("Admin server disabled, disbursements paused by a " +
+				"deep L1 reorg cannot be resumed without restarting with " +
+				"the admin server enabled")
+		}
+
 		chainID, err := l2Client.ChainID(ctx)
 		if err != nil {
 			return err
 		}
 
 		txManagerConfig := txmgr.Config{
+			Log:                       log.Root(),
+			Name:                      "Teleportr",
 			ResubmissionTimeout:       cfg.ResubmissionTimeout,
 			ReceiptQueryInterval:      time.Second,
 			NumConfirmations:          1, // L2 insta confs
@@ -111,18 +138,18 @@ func Main(gitVersion string) func(ctx *cli.Context) error {
 			ChainID:              chainID,
 			PrivKey:              disburserPrivKey,
 			ChainMetricsEnable:   cfg.MetricsServerEnable,
+			Tokens:               tokenList,
 		}, ctx)
 		if err != nil {
 			return err
 		}
 
 		teleportrService := bsscore.NewService(bsscore.ServiceConfig{
-			Context:         ctx,
-			Driver:          teleportrDriver,
-			PollInterval:    cfg.PollInterval,
-			ClearPendingTx:  false,
-			L1Client:        l2Client,
-			TxManagerConfig: txManagerConfig,
+			Context:      ctx,
+			Driver:       teleportrDriver,
+			PollInterval: cfg.PollInterval,
+			L1Client:     l2Client,
+			TxManager:    teleportrDriver.NewTxManager(txManagerConfig),
 		})
 
 		services := []*bsscore.Service{teleportrService}
@@ -0,0 +1,165 @@
+package tokens
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// ETH is the token address of native ether deposits, which are received by the
+// TeleportrDeposit contract and disbursed by the TeleportrDisburser contract.
+var ETH = common.Address{}
+
+// ERC20ABI is the subset of the ERC20 ABI used by teleportr.
+const ERC20ABI = `[
+	{"type":"function","name":"transfer","stateMutability":"nonpayable","inputs":[{"name":"to","type":"address"},{"name":"amount","type":"uint256"}],"outputs":[{"name":"","type":"bool"}]},
+	{"type":"function","name":"balanceOf","stateMutability":"view","inputs":[{"name":"account","type":"address"}],"outputs":[{"name":"","type":"uint256"}]},
+	{"type":"event","name":"Transfer","anonymous":false,"inputs":[{"name":"from","type":"address","indexed":true},{"name":"to","type":"address","indexed":true},{"name":"value","type":"uint256","indexed":false}]}
+]`
+
+// TransferTopic is the topic hash of ERC20 Transfer events.
+var TransferTopic = crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))
+
+var parsedERC20ABI abi.ABI
+
+func init() {
+	parsed, err := abi.JSON(strings.NewReader(ERC20ABI))
+	if err != nil {
+		panic(err)
+	}
+	parsedERC20ABI = parsed
+}
+
+// Token is an ERC20 token that can be teleported. Tokens are deposited with a
+// plain ERC20 transfer to the deposit address on L1, and disbursed with an
+// ERC20 transfer from the disburser wallet on L2.
+type Token struct {
+	// Symbol identifies the token in logs and metrics.
+	Symbol string `json:"symbol"`
+
+	// L1Address is the address of the token contract on L1.
+	L1Address common.Address `json:"l1_address"`
+
+	// L2Address is the address of the token contract on L2.
+	L2Address common.Address `json:"l2_address"`
+
+	// DepositAddress is the L1 account that receives the token deposits.
+	DepositAddress common.Address `json:"deposit_address"`
+
+	// MinDepositAmount is the smallest deposit, in base units, that is disbursed.
+	MinDepositAmount *big.Int `json:"min_deposit_amount"`
+
+	// MaxDepositAmount is the largest deposit, in base units, that is disbursed.
+	MaxDepositAmount *big.Int `json:"max_deposit_amount"`
+
+	// MaxBalance is the token balance of the deposit address above which
+	// teleportr reports the token as unavailable.
+	MaxBalance *big.Int `json:"max_balance"`
+}
+
+func (t Token) Check() error {
+	if t.Symbol == "" {
+		return errors.New("token symbol must be set")
+	}
+	if t.L1Address == ETH || t.L2Address == ETH {
+		return fmt.Errorf("token %s: L1 and L2 addresses must be set", t.Symbol)
+	}
+	if t.DepositAddress == (common.Address{}) {
+		return fmt.Errorf("token %s: deposit address must be set", t.Symbol)
+	}
+	if t.MinDepositAmount == nil || t.MaxDepositAmount == nil || t.MaxBalance == nil {
+		return fmt.Errorf("token %s: deposit limits and max balance must be set", t.Symbol)
+	}
+	if t.MinDepositAmount.Cmp(t.MaxDepositAmount) > 0 {
+		return fmt.Errorf("token %s: min deposit amount exceeds max deposit amount", t.Symbol)
+	}
+	return nil
+}
+
+// InLimits returns true if the amount is within the deposit limits of the token.
+func (t Token) InLimits(amount *big.Int) bool {
+	return amount.Cmp(t.MinDepositAmount) >= 0 && amount.Cmp(t.MaxDepositAmount) <= 0
+}
+
+// Load reads the list of tokens from the JSON file at path. No tokens are
+// configured if the path is empty.
+func Load(path string) ([]Token, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read tokens config: %w", err)
+	}
+	var tokens []Token
+	if err := json.Unmarshal(data, &tokens); err != nil {
+		return nil, fmt.Errorf("unable to decode tokens config: %w", err)
+	}
+	seen := make(map[common.Address]bool)
+	for _, token := range tokens {
+		if err := token.Check(); err != nil {
+			return nil, err
+		}
+		if seen[token.L1Address] {
+			return nil, fmt.Errorf("token %s: duplicate L1 address %s", token.Symbol, token.L1Address)
+		}
+		seen[token.L1Address] = true
+	}
+	return tokens, nil
+}
+
+// Find returns the token with the given L1 address.
+func Find(tokens []Token, l1Address common.Address) (Token, bool) {
+	for _, token := range tokens {
+		if token.L1Address == l1Address {
+			return token, true
+		}
+	}
+	return Token{}, false
+}
+
+// DepositID returns the id of a token deposit made by the transfer emitted at
+// the given log index of the L1 block. Unlike ETH deposits, token deposits are
+// not numbered by a contract, so their ids are derived from the log position.
+func DepositID(blockNumber uint64, logIndex uint) uint64 {
+	return blockNumber<<32 | uint64(logIndex)
+}
+
+// TransferData returns the calldata of an ERC20 transfer of amount to the recipient.
+func TransferData(to common.Address, amount *big.Int) ([]byte, error) {
+	return parsedERC20ABI.Pack("transfer", to, amount)
+}
+
+// ParseTransfer returns the value of an ERC20 Transfer event from the log data.
+func ParseTransfer(data []byte) (*big.Int, error) {
+	values, err := parsedERC20ABI.Unpack("Transfer", data)
+	if err != nil {
+		return nil, err
+	}
+	return values[0].(*big.Int), nil
+}
+
+// BalanceOf returns the token balance of the account.
+func BalanceOf(
+	ctx context.Context,
+	caller bind.ContractCaller,
+	token, account common.Address,
+) (*big.Int, error) {
+
+	contract := bind.NewBoundContract(token, parsedERC20ABI, caller, nil, nil)
+	var out []interface{}
+	err := contract.Call(&bind.CallOpts{Context: ctx}, &out, "balanceOf", account)
+	if err != nil {
+		return nil, err
+	}
+	return out[0].(*big.Int), nil
+}
@@ -0,0 +1,133 @@
+package tokens_test
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum-optimism/optimism/teleportr/tokens"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+const testConfig = `[
+	{
+		"symbol": "USDC",
+		"l1_address": "0x00000000000000000000000000000000000000a1",
+		"l2_address": "0x00000000000000000000000000000000000000a2",
+		"deposit_address": "0x00000000000000000000000000000000000000a3",
+		"min_deposit_amount": 1000000,
+		"max_deposit_amount": 5000000000,
+		"max_balance": 100000000000000000000000
+	}
+]`
+
+func writeConfig(t *testing.T, config string) string {
+	path := filepath.Join(t.TempDir(), "tokens.json")
+	require.Nil(t, os.WriteFile(path, []byte(config), 0644))
+	return path
+}
+
+// TestLoad asserts that a token config is decoded into a list of tokens,
+// including amounts that overflow uint64.
+func TestLoad(t *testing.T) {
+	list, err := tokens.Load(writeConfig(t, testConfig))
+	require.Nil(t, err)
+
+	maxBalance, _ := new(big.Int).SetString("100000000000000000000000", 10)
+	require.Equal(t, []tokens.Token{{
+		Symbol:           "USDC",
+		L1Address:        common.HexToAddress("0xa1"),
+		L2Address:        common.HexToAddress("0xa2"),
+		DepositAddress:   common.HexToAddress("0xa3"),
+		MinDepositAmount: big.NewInt(1000000),
+		MaxDepositAmount: big.NewInt(5000000000),
+		MaxBalance:       maxBalance,
+	}}, list)
+
+	token, ok := tokens.Find(list, common.HexToAddress("0xa1"))
+	require.True(t, ok)
+	require.Equal(t, "USDC", token.Symbol)
+
+	_, ok = tokens.Find(list, common.HexToAddress("0xa2"))
+	require.False(t, ok)
+}
+
+// TestLoadEmptyPath asserts that no tokens are configured without a path.
+func TestLoadEmptyPath(t *testing.T) {
+	list, err := tokens.Load("")
+	require.Nil(t, err)
+	require.Nil(t, list)
+}
+
+// TestLoadInvalid asserts that invalid token configs are rejected.
+func TestLoadInvalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+	}{
+		{
+			name:   "missing symbol",
+			config: `[{"l1_address": "0xa1", "l2_address": "0xa2", "deposit_address": "0xa3", "min_deposit_amount": 1, "max_deposit_amount": 2, "max_balance": 3}]`,
+		},
+		{
+			name:   "missing l2 address",
+			config: `[{"symbol": "DAI", "l1_address": "0xa1", "deposit_address": "0xa3", "min_deposit_amount": 1, "max_deposit_amount": 2, "max_balance": 3}]`,
+		},
+		{
+			name:   "missing limits",
+			config: `[{"symbol": "DAI", "l1_address": "0xa1", "l2_address": "0xa2", "deposit_address": "0xa3"}]`,
+		},
+		{
+			name:   "min above max",
+			config: `[{"symbol": "DAI", "l1_address": "0xa1", "l2_address": "0xa2", "deposit_address": "0xa3", "min_deposit_amount": 3, "max_deposit_amount": 2, "max_balance": 3}]`,
+		},
+		{
+			name: "duplicate token",
+			config: `[
+				{"symbol": "DAI", "l1_address": "0xa1", "l2_address": "0xa2", "deposit_address": "0xa3", "min_deposit_amount": 1, "max_deposit_amount": 2, "max_balance": 3},
+				{"symbol": "DAI2", "l1_address": "0xa1", "l2_address": "0xa4", "deposit_address": "0xa3", "min_deposit_amount": 1, "max_deposit_amount": 2, "max_balance": 3}
+			]`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := tokens.Load(writeConfig(t, test.config))
+			require.NotNil(t, err)
+		})
+	}
+}
+
+// TestInLimits asserts that the deposit limits of a token are inclusive.
+func TestInLimits(t *testing.T) {
+	token := tokens.Token{
+		MinDepositAmount: big.NewInt(10),
+		MaxDepositAmount: big.NewInt(20),
+	}
+	require.False(t, token.InLimits(big.NewInt(9)))
+	require.True(t, token.InLimits(big.NewInt(10)))
+	require.True(t, token.InLimits(big.NewInt(20)))
+	require.False(t, token.InLimits(big.NewInt(21)))
+}
+
+// TestTransferRoundTrip asserts that the amount packed into transfer calldata
+// is parsed back out of the equivalent Transfer event data.
+func TestTransferRoundTrip(t *testing.T) {
+	amount := big.NewInt(123456789)
+	data, err := tokens.TransferData(common.HexToAddress("0xa1"), amount)
+	require.Nil(t, err)
+	require.Equal(t, 4+32+32, len(data))
+
+	parsed, err := tokens.ParseTransfer(data[4+32:])
+	require.Nil(t, err)
+	require.Equal(t, amount, parsed)
+}
+
+// TestDepositID asserts that token deposit ids are ordered by block number and
+// then by log index.
+func TestDepositID(t *testing.T) {
+	require.Less(t, tokens.DepositID(1, 5), tokens.DepositID(1, 6))
+	require.Less(t, tokens.DepositID(1, 1<<20), tokens.DepositID(2, 0))
+}